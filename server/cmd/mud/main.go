@@ -9,6 +9,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/lawnchairsociety/opentowermud/server/internal/channels"
 	"github.com/lawnchairsociety/opentowermud/server/internal/chatfilter"
 	"github.com/lawnchairsociety/opentowermud/server/internal/config"
 	"github.com/lawnchairsociety/opentowermud/server/internal/crafting"
@@ -155,6 +156,42 @@ func main() {
 	}
 	logger.Info("Spells loaded", "count", len(spellRegistry.GetAllSpells()))
 
+	// Check that mob abilities only use spells and summons that exist
+	if mobConfig != nil {
+		for mobID, def := range mobConfig.NPCs {
+			for _, ability := range def.Abilities {
+				spell, exists := spellRegistry.GetSpell(ability.Spell)
+				if !exists {
+					logger.Warning("Mob ability uses an unknown spell", "mob", mobID, "spell", ability.Spell)
+					continue
+				}
+				for _, effect := range spell.Effects {
+					if _, exists := mobConfig.NPCs[effect.Summon]; effect.Summon != "" && !exists {
+						logger.Warning("Mob ability summons an unknown mob", "mob", mobID, "spell", ability.Spell, "summon", effect.Summon)
+					}
+				}
+			}
+		}
+	}
+
+	// Check that boss encounters script real bosses and call in real mobs
+	bossEncounters := towerManager.GetEncounters()
+	if mobConfig != nil {
+		for bossID, enc := range bossEncounters {
+			if def, exists := mobConfig.NPCs[bossID]; !exists || !def.Boss {
+				logger.Warning("Boss encounter scripts a mob that isn't a boss", "boss", bossID)
+			}
+			for _, phase := range enc.Phases {
+				for _, wave := range phase.Adds {
+					if _, exists := mobConfig.NPCs[wave.Mob]; !exists {
+						logger.Warning("Boss encounter calls in an unknown mob", "boss", bossID, "mob", wave.Mob)
+					}
+				}
+			}
+		}
+	}
+	logger.Info("Boss encounters loaded", "count", len(bossEncounters))
+
 	// Load recipes config
 	recipeRegistry := crafting.NewRecipeRegistry()
 	if err := recipeRegistry.LoadFromYAML(serverCfg.Paths.Recipes); err != nil {
@@ -227,10 +264,12 @@ func main() {
 	addr := fmt.Sprintf(":%d", *port)
 	srv := server.NewServer(addr, gameWorld, *pilgrimMode)
 
-	// Set database, items config, spell registry, recipe registry, and quest registry on server
+	// Set database, items config, spell registry, mob config, recipe registry, and quest registry on server
 	srv.SetDatabase(db)
 	srv.SetItemsConfig(itemsConfig)
 	srv.SetSpellRegistry(spellRegistry)
+	srv.SetMobConfig(mobConfig)
+	srv.SetBossEncounters(bossEncounters)
 	srv.SetRecipeRegistry(recipeRegistry)
 	srv.SetQuestRegistry(questRegistry)
 
@@ -273,6 +312,15 @@ func main() {
 		}
 	}
 
+	// Load chat channels
+	channelRegistry := channels.NewRegistry()
+	if err := channelRegistry.LoadFromYAML(serverCfg.Paths.Channels); err != nil {
+		logger.Warning("Failed to load channels config, chat channels disabled", "path", serverCfg.Paths.Channels, "error", err)
+	} else {
+		srv.SetChannelRegistry(channelRegistry)
+		logger.Info("Chat channels loaded", "count", channelRegistry.Count())
+	}
+
 	// Load and set name filter
 	nameCfg, err := namefilter.LoadConfig(serverCfg.Paths.NameFilter)
 	if err != nil {
@@ -304,13 +352,32 @@ func main() {
 		}
 	}()
 
-	logger.Info("MUD Server running", "telnet_port", *port, "websocket_port", *wsPort)
+	// Start TLS telnet server in a goroutine (if enabled)
+	if serverCfg.TLS.Enabled {
+		tlsAddr := fmt.Sprintf(":%d", serverCfg.TLS.Port)
+		go func() {
+			if err := srv.StartTLS(tlsAddr, serverCfg.TLS.CertFile, serverCfg.TLS.KeyFile); err != nil {
+				log.Fatalf("TLS telnet server error: %v", err)
+			}
+		}()
+		logger.Info("MUD Server running", "telnet_port", *port, "tls_port", serverCfg.TLS.Port, "websocket_port", *wsPort)
+	} else {
+		logger.Info("MUD Server running", "telnet_port", *port, "websocket_port", *wsPort)
+	}
 	logger.Info("Press Ctrl+C to shutdown")
 
-	// Wait for interrupt signal
+	// Wait for interrupt signal. SIGHUP reloads the TLS certificate instead
+	// (e.g., after certbot renews it).
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	<-sigChan
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	for sig := range sigChan {
+		if sig != syscall.SIGHUP {
+			break
+		}
+		if err := srv.ReloadTLSCertificate(); err != nil {
+			logger.Error("Failed to reload TLS certificate, keeping the current one", "error", err)
+		}
+	}
 
 	logger.Info("Shutting down server")
 	srv.Shutdown()
@@ -0,0 +1,159 @@
+// Package auction provides the auction house: item listings with bids and
+// buyouts that stay up while the seller is offline.
+package auction
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HouseName is the sender name on mail from the auction house.
+const HouseName = "Auction House"
+
+// Auction house constants.
+const (
+	MaxListingsPerPlayer   = 20             // Active listings a seller may have
+	MaxSearchResults       = 25             // Listings shown per search
+	DefaultDuration        = 24 * time.Hour // Listing duration when none is given
+	MinDuration            = 2 * time.Hour
+	MaxDuration            = 72 * time.Hour
+	HouseCutPercent        = 5 // Share of the sale price the house keeps
+	MinBidIncrementPercent = 5 // A new bid must beat the current one by this much
+)
+
+// Errors returned by auction house operations.
+var (
+	ErrNotFound     = errors.New("auction not found")
+	ErrOwnAuction   = errors.New("can't bid on own auction")
+	ErrNoBidding    = errors.New("auction is buyout only")
+	ErrBidTooLow    = errors.New("bid too low")
+	ErrHasBids      = errors.New("auction has bids")
+	ErrNotSeller    = errors.New("not the seller")
+	ErrHighBidder   = errors.New("already the high bidder")
+	ErrInvalidPrice = errors.New("invalid price")
+)
+
+// Listing is an item up for auction.
+type Listing struct {
+	ID         int64
+	SellerID   int64
+	SellerName string
+	ItemID     string // References items.yaml
+	ItemName   string
+	ItemType   string // Copied from the item so searches don't need items.yaml
+	ItemSlot   string
+	ItemTier   int
+	MinBid     int // Starting bid (0 = buyout only)
+	Buyout     int
+	CurrentBid int // Highest bid so far (0 = no bids)
+	BidderID   int64
+	BidderName string
+	ListedAt   time.Time
+	ExpiresAt  time.Time
+}
+
+// AcceptsBids returns true if the listing can be bid on, not just bought out.
+func (l *Listing) AcceptsBids() bool {
+	return l.MinBid > 0
+}
+
+// HasBid returns true if someone has bid on the listing.
+func (l *Listing) HasBid() bool {
+	return l.BidderID != 0
+}
+
+// NextMinBid returns the smallest bid the listing will accept.
+func (l *Listing) NextMinBid() int {
+	if !l.HasBid() {
+		return l.MinBid
+	}
+	increment := l.CurrentBid * MinBidIncrementPercent / 100
+	if increment < 1 {
+		increment = 1
+	}
+	return l.CurrentBid + increment
+}
+
+// TimeLeft returns a short description of how long the listing has left.
+func (l *Listing) TimeLeft(now time.Time) string {
+	left := l.ExpiresAt.Sub(now)
+	switch {
+	case left <= 0:
+		return "ending"
+	case left < time.Hour:
+		return fmt.Sprintf("%dm", int(left.Minutes())+1)
+	default:
+		return fmt.Sprintf("%dh", int(left.Hours()))
+	}
+}
+
+// SaleProceeds returns what the seller receives for a sale after the house cut.
+func SaleProceeds(price int) int {
+	return price - price*HouseCutPercent/100
+}
+
+// SearchFilter narrows an auction search. Zero values match everything.
+type SearchFilter struct {
+	Name     string // Substring of the item name
+	ItemType string
+	Slot     string
+	Tier     int
+	SellerID int64
+	BidderID int64
+}
+
+// ParseSearch builds a filter from search words. Words of the form type:<t>,
+// slot:<s> and tier:<n> set those filters; the rest match the item name.
+func ParseSearch(words []string) (SearchFilter, error) {
+	var f SearchFilter
+	var name []string
+	for _, word := range words {
+		key, value, found := strings.Cut(strings.ToLower(word), ":")
+		if !found {
+			name = append(name, word)
+			continue
+		}
+		switch key {
+		case "type":
+			f.ItemType = value
+		case "slot":
+			f.Slot = value
+		case "tier":
+			tier, err := strconv.Atoi(value)
+			if err != nil || tier < 1 {
+				return f, fmt.Errorf("invalid tier '%s'", value)
+			}
+			f.Tier = tier
+		default:
+			return f, fmt.Errorf("unknown filter '%s'", key)
+		}
+	}
+	f.Name = strings.Join(name, " ")
+	return f, nil
+}
+
+// ParseDuration parses a listing duration such as "12h" or "2d".
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.ToLower(s)
+	var unit time.Duration
+	switch {
+	case strings.HasSuffix(s, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(s, "h"):
+		unit = time.Hour
+	default:
+		return 0, fmt.Errorf("invalid duration '%s'", s)
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid duration '%s'", s)
+	}
+	d := time.Duration(n) * unit
+	if d < MinDuration || d > MaxDuration {
+		return 0, fmt.Errorf("duration must be between %dh and %dh", int(MinDuration.Hours()), int(MaxDuration.Hours()))
+	}
+	return d, nil
+}
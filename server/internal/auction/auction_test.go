@@ -0,0 +1,91 @@
+package auction
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextMinBid(t *testing.T) {
+	tests := []struct {
+		name     string
+		listing  Listing
+		expected int
+	}{
+		{
+			name:     "no bids",
+			listing:  Listing{MinBid: 50},
+			expected: 50,
+		},
+		{
+			name:     "raises by the increment",
+			listing:  Listing{MinBid: 50, CurrentBid: 200, BidderID: 1},
+			expected: 210,
+		},
+		{
+			name:     "raises by at least one",
+			listing:  Listing{MinBid: 5, CurrentBid: 10, BidderID: 1},
+			expected: 11,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.listing.NextMinBid(); got != tt.expected {
+				t.Errorf("NextMinBid() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSaleProceeds(t *testing.T) {
+	if got := SaleProceeds(200); got != 190 {
+		t.Errorf("SaleProceeds(200) = %d, want 190", got)
+	}
+	if got := SaleProceeds(10); got != 10 {
+		t.Errorf("SaleProceeds(10) = %d, want 10 (cut rounds down)", got)
+	}
+}
+
+func TestParseSearch(t *testing.T) {
+	f, err := ParseSearch([]string{"Long", "tier:3", "Sword", "TYPE:weapon", "slot:weapon"})
+	if err != nil {
+		t.Fatalf("ParseSearch failed: %v", err)
+	}
+	if f.Name != "Long Sword" || f.Tier != 3 || f.ItemType != "weapon" || f.Slot != "weapon" {
+		t.Errorf("Unexpected filter: %+v", f)
+	}
+
+	if _, err := ParseSearch([]string{"tier:gold"}); err == nil {
+		t.Error("Expected an invalid tier to fail")
+	}
+	if _, err := ParseSearch([]string{"color:red"}); err == nil {
+		t.Error("Expected an unknown filter to fail")
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"12h", 12 * time.Hour, false},
+		{"2D", 48 * time.Hour, false},
+		{"1h", 0, true},
+		{"4d", 0, true},
+		{"12", 0, true},
+		{"xh", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDuration(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDuration(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseDuration(%q) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
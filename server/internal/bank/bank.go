@@ -0,0 +1,50 @@
+// Package bank provides the account vault: gold and item storage shared by
+// every character on an account, reached through banker NPCs.
+package bank
+
+import "errors"
+
+// Vault constants.
+const (
+	BaseSlots         = 20  // Item slots every vault starts with
+	SlotsPerExpansion = 10  // Slots added by each expansion
+	MaxSlots          = 100 // Slots a vault can be expanded to
+	BaseExpansionCost = 500 // Gold for the first expansion; each one after costs more
+)
+
+// Errors returned by vault operations.
+var (
+	ErrVaultFull        = errors.New("vault is full")
+	ErrInsufficientGold = errors.New("not enough gold in vault")
+	ErrMaxSlots         = errors.New("vault is fully expanded")
+)
+
+// Vault is an account's bank vault.
+type Vault struct {
+	AccountID int64
+	Gold      int
+	Slots     int // Item slots available
+}
+
+// Item is an item stored in a vault.
+type Item struct {
+	ID          int64
+	AccountID   int64
+	ItemID      string // References items.yaml
+	DepositedBy string // Character that stored it
+}
+
+// CanExpand returns true if the vault can take another expansion.
+func (v *Vault) CanExpand() bool {
+	return v.Slots+SlotsPerExpansion <= MaxSlots
+}
+
+// ExpansionCost returns the gold the next expansion costs. Each expansion
+// costs the base price times the number of expansions bought so far plus one.
+func (v *Vault) ExpansionCost() int {
+	expansions := (v.Slots - BaseSlots) / SlotsPerExpansion
+	if expansions < 0 {
+		expansions = 0
+	}
+	return BaseExpansionCost * (expansions + 1)
+}
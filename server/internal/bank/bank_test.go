@@ -0,0 +1,27 @@
+package bank
+
+import "testing"
+
+func TestVaultExpansion(t *testing.T) {
+	tests := []struct {
+		slots     int
+		canExpand bool
+		cost      int
+	}{
+		{BaseSlots, true, BaseExpansionCost},
+		{BaseSlots + SlotsPerExpansion, true, BaseExpansionCost * 2},
+		{BaseSlots + 3*SlotsPerExpansion, true, BaseExpansionCost * 4},
+		{MaxSlots - SlotsPerExpansion, true, BaseExpansionCost * 8},
+		{MaxSlots, false, BaseExpansionCost * 9},
+	}
+
+	for _, tt := range tests {
+		v := &Vault{Slots: tt.slots}
+		if got := v.CanExpand(); got != tt.canExpand {
+			t.Errorf("slots %d: CanExpand() = %v, want %v", tt.slots, got, tt.canExpand)
+		}
+		if got := v.ExpansionCost(); got != tt.cost {
+			t.Errorf("slots %d: ExpansionCost() = %d, want %d", tt.slots, got, tt.cost)
+		}
+	}
+}
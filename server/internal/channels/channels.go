@@ -0,0 +1,171 @@
+// Package channels provides data-driven chat channels loaded from channels.yaml.
+package channels
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultHistorySize is how many messages a channel keeps when channels.yaml doesn't say.
+const DefaultHistorySize = 20
+
+// ChannelDefinition represents a channel definition from the YAML file.
+type ChannelDefinition struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Aliases     []string `yaml:"aliases,omitempty"`   // Extra command words for talking on the channel
+	Default     bool     `yaml:"default,omitempty"`   // Joined automatically by everyone allowed on it
+	Races       []string `yaml:"races,omitempty"`     // Race IDs allowed on the channel (empty = all)
+	MinLevel    int      `yaml:"min_level,omitempty"` // Minimum level to join
+	History     int      `yaml:"history,omitempty"`   // Messages kept for 'channel history'
+}
+
+// ChannelsConfig represents the structure of the channels.yaml file.
+type ChannelsConfig struct {
+	Channels map[string]ChannelDefinition `yaml:"channels"`
+}
+
+// Channel is a chat channel players can join, leave and talk on.
+type Channel struct {
+	ID          string
+	Name        string
+	Description string
+	Aliases     []string
+	Default     bool
+	Races       []string
+	MinLevel    int
+	HistorySize int
+}
+
+// Allows returns true if a player of the given race and level may use the channel.
+func (c *Channel) Allows(raceID string, level int) bool {
+	if level < c.MinLevel {
+		return false
+	}
+	if len(c.Races) == 0 {
+		return true
+	}
+	for _, r := range c.Races {
+		if strings.EqualFold(r, raceID) {
+			return true
+		}
+	}
+	return false
+}
+
+// Restriction describes who may use the channel, or "" if anyone can.
+func (c *Channel) Restriction() string {
+	var parts []string
+	if len(c.Races) > 0 {
+		parts = append(parts, strings.Join(c.Races, "/")+" only")
+	}
+	if c.MinLevel > 1 {
+		parts = append(parts, fmt.Sprintf("level %d+", c.MinLevel))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Registry holds all loaded channels and provides lookup.
+type Registry struct {
+	channels map[string]*Channel
+	commands map[string]*Channel // channel ID or alias -> channel
+}
+
+// NewRegistry creates a new empty channel registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		channels: make(map[string]*Channel),
+		commands: make(map[string]*Channel),
+	}
+}
+
+// LoadChannelsFromYAML loads channel definitions from a YAML file.
+func LoadChannelsFromYAML(filename string) (*ChannelsConfig, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read channels file: %w", err)
+	}
+
+	var config ChannelsConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse channels YAML: %w", err)
+	}
+
+	return &config, nil
+}
+
+// LoadFromYAML loads channels from a YAML file into the registry.
+func (r *Registry) LoadFromYAML(filename string) error {
+	config, err := LoadChannelsFromYAML(filename)
+	if err != nil {
+		return err
+	}
+	return r.LoadFromConfig(config)
+}
+
+// LoadFromConfig adds the configured channels to the registry.
+// Returns an error if two channels claim the same command word.
+func (r *Registry) LoadFromConfig(config *ChannelsConfig) error {
+	for id, def := range config.Channels {
+		id = strings.ToLower(id)
+		ch := &Channel{
+			ID:          id,
+			Name:        def.Name,
+			Description: def.Description,
+			Aliases:     def.Aliases,
+			Default:     def.Default,
+			Races:       def.Races,
+			MinLevel:    def.MinLevel,
+			HistorySize: def.History,
+		}
+		if ch.Name == "" {
+			ch.Name = id
+		}
+		if ch.HistorySize <= 0 {
+			ch.HistorySize = DefaultHistorySize
+		}
+
+		r.channels[id] = ch
+		for _, word := range append([]string{id}, def.Aliases...) {
+			word = strings.ToLower(word)
+			if other, exists := r.commands[word]; exists && other != ch {
+				return fmt.Errorf("channel %s: '%s' is already used by channel %s", id, word, other.ID)
+			}
+			r.commands[word] = ch
+		}
+	}
+	return nil
+}
+
+// Get returns a channel by its ID.
+func (r *Registry) Get(id string) (*Channel, bool) {
+	ch, exists := r.channels[strings.ToLower(id)]
+	return ch, exists
+}
+
+// FindByCommand returns the channel a command word (ID or alias) talks on.
+func (r *Registry) FindByCommand(word string) (*Channel, bool) {
+	ch, exists := r.commands[strings.ToLower(word)]
+	return ch, exists
+}
+
+// All returns every channel, sorted by ID.
+func (r *Registry) All() []*Channel {
+	result := make([]*Channel, 0, len(r.channels))
+	for _, ch := range r.channels {
+		result = append(result, ch)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// Count returns the number of channels in the registry.
+func (r *Registry) Count() int {
+	return len(r.channels)
+}
@@ -0,0 +1,160 @@
+package channels
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testRegistry(t *testing.T) *Registry {
+	t.Helper()
+	r := NewRegistry()
+	err := r.LoadFromConfig(&ChannelsConfig{Channels: map[string]ChannelDefinition{
+		"newbie": {Name: "Newbie", Aliases: []string{"nb"}, Default: true},
+		"lfg":    {Name: "LFG", MinLevel: 3},
+		"elvish": {Name: "Elvish", Races: []string{"elf"}, Default: true, History: 5},
+	}})
+	if err != nil {
+		t.Fatalf("LoadFromConfig failed: %v", err)
+	}
+	return r
+}
+
+func TestRegistry_Lookup(t *testing.T) {
+	r := testRegistry(t)
+
+	tests := []struct {
+		word     string
+		expected string
+	}{
+		{"newbie", "newbie"},
+		{"NB", "newbie"},
+		{"elvish", "elvish"},
+		{"say", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			ch, ok := r.FindByCommand(tt.word)
+			if tt.expected == "" {
+				if ok {
+					t.Errorf("FindByCommand(%s) = %s, want no channel", tt.word, ch.ID)
+				}
+				return
+			}
+			if !ok || ch.ID != tt.expected {
+				t.Errorf("FindByCommand(%s) = %v, want %s", tt.word, ch, tt.expected)
+			}
+		})
+	}
+
+	if ch, _ := r.Get("lfg"); ch.HistorySize != DefaultHistorySize {
+		t.Errorf("Expected default history size %d, got %d", DefaultHistorySize, ch.HistorySize)
+	}
+	if all := r.All(); len(all) != 3 || all[0].ID != "elvish" {
+		t.Errorf("Expected 3 channels sorted by ID, got %v", all)
+	}
+}
+
+func TestRegistry_AliasClash(t *testing.T) {
+	r := NewRegistry()
+	err := r.LoadFromConfig(&ChannelsConfig{Channels: map[string]ChannelDefinition{
+		"newbie": {Aliases: []string{"ooc"}},
+		"ooc":    {},
+	}})
+	if err == nil {
+		t.Error("Expected an error when an alias clashes with another channel")
+	}
+}
+
+func TestChannel_Allows(t *testing.T) {
+	r := testRegistry(t)
+	lfg, _ := r.Get("lfg")
+	elvish, _ := r.Get("elvish")
+
+	tests := []struct {
+		name     string
+		ch       *Channel
+		race     string
+		level    int
+		expected bool
+	}{
+		{"lfg below min level", lfg, "human", 2, false},
+		{"lfg at min level", lfg, "human", 3, true},
+		{"elvish for an elf", elvish, "elf", 1, true},
+		{"elvish for a dwarf", elvish, "dwarf", 10, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ch.Allows(tt.race, tt.level); got != tt.expected {
+				t.Errorf("Allows(%s, %d) = %v, want %v", tt.race, tt.level, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPreferences_JoinLeaveMute(t *testing.T) {
+	r := testRegistry(t)
+	newbie, _ := r.Get("newbie")
+	lfg, _ := r.Get("lfg")
+
+	var prefs Preferences
+	if !prefs.IsMember(newbie) || prefs.IsMember(lfg) {
+		t.Fatal("Expected default channels joined and others not")
+	}
+	if prefs.ToJSON() != "" {
+		t.Errorf("Expected defaults to encode as empty, got %q", prefs.ToJSON())
+	}
+
+	prefs.Leave(newbie)
+	prefs.Join(lfg)
+	prefs.SetMuted(lfg, true)
+
+	restored, err := ParsePreferences(prefs.ToJSON())
+	if err != nil {
+		t.Fatalf("ParsePreferences failed: %v", err)
+	}
+	if restored.IsMember(newbie) {
+		t.Error("Expected newbie to stay left after a round trip")
+	}
+	if !restored.IsMember(lfg) || !restored.IsMuted(lfg) {
+		t.Error("Expected lfg to stay joined and muted after a round trip")
+	}
+
+	restored.Join(newbie)
+	restored.Leave(lfg)
+	if !restored.IsMember(newbie) || restored.IsMember(lfg) || restored.IsMuted(lfg) {
+		t.Error("Expected rejoin and leave (which clears the mute) to apply")
+	}
+	if restored.ToJSON() != "" {
+		t.Errorf("Expected preferences back at defaults, got %q", restored.ToJSON())
+	}
+}
+
+func TestHistory_KeepsMostRecent(t *testing.T) {
+	h := NewHistory(3)
+	for _, text := range []string{"one", "two", "three", "four"} {
+		h.Add(Message{Sender: "Alice", Text: text})
+	}
+
+	recent := h.Recent()
+	if len(recent) != 3 || recent[0].Text != "two" || recent[2].Text != "four" {
+		t.Errorf("Expected [two three four], got %v", recent)
+	}
+}
+
+func TestLoadFromYAML_DataFile(t *testing.T) {
+	path := filepath.Join("..", "..", "data", "channels.yaml")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		t.Skip("channels.yaml not found")
+	}
+
+	r := NewRegistry()
+	if err := r.LoadFromYAML(path); err != nil {
+		t.Fatalf("Failed to load channels.yaml: %v", err)
+	}
+	if _, ok := r.Get("newbie"); !ok {
+		t.Error("Expected a newbie channel")
+	}
+}
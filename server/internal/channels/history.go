@@ -0,0 +1,53 @@
+package channels
+
+import (
+	"sync"
+	"time"
+)
+
+// Message is a line said on a channel.
+type Message struct {
+	Sender string
+	Text   string
+	Time   time.Time
+}
+
+// History keeps the most recent messages said on a channel.
+type History struct {
+	mu       sync.Mutex
+	size     int
+	messages []Message
+}
+
+// NewHistory creates a history that keeps up to size messages.
+func NewHistory(size int) *History {
+	if size <= 0 {
+		size = DefaultHistorySize
+	}
+	return &History{
+		size:     size,
+		messages: make([]Message, 0, size),
+	}
+}
+
+// Add records a message, dropping the oldest once the history is full.
+func (h *History) Add(msg Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.messages) == h.size {
+		copy(h.messages, h.messages[1:])
+		h.messages = h.messages[:h.size-1]
+	}
+	h.messages = append(h.messages, msg)
+}
+
+// Recent returns the recorded messages, oldest first.
+func (h *History) Recent() []Message {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	result := make([]Message, len(h.messages))
+	copy(result, h.messages)
+	return result
+}
@@ -0,0 +1,100 @@
+package channels
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Preferences are a player's channel settings, persisted with the character.
+// Only changes from the defaults are stored, so channels added to channels.yaml
+// later are picked up by existing characters.
+type Preferences struct {
+	Joined []string `json:"joined,omitempty"` // Non-default channels the player joined
+	Left   []string `json:"left,omitempty"`   // Default channels the player left
+	Muted  []string `json:"muted,omitempty"`  // Channels the player stays on but doesn't hear
+}
+
+// ParsePreferences decodes preferences saved with ToJSON.
+// Empty input yields the defaults (on every default channel, nothing muted).
+func ParsePreferences(data string) (Preferences, error) {
+	var prefs Preferences
+	if data == "" || data == "{}" {
+		return prefs, nil
+	}
+	if err := json.Unmarshal([]byte(data), &prefs); err != nil {
+		return Preferences{}, fmt.Errorf("failed to parse channel preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// ToJSON encodes the preferences for storage. Defaults encode as "".
+func (p Preferences) ToJSON() string {
+	if len(p.Joined) == 0 && len(p.Left) == 0 && len(p.Muted) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// IsMember returns true if the player is on the channel.
+func (p *Preferences) IsMember(ch *Channel) bool {
+	if ch.Default {
+		return !contains(p.Left, ch.ID)
+	}
+	return contains(p.Joined, ch.ID)
+}
+
+// IsMuted returns true if the player has muted the channel.
+func (p *Preferences) IsMuted(ch *Channel) bool {
+	return contains(p.Muted, ch.ID)
+}
+
+// Join puts the player on the channel.
+func (p *Preferences) Join(ch *Channel) {
+	p.Left = remove(p.Left, ch.ID)
+	if !ch.Default && !contains(p.Joined, ch.ID) {
+		p.Joined = append(p.Joined, ch.ID)
+	}
+}
+
+// Leave takes the player off the channel. Leaving also clears a mute.
+func (p *Preferences) Leave(ch *Channel) {
+	p.Joined = remove(p.Joined, ch.ID)
+	p.Muted = remove(p.Muted, ch.ID)
+	if ch.Default && !contains(p.Left, ch.ID) {
+		p.Left = append(p.Left, ch.ID)
+	}
+}
+
+// SetMuted mutes or unmutes the channel.
+func (p *Preferences) SetMuted(ch *Channel, muted bool) {
+	p.Muted = remove(p.Muted, ch.ID)
+	if muted {
+		p.Muted = append(p.Muted, ch.ID)
+	}
+}
+
+func contains(ids []string, id string) bool {
+	for _, existing := range ids {
+		if existing == id {
+			return true
+		}
+	}
+	return false
+}
+
+func remove(ids []string, id string) []string {
+	result := ids[:0]
+	for _, existing := range ids {
+		if existing != id {
+			result = append(result, existing)
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
@@ -0,0 +1,308 @@
+// Package color implements the {category} markup used in game output and renders
+// it for each kind of client: ANSI or xterm-256 escape codes for telnet, HTML spans
+// with CSS classes for structured WebSocket clients, or plain text.
+//
+// Markup wraps text in a category tag and closes it with {/}:
+//
+//	"{npc}goblin{/} hits you for {damage}5{/} damage!"
+//
+// Only known categories are treated as markup; any other braces are left alone.
+// A literal "{" can be written as "{{" (see Escape).
+package color
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Mode selects how markup is rendered.
+type Mode int
+
+const (
+	ModeNone Mode = iota // Strip markup
+	ModeANSI             // 16-color ANSI escape codes
+	Mode256              // xterm-256 escape codes
+	ModeHTML             // <span class="c-category"> with HTML-escaped text
+)
+
+// Markup categories.
+const (
+	Room    = "room"
+	Exits   = "exits"
+	NPC     = "npc"
+	Player  = "player"
+	Item    = "item"
+	Damage  = "damage"
+	Heal    = "heal"
+	Say     = "say"
+	Tell    = "tell"
+	Shout   = "shout"
+	Group   = "group"
+	Guild   = "guild"
+	Channel = "channel"
+	Gold    = "gold"
+	System  = "system"
+	Warning = "warning"
+)
+
+// Color is a terminal color with a 16-color ANSI form and an xterm-256 form.
+type Color struct {
+	ANSI  string // SGR parameters, e.g. "1;31"
+	XTerm int    // xterm-256 palette index
+}
+
+// categoryInfo describes a category's default color.
+type categoryInfo struct {
+	description string
+	color       Color
+}
+
+// categories holds the built-in categories and their default colors.
+var categories = map[string]categoryInfo{
+	Room:    {"room names", Color{"1;36", 87}},
+	Exits:   {"exits", Color{"32", 71}},
+	NPC:     {"creatures and NPCs", Color{"33", 214}},
+	Player:  {"player names", Color{"1;37", 231}},
+	Item:    {"items", Color{"36", 44}},
+	Damage:  {"damage dealt and taken", Color{"1;31", 196}},
+	Heal:    {"healing", Color{"1;32", 46}},
+	Say:     {"room speech", Color{"37", 252}},
+	Tell:    {"private tells", Color{"35", 177}},
+	Shout:   {"shouts", Color{"1;33", 226}},
+	Group:   {"group chat", Color{"1;35", 213}},
+	Guild:   {"guild chat", Color{"1;32", 120}},
+	Channel: {"chat channels", Color{"34", 111}},
+	Gold:    {"gold amounts", Color{"33", 220}},
+	System:  {"system and level-up messages", Color{"1;34", 75}},
+	Warning: {"warnings and death", Color{"31", 160}},
+}
+
+// namedColors are the color names players can use when remapping categories.
+var namedColors = map[string]Color{
+	"black":          {"30", 0},
+	"red":            {"31", 1},
+	"green":          {"32", 2},
+	"yellow":         {"33", 3},
+	"blue":           {"34", 4},
+	"magenta":        {"35", 5},
+	"cyan":           {"36", 6},
+	"white":          {"37", 7},
+	"gray":           {"1;30", 8},
+	"bright-red":     {"1;31", 9},
+	"bright-green":   {"1;32", 10},
+	"bright-yellow":  {"1;33", 11},
+	"bright-blue":    {"1;34", 12},
+	"bright-magenta": {"1;35", 13},
+	"bright-cyan":    {"1;36", 14},
+	"bright-white":   {"1;37", 15},
+}
+
+const ansiReset = "\x1b[0m"
+
+var (
+	ansiEscapePattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+	htmlTagPattern    = regexp.MustCompile(`<[^>]*>`)
+)
+
+// Categories returns the names of all markup categories, sorted.
+func Categories() []string {
+	names := make([]string, 0, len(categories))
+	for name := range categories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsCategory returns true if name is a known markup category.
+func IsCategory(name string) bool {
+	_, ok := categories[name]
+	return ok
+}
+
+// Describe returns a short description of a category.
+func Describe(category string) string {
+	return categories[category].description
+}
+
+// ColorNames returns the named colors available for remapping, sorted.
+func ColorNames() []string {
+	names := make([]string, 0, len(namedColors))
+	for name := range namedColors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseColor resolves a color name or an xterm-256 index ("0"-"255").
+// Numeric colors outside the basic 16 have no ANSI form; 16-color clients
+// fall back to the category default for them.
+func ParseColor(name string) (Color, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if c, ok := namedColors[name]; ok {
+		return c, true
+	}
+	n, err := strconv.Atoi(name)
+	if err != nil || n < 0 || n > 255 {
+		return Color{}, false
+	}
+	for _, c := range namedColors {
+		if c.XTerm == n {
+			return c, true
+		}
+	}
+	return Color{XTerm: n}, true
+}
+
+// Wrap surrounds text with markup for a category.
+func Wrap(category, text string) string {
+	return "{" + category + "}" + text + "{/}"
+}
+
+// Escape makes user-supplied text safe to embed in marked-up output,
+// so players can't inject color tags into chat.
+func Escape(text string) string {
+	return strings.ReplaceAll(text, "{", "{{")
+}
+
+// Strip removes all markup, leaving plain text.
+func Strip(text string) string {
+	return Render(text, ModeNone, nil)
+}
+
+// StripRendered removes the colors from output that was already rendered for mode,
+// leaving plain text. Used when rendered output has to be passed on to a client
+// with a different color mode.
+func StripRendered(text string, mode Mode) string {
+	switch mode {
+	case ModeANSI, Mode256:
+		return ansiEscapePattern.ReplaceAllString(text, "")
+	case ModeHTML:
+		return html.UnescapeString(htmlTagPattern.ReplaceAllString(text, ""))
+	}
+	return text
+}
+
+// Render converts markup for the given mode. theme optionally remaps categories
+// to color names (see ParseColor); invalid entries use the category default.
+func Render(text string, mode Mode, theme map[string]string) string {
+	if !strings.Contains(text, "{") {
+		if mode == ModeHTML {
+			return html.EscapeString(text)
+		}
+		return text
+	}
+
+	var sb strings.Builder
+	var stack []string // open categories, innermost last
+	plain := 0         // start of pending plain text
+
+	flush := func(end int) {
+		if mode == ModeHTML {
+			sb.WriteString(html.EscapeString(text[plain:end]))
+		} else {
+			sb.WriteString(text[plain:end])
+		}
+	}
+
+	for i := 0; i < len(text); i++ {
+		if text[i] != '{' {
+			continue
+		}
+
+		// "{{" is a literal brace
+		if i+1 < len(text) && text[i+1] == '{' {
+			flush(i + 1)
+			i++
+			plain = i + 1
+			continue
+		}
+
+		end := strings.IndexByte(text[i:], '}')
+		if end == -1 {
+			break
+		}
+		tag := text[i+1 : i+end]
+
+		switch {
+		case tag == "/":
+			flush(i)
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+				sb.WriteString(closeTag(mode, stack, theme))
+			}
+		case IsCategory(tag):
+			flush(i)
+			stack = append(stack, tag)
+			sb.WriteString(openTag(mode, tag, theme))
+		default:
+			// Not markup - leave it in the text
+			continue
+		}
+
+		i += end
+		plain = i + 1
+	}
+	flush(len(text))
+
+	// Close anything left open so color doesn't bleed into later output
+	for len(stack) > 0 {
+		stack = stack[:len(stack)-1]
+		sb.WriteString(closeTag(mode, stack, theme))
+	}
+
+	return sb.String()
+}
+
+// openTag returns the output that starts a category.
+func openTag(mode Mode, category string, theme map[string]string) string {
+	switch mode {
+	case ModeANSI, Mode256:
+		return escapeCode(mode, category, theme)
+	case ModeHTML:
+		class := "c-" + category
+		if name, ok := theme[category]; ok {
+			if _, valid := ParseColor(name); valid {
+				class += " c-fg-" + strings.ToLower(name)
+			}
+		}
+		return `<span class="` + class + `">`
+	}
+	return ""
+}
+
+// closeTag returns the output that ends the innermost category,
+// restoring the enclosing category's color for terminals.
+func closeTag(mode Mode, stack []string, theme map[string]string) string {
+	switch mode {
+	case ModeANSI, Mode256:
+		if len(stack) == 0 {
+			return ansiReset
+		}
+		return ansiReset + escapeCode(mode, stack[len(stack)-1], theme)
+	case ModeHTML:
+		return "</span>"
+	}
+	return ""
+}
+
+// escapeCode returns the terminal escape sequence for a category's color.
+func escapeCode(mode Mode, category string, theme map[string]string) string {
+	c := categories[category].color
+	if name, ok := theme[category]; ok {
+		if custom, valid := ParseColor(name); valid {
+			if mode == Mode256 || custom.ANSI != "" {
+				c = custom
+			}
+		}
+	}
+
+	if mode == Mode256 {
+		return "\x1b[38;5;" + strconv.Itoa(c.XTerm) + "m"
+	}
+	return "\x1b[" + c.ANSI + "m"
+}
@@ -0,0 +1,113 @@
+package color
+
+import "testing"
+
+// TestRender_Modes tests that markup renders correctly for each client mode
+func TestRender_Modes(t *testing.T) {
+	text := "{npc}goblin{/} hits you for {damage}5{/} damage!"
+
+	tests := []struct {
+		mode     Mode
+		expected string
+	}{
+		{ModeNone, "goblin hits you for 5 damage!"},
+		{ModeANSI, "\x1b[33mgoblin\x1b[0m hits you for \x1b[1;31m5\x1b[0m damage!"},
+		{Mode256, "\x1b[38;5;214mgoblin\x1b[0m hits you for \x1b[38;5;196m5\x1b[0m damage!"},
+		{ModeHTML, `<span class="c-npc">goblin</span> hits you for <span class="c-damage">5</span> damage!`},
+	}
+
+	for _, tt := range tests {
+		if got := Render(text, tt.mode, nil); got != tt.expected {
+			t.Errorf("Render(mode %d) = %q, expected %q", tt.mode, got, tt.expected)
+		}
+	}
+}
+
+// TestRender_NestingAndLiterals tests nested tags, unknown braces, escaped braces and unclosed tags
+func TestRender_NestingAndLiterals(t *testing.T) {
+	// Closing an inner tag restores the outer color
+	got := Render("{tell}Bob says {item}sword{/}!{/}", ModeANSI, nil)
+	expected := "\x1b[35mBob says \x1b[36msword\x1b[0m\x1b[35m!\x1b[0m"
+	if got != expected {
+		t.Errorf("Nested render = %q, expected %q", got, expected)
+	}
+
+	if got := Strip("{unknown} and {{room} stay"); got != "{unknown} and {room} stay" {
+		t.Errorf("Expected unknown/escaped braces preserved, got %q", got)
+	}
+
+	if got := Render("{room}Hall", ModeANSI, nil); got != "\x1b[1;36mHall\x1b[0m" {
+		t.Errorf("Expected unclosed tag to be reset, got %q", got)
+	}
+
+	if got := Render("<b>{say}a & b{/}", ModeHTML, nil); got != `&lt;b&gt;<span class="c-say">a &amp; b</span>` {
+		t.Errorf("Expected HTML-escaped text, got %q", got)
+	}
+
+	if got := Strip(Escape("{damage}fake{/}")); got != "{damage}fake{/}" {
+		t.Errorf("Expected escaped markup to render literally, got %q", got)
+	}
+}
+
+// TestRender_Theme tests that a player's remapped colors are used
+func TestRender_Theme(t *testing.T) {
+	theme := map[string]string{NPC: "red", Damage: "202"}
+
+	if got := Render("{npc}x{/}", ModeANSI, theme); got != "\x1b[31mx\x1b[0m" {
+		t.Errorf("Expected remapped ANSI color, got %q", got)
+	}
+	// xterm-only colors fall back to the default on 16-color clients
+	if got := Render("{damage}x{/}", ModeANSI, theme); got != "\x1b[1;31mx\x1b[0m" {
+		t.Errorf("Expected default ANSI color for xterm-only remap, got %q", got)
+	}
+	if got := Render("{damage}x{/}", Mode256, theme); got != "\x1b[38;5;202mx\x1b[0m" {
+		t.Errorf("Expected remapped xterm color, got %q", got)
+	}
+	if got := Render("{npc}x{/}", ModeHTML, theme); got != `<span class="c-npc c-fg-red">x</span>` {
+		t.Errorf("Expected remap class in HTML, got %q", got)
+	}
+}
+
+// TestPreferences_RoundTrip tests remapping, validation, and JSON persistence
+func TestPreferences_RoundTrip(t *testing.T) {
+	var prefs Preferences
+	if prefs.ToJSON() != "" {
+		t.Errorf("Expected defaults to encode as empty string, got %q", prefs.ToJSON())
+	}
+
+	if err := prefs.Remap("dragon", "red"); err == nil {
+		t.Error("Expected error for unknown category")
+	}
+	if err := prefs.Remap("npc", "chartreuse"); err == nil {
+		t.Error("Expected error for unknown color")
+	}
+	if err := prefs.Remap("NPC", "Bright-Red"); err != nil {
+		t.Fatalf("Remap failed: %v", err)
+	}
+	prefs.Disabled = true
+
+	loaded, err := ParsePreferences(prefs.ToJSON())
+	if err != nil {
+		t.Fatalf("ParsePreferences failed: %v", err)
+	}
+	if !loaded.Disabled || loaded.Theme[NPC] != "bright-red" {
+		t.Errorf("Round trip lost data: %+v", loaded)
+	}
+
+	loaded.Reset("")
+	if len(loaded.Theme) != 0 {
+		t.Error("Expected Reset(\"\") to clear all remaps")
+	}
+}
+
+// TestStripRendered tests that rendered output converts back to the original plain text
+func TestStripRendered(t *testing.T) {
+	input := "{npc}goblin{/} hits you for {damage}5{/} <ouch> & more"
+	plain := Strip(input)
+
+	for _, mode := range []Mode{ModeNone, ModeANSI, Mode256, ModeHTML} {
+		if got := StripRendered(Render(input, mode, nil), mode); got != plain {
+			t.Errorf("Mode %d: expected %q, got %q", mode, plain, got)
+		}
+	}
+}
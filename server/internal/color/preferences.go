@@ -0,0 +1,69 @@
+package color
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Preferences are a player's color settings, persisted with the character.
+type Preferences struct {
+	Disabled bool              `json:"off,omitempty"`   // Strip all color
+	Theme    map[string]string `json:"theme,omitempty"` // category -> color name
+}
+
+// ParsePreferences decodes preferences saved with ToJSON.
+// Empty input yields the defaults (color on, no remapping).
+func ParsePreferences(data string) (Preferences, error) {
+	var prefs Preferences
+	if data == "" || data == "{}" {
+		return prefs, nil
+	}
+	if err := json.Unmarshal([]byte(data), &prefs); err != nil {
+		return Preferences{}, fmt.Errorf("failed to parse color preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// ToJSON encodes the preferences for storage. Defaults encode as "".
+func (p Preferences) ToJSON() string {
+	if !p.Disabled && len(p.Theme) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// Remap sets the color used for a category.
+func (p *Preferences) Remap(category, colorName string) error {
+	category = strings.ToLower(category)
+	colorName = strings.ToLower(colorName)
+	if !IsCategory(category) {
+		return fmt.Errorf("unknown color category '%s'", category)
+	}
+	if _, ok := ParseColor(colorName); !ok {
+		return fmt.Errorf("unknown color '%s'", colorName)
+	}
+	if p.Theme == nil {
+		p.Theme = make(map[string]string)
+	}
+	p.Theme[category] = colorName
+	return nil
+}
+
+// Reset restores a category's default color, or all categories if category is "".
+func (p *Preferences) Reset(category string) error {
+	if category == "" {
+		p.Theme = nil
+		return nil
+	}
+	category = strings.ToLower(category)
+	if !IsCategory(category) {
+		return fmt.Errorf("unknown color category '%s'", category)
+	}
+	delete(p.Theme, category)
+	return nil
+}
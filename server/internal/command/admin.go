@@ -2,6 +2,7 @@ package command
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/lawnchairsociety/opentowermud/server/internal/database"
@@ -44,6 +45,10 @@ func executeAdmin(c *Command, p PlayerInterface) string {
 		return executeAdminStats(c, p)
 	case "players":
 		return executeAdminPlayers(c, p)
+	case "snoop":
+		return executeAdminSnoop(c, p)
+	case "trades":
+		return executeAdminTrades(c, p)
 	default:
 		return fmt.Sprintf("Unknown admin command: %s. Type 'admin help' for commands.", subcommand)
 	}
@@ -61,6 +66,8 @@ Player Management:
   admin ban <player> [reason] - Ban a player's account
   admin unban <username>     - Unban an account by username
   admin kick <player> [reason] - Disconnect a player
+  admin snoop <player>       - See everything sent to a player
+  admin snoop off            - Stop snooping
 
 Communication:
   admin announce <message>   - Broadcast to all players
@@ -73,6 +80,7 @@ Teleportation:
 Information:
   admin stats               - Show server statistics
   admin players             - List all online players with details
+  admin trades <player>     - Show a player's recent trades
   admin help                - Show this help message
 `
 }
@@ -393,6 +401,48 @@ func executeAdminKick(c *Command, p PlayerInterface) string {
 	return fmt.Sprintf("%s has been kicked.", targetName)
 }
 
+// executeAdminSnoop mirrors a player's output to the admin, or stops snooping
+func executeAdminSnoop(c *Command, p PlayerInterface) string {
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return "Internal error: invalid server type"
+	}
+
+	if len(c.Args) < 2 {
+		if target, spectating := server.GetWatchTarget(p.GetName()); target != "" && !spectating {
+			return fmt.Sprintf("You are snooping %s. Use 'admin snoop off' to stop.", target)
+		}
+		return "Usage: admin snoop <player_name> | admin snoop off"
+	}
+
+	if strings.ToLower(c.Args[1]) == "off" {
+		if target, spectating := server.GetWatchTarget(p.GetName()); target == "" || spectating {
+			return "You aren't snooping anyone."
+		}
+		target := server.StopWatching(p.GetName())
+
+		logger.Always("ADMIN_ACTION",
+			"action", "snoop_off",
+			"admin", p.GetName(),
+			"target", target)
+
+		return fmt.Sprintf("You stop snooping %s.", target)
+	}
+
+	targetName := c.Args[1]
+	if err := server.StartSnoop(p.GetName(), targetName); err != nil {
+		return err.Error()
+	}
+
+	// Log admin action
+	logger.Always("ADMIN_ACTION",
+		"action", "snoop",
+		"admin", p.GetName(),
+		"target", targetName)
+
+	return fmt.Sprintf("You are now snooping %s. Their output is marked [snoop %s].", targetName, targetName)
+}
+
 // executeAdminAnnounce broadcasts a server-wide message
 func executeAdminAnnounce(c *Command, p PlayerInterface) string {
 	if len(c.Args) < 2 {
@@ -557,7 +607,7 @@ func executeAdminStats(c *Command, p PlayerInterface) string {
 	totalAccounts, _ := db.GetTotalAccounts()
 	totalCharacters, _ := db.GetTotalCharacters()
 
-	return fmt.Sprintf(`
+	result := fmt.Sprintf(`
 Server Statistics
 =================
 Uptime:           %d hours, %d minutes, %d seconds
@@ -571,6 +621,100 @@ Total Characters: %d
 		roomCount,
 		totalAccounts,
 		totalCharacters)
+
+	return result + formatTrafficStats(server.GetOnlinePlayersDetailed())
+}
+
+// adminTradeLogLimit is how many trades 'admin trades' shows.
+const adminTradeLogLimit = 20
+
+// executeAdminTrades shows a player's recent trades from the audit log
+func executeAdminTrades(c *Command, p PlayerInterface) string {
+	if len(c.Args) < 2 {
+		return "Usage: admin trades <player_name>"
+	}
+
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return "Internal error: invalid server type"
+	}
+
+	db, ok := server.GetDatabase().(*database.Database)
+	if !ok {
+		return "Internal error: database not available"
+	}
+
+	targetName := c.Args[1]
+	records, err := db.GetTradesForPlayer(targetName, adminTradeLogLimit)
+	if err != nil {
+		return fmt.Sprintf("Failed to read trade log: %v", err)
+	}
+	if len(records) == 0 {
+		return fmt.Sprintf("No trades recorded for %s.", targetName)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("\nRecent trades for %s\n", targetName))
+	sb.WriteString("====================\n")
+	for _, r := range records {
+		sb.WriteString(fmt.Sprintf("#%d  %s  in %s\n", r.ID, r.TradedAt.Format("2006-01-02 15:04"), r.RoomID))
+		sb.WriteString(fmt.Sprintf("  %s gave: %s\n", r.Player1, formatTradeSide(r.Player1Items, r.Player1Gold)))
+		sb.WriteString(fmt.Sprintf("  %s gave: %s\n", r.Player2, formatTradeSide(r.Player2Items, r.Player2Gold)))
+	}
+	return sb.String()
+}
+
+// formatTradeSide summarises what one side of a logged trade handed over.
+func formatTradeSide(itemIDs []string, gold int) string {
+	parts := append([]string(nil), itemIDs...)
+	if gold > 0 {
+		parts = append(parts, fmt.Sprintf("%d gold", gold))
+	}
+	if len(parts) == 0 {
+		return "nothing"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatTrafficStats formats per-connection output byte counters and MCCP savings.
+func formatTrafficStats(players []PlayerInfo) string {
+	if len(players) == 0 {
+		return ""
+	}
+
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].Name < players[j].Name
+	})
+
+	var sb strings.Builder
+	sb.WriteString("\nNetwork Traffic\n===============\n")
+	sb.WriteString(fmt.Sprintf("%-15s %-5s %12s %12s %7s\n", "Player", "MCCP", "Raw", "Sent", "Saved"))
+
+	var totalRaw, totalSent int64
+	compressedCount := 0
+	for _, info := range players {
+		mccp := "no"
+		if info.Compressed {
+			mccp = "yes"
+			compressedCount++
+		}
+		totalRaw += info.BytesRaw
+		totalSent += info.BytesSent
+		sb.WriteString(fmt.Sprintf("%-15s %-5s %12d %12d %6.1f%%\n",
+			info.Name, mccp, info.BytesRaw, info.BytesSent, savingsPercent(info.BytesRaw, info.BytesSent)))
+	}
+
+	sb.WriteString(fmt.Sprintf("%-15s %-5s %12d %12d %6.1f%%\n",
+		"Total", fmt.Sprintf("%d", compressedCount), totalRaw, totalSent, savingsPercent(totalRaw, totalSent)))
+	return sb.String()
+}
+
+// savingsPercent returns how much smaller sent is than raw, as a percentage.
+func savingsPercent(raw, sent int64) float64 {
+	if raw <= 0 {
+		return 0
+	}
+	return float64(raw-sent) / float64(raw) * 100
 }
 
 // executeAdminPlayers lists all online players with details
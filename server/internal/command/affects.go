@@ -0,0 +1,36 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/effects"
+)
+
+// executeAffects lists the status effects on the player and how long each has left
+func executeAffects(c *Command, p PlayerInterface) string {
+	list := p.Effects().List()
+	if len(list) == 0 {
+		return "You are not affected by anything."
+	}
+
+	var sb strings.Builder
+	sb.WriteString("You are affected by:\n")
+	for _, e := range list {
+		tag := "{heal}"
+		if e.Kind.Harmful() {
+			tag = "{warning}"
+		}
+		sb.WriteString(fmt.Sprintf("  %s%-20s{/} %-40s %s\n", tag, e.Name, e.Describe(), formatEffectTime(e)))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// formatEffectTime shows how long an effect has left, e.g. "(1m05s)"
+func formatEffectTime(e effects.Effect) string {
+	remaining := e.Remaining()
+	if remaining >= 60 {
+		return fmt.Sprintf("(%dm%02ds)", remaining/60, remaining%60)
+	}
+	return fmt.Sprintf("(%ds)", remaining)
+}
@@ -0,0 +1,191 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+)
+
+// maxAliasDepth is how deeply aliases may use other aliases. The limit also
+// stops aliases that expand into themselves.
+const maxAliasDepth = 5
+
+// maxAliasCommands is the most commands one alias may expand into.
+const maxAliasCommands = 20
+
+// The alias commands check the registry so aliases can't hide commands, so they
+// are registered here rather than in the map literal to avoid an initialization cycle.
+func init() {
+	commandRegistry["alias"] = executeAlias
+	commandRegistry["unalias"] = executeUnalias
+}
+
+// executeAlias lists, shows or defines the player's aliases
+func executeAlias(c *Command, p PlayerInterface) string {
+	if len(c.Args) == 0 {
+		return listAliases(p)
+	}
+
+	name := strings.ToLower(c.Args[0])
+	if len(c.Args) == 1 {
+		expansion, ok := p.GetAlias(name)
+		if !ok {
+			return fmt.Sprintf("You have no alias named '%s'.", name)
+		}
+		return fmt.Sprintf("%s = %s", name, expansion)
+	}
+
+	if _, builtin := commandRegistry[name]; builtin {
+		return fmt.Sprintf("'%s' is already a command and can't be used as an alias.", name)
+	}
+
+	expansion := strings.Join(c.Args[1:], " ")
+	_, replaced := p.GetAlias(name)
+	if err := p.SetAlias(name, expansion); err != nil {
+		return fmt.Sprintf("Can't set alias: %v.", err)
+	}
+	saveAliases(p)
+
+	if replaced {
+		return fmt.Sprintf("Alias '%s' changed to: %s", name, expansion)
+	}
+	return fmt.Sprintf("Alias '%s' set to: %s", name, expansion)
+}
+
+// executeUnalias removes one of the player's aliases
+func executeUnalias(c *Command, p PlayerInterface) string {
+	if err := c.RequireArgs(1, "Usage: unalias <name>"); err != nil {
+		return err.Error()
+	}
+
+	name := strings.ToLower(c.Args[0])
+	if !p.RemoveAlias(name) {
+		return fmt.Sprintf("You have no alias named '%s'.", name)
+	}
+	saveAliases(p)
+	return fmt.Sprintf("Alias '%s' removed.", name)
+}
+
+// listAliases shows all of the player's aliases, sorted by name
+func listAliases(p PlayerInterface) string {
+	aliases := p.GetAliases()
+	if len(aliases) == 0 {
+		return "You have no aliases. Type 'help alias' to learn how to make one."
+	}
+
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Your aliases (%d):\n", len(aliases)))
+	for _, name := range names {
+		result.WriteString(fmt.Sprintf("  %-12s %s\n", name, aliases[name]))
+	}
+	return result.String()
+}
+
+// saveAliases persists the player's aliases right away
+func saveAliases(p PlayerInterface) {
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return
+	}
+	if saveErr := server.SavePlayer(p); saveErr != nil {
+		logger.Warning("Failed to save player after alias change", "player", p.GetName(), "error", saveErr)
+	}
+}
+
+// executeAliasExpansion runs an alias: the first command it expands into runs
+// now and the rest are queued to run next.
+func (c *Command) executeAliasExpansion(expansion string, p PlayerInterface, worldIface interface{}) string {
+	commands, err := expandAlias(expansion, c.Args, p, 1)
+	if err != nil {
+		return fmt.Sprintf("Alias '%s' %v.", c.Name, err)
+	}
+	if len(commands) == 0 {
+		return fmt.Sprintf("Alias '%s' doesn't expand into any commands.", c.Name)
+	}
+
+	p.QueueCommands(commands[1:])
+	first := ParseCommand(commands[0])
+	result := first.Execute(p, worldIface)
+	c.kind = first.OutputKind()
+	return result
+}
+
+// errAliasLoop is returned when an alias expands into itself.
+var errAliasLoop = errors.New("expands into itself or nests too deeply")
+
+// expandAlias substitutes args into an alias and splits it into commands,
+// expanding any aliases it uses in turn.
+func expandAlias(expansion string, args []string, p PlayerInterface, depth int) ([]string, error) {
+	if depth > maxAliasDepth {
+		return nil, errAliasLoop
+	}
+
+	var commands []string
+	for _, part := range p.SplitCommands(substituteAliasArgs(expansion, args)) {
+		cmd := ParseCommand(part)
+		if nested, ok := p.GetAlias(cmd.Name); ok {
+			expanded, err := expandAlias(nested, cmd.Args, p, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			commands = append(commands, expanded...)
+		} else {
+			commands = append(commands, part)
+		}
+		if len(commands) > maxAliasCommands {
+			return nil, fmt.Errorf("expands into more than %d commands", maxAliasCommands)
+		}
+	}
+	return commands, nil
+}
+
+// substituteAliasArgs replaces $1..$n with the matching argument and $* with
+// all of them. Missing arguments become empty. If the expansion uses neither,
+// the arguments are added to the end.
+func substituteAliasArgs(expansion string, args []string) string {
+	var result strings.Builder
+	used := false
+	for i := 0; i < len(expansion); i++ {
+		if expansion[i] != '$' || i+1 >= len(expansion) {
+			result.WriteByte(expansion[i])
+			continue
+		}
+
+		if expansion[i+1] == '*' {
+			result.WriteString(strings.Join(args, " "))
+			used = true
+			i++
+			continue
+		}
+
+		end := i + 1
+		for end < len(expansion) && expansion[end] >= '0' && expansion[end] <= '9' {
+			end++
+		}
+		n, err := strconv.Atoi(expansion[i+1 : end])
+		if err != nil || n < 1 {
+			result.WriteByte(expansion[i])
+			continue
+		}
+		if n <= len(args) {
+			result.WriteString(args[n-1])
+		}
+		used = true
+		i = end - 1
+	}
+
+	if !used && len(args) > 0 {
+		return result.String() + " " + strings.Join(args, " ")
+	}
+	return result.String()
+}
@@ -0,0 +1,27 @@
+package command
+
+import "testing"
+
+// TestSubstituteAliasArgs tests positional and whole-line argument substitution
+func TestSubstituteAliasArgs(t *testing.T) {
+	tests := []struct {
+		expansion string
+		args      []string
+		expected  string
+	}{
+		{"cast fireball $1", []string{"goblin"}, "cast fireball goblin"},
+		{"give $2 to $1", []string{"bob", "sword"}, "give sword to bob"},
+		{"say $*", []string{"hello", "there"}, "say hello there"},
+		{"cast fireball", []string{"goblin"}, "cast fireball goblin"}, // No placeholders: args appended
+		{"kill $1;loot", nil, "kill ;loot"},                           // Missing args are empty
+		{"say $10 $1", []string{"a"}, "say  a"},
+		{"say costs $ and $x", nil, "say costs $ and $x"}, // Not placeholders
+		{"say $", []string{"hi"}, "say $ hi"},
+	}
+
+	for _, tt := range tests {
+		if got := substituteAliasArgs(tt.expansion, tt.args); got != tt.expected {
+			t.Errorf("substituteAliasArgs(%q, %v) = %q, expected %q", tt.expansion, tt.args, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,465 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/auction"
+	"github.com/lawnchairsociety/opentowermud/server/internal/database"
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+	"github.com/lawnchairsociety/opentowermud/server/internal/npc"
+)
+
+// executeAuction handles the auction command and its subcommands
+func executeAuction(c *Command, p PlayerInterface) string {
+	if len(c.Args) == 0 {
+		return executeAuctionHelp()
+	}
+
+	subcommand := strings.ToLower(c.Args[0])
+	if subcommand == "help" {
+		return executeAuctionHelp()
+	}
+
+	room, ok := GetRoom(p)
+	if !ok {
+		return "Internal error: invalid room"
+	}
+	if findAuctioneer(room) == nil {
+		return "There is no auctioneer here. Visit the auction house in any city center."
+	}
+
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return "Internal error: invalid server type"
+	}
+	db, ok := server.GetDatabase().(*database.Database)
+	if !ok {
+		return "Internal error: database not available"
+	}
+
+	args := c.Args[1:]
+	switch subcommand {
+	case "sell":
+		return executeAuctionSell(args, p, server, db)
+	case "search":
+		return executeAuctionSearch(args, db)
+	case "bid":
+		return executeAuctionBid(args, p, server, db)
+	case "buyout":
+		return executeAuctionBuyout(args, p, server, db)
+	case "cancel":
+		return executeAuctionCancel(args, p, db)
+	case "mine":
+		return executeAuctionMine(p, db)
+	default:
+		return fmt.Sprintf("Unknown auction command: %s\n%s", subcommand, executeAuctionHelp())
+	}
+}
+
+// executeAuctionHelp shows the auction command help
+func executeAuctionHelp() string {
+	return fmt.Sprintf(`=== Auction House Commands ===
+Buy and sell items with players in every city, even while they're offline.
+
+Commands:
+  auction sell <item> <buyout> [<starting bid>] [<duration>]
+                            - List an item (duration like 12h or 2d, default 24h)
+  auction search [words] [type:<type>] [slot:<slot>] [tier:<n>]
+                            - Search listings
+  auction bid <id> <amount> - Bid on a listing
+  auction buyout <id>       - Buy a listing outright
+  auction cancel <id>       - Take down your listing (only if it has no bids)
+  auction mine              - Show your listings and bids
+
+Notes:
+  - Use these commands beside an auctioneer in any city center
+  - Gold for a bid is held by the house; if you're outbid it's mailed back
+  - Won items and sale proceeds are delivered by mail
+  - Unsold items are mailed back to the seller when the auction ends
+  - The house keeps %d%% of every sale`, auction.HouseCutPercent)
+}
+
+// findAuctioneer returns the first auctioneer NPC in the room, or nil if there isn't one
+func findAuctioneer(room RoomInterface) *npc.NPC {
+	for _, n := range room.GetNPCs() {
+		if n.IsAuctioneer() {
+			return n
+		}
+	}
+	return nil
+}
+
+// executeAuctionSell lists an item from the player's inventory
+func executeAuctionSell(args []string, p PlayerInterface, server ServerInterface, db *database.Database) string {
+	usage := "Usage: auction sell <item> <buyout> [<starting bid>] [<duration>]\nExample: auction sell long sword 500 200 48h"
+
+	// Parse from the end: optional duration, then one or two prices
+	duration := auction.DefaultDuration
+	if len(args) > 0 && isAuctionDuration(args[len(args)-1]) {
+		d, err := auction.ParseDuration(args[len(args)-1])
+		if err != nil {
+			return fmt.Sprintf("Invalid duration: %v.", err)
+		}
+		duration = d
+		args = args[:len(args)-1]
+	}
+
+	var prices []int
+	for len(args) > 0 && len(prices) < 2 {
+		n, err := strconv.Atoi(args[len(args)-1])
+		if err != nil {
+			break
+		}
+		prices = append([]int{n}, prices...)
+		args = args[:len(args)-1]
+	}
+	itemName := strings.Join(args, " ")
+	if itemName == "" || len(prices) == 0 {
+		return usage
+	}
+
+	buyout, minBid := prices[0], 0
+	if len(prices) == 2 {
+		minBid = prices[1]
+	}
+	if buyout <= 0 || minBid < 0 {
+		return "Prices must be positive numbers.\n" + usage
+	}
+	if minBid >= buyout {
+		return "The starting bid must be lower than the buyout price."
+	}
+
+	item, found := p.FindItem(itemName)
+	if !found {
+		return fmt.Sprintf("You don't have '%s' in your inventory.", itemName)
+	}
+	if item.Unique {
+		return fmt.Sprintf("The auctioneer refuses to list %s. It is one of a kind.", item.Name)
+	}
+	// Listings and the mail that delivers them don't keep wear
+	if item.Wear > 0 {
+		return fmt.Sprintf("The auctioneer won't list a worn %s. Have it repaired first.", item.Name)
+	}
+
+	count, err := db.CountAuctionsBySeller(p.GetCharacterID())
+	if err != nil {
+		logger.Error("Failed to count auctions", "error", err, "player", p.GetName())
+		return "Failed to check your listings."
+	}
+	if count >= auction.MaxListingsPerPlayer {
+		return fmt.Sprintf("You already have %d listings. Cancel one or wait for it to end.", auction.MaxListingsPerPlayer)
+	}
+
+	removedItem, removed := p.RemoveItem(item.Name)
+	if !removed {
+		return "Something went wrong trying to list that item."
+	}
+
+	slot := ""
+	if removedItem.Slot != 0 {
+		slot = removedItem.Slot.String()
+	}
+	id, err := db.CreateAuction(&auction.Listing{
+		SellerID:   p.GetCharacterID(),
+		SellerName: p.GetName(),
+		ItemID:     removedItem.ID,
+		ItemName:   removedItem.Name,
+		ItemType:   removedItem.Type.String(),
+		ItemSlot:   slot,
+		ItemTier:   removedItem.Tier,
+		MinBid:     minBid,
+		Buyout:     buyout,
+	}, duration)
+	if err != nil {
+		// Return the item on failure
+		p.AddItem(removedItem)
+		logger.Error("Failed to create auction", "error", err, "player", p.GetName(), "item", removedItem.ID)
+		return "Failed to list your item. It has been returned to you."
+	}
+
+	logger.Info("Auction listed",
+		"id", id,
+		"seller", p.GetName(),
+		"item", removedItem.ID,
+		"buyout", buyout,
+		"minBid", minBid)
+
+	if saveErr := server.SavePlayer(p); saveErr != nil {
+		logger.Warning("Failed to save player after auction listing", "player", p.GetName(), "error", saveErr)
+	}
+
+	result := fmt.Sprintf("You list {item}%s{/} as auction #%d with a buyout of {gold}%d gold{/}", removedItem.Name, id, buyout)
+	if minBid > 0 {
+		result += fmt.Sprintf(" and a starting bid of {gold}%d gold{/}", minBid)
+	}
+	return result + fmt.Sprintf(" for %d hours.", int(duration.Hours()))
+}
+
+// executeAuctionSearch lists auctions matching the search words
+func executeAuctionSearch(args []string, db *database.Database) string {
+	filter, err := auction.ParseSearch(args)
+	if err != nil {
+		return fmt.Sprintf("Invalid search: %v.", err)
+	}
+
+	listings, err := db.SearchAuctions(filter, auction.MaxSearchResults)
+	if err != nil {
+		logger.Error("Failed to search auctions", "error", err)
+		return "Failed to search the auction house."
+	}
+	if len(listings) == 0 {
+		return "No auctions match your search."
+	}
+
+	var result strings.Builder
+	result.WriteString("\n=== Auction House ===\n")
+	writeAuctionListings(&result, listings)
+	if len(listings) == auction.MaxSearchResults {
+		result.WriteString("Only the first results are shown. Narrow your search to see more.\n")
+	}
+	return result.String()
+}
+
+// executeAuctionBid places a bid on a listing
+func executeAuctionBid(args []string, p PlayerInterface, server ServerInterface, db *database.Database) string {
+	if len(args) < 2 {
+		return "Usage: auction bid <id> <amount>"
+	}
+	id, ok := parseAuctionID(args[0])
+	if !ok {
+		return "Invalid auction number."
+	}
+	amount, err := strconv.Atoi(args[1])
+	if err != nil || amount <= 0 {
+		return "Invalid bid amount."
+	}
+
+	l, err := db.GetAuction(id)
+	if err != nil {
+		logger.Error("Failed to get auction", "error", err, "id", id)
+		return "Failed to look up that auction."
+	}
+	if l == nil {
+		return fmt.Sprintf("There is no auction #%d.", id)
+	}
+	if msg := checkAuctionSellerMode(p, db, l); msg != "" {
+		return msg
+	}
+	if amount >= l.Buyout {
+		return fmt.Sprintf("That meets the buyout price. Use 'auction buyout %d' to buy it for %d gold.", id, l.Buyout)
+	}
+
+	// The house holds the gold until the auction ends or the bid is beaten
+	if !p.SpendGold(amount) {
+		return fmt.Sprintf("You don't have %d gold. You have %d gold.", amount, p.GetGold())
+	}
+
+	prev, err := db.PlaceBid(id, p.GetCharacterID(), p.GetName(), amount)
+	if err != nil {
+		p.AddGold(amount)
+		return auctionErrorMessage(err, l, p.GetName())
+	}
+
+	logger.Info("Auction bid",
+		"id", id,
+		"bidder", p.GetName(),
+		"amount", amount)
+
+	if saveErr := server.SavePlayer(p); saveErr != nil {
+		logger.Warning("Failed to save player after auction bid", "player", p.GetName(), "error", saveErr)
+	}
+
+	// Notify the outbid player if online
+	if prev.HasBid() {
+		notifyAuctionPlayer(server, prev.BidderName, fmt.Sprintf("\nYou have been outbid on {item}%s{/} (auction #%d). Your %d gold has been returned by mail.\n", prev.ItemName, id, prev.CurrentBid))
+	}
+
+	return fmt.Sprintf("You bid {gold}%d gold{/} on {item}%s{/}. The auction house holds your gold until the auction ends.", amount, prev.ItemName)
+}
+
+// executeAuctionBuyout buys a listing at its buyout price
+func executeAuctionBuyout(args []string, p PlayerInterface, server ServerInterface, db *database.Database) string {
+	if len(args) < 1 {
+		return "Usage: auction buyout <id>"
+	}
+	id, ok := parseAuctionID(args[0])
+	if !ok {
+		return "Invalid auction number."
+	}
+
+	l, err := db.GetAuction(id)
+	if err != nil {
+		logger.Error("Failed to get auction", "error", err, "id", id)
+		return "Failed to look up that auction."
+	}
+	if l == nil {
+		return fmt.Sprintf("There is no auction #%d.", id)
+	}
+	if msg := checkAuctionSellerMode(p, db, l); msg != "" {
+		return msg
+	}
+
+	if !p.SpendGold(l.Buyout) {
+		return fmt.Sprintf("The buyout price is %d gold. You have %d gold.", l.Buyout, p.GetGold())
+	}
+
+	sold, err := db.BuyoutAuction(id, p.GetCharacterID(), p.GetName())
+	if err != nil {
+		p.AddGold(l.Buyout)
+		return auctionErrorMessage(err, l, p.GetName())
+	}
+
+	logger.Info("Auction bought out",
+		"id", id,
+		"buyer", p.GetName(),
+		"seller", sold.SellerName,
+		"price", sold.Buyout)
+
+	if saveErr := server.SavePlayer(p); saveErr != nil {
+		logger.Warning("Failed to save player after auction buyout", "player", p.GetName(), "error", saveErr)
+	}
+
+	notifyAuctionPlayer(server, sold.SellerName, fmt.Sprintf("\nYour auction of {item}%s{/} sold to {player}%s{/} for {gold}%d gold{/}. The proceeds await you at the mailbox.\n", sold.ItemName, p.GetName(), sold.Buyout))
+	if sold.HasBid() {
+		notifyAuctionPlayer(server, sold.BidderName, fmt.Sprintf("\n{player}%s{/} bought {item}%s{/} outright. Your %d gold has been returned by mail.\n", p.GetName(), sold.ItemName, sold.CurrentBid))
+	}
+
+	return fmt.Sprintf("You buy {item}%s{/} for {gold}%d gold{/}. It will be delivered to your mailbox.", sold.ItemName, sold.Buyout)
+}
+
+// executeAuctionCancel takes down one of the player's listings
+func executeAuctionCancel(args []string, p PlayerInterface, db *database.Database) string {
+	if len(args) < 1 {
+		return "Usage: auction cancel <id>"
+	}
+	id, ok := parseAuctionID(args[0])
+	if !ok {
+		return "Invalid auction number."
+	}
+
+	l, err := db.CancelAuction(id, p.GetCharacterID())
+	if err != nil {
+		return auctionErrorMessage(err, nil, p.GetName())
+	}
+
+	logger.Info("Auction cancelled", "id", id, "seller", p.GetName(), "item", l.ItemID)
+
+	return fmt.Sprintf("You cancel auction #%d. Your {item}%s{/} will be returned to your mailbox.", id, l.ItemName)
+}
+
+// executeAuctionMine shows the player's own listings and the auctions they lead
+func executeAuctionMine(p PlayerInterface, db *database.Database) string {
+	selling, err := db.SearchAuctions(auction.SearchFilter{SellerID: p.GetCharacterID()}, auction.MaxListingsPerPlayer)
+	if err != nil {
+		logger.Error("Failed to get auctions", "error", err, "player", p.GetName())
+		return "Failed to look up your auctions."
+	}
+	bidding, err := db.SearchAuctions(auction.SearchFilter{BidderID: p.GetCharacterID()}, auction.MaxSearchResults)
+	if err != nil {
+		logger.Error("Failed to get bids", "error", err, "player", p.GetName())
+		return "Failed to look up your bids."
+	}
+
+	if len(selling) == 0 && len(bidding) == 0 {
+		return "You have no auctions or bids."
+	}
+
+	var result strings.Builder
+	if len(selling) > 0 {
+		result.WriteString(fmt.Sprintf("\n=== Your Listings (%d/%d) ===\n", len(selling), auction.MaxListingsPerPlayer))
+		writeAuctionListings(&result, selling)
+	}
+	if len(bidding) > 0 {
+		result.WriteString("\n=== Your Winning Bids ===\n")
+		writeAuctionListings(&result, bidding)
+	}
+	return result.String()
+}
+
+// writeAuctionListings writes a table of auction listings
+func writeAuctionListings(result *strings.Builder, listings []*auction.Listing) {
+	now := time.Now()
+	result.WriteString("  ID  Item                      Bid      Buyout   Left  Seller\n")
+	result.WriteString("----  ----                      ---      ------   ----  ------\n")
+	for _, l := range listings {
+		bid := "-"
+		if l.HasBid() {
+			bid = strconv.Itoa(l.CurrentBid)
+		} else if l.AcceptsBids() {
+			bid = strconv.Itoa(l.MinBid) + "*"
+		}
+		result.WriteString(fmt.Sprintf("%4d  %-25s %-8s %-8d %-5s %s\n",
+			l.ID, truncate(l.ItemName, 25), bid, l.Buyout, l.TimeLeft(now), l.SellerName))
+	}
+	result.WriteString("(* = starting bid, - = buyout only)\n")
+}
+
+// isAuctionDuration returns true if the word looks like a duration such as 12h or 2d
+func isAuctionDuration(word string) bool {
+	word = strings.ToLower(word)
+	if len(word) < 2 || !(strings.HasSuffix(word, "h") || strings.HasSuffix(word, "d")) {
+		return false
+	}
+	_, err := strconv.Atoi(word[:len(word)-1])
+	return err == nil
+}
+
+// parseAuctionID parses an auction number, allowing a leading '#'
+func parseAuctionID(s string) (int64, bool) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(s, "#"), 10, 64)
+	return id, err == nil && id > 0
+}
+
+// auctionErrorMessage turns an auction house error into a message for the player
+func auctionErrorMessage(err error, l *auction.Listing, playerName string) string {
+	switch {
+	case errors.Is(err, auction.ErrNotFound):
+		return "That auction has already ended."
+	case errors.Is(err, auction.ErrOwnAuction):
+		return "You can't bid on your own auction."
+	case errors.Is(err, auction.ErrNoBidding):
+		return fmt.Sprintf("That auction is buyout only. Use 'auction buyout %d' to buy it.", l.ID)
+	case errors.Is(err, auction.ErrHighBidder):
+		return "You are already the high bidder."
+	case errors.Is(err, auction.ErrBidTooLow):
+		if l != nil {
+			return fmt.Sprintf("Your bid is too low. The minimum bid is %d gold.", l.NextMinBid())
+		}
+		return "Your bid is too low."
+	case errors.Is(err, auction.ErrNotSeller):
+		return "That isn't your auction."
+	case errors.Is(err, auction.ErrHasBids):
+		return "You can't cancel an auction that has bids."
+	default:
+		logger.Error("Auction house error", "error", err, "player", playerName)
+		return "The auctioneer fumbles the paperwork. Please try again."
+	}
+}
+
+// checkAuctionSellerMode returns why the player can't buy from the listing's
+// seller, or "" if they can. Hardcore characters only buy from and sell to
+// each other.
+func checkAuctionSellerMode(p PlayerInterface, db *database.Database, l *auction.Listing) string {
+	hardcore, _, err := db.GetCharacterMode(l.SellerID)
+	if err != nil && !errors.Is(err, database.ErrCharacterNotFound) {
+		logger.Error("Failed to look up seller mode", "error", err, "seller", l.SellerName)
+		return "Failed to look up that auction."
+	}
+	if hardcoreApart(p, hardcore) {
+		return "Hardcore characters can only trade with other hardcore characters."
+	}
+	return ""
+}
+
+// notifyAuctionPlayer sends a message to a player if they're online
+func notifyAuctionPlayer(server ServerInterface, name, message string) {
+	if targetIface := server.FindPlayer(name); targetIface != nil {
+		if target, ok := targetIface.(PlayerInterface); ok {
+			target.SendMessage(message)
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/bank"
+	"github.com/lawnchairsociety/opentowermud/server/internal/database"
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+	"github.com/lawnchairsociety/opentowermud/server/internal/npc"
+)
+
+// findBanker returns the first banker NPC in the room, or nil if there isn't one
+func findBanker(room RoomInterface) *npc.NPC {
+	for _, n := range room.GetNPCs() {
+		if n.IsBanker() {
+			return n
+		}
+	}
+	return nil
+}
+
+// requireBanker returns the server and database if the player is beside a
+// banker, or a message explaining why they can't use the vault.
+func requireBanker(p PlayerInterface) (ServerInterface, *database.Database, string) {
+	room, ok := GetRoom(p)
+	if !ok {
+		return nil, nil, "Internal error: invalid room"
+	}
+	if findBanker(room) == nil {
+		return nil, nil, "There is no banker here. Visit the bank in any city center."
+	}
+	// The vault is shared by every character on the account, hardcore or not
+	if p.IsHardcore() {
+		return nil, nil, "Your vault is shared with your account's other characters, so hardcore characters can't use it."
+	}
+
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return nil, nil, "Internal error: invalid server type"
+	}
+	db, ok := server.GetDatabase().(*database.Database)
+	if !ok {
+		return nil, nil, "Internal error: database not available"
+	}
+	return server, db, ""
+}
+
+// executeBank shows the vault, or expands it with 'bank expand'
+func executeBank(c *Command, p PlayerInterface) string {
+	if len(c.Args) > 0 {
+		switch strings.ToLower(c.Args[0]) {
+		case "help":
+			return executeBankHelp()
+		case "expand":
+			return executeBankExpand(p)
+		default:
+			return fmt.Sprintf("Unknown bank command: %s\n%s", c.Args[0], executeBankHelp())
+		}
+	}
+
+	server, db, msg := requireBanker(p)
+	if msg != "" {
+		return msg
+	}
+
+	accountID := p.GetAccountID()
+	v, err := db.GetVault(accountID)
+	if err != nil {
+		logger.Error("Failed to get vault", "error", err, "account_id", accountID)
+		return "Failed to open your vault."
+	}
+	bankItems, err := db.GetBankItems(accountID)
+	if err != nil {
+		logger.Error("Failed to get vault items", "error", err, "account_id", accountID)
+		return "Failed to open your vault."
+	}
+
+	var result strings.Builder
+	result.WriteString("\n=== Your Vault ===\n")
+	result.WriteString(fmt.Sprintf("Gold: {gold}%d{/}\n", v.Gold))
+	result.WriteString(fmt.Sprintf("Slots: %d/%d\n", len(bankItems), v.Slots))
+	if len(bankItems) == 0 {
+		result.WriteString("\nNo items are stored.")
+	} else {
+		result.WriteString("\nItems:\n")
+		for i, bankItem := range bankItems {
+			name := bankItem.ItemID
+			if item := server.CreateItem(bankItem.ItemID); item != nil {
+				name = item.Name
+			}
+			result.WriteString(fmt.Sprintf("  %2d. {item}%-30s{/} (from %s)\n", i+1, name, bankItem.DepositedBy))
+		}
+	}
+	if v.CanExpand() {
+		result.WriteString(fmt.Sprintf("\nType 'bank expand' to add %d slots for {gold}%d gold{/}.", bank.SlotsPerExpansion, v.ExpansionCost()))
+	}
+	return result.String()
+}
+
+// executeBankHelp shows the bank command help
+func executeBankHelp() string {
+	return fmt.Sprintf(`=== Bank Commands ===
+Store gold and items in a vault shared by every character on your account.
+
+Commands:
+  balance                   - Show your vault (also: bank, vault)
+  deposit <amount> [gold]   - Store gold
+  deposit <item>            - Store an item
+  withdraw <amount> gold    - Take out gold
+  withdraw <number|item>    - Take out an item
+  bank expand               - Buy %d more item slots
+
+Notes:
+  - Use these commands beside a banker in any city center
+  - Vaults start with %d slots and can be expanded to %d
+  - Unique items can't be stored`, bank.SlotsPerExpansion, bank.BaseSlots, bank.MaxSlots)
+}
+
+// executeBankExpand buys more item slots for the player's vault
+func executeBankExpand(p PlayerInterface) string {
+	server, db, msg := requireBanker(p)
+	if msg != "" {
+		return msg
+	}
+
+	accountID := p.GetAccountID()
+	v, err := db.GetVault(accountID)
+	if err != nil {
+		logger.Error("Failed to get vault", "error", err, "account_id", accountID)
+		return "Failed to open your vault."
+	}
+	if !v.CanExpand() {
+		return fmt.Sprintf("Your vault is already as large as it can be (%d slots).", v.Slots)
+	}
+
+	cost := v.ExpansionCost()
+	if !p.SpendGold(cost) {
+		return fmt.Sprintf("Expanding your vault costs %d gold, but you only have %d.", cost, p.GetGold())
+	}
+	expanded, err := db.ExpandVault(accountID, v.Slots)
+	if err != nil || !expanded {
+		p.AddGold(cost)
+		if err != nil && !errors.Is(err, bank.ErrMaxSlots) {
+			logger.Error("Failed to expand vault", "error", err, "account_id", accountID)
+		}
+		return "Your vault couldn't be expanded. Please try again."
+	}
+
+	logger.Info("Vault expanded", "player", p.GetName(), "account_id", accountID, "slots", v.Slots+bank.SlotsPerExpansion, "cost", cost)
+
+	if saveErr := server.SavePlayer(p); saveErr != nil {
+		logger.Warning("Failed to save player after vault expansion", "player", p.GetName(), "error", saveErr)
+	}
+
+	return fmt.Sprintf("You pay {gold}%d gold{/} and your vault grows to %d slots.", cost, v.Slots+bank.SlotsPerExpansion)
+}
+
+// executeDeposit puts gold or an item into the player's vault
+func executeDeposit(c *Command, p PlayerInterface) string {
+	if err := c.RequireArgs(1, "Usage: deposit <amount> [gold] | deposit <item>"); err != nil {
+		return err.Error()
+	}
+
+	server, db, msg := requireBanker(p)
+	if msg != "" {
+		return msg
+	}
+	accountID := p.GetAccountID()
+
+	if amount, isGold := parseGoldAmount(c.Args); isGold {
+		if amount <= 0 {
+			return "You must deposit a positive amount of gold."
+		}
+		if !p.SpendGold(amount) {
+			return fmt.Sprintf("You don't have %d gold.", amount)
+		}
+		if err := db.DepositBankGold(accountID, amount); err != nil {
+			p.AddGold(amount)
+			logger.Error("Failed to deposit vault gold", "error", err, "player", p.GetName())
+			return "Failed to deposit the gold."
+		}
+
+		logger.Info("Vault gold deposited", "player", p.GetName(), "account_id", accountID, "amount", amount)
+		if saveErr := server.SavePlayer(p); saveErr != nil {
+			logger.Warning("Failed to save player after vault deposit", "player", p.GetName(), "error", saveErr)
+		}
+		return fmt.Sprintf("You deposit {gold}%d gold{/} in your vault.", amount)
+	}
+
+	itemName := c.GetItemName()
+	item, found := p.FindItem(itemName)
+	if !found {
+		return fmt.Sprintf("You don't have '%s' in your inventory.", itemName)
+	}
+	if item.Unique {
+		return fmt.Sprintf("The %s is unique and can't be stored in your vault.", item.Name)
+	}
+	// The vault only remembers what an item is, not how worn it was
+	if item.Wear > 0 {
+		return fmt.Sprintf("The %s is worn. Have it repaired before storing it in your vault.", item.Name)
+	}
+
+	removed, ok := p.RemoveItem(item.Name)
+	if !ok {
+		return fmt.Sprintf("You don't have '%s' in your inventory.", itemName)
+	}
+	if err := db.DepositBankItem(accountID, removed.ID, p.GetName()); err != nil {
+		p.AddItem(removed)
+		if errors.Is(err, bank.ErrVaultFull) {
+			return "Your vault is full. Type 'bank expand' to buy more slots."
+		}
+		logger.Error("Failed to deposit vault item", "error", err, "player", p.GetName(), "item", removed.ID)
+		return "Failed to deposit the item."
+	}
+
+	logger.Info("Vault item deposited", "player", p.GetName(), "account_id", accountID, "item", removed.ID)
+	if saveErr := server.SavePlayer(p); saveErr != nil {
+		logger.Warning("Failed to save player after vault deposit", "player", p.GetName(), "error", saveErr)
+	}
+	return fmt.Sprintf("You deposit {item}%s{/} in your vault.", removed.Name)
+}
+
+// executeWithdraw takes gold or an item out of the player's vault
+func executeWithdraw(c *Command, p PlayerInterface) string {
+	if err := c.RequireArgs(1, "Usage: withdraw <amount> gold | withdraw <number|item>"); err != nil {
+		return err.Error()
+	}
+
+	server, db, msg := requireBanker(p)
+	if msg != "" {
+		return msg
+	}
+	accountID := p.GetAccountID()
+
+	// A bare number is an item number from 'balance'; gold needs the "gold" suffix
+	if amount, isGold := parseGoldAmount(c.Args); isGold && len(c.Args) == 2 {
+		if amount <= 0 {
+			return "You must withdraw a positive amount of gold."
+		}
+		err := db.WithdrawBankGold(accountID, amount)
+		if errors.Is(err, bank.ErrInsufficientGold) {
+			if v, vErr := db.GetVault(accountID); vErr == nil {
+				return fmt.Sprintf("Your vault only holds %d gold.", v.Gold)
+			}
+			return "Your vault doesn't hold that much gold."
+		}
+		if err != nil {
+			logger.Error("Failed to withdraw vault gold", "error", err, "player", p.GetName())
+			return "Failed to withdraw the gold."
+		}
+		p.AddGold(amount)
+
+		logger.Info("Vault gold withdrawn", "player", p.GetName(), "account_id", accountID, "amount", amount)
+		if saveErr := server.SavePlayer(p); saveErr != nil {
+			logger.Warning("Failed to save player after vault withdrawal", "player", p.GetName(), "error", saveErr)
+		}
+		return fmt.Sprintf("You withdraw {gold}%d gold{/} from your vault.", amount)
+	}
+
+	bankItems, err := db.GetBankItems(accountID)
+	if err != nil {
+		logger.Error("Failed to get vault items", "error", err, "account_id", accountID)
+		return "Failed to open your vault."
+	}
+
+	// Find the item by its number in 'balance', or by name
+	query := c.GetItemName()
+	var chosen *bank.Item
+	if index, err := strconv.Atoi(query); err == nil {
+		if index >= 1 && index <= len(bankItems) {
+			chosen = &bankItems[index-1]
+		}
+	} else {
+		queryLower := strings.ToLower(query)
+		for i := range bankItems {
+			item := server.CreateItem(bankItems[i].ItemID)
+			if item != nil && strings.Contains(strings.ToLower(item.Name), queryLower) {
+				chosen = &bankItems[i]
+				break
+			}
+		}
+	}
+	if chosen == nil {
+		return fmt.Sprintf("Your vault doesn't hold '%s'. Type 'balance' to see what's stored.", query)
+	}
+
+	item := server.CreateItem(chosen.ItemID)
+	if item == nil {
+		logger.Warning("Unknown item in vault", "item_id", chosen.ItemID, "account_id", accountID)
+		return "That item can't be withdrawn."
+	}
+	if !p.CanCarry(item) {
+		return fmt.Sprintf("You can't carry the %s.", item.Name)
+	}
+
+	taken, err := db.WithdrawBankItem(accountID, chosen.ID)
+	if err != nil {
+		logger.Error("Failed to withdraw vault item", "error", err, "player", p.GetName(), "item", chosen.ItemID)
+		return "Failed to withdraw the item."
+	}
+	if !taken {
+		return "That item is no longer in your vault."
+	}
+	p.AddItem(item)
+
+	logger.Info("Vault item withdrawn", "player", p.GetName(), "account_id", accountID, "item", chosen.ItemID)
+	if saveErr := server.SavePlayer(p); saveErr != nil {
+		logger.Warning("Failed to save player after vault withdrawal", "player", p.GetName(), "error", saveErr)
+	}
+	return fmt.Sprintf("You withdraw {item}%s{/} from your vault.", item.Name)
+}
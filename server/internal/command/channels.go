@@ -0,0 +1,198 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/channels"
+	"github.com/lawnchairsociety/opentowermud/server/internal/color"
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+)
+
+// findChannelCommand returns the chat channel a command word talks on, if any.
+func findChannelCommand(word string, p PlayerInterface) (*channels.Channel, bool) {
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return nil, false
+	}
+	registry := server.GetChannelRegistry()
+	if registry == nil {
+		return nil, false
+	}
+	return registry.FindByCommand(word)
+}
+
+// executeChannels lists the chat channels and the player's status on each.
+func executeChannels(c *Command, p PlayerInterface) string {
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return "Internal error: invalid server type"
+	}
+
+	registry := server.GetChannelRegistry()
+	if registry == nil || registry.Count() == 0 {
+		return "There are no chat channels."
+	}
+
+	var result strings.Builder
+	result.WriteString("Chat Channels:\n")
+	for _, ch := range registry.All() {
+		var status string
+		switch {
+		case !p.CanUseChannel(ch):
+			if restriction := ch.Restriction(); restriction != "" {
+				status = "(" + restriction + ")"
+			} else {
+				status = "(unavailable)"
+			}
+		case !p.IsOnChannel(ch):
+			// Available to join
+		case p.IsChannelMuted(ch):
+			status = "[joined, muted]"
+		default:
+			status = "[joined]"
+		}
+		result.WriteString(fmt.Sprintf("  {channel}%-10s{/} %-45s %s\n", ch.ID, ch.Description, status))
+	}
+	result.WriteString("\nTalk with '<channel> <message>'. Use 'channel join|leave|mute|unmute|history <channel>'.")
+	return result.String()
+}
+
+// executeChannel handles channel membership: join, leave, mute, unmute and history.
+func executeChannel(c *Command, p PlayerInterface) string {
+	if len(c.Args) == 0 {
+		return executeChannels(c, p)
+	}
+
+	sub := strings.ToLower(c.Args[0])
+	if sub == "list" {
+		return executeChannels(c, p)
+	}
+	if len(c.Args) < 2 {
+		return "Usage: channel join|leave|mute|unmute|history <channel>"
+	}
+
+	ch, ok := findChannelCommand(c.Args[1], p)
+	if !ok {
+		return fmt.Sprintf("There is no '%s' channel. Type 'channels' to see them all.", c.Args[1])
+	}
+
+	switch sub {
+	case "join":
+		if !p.CanUseChannel(ch) {
+			return fmt.Sprintf("You can't join the %s channel (%s).", ch.Name, ch.Restriction())
+		}
+		if p.IsOnChannel(ch) {
+			return fmt.Sprintf("You are already on the %s channel.", ch.Name)
+		}
+		p.JoinChannel(ch)
+		return fmt.Sprintf("You join the {channel}%s{/} channel. Talk on it with '%s <message>'.", ch.Name, ch.ID)
+	case "leave":
+		if !p.IsOnChannel(ch) {
+			return fmt.Sprintf("You aren't on the %s channel.", ch.Name)
+		}
+		p.LeaveChannel(ch)
+		return fmt.Sprintf("You leave the %s channel.", ch.Name)
+	case "mute", "unmute":
+		if !p.IsOnChannel(ch) {
+			return fmt.Sprintf("You aren't on the %s channel.", ch.Name)
+		}
+		muted := sub == "mute"
+		if p.IsChannelMuted(ch) == muted {
+			return fmt.Sprintf("The %s channel is already %sd.", ch.Name, sub)
+		}
+		p.SetChannelMuted(ch, muted)
+		if muted {
+			return fmt.Sprintf("You mute the %s channel. You stay on it, but won't hear it until you unmute it.", ch.Name)
+		}
+		return fmt.Sprintf("You unmute the %s channel.", ch.Name)
+	case "history":
+		return showChannelHistory(ch, p)
+	default:
+		return "Usage: channel join|leave|mute|unmute|history <channel>"
+	}
+}
+
+// showChannelHistory shows the recent messages said on a channel.
+func showChannelHistory(ch *channels.Channel, p PlayerInterface) string {
+	if !p.IsOnChannel(ch) {
+		return fmt.Sprintf("You aren't on the %s channel.", ch.Name)
+	}
+
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return "Internal error: invalid server type"
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Recent messages on %s:\n", ch.Name))
+	shown := 0
+	for _, msg := range server.GetChannelHistory(ch.ID) {
+		if p.IsIgnoring(msg.Sender) {
+			continue
+		}
+		result.WriteString(fmt.Sprintf("  %s {channel}[%s] %s: \"%s\"{/}\n", msg.Time.Format("15:04"), ch.Name, msg.Sender, color.Escape(msg.Text)))
+		shown++
+	}
+	if shown == 0 {
+		return fmt.Sprintf("Nothing has been said on %s recently.", ch.Name)
+	}
+	return strings.TrimRight(result.String(), "\n")
+}
+
+// executeChannelTalk sends a message on a chat channel. With no message, it shows
+// the channel's recent history instead.
+func executeChannelTalk(ch *channels.Channel, c *Command, p PlayerInterface) string {
+	if len(c.Args) == 0 {
+		return showChannelHistory(ch, p)
+	}
+
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return "Internal error: invalid server type"
+	}
+
+	if !p.CanUseChannel(ch) {
+		return fmt.Sprintf("You can't use the %s channel (%s).", ch.Name, ch.Restriction())
+	}
+	if !p.IsOnChannel(ch) {
+		return fmt.Sprintf("You aren't on the %s channel. Type 'channel join %s' first.", ch.Name, ch.ID)
+	}
+	if p.IsChannelMuted(ch) {
+		return fmt.Sprintf("You have muted the %s channel. Type 'channel unmute %s' to talk on it.", ch.Name, ch.ID)
+	}
+
+	message := c.GetItemName()
+	if allowed, reason := p.CheckChatSpam(message); !allowed {
+		return reason
+	}
+
+	// Apply chat filter if enabled
+	if filter := server.GetChatFilter(); filter != nil && filter.IsEnabled() {
+		result := filter.Check(message)
+		if result.Violated {
+			logger.Always("CHAT_FILTER",
+				"player", p.GetName(),
+				"command", ch.ID,
+				"original", message,
+				"matched", strings.Join(result.MatchedWords, ", "),
+				"mode", string(filter.Mode()))
+
+			if filter.IsBlockMode() {
+				return "Your message contains inappropriate language and was not sent."
+			}
+			message = result.Filtered
+		}
+	}
+
+	server.SendChannelMessage(ch.ID, p.GetName(), message)
+	p.SendChannelGMCP(ch.ID, p.GetName(), message)
+
+	// AUDIT LOG - Always logged regardless of log level (security/moderation)
+	logger.Always("CHAT_CHANNEL",
+		"sender", p.GetName(),
+		"channel", ch.ID,
+		"message", message)
+
+	return fmt.Sprintf("{channel}[%s] You: \"%s\"{/}", ch.Name, color.Escape(message))
+}
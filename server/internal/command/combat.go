@@ -17,6 +17,12 @@ func executeAttack(c *Command, p PlayerInterface) string {
 	if p.IsInCombat() {
 		return "You are already fighting!"
 	}
+	if opponent, dueling := server.GetDuelOpponent(p.GetName()); dueling {
+		return fmt.Sprintf("You are in the middle of a duel with %s!", opponent)
+	}
+	if p.IsStunned() {
+		return stunnedMessage(p)
+	}
 
 	// Require target name
 	if err := c.RequireArgs(1, "Usage: attack <target>"); err != nil {
@@ -59,10 +65,20 @@ func executeAttack(c *Command, p PlayerInterface) string {
 
 // executeFlee attempts to escape from combat
 func executeFlee(c *Command, p PlayerInterface) string {
+	// Fleeing a duel means giving it up
+	if server, ok := p.GetServer().(ServerInterface); ok {
+		if _, dueling := server.GetDuelOpponent(p.GetName()); dueling {
+			return executeDuelYield(p, server)
+		}
+	}
+
 	// Check if in combat
 	if !p.IsInCombat() {
 		return "You aren't fighting anyone!"
 	}
+	if p.IsStunned() {
+		return stunnedMessage(p)
+	}
 
 	room, ok := GetRoom(p)
 	if !ok {
@@ -114,6 +130,27 @@ func executeFlee(c *Command, p PlayerInterface) string {
 	return fmt.Sprintf("You flee %s!\n\n%s", direction, newRoom.GetDescriptionForPlayer(p.GetName()))
 }
 
+// executeDefend braces the player against room-wide attacks, halving their
+// damage for a couple of rounds at the cost of not attacking
+func executeDefend(c *Command, p PlayerInterface) string {
+	if !p.IsInCombat() {
+		return "You aren't fighting anyone!"
+	}
+	if p.IsStunned() {
+		return stunnedMessage(p)
+	}
+	if p.IsDefending() {
+		return "You are already braced for the next blow."
+	}
+
+	p.Defend()
+	if room, ok := GetRoom(p); ok {
+		server := p.GetServer().(ServerInterface)
+		server.BroadcastToRoom(room.GetID(), fmt.Sprintf("%s raises their guard.", p.GetName()), p)
+	}
+	return "You raise your guard and brace yourself. Room-wide attacks will do half damage, but you won't attack while defending."
+}
+
 // executeConsider evaluates an NPC's difficulty
 func executeConsider(c *Command, p PlayerInterface) string {
 	// Require target name
@@ -182,3 +219,8 @@ Armor: %d | Damage: %d | XP Reward: %d`,
 func executeConsiderSelf(c *Command, p PlayerInterface) string {
 	return executeScore(c, p)
 }
+
+// stunnedMessage is what a stunned player is told when they try to act
+func stunnedMessage(p PlayerInterface) string {
+	return fmt.Sprintf("You are stunned and can't act! (%ds remaining)", p.GetStunRemaining())
+}
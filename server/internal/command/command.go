@@ -6,8 +6,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/lawnchairsociety/opentowermud/server/internal/channels"
 	"github.com/lawnchairsociety/opentowermud/server/internal/chatfilter"
 	"github.com/lawnchairsociety/opentowermud/server/internal/crafting"
+	"github.com/lawnchairsociety/opentowermud/server/internal/effects"
+	"github.com/lawnchairsociety/opentowermud/server/internal/guild"
 	"github.com/lawnchairsociety/opentowermud/server/internal/items"
 	"github.com/lawnchairsociety/opentowermud/server/internal/leveling"
 	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
@@ -22,6 +25,13 @@ type StallItem struct {
 	Price int         // The asking price in gold
 }
 
+// CorpseInfo describes one of a player's unrecovered corpses.
+type CorpseInfo struct {
+	RoomID    string        // Room the player died in
+	Items     int           // How many items lie in the corpse
+	Remaining time.Duration // Time left before it decays
+}
+
 // ServerInterface defines the contract for server operations needed by command handlers.
 //
 // This interface exists to avoid circular dependencies between the command and server
@@ -48,6 +58,7 @@ type StallItem struct {
 // All methods are required for full functionality. However, some methods may return
 // nil or zero values in test scenarios:
 //   - GetChatFilter() may return nil if chat filtering is disabled
+//   - GetChannelRegistry() may return nil if chat channels are not configured
 //   - GetSpellRegistry() may return nil in tests without spell data
 //   - GetRecipeRegistry() may return nil in tests without crafting data
 //   - GetQuestRegistry() may return nil in tests without quest data
@@ -76,6 +87,13 @@ type ServerInterface interface {
 	// BroadcastToAdmins sends a message only to players with admin privileges.
 	BroadcastToAdmins(message string)
 
+	// SendChannelToRoom sends a Comm.Channel.Text GMCP package to players in a room
+	// (respecting ignore lists). Only GMCP-enabled clients receive anything.
+	SendChannelToRoom(roomID string, channel string, talker string, text string)
+
+	// SendChannelToFloor sends a Comm.Channel.Text GMCP package to players on a floor.
+	SendChannelToFloor(floor int, channel string, talker string, text string)
+
 	// === Player Lookup Methods ===
 
 	// FindPlayer finds an online player by name (case-insensitive).
@@ -115,7 +133,8 @@ type ServerInterface interface {
 
 	// === Server Mode Methods ===
 
-	// IsPilgrimMode returns true if the server is in pilgrim (permadeath) mode.
+	// IsPilgrimMode returns true if the server is in pilgrim mode (exploration only, no combat).
+	// Permadeath is per character: see PlayerInterface.IsHardcore.
 	IsPilgrimMode() bool
 
 	// === Filter Methods ===
@@ -149,6 +168,148 @@ type ServerInterface interface {
 	// Returns true if the player was found and kicked, false otherwise.
 	KickPlayer(playerName string, reason string) bool
 
+	// StartSnoop mirrors everything sent to a player to an admin's connection.
+	// Returns an error with a player-facing message if the snoop can't start.
+	StartSnoop(watcherName, targetName string) error
+
+	// === Spectate Methods ===
+
+	// StartSpectate lets a player watch another player's fight, read-only.
+	// Returns an error with a player-facing message if the fight can't be watched.
+	StartSpectate(watcherName, targetName string) error
+
+	// StopWatching ends a snoop or spectate session.
+	// Returns the name of the player being watched, or "" if there was no session.
+	StopWatching(watcherName string) string
+
+	// GetWatchTarget returns who a player is watching and whether they are a
+	// read-only spectator. Returns "" if they aren't watching anyone.
+	GetWatchTarget(watcherName string) (targetName string, spectating bool)
+
+	// === Party Methods ===
+	// Errors carry a player-facing message.
+
+	// InviteToParty invites a player to the inviter's party (starting one on acceptance).
+	InviteToParty(inviterName, targetName string) error
+
+	// AcceptPartyInvite joins the party of whoever last invited the player.
+	AcceptPartyInvite(name string) error
+
+	// DeclinePartyInvite turns down a pending invite and returns who sent it.
+	DeclinePartyInvite(name string) (inviterName string, err error)
+
+	// LeaveParty removes a player from their party.
+	LeaveParty(name string) error
+
+	// KickFromParty removes a member from the leader's party.
+	KickFromParty(leaderName, targetName string) error
+
+	// SetPartyLeader hands leadership of the party to another member.
+	SetPartyLeader(leaderName, targetName string) error
+
+	// DisbandParty breaks up the leader's party.
+	DisbandParty(leaderName string) error
+
+	// SetPartyLootMode changes how the leader's party shares item drops (LootFree, ...).
+	SetPartyLootMode(leaderName, mode string) error
+
+	// GetPartyInfo returns the party a player belongs to, or false if they aren't in one.
+	GetPartyInfo(name string) (PartyInfo, bool)
+
+	// SendPartyMessage delivers a group chat message to the sender's party.
+	SendPartyMessage(senderName, message string) error
+
+	// RollOnLoot answers the player's open need/greed rolls with LootNeed, LootGreed or LootPass.
+	// Returns a summary of the rolls for the player.
+	RollOnLoot(name, choice string) (string, error)
+
+	// === Trade Methods ===
+	// Errors carry a player-facing message.
+
+	// RequestTrade asks a player in the same room to trade, or opens the trade
+	// window if they already asked. Returns true if the window opened.
+	RequestTrade(fromName, toName string) (opened bool, err error)
+
+	// GetTradeWindow returns both sides of a player's open trade, or false if they aren't trading.
+	GetTradeWindow(name string) (TradeWindow, bool)
+
+	// OfferTradeItem adds an inventory item to the player's offer and returns its name.
+	OfferTradeItem(name, itemName string) (string, error)
+
+	// WithdrawTradeItem takes an item back out of the player's offer and returns its name.
+	WithdrawTradeItem(name, itemName string) (string, error)
+
+	// OfferTradeGold sets how much gold the player offers (0 withdraws it).
+	OfferTradeGold(name string, amount int) error
+
+	// AcceptTrade accepts the current offers, completing the trade once both sides have.
+	AcceptTrade(name string) (completed bool, err error)
+
+	// CancelTrade closes the player's trade window without exchanging anything.
+	CancelTrade(name string) error
+
+	// === Duel Methods ===
+	// Errors carry a player-facing message.
+
+	// ChallengeDuel challenges a player in the same room to a duel, or starts the
+	// duel if they already challenged. Returns true if the duel started.
+	ChallengeDuel(fromName, toName string) (started bool, err error)
+
+	// AcceptDuel accepts the open challenge to the player and returns the challenger's name.
+	AcceptDuel(name string) (string, error)
+
+	// DeclineDuel refuses the open challenge to the player and returns the challenger's name.
+	DeclineDuel(name string) (string, error)
+
+	// YieldDuel gives up the player's duel, handing their opponent the win.
+	YieldDuel(name string) error
+
+	// GetDuelOpponent returns who the player is dueling, or false if they aren't.
+	GetDuelOpponent(name string) (string, bool)
+
+	// === Stall Methods ===
+	// Open stalls keep selling while their owners are offline.
+
+	// GetUnattendedStall returns the owner's name and the items for sale in an
+	// offline player's stall in the room, or false if there isn't one.
+	GetUnattendedStall(roomID, ownerName string) (string, []*StallItem, bool)
+
+	// GetUnattendedStalls returns the item count of each offline player's stall
+	// in the room, by owner name.
+	GetUnattendedStalls(roomID string) map[string]int
+
+	// BuyFromUnattendedStall buys an item from an offline player's stall in the
+	// buyer's room. The price is mailed to the owner. Errors carry a player-facing message.
+	BuyFromUnattendedStall(buyerName, ownerName, itemName string) (*StallItem, error)
+
+	// === Corpse Methods ===
+	// Corpses are left by deaths when the server's death policy enables them.
+
+	// GetCorpses returns the player's unrecovered corpses, oldest first.
+	GetCorpses(name string) []CorpseInfo
+
+	// GetCorpseOwnersInRoom returns whose corpses lie in a room.
+	GetCorpseOwnersInRoom(roomID string) []string
+
+	// RecoverCorpse moves everything in the player's corpses in their room back
+	// into their inventory and returns it. Errors carry a player-facing message.
+	RecoverCorpse(name string) ([]*items.Item, error)
+
+	// === Guild Methods ===
+
+	// SendGuildMessage delivers a guild chat message to every online member of a guild
+	// except the sender, respecting ignore lists.
+	SendGuildMessage(guildID int64, senderName, message string)
+
+	// === Channel Methods ===
+
+	// SendChannelMessage records a message in the channel's history and delivers it
+	// to every online player listening to the channel except the sender.
+	SendChannelMessage(channelID, senderName, message string)
+
+	// GetChannelHistory returns the recent messages said on a channel, oldest first.
+	GetChannelHistory(channelID string) []channels.Message
+
 	// === Registry Methods ===
 	// These return nil if the corresponding system is not initialized.
 
@@ -161,6 +322,9 @@ type ServerInterface interface {
 	// GetQuestRegistry returns the registry of available quests.
 	GetQuestRegistry() *quest.QuestRegistry
 
+	// GetChannelRegistry returns the registry of chat channels.
+	GetChannelRegistry() *channels.Registry
+
 	// === Tower Methods ===
 
 	// GenerateNextFloor generates the next tower floor and returns the stairs room.
@@ -254,6 +418,18 @@ type PlayerInterface interface {
 	// Messages are queued and sent asynchronously.
 	SendMessage(message string)
 
+	// IsLinkDead returns true if the player's connection dropped and they are
+	// waiting in the world to reconnect.
+	IsLinkDead() bool
+
+	// SendTyped sends a message tagged with its kind (MessageRoom, MessageCombat, ...).
+	// Plain-text clients receive it exactly like SendMessage.
+	SendTyped(kind string, message string)
+
+	// SendChannelGMCP sends a Comm.Channel.Text GMCP package to this player.
+	// Does nothing if the player's client doesn't support GMCP.
+	SendChannelGMCP(channel, talker, text string)
+
 	// Disconnect closes the player's connection gracefully.
 	// Triggers save and cleanup.
 	Disconnect()
@@ -291,6 +467,13 @@ type PlayerInterface interface {
 	// GetExperience returns current experience points toward next level.
 	GetExperience() int
 
+	// GetXPDebt returns the experience the player owes from dying.
+	GetXPDebt() int
+
+	// IsHardcore returns true if the character has one life and may only trade,
+	// give or mail with other hardcore characters.
+	IsHardcore() bool
+
 	// IsAlive returns true if health > 0.
 	IsAlive() bool
 
@@ -304,6 +487,9 @@ type PlayerInterface interface {
 	// Does not check weight limits - use CanCarry() first.
 	AddItem(item *items.Item)
 
+	// GetWornItems returns the equipped and carried items that need repair.
+	GetWornItems() []*items.Item
+
 	// RemoveItem removes an item by exact name (case-insensitive).
 	// Returns the removed item and true, or nil and false if not found.
 	RemoveItem(itemName string) (*items.Item, bool)
@@ -365,6 +551,23 @@ type PlayerInterface interface {
 	// Returns empty string if not in combat.
 	GetCombatTarget() string
 
+	// IsStunned returns true while an NPC ability has the player stunned.
+	// Stunned players can't attack, cast or flee.
+	IsStunned() bool
+
+	// GetStunRemaining returns the seconds left on the player's stun.
+	GetStunRemaining() int
+
+	// Defend braces the player against room-wide attacks for a couple of rounds.
+	// Defending players don't attack.
+	Defend()
+
+	// IsDefending returns true while the player is braced.
+	IsDefending() bool
+
+	// Effects returns the player's status effects (buffs, debuffs, poison, stun).
+	Effects() *effects.Set
+
 	// StartCombat initiates combat with the named NPC.
 	StartCombat(npcName string)
 
@@ -394,6 +597,13 @@ type PlayerInterface interface {
 	// Returns a slice of LevelUpInfo for any levels gained.
 	GainExperience(xp int) []leveling.LevelUpInfo
 
+	// GetLastDeath returns the room the player last died in and when.
+	// Returns an empty room ID if there is no death on record.
+	GetLastDeath() (roomID string, at time.Time)
+
+	// ClearLastDeath forgets the player's last death (after a resurrection).
+	ClearLastDeath()
+
 	// === Spells & Magic ===
 
 	// HasSpell returns true if the player knows the spell.
@@ -539,6 +749,60 @@ type PlayerInterface interface {
 	// GetIgnoreList returns all ignored player names.
 	GetIgnoreList() []string
 
+	// === Guild ===
+
+	// GetGuildID returns the ID of the player's guild (0 if not in one).
+	GetGuildID() int64
+
+	// GetGuildName returns the name of the player's guild.
+	GetGuildName() string
+
+	// GetGuildTag returns the player's guild tag ("" if not in a guild).
+	GetGuildTag() string
+
+	// GetGuildRank returns the player's rank in their guild.
+	GetGuildRank() guild.Rank
+
+	// SetGuild records the player's guild membership.
+	SetGuild(guildID int64, name, tag string, rank guild.Rank)
+
+	// ClearGuild removes the player's guild membership.
+	ClearGuild()
+
+	// SetGuildInvite records a pending guild invite, replacing any earlier one.
+	SetGuildInvite(guildID int64, inviter string)
+
+	// GetGuildInvite returns the pending guild invite and who sent it (0 if none).
+	GetGuildInvite() (guildID int64, inviter string)
+
+	// === Chat Channels ===
+
+	// CanUseChannel returns true if the player's race and level allow them on the channel.
+	CanUseChannel(ch *channels.Channel) bool
+
+	// IsOnChannel returns true if the player is allowed on and has joined the channel.
+	IsOnChannel(ch *channels.Channel) bool
+
+	// IsChannelMuted returns true if the player has muted the channel.
+	IsChannelMuted(ch *channels.Channel) bool
+
+	// JoinChannel puts the player on the channel.
+	JoinChannel(ch *channels.Channel)
+
+	// LeaveChannel takes the player off the channel.
+	LeaveChannel(ch *channels.Channel)
+
+	// SetChannelMuted mutes or unmutes the channel for the player.
+	SetChannelMuted(ch *channels.Channel, muted bool)
+
+	// === Friends ===
+
+	// IsLocationHidden returns true if the player hides their tower and floor from friends lists.
+	IsLocationHidden() bool
+
+	// SetLocationHidden sets whether the player hides their tower and floor from friends lists.
+	SetLocationHidden(hidden bool)
+
 	// === Crafting System ===
 
 	// GetCraftingSkill returns the skill level for a crafting skill.
@@ -604,6 +868,64 @@ type PlayerInterface interface {
 	// Returns an error if the title hasn't been earned.
 	SetActiveTitle(titleID string) error
 
+	// === Prompt ===
+
+	// GetPrompt returns the player's prompt format (the default if unset).
+	GetPrompt() string
+
+	// SetPrompt changes the prompt format. An empty format restores the default.
+	// Returns an error if the format is too long or contains line breaks.
+	SetPrompt(format string) error
+
+	// RenderPrompt expands prompt tokens (%h, %m, %t, ...) for the player's current state.
+	RenderPrompt(format string) string
+
+	// === Color ===
+
+	// IsColorEnabled returns true if the player wants colored output.
+	IsColorEnabled() bool
+
+	// SetColorEnabled turns colored output on or off.
+	SetColorEnabled(enabled bool)
+
+	// RemapColor changes the color used for a markup category.
+	// Returns an error if the category or color is unknown.
+	RemapColor(category, colorName string) error
+
+	// ResetColor restores a category's default color ("" resets all).
+	ResetColor(category string) error
+
+	// GetColorTheme returns the player's remapped categories (category -> color name).
+	GetColorTheme() map[string]string
+
+	// === Input Queue ===
+
+	// ClearInputQueue drops any commands waiting in the player's input queue.
+	// Returns the number of commands dropped.
+	ClearInputQueue() int
+
+	// QueueCommands puts commands at the front of the input queue, so they run next.
+	QueueCommands(commands []string)
+
+	// SplitCommands splits a line into commands using the player's command
+	// separator and speedwalk settings.
+	SplitCommands(line string) []string
+
+	// === Aliases ===
+
+	// GetAlias returns the expansion of one of the player's aliases.
+	GetAlias(name string) (string, bool)
+
+	// SetAlias defines or replaces an alias.
+	// Returns an error if the alias is too long or the player has too many.
+	SetAlias(name, expansion string) error
+
+	// RemoveAlias deletes an alias. Returns false if there was no such alias.
+	RemoveAlias(name string) bool
+
+	// GetAliases returns a copy of the player's aliases (name -> expansion).
+	GetAliases() map[string]string
+
 	// === Labyrinth Exploration ===
 
 	// VisitLabyrinthGate marks a city gate as visited. Returns true if first visit.
@@ -709,6 +1031,10 @@ type PlayerInfo struct {
 	IP        string
 	LoginTime time.Time
 	IsAdmin   bool
+	// Connection traffic
+	BytesRaw   int64 // Bytes written before compression
+	BytesSent  int64 // Bytes sent on the wire
+	Compressed bool  // MCCP2 compression active
 }
 
 // RoomInterface defines the contract for room operations needed by command handlers.
@@ -737,6 +1063,7 @@ type PlayerInfo struct {
 //   - RoomTypeStairs - Connections between floors
 //   - RoomTypeTreasure - Loot rooms (often locked)
 //   - RoomTypeBoss - Boss encounter rooms
+//   - RoomTypeArena - City arenas where duels are ranked
 //
 // # Features
 //
@@ -896,6 +1223,7 @@ type WorldInterface interface {
 type Command struct {
 	Name string
 	Args []string
+	kind string // Output kind for commands not in commandOutputKinds (e.g., channel talk)
 }
 
 // CommandHandler is the function signature for command handlers
@@ -929,6 +1257,7 @@ var commandRegistry = map[string]CommandHandler{
 	"enter":   func(c *Command, p PlayerInterface) string { return executeMoveDirection(c, p, "enter") },
 	"leave":   func(c *Command, p PlayerInterface) string { return executeMoveDirection(c, p, "leave") },
 	"exits":   executeExits,
+	"clear":   executeClear,
 	"portal":  executePortal,
 
 	// Item commands
@@ -962,6 +1291,21 @@ var commandRegistry = map[string]CommandHandler{
 	"report":   executeReport,
 	"ignore":   executeIgnore,
 	"unignore": executeUnignore,
+	"spectate": executeSpectate,
+	"group":    executeGroup,
+	"party":    executeGroup,
+	"gtell":    executeGtell,
+	"gt":       executeGtell,
+	"need":     executeLootRoll,
+	"greed":    executeLootRoll,
+	"pass":     executeLootRoll,
+	"guild":    executeGuild,
+	"gchat":    executeGchat,
+	"gc":       executeGchat,
+	"channels": executeChannels,
+	"channel":  executeChannel,
+	"friend":   executeFriend,
+	"friends":  executeFriend,
 	"quit":  executeQuit,
 	"exit":  executeQuit,
 
@@ -976,28 +1320,52 @@ var commandRegistry = map[string]CommandHandler{
 	"kill":     executeAttack,
 	"hit":      executeAttack,
 	"flee":     executeFlee,
+	"defend":   executeDefend,
 	"consider": executeConsider,
 	"con":      executeConsider,
 
+	// Duel commands
+	"duel":        executeDuel,
+	"leaderboard": executeLeaderboard,
+
 	// Magic commands
-	"cast":   executeCast,
-	"spells": executeSpells,
+	"cast":    executeCast,
+	"spells":  executeSpells,
+	"affects": executeAffects,
+	"aff":     executeAffects,
 
 	// Commerce commands
 	"shop":     executeShop,
 	"list":     executeShop,
 	"buy":      executeBuy,
 	"sell":     executeSell,
+	"repair":   executeRepair,
 	"gold":     executeGold,
 	"money":    executeGold,
 	"wallet":   executeGold,
 	"give":     executeGive,
+	"trade":    executeTrade,
 
 	// Player stall commands
 	"stall":    executeStall,
 	"browse":   executeBrowse,
 	"purchase": executePurchase,
 
+	// Auction house commands
+	"auction": executeAuction,
+	"ah":      executeAuction,
+
+	// Bank vault commands
+	"deposit":  executeDeposit,
+	"withdraw": executeWithdraw,
+	"balance":  executeBank,
+	"bank":     executeBank,
+	"vault":    executeBank,
+
+	// Death commands
+	"corpse":   executeCorpse,
+	"memorial": executeMemorial,
+
 	// Interaction commands
 	"talk":   executeTalk,
 	"speak":  executeTalk,
@@ -1015,6 +1383,9 @@ var commandRegistry = map[string]CommandHandler{
 	"abilities":  executeScore, // Alias for score
 	"attributes": executeScore, // Alias for score
 	"password":   executePassword,
+	"prompt":     executePrompt,
+	"color":      executeColor,
+	"colour":     executeColor,
 	"class":      executeClass,
 	"classes":    executeClass, // Alias for class
 	"race":       executeRace,
@@ -1093,11 +1464,26 @@ func (c *Command) Execute(playerIface interface{}, worldIface interface{}) strin
 		"command", c.Name,
 		"args", strings.Join(c.Args, " "))
 
+	// Player aliases are expanded before the registry lookup
+	if expansion, ok := p.GetAlias(c.Name); ok {
+		return c.executeAliasExpansion(expansion, p, worldIface)
+	}
+
 	// Look up the handler in the registry
 	handler, exists := commandRegistry[c.Name]
 	if !exists {
+		// Channel names from channels.yaml work as commands (e.g., "newbie hello")
+		if ch, ok := findChannelCommand(c.Name, p); ok {
+			c.kind = MessageChat
+			return executeChannelTalk(ch, c, p)
+		}
 		return fmt.Sprintf("Unknown command: %s. Type 'help' for available commands.", c.Name)
 	}
 
+	// Spectators are read-only
+	if isSpectating(p) && !spectatorCommands[c.Name] {
+		return "You are spectating. Type 'spectate stop' to stop watching."
+	}
+
 	return handler(c, p)
 }
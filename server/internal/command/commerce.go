@@ -234,6 +234,68 @@ func executeSell(c *Command, p PlayerInterface) string {
 	return fmt.Sprintf("You sell your %s for %d gold.\nGold: %d", removedItem.Name, sellPrice, p.GetGold())
 }
 
+// executeRepair has a shopkeeper mend worn and broken equipment for gold
+// Usage: repair, repair <item>, repair all
+func executeRepair(c *Command, p PlayerInterface) string {
+	room, ok := p.GetCurrentRoom().(RoomInterface)
+	if !ok {
+		return "Internal error: invalid room type"
+	}
+	shopNPC := findMerchantNPC(room)
+	if shopNPC == nil {
+		return "There is no shop here to repair your gear."
+	}
+
+	worn := p.GetWornItems()
+	if len(worn) == 0 {
+		return "Your gear is in good repair."
+	}
+
+	// No arguments: list what needs fixing and what it costs
+	if len(c.Args) == 0 {
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("%s looks over your gear:\n", shopNPC.GetName()))
+		total := 0
+		for _, item := range worn {
+			sb.WriteString(fmt.Sprintf("  %-30s %-10s %d gold\n", item.Name, item.Condition(), item.RepairCost()))
+			total += item.RepairCost()
+		}
+		sb.WriteString(fmt.Sprintf("\nRepairing everything costs %d gold. Type 'repair <item>' or 'repair all'.", total))
+		return sb.String()
+	}
+
+	toRepair := worn
+	if itemName := c.GetItemName(); !strings.EqualFold(itemName, "all") {
+		item, found := items.FindItem(worn, itemName)
+		if !found {
+			return fmt.Sprintf("You have nothing called '%s' that needs repair.", itemName)
+		}
+		toRepair = []*items.Item{item}
+	}
+
+	cost := 0
+	for _, item := range toRepair {
+		cost += item.RepairCost()
+	}
+	if !p.SpendGold(cost) {
+		return fmt.Sprintf("%s wants %d gold for the work. You only have %d.", shopNPC.GetName(), cost, p.GetGold())
+	}
+
+	names := make([]string, len(toRepair))
+	for i, item := range toRepair {
+		item.Wear = 0
+		names[i] = item.Name
+	}
+
+	logger.Debug("Items repaired",
+		"player", p.GetName(),
+		"items", len(toRepair),
+		"cost", cost,
+		"repairer", shopNPC.GetName())
+
+	return fmt.Sprintf("%s repairs your %s for %d gold.\nGold: %d", shopNPC.GetName(), strings.Join(names, ", "), cost, p.GetGold())
+}
+
 // executeGold shows the player's gold amount
 func executeGold(c *Command, p PlayerInterface) string {
 	return fmt.Sprintf("You have %d gold.", p.GetGold())
@@ -296,6 +358,9 @@ func executeGiveGold(args []string, p PlayerInterface, server ServerInterface, r
 	if target == nil {
 		return fmt.Sprintf("%s is not here.", targetName)
 	}
+	if hardcoreApart(p, target.IsHardcore()) {
+		return "Hardcore characters can only trade with other hardcore characters."
+	}
 
 	// Transfer the gold
 	p.SpendGold(amount)
@@ -361,6 +426,9 @@ func executeGiveItem(args []string, p PlayerInterface, server ServerInterface, r
 	if target == nil {
 		return fmt.Sprintf("%s is not here.", targetName)
 	}
+	if hardcoreApart(p, target.IsHardcore()) {
+		return "Hardcore characters can only trade with other hardcore characters."
+	}
 
 	// Check if target can carry the item
 	if !target.CanCarry(item) {
@@ -0,0 +1,83 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/world"
+)
+
+// executeCorpse finds the player's corpses, or recovers the one they're standing over
+// Usage: corpse, corpse recover
+func executeCorpse(c *Command, p PlayerInterface) string {
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return "Internal error: invalid server type"
+	}
+
+	if len(c.Args) > 0 {
+		switch strings.ToLower(c.Args[0]) {
+		case "recover", "loot", "get", "take":
+			return executeCorpseRecover(p, server)
+		default:
+			return "Usage: corpse [recover]"
+		}
+	}
+
+	corpses := server.GetCorpses(p.GetName())
+	if len(corpses) == 0 {
+		return "You have no corpse waiting to be recovered."
+	}
+
+	w, _ := server.GetWorld().(*world.World)
+	var sb strings.Builder
+	sb.WriteString("Your corpses:\n")
+	standingOver := false
+	for _, corpse := range corpses {
+		where := "somewhere unknown"
+		if w != nil {
+			if room := w.GetRoom(corpse.RoomID); room != nil {
+				where = fmt.Sprintf("{room}%s{/} (%s)", room.Name, describeRoomLocation(room, server))
+			}
+		}
+		if corpse.RoomID == p.GetRoomID() {
+			standingOver = true
+			where += " - {heal}here{/}"
+		}
+		sb.WriteString(fmt.Sprintf("  %s, %d items, decays in %s\n", where, corpse.Items, formatCorpseTime(corpse.Remaining)))
+	}
+	if standingOver {
+		sb.WriteString("\nType 'corpse recover' to take your belongings back.")
+	} else {
+		sb.WriteString("\nReturn to where you fell and type 'corpse recover' to take your belongings back.")
+	}
+	return sb.String()
+}
+
+// executeCorpseRecover gives the player back what they left in their corpse
+func executeCorpseRecover(p PlayerInterface, server ServerInterface) string {
+	recovered, err := server.RecoverCorpse(p.GetName())
+	if err != nil {
+		return err.Error()
+	}
+
+	server.BroadcastToRoom(p.GetRoomID(), fmt.Sprintf("%s recovers their belongings from their corpse.\n", p.GetName()), p)
+	names := make([]string, len(recovered))
+	for i, item := range recovered {
+		names[i] = item.Name
+	}
+	return fmt.Sprintf("You recover your belongings from your corpse: {item}%s{/}.", strings.Join(names, ", "))
+}
+
+// formatCorpseTime shows how long a corpse has left, e.g. "12m30s"
+func formatCorpseTime(d time.Duration) string {
+	if d < time.Second {
+		return "moments"
+	}
+	d = d.Round(time.Second)
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
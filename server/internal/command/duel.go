@@ -0,0 +1,178 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/database"
+	"github.com/lawnchairsociety/opentowermud/server/internal/duel"
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+)
+
+// leaderboardSize is how many duelists the leaderboard shows.
+const leaderboardSize = 10
+
+// executeDuel handles the duel command and its subcommands
+func executeDuel(c *Command, p PlayerInterface) string {
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return "Internal error: invalid server type"
+	}
+
+	if len(c.Args) == 0 {
+		if opponent, dueling := server.GetDuelOpponent(p.GetName()); dueling {
+			return fmt.Sprintf("You are dueling {player}%s{/}. Type 'duel yield' to give up.", opponent)
+		}
+		return executeDuelHelp()
+	}
+
+	subcommand := strings.ToLower(c.Args[0])
+	switch subcommand {
+	case "accept":
+		challenger, err := server.AcceptDuel(p.GetName())
+		if err != nil {
+			return err.Error()
+		}
+		return fmt.Sprintf("You accept {player}%s{/}'s challenge. Fight!", challenger)
+	case "decline", "refuse":
+		challenger, err := server.DeclineDuel(p.GetName())
+		if err != nil {
+			return err.Error()
+		}
+		return fmt.Sprintf("You decline {player}%s{/}'s challenge.", challenger)
+	case "yield", "forfeit", "surrender":
+		return executeDuelYield(p, server)
+	case "rating", "rank":
+		return executeDuelRating(strings.Join(c.Args[1:], " "), p, server)
+	case "help":
+		return executeDuelHelp()
+	default:
+		if len(c.Args) > 1 {
+			return "Usage: duel <player>"
+		}
+		target := c.Args[0]
+		started, err := server.ChallengeDuel(p.GetName(), target)
+		if err != nil {
+			return err.Error()
+		}
+		if started {
+			opponent, _ := server.GetDuelOpponent(p.GetName())
+			return fmt.Sprintf("You accept {player}%s{/}'s challenge. Fight!", opponent)
+		}
+		return fmt.Sprintf("You challenge %s to a duel.", target)
+	}
+}
+
+// executeDuelHelp shows the duel command help
+func executeDuelHelp() string {
+	return `=== Duel Commands ===
+Test yourself against another player. Nobody dies in a duel.
+
+Commands:
+  duel <player>         - Challenge a player in the same room to a duel
+  duel accept           - Accept the challenge made to you
+  duel decline          - Refuse the challenge made to you
+  duel yield            - Give up your duel (also: flee)
+  duel rating [player]  - Show your ranked duel record, or another player's
+  leaderboard           - Show the highest rated duelists
+
+Notes:
+  - A duel ends when a fighter is beaten down to 1 HP
+  - Leaving the room or the game forfeits the duel
+  - Duels fought in a city arena are ranked and change both fighters' ratings`
+}
+
+// executeDuelYield gives up the player's duel
+func executeDuelYield(p PlayerInterface, server ServerInterface) string {
+	opponent, _ := server.GetDuelOpponent(p.GetName())
+	if err := server.YieldDuel(p.GetName()); err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("You yield to {player}%s{/}.", opponent)
+}
+
+// executeDuelRating shows a character's ranked duel record
+func executeDuelRating(name string, p PlayerInterface, server ServerInterface) string {
+	db, ok := server.GetDatabase().(*database.Database)
+	if !ok {
+		return "Internal error: database not available"
+	}
+
+	characterID := p.GetCharacterID()
+	if name != "" {
+		char, err := db.GetCharacterByName(name)
+		if errors.Is(err, database.ErrCharacterNotFound) {
+			return fmt.Sprintf("There is no player named '%s'.", name)
+		}
+		if err != nil {
+			logger.Error("Failed to look up duelist", "error", err, "player", p.GetName(), "target", name)
+			return "Failed to load that duel rating."
+		}
+		characterID = char.ID
+	}
+
+	r, err := db.GetDuelRating(characterID)
+	if err != nil {
+		logger.Error("Failed to get duel rating", "error", err, "player", p.GetName())
+		return "Failed to load that duel rating."
+	}
+	if r.Matches() == 0 {
+		return fmt.Sprintf("{player}%s{/} hasn't fought a ranked duel yet (rating %d).", r.Name, r.Rating)
+	}
+	return fmt.Sprintf("{player}%s{/}: rating {gold}%d{/} (%d won, %d lost)", r.Name, r.Rating, r.Wins, r.Losses)
+}
+
+// executeLeaderboard shows the highest rated duelists, or the hardcore leaderboard
+// Usage: leaderboard [duel|hardcore]
+func executeLeaderboard(c *Command, p PlayerInterface) string {
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return "Internal error: invalid server type"
+	}
+	db, ok := server.GetDatabase().(*database.Database)
+	if !ok {
+		return "Internal error: database not available"
+	}
+
+	if len(c.Args) > 0 {
+		switch strings.ToLower(c.Args[0]) {
+		case "hardcore", "hc":
+			return executeHardcoreLeaderboard(p, db)
+		case "duel", "duels":
+		default:
+			return "Usage: leaderboard [duel|hardcore]"
+		}
+	}
+
+	ratings, err := db.GetDuelLeaderboard(leaderboardSize)
+	if err != nil {
+		logger.Error("Failed to get duel leaderboard", "error", err)
+		return "Failed to load the leaderboard."
+	}
+	if len(ratings) == 0 {
+		return "No ranked duels have been fought yet. Challenge someone in a city arena!"
+	}
+
+	var result strings.Builder
+	result.WriteString("\n=== Duel Leaderboard ===\n")
+	result.WriteString(fmt.Sprintf("  %-4s %-20s %6s %5s %6s\n", "Rank", "Name", "Rating", "Won", "Lost"))
+	for i, r := range ratings {
+		result.WriteString(fmt.Sprintf("  %-4d {player}%-20s{/} %6d %5d %6d\n", i+1, r.Name, r.Rating, r.Wins, r.Losses))
+	}
+
+	if mine, err := db.GetDuelRating(p.GetCharacterID()); err == nil && !onLeaderboard(ratings, mine) {
+		result.WriteString(fmt.Sprintf("\nYour rating: %d (%d won, %d lost)\n", mine.Rating, mine.Wins, mine.Losses))
+	}
+	return result.String()
+}
+
+// onLeaderboard returns true if the rating is one of the listed ones
+func onLeaderboard(ratings []duel.Rating, r *duel.Rating) bool {
+	for _, listed := range ratings {
+		if listed.CharacterID == r.CharacterID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/database"
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+	"github.com/lawnchairsociety/opentowermud/server/internal/tower"
+)
+
+// executeFriend handles the friend command and its subcommands
+func executeFriend(c *Command, p PlayerInterface) string {
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return "Internal error: invalid server type"
+	}
+	db, ok := server.GetDatabase().(*database.Database)
+	if !ok {
+		return "Internal error: database not available"
+	}
+
+	if len(c.Args) == 0 {
+		return executeFriendList(p, server, db)
+	}
+
+	subcommand := strings.ToLower(c.Args[0])
+	target := strings.Join(c.Args[1:], " ")
+	switch subcommand {
+	case "list":
+		return executeFriendList(p, server, db)
+	case "add":
+		if target == "" {
+			return "Usage: friend add <player>"
+		}
+		return executeFriendAdd(target, p, db)
+	case "remove", "delete":
+		if target == "" {
+			return "Usage: friend remove <player>"
+		}
+		return executeFriendRemove(target, p, db)
+	case "privacy":
+		return executeFriendPrivacy(target, p, server)
+	case "help":
+		return executeFriendHelp()
+	default:
+		return fmt.Sprintf("Unknown friend command: %s\n%s", subcommand, executeFriendHelp())
+	}
+}
+
+// executeFriendHelp shows the friend command help
+func executeFriendHelp() string {
+	return fmt.Sprintf(`=== Friend Commands ===
+Keep track of the players you adventure with.
+
+Commands:
+  friend                    - Show your friends list (also: friend list)
+  friend add <player>       - Add a player to your friends list
+  friend remove <player>    - Take a player off your friends list
+  friend privacy on|off     - Hide or show your location on other players' lists
+
+Notes:
+  - You're told when a friend logs in or out
+  - Friends lists hold up to %d players`, database.MaxFriends)
+}
+
+// executeFriendList shows which friends are online and where, and when the
+// others were last seen
+func executeFriendList(p PlayerInterface, server ServerInterface, db *database.Database) string {
+	friends, err := db.GetFriends(p.GetCharacterID())
+	if err != nil {
+		logger.Error("Failed to get friends", "error", err, "player", p.GetName())
+		return "Failed to load your friends list."
+	}
+	if len(friends) == 0 {
+		return "Your friends list is empty. Type 'friend add <player>' to add someone."
+	}
+
+	var online, offline strings.Builder
+	onlineCount := 0
+	for _, f := range friends {
+		friend, ok := server.FindPlayer(f.Name).(PlayerInterface)
+		if !ok {
+			lastSeen := "never"
+			if f.LastPlayed != nil {
+				lastSeen = formatMailTime(*f.LastPlayed)
+			}
+			offline.WriteString(fmt.Sprintf("  {player}%-20s{/} last seen %s\n", f.Name, lastSeen))
+			continue
+		}
+
+		onlineCount++
+		location := "location hidden"
+		if !friend.IsLocationHidden() {
+			location = describeFriendLocation(friend, server)
+		}
+		entry := fmt.Sprintf("  {player}%-20s{/} %s", friend.GetName(), location)
+		if friend.IsLinkDead() {
+			entry += " [link-dead]"
+		}
+		online.WriteString(entry + "\n")
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("\n=== Friends (%d/%d) ===\n", len(friends), database.MaxFriends))
+	result.WriteString(fmt.Sprintf("\nOnline (%d):\n", onlineCount))
+	if onlineCount == 0 {
+		result.WriteString("  No friends are online.\n")
+	} else {
+		result.WriteString(online.String())
+	}
+	if onlineCount < len(friends) {
+		result.WriteString(fmt.Sprintf("\nOffline (%d):\n", len(friends)-onlineCount))
+		result.WriteString(offline.String())
+	}
+	return result.String()
+}
+
+// describeFriendLocation returns the tower and floor an online friend is on
+func describeFriendLocation(friend PlayerInterface, server ServerInterface) string {
+	room, ok := friend.GetCurrentRoom().(RoomInterface)
+	if !ok || room == nil {
+		return "somewhere unknown"
+	}
+	return describeRoomLocation(room, server)
+}
+
+// describeRoomLocation returns the city, or tower and floor, a room is in
+func describeRoomLocation(room RoomInterface, server ServerInterface) string {
+	towerID := getTowerFromRoomID(room.GetID())
+	if towerID == "" {
+		// City rooms don't carry the tower in their ID
+		if towerMgr, ok := server.GetTowerManager().(*tower.TowerManager); ok && towerMgr != nil {
+			if _, id := towerMgr.FindRoom(room.GetID()); id != "" {
+				towerID = string(id)
+			}
+		}
+	}
+	if towerID == "" {
+		return "somewhere unknown"
+	}
+
+	if room.GetFloor() == 0 {
+		return getCityDisplayName(towerID)
+	}
+	if tower.TowerID(towerID) == tower.TowerUnified {
+		return fmt.Sprintf("%s, %s", getTowerDisplayName(towerID), getUnifiedFloorDisplayName(room.GetFloor()))
+	}
+	return fmt.Sprintf("%s, %s", getTowerDisplayName(towerID), getFloorDisplayName(room.GetFloor()))
+}
+
+// executeFriendAdd puts a player on the friends list
+func executeFriendAdd(name string, p PlayerInterface, db *database.Database) string {
+	char, err := db.GetCharacterByName(name)
+	if errors.Is(err, database.ErrCharacterNotFound) {
+		return fmt.Sprintf("There is no player named '%s'.", name)
+	}
+	if err != nil {
+		logger.Error("Failed to look up friend", "error", err, "player", p.GetName(), "friend", name)
+		return "Failed to add that friend."
+	}
+	if char.ID == p.GetCharacterID() {
+		return "You can't add yourself as a friend."
+	}
+
+	added, err := db.AddFriend(p.GetCharacterID(), char.ID)
+	if errors.Is(err, database.ErrFriendsListFull) {
+		return fmt.Sprintf("Your friends list is full (%d players).", database.MaxFriends)
+	}
+	if err != nil {
+		logger.Error("Failed to add friend", "error", err, "player", p.GetName(), "friend", char.Name)
+		return "Failed to add that friend."
+	}
+	if !added {
+		return fmt.Sprintf("%s is already on your friends list.", char.Name)
+	}
+
+	logger.Info("Friend added", "player", p.GetName(), "friend", char.Name)
+	return fmt.Sprintf("You add {player}%s{/} to your friends list.", char.Name)
+}
+
+// executeFriendRemove takes a player off the friends list
+func executeFriendRemove(name string, p PlayerInterface, db *database.Database) string {
+	char, err := db.GetCharacterByName(name)
+	if errors.Is(err, database.ErrCharacterNotFound) {
+		return fmt.Sprintf("%s isn't on your friends list.", name)
+	}
+	if err != nil {
+		logger.Error("Failed to look up friend", "error", err, "player", p.GetName(), "friend", name)
+		return "Failed to remove that friend."
+	}
+
+	removed, err := db.RemoveFriend(p.GetCharacterID(), char.ID)
+	if err != nil {
+		logger.Error("Failed to remove friend", "error", err, "player", p.GetName(), "friend", char.Name)
+		return "Failed to remove that friend."
+	}
+	if !removed {
+		return fmt.Sprintf("%s isn't on your friends list.", char.Name)
+	}
+
+	logger.Info("Friend removed", "player", p.GetName(), "friend", char.Name)
+	return fmt.Sprintf("You remove {player}%s{/} from your friends list.", char.Name)
+}
+
+// executeFriendPrivacy hides or shows the player's location on other players' friends lists
+func executeFriendPrivacy(setting string, p PlayerInterface, server ServerInterface) string {
+	switch strings.ToLower(setting) {
+	case "":
+		if p.IsLocationHidden() {
+			return "Your location is hidden from friends lists. Type 'friend privacy off' to show it."
+		}
+		return "Your location is shown on friends lists. Type 'friend privacy on' to hide it."
+	case "on":
+		p.SetLocationHidden(true)
+	case "off":
+		p.SetLocationHidden(false)
+	default:
+		return "Usage: friend privacy on|off"
+	}
+
+	if saveErr := server.SavePlayer(p); saveErr != nil {
+		logger.Warning("Failed to save player after privacy change", "player", p.GetName(), "error", saveErr)
+	}
+	if p.IsLocationHidden() {
+		return "Your location is now hidden from friends lists."
+	}
+	return "Your location is now shown on friends lists."
+}
@@ -0,0 +1,236 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/color"
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+)
+
+// Party loot modes.
+const (
+	LootFree       = "free"       // Drops land on the floor for anyone to take
+	LootRoundRobin = "roundrobin" // Each drop goes to the next member in turn
+	LootNeedGreed  = "needgreed"  // Members roll need or greed on each drop
+)
+
+// Need/greed roll choices.
+const (
+	LootNeed  = "need"
+	LootGreed = "greed"
+	LootPass  = "pass"
+)
+
+// PartyInfo describes a player's party.
+type PartyInfo struct {
+	Leader   string
+	Members  []string // In join order, leader included
+	LootMode string
+}
+
+// IsLootMode returns true if mode is a known party loot mode.
+func IsLootMode(mode string) bool {
+	return mode == LootFree || mode == LootRoundRobin || mode == LootNeedGreed
+}
+
+// LootModeDescription returns a short player-facing description of a loot mode.
+func LootModeDescription(mode string) string {
+	switch mode {
+	case LootRoundRobin:
+		return "round robin (drops go to each member in turn)"
+	case LootNeedGreed:
+		return "need/greed (members roll on each drop)"
+	default:
+		return "free for all (drops land on the floor)"
+	}
+}
+
+// executeGroup handles the group command and its subcommands
+func executeGroup(c *Command, p PlayerInterface) string {
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return "Internal error: invalid server type"
+	}
+
+	if len(c.Args) == 0 {
+		return showGroup(p, server)
+	}
+
+	sub := strings.ToLower(c.Args[0])
+	arg := strings.Join(c.Args[1:], " ")
+
+	switch sub {
+	case "invite":
+		if arg == "" {
+			return "Usage: group invite <player>"
+		}
+		if err := server.InviteToParty(p.GetName(), arg); err != nil {
+			return err.Error()
+		}
+		return fmt.Sprintf("You invite %s to join your group.", arg)
+
+	case "accept", "join":
+		if err := server.AcceptPartyInvite(p.GetName()); err != nil {
+			return err.Error()
+		}
+		info, _ := server.GetPartyInfo(p.GetName())
+		return fmt.Sprintf("You join %s's group.", info.Leader)
+
+	case "decline":
+		inviter, err := server.DeclinePartyInvite(p.GetName())
+		if err != nil {
+			return err.Error()
+		}
+		return fmt.Sprintf("You decline %s's invitation.", inviter)
+
+	case "leave", "quit":
+		if err := server.LeaveParty(p.GetName()); err != nil {
+			return err.Error()
+		}
+		return "You leave the group."
+
+	case "kick", "remove":
+		if arg == "" {
+			return "Usage: group kick <player>"
+		}
+		if err := server.KickFromParty(p.GetName(), arg); err != nil {
+			return err.Error()
+		}
+		return fmt.Sprintf("You remove %s from the group.", arg)
+
+	case "leader", "promote":
+		if arg == "" {
+			return "Usage: group leader <player>"
+		}
+		if err := server.SetPartyLeader(p.GetName(), arg); err != nil {
+			return err.Error()
+		}
+		return fmt.Sprintf("You make %s the group leader.", arg)
+
+	case "disband":
+		if err := server.DisbandParty(p.GetName()); err != nil {
+			return err.Error()
+		}
+		return "You disband the group."
+
+	case "loot":
+		if arg == "" {
+			info, ok := server.GetPartyInfo(p.GetName())
+			if !ok {
+				return "You aren't in a group."
+			}
+			return fmt.Sprintf("Group loot is %s.\nUsage: group loot <%s|%s|%s>", LootModeDescription(info.LootMode), LootFree, LootRoundRobin, LootNeedGreed)
+		}
+		mode := strings.ToLower(strings.ReplaceAll(arg, " ", ""))
+		if err := server.SetPartyLootMode(p.GetName(), mode); err != nil {
+			return err.Error()
+		}
+		return fmt.Sprintf("Group loot is now %s.", LootModeDescription(mode))
+
+	default:
+		return "Usage: group [invite|accept|decline|leave|kick|leader|disband|loot] [player]"
+	}
+}
+
+// showGroup lists the members of the player's party
+func showGroup(p PlayerInterface, server ServerInterface) string {
+	info, ok := server.GetPartyInfo(p.GetName())
+	if !ok {
+		return "You aren't in a group. Use 'group invite <player>' to start one."
+	}
+
+	room, _ := GetRoom(p)
+
+	var sb strings.Builder
+	sb.WriteString("=== Your Group ===\n\n")
+	for _, name := range info.Members {
+		member, ok := server.FindPlayer(name).(PlayerInterface)
+		if !ok {
+			continue
+		}
+
+		marker := " "
+		if strings.EqualFold(name, info.Leader) {
+			marker = "*"
+		}
+
+		where := "elsewhere"
+		if member.IsLinkDead() {
+			where = "link-dead"
+		} else if memberRoom, ok := GetRoom(member); ok && room != nil {
+			if memberRoom.GetID() == room.GetID() {
+				where = "here"
+			} else if memberRoom.GetFloor() == room.GetFloor() {
+				where = "same floor"
+			}
+		}
+
+		sb.WriteString(fmt.Sprintf("%s {player}%-15s{/} Lvl %-2d  HP %d/%d  MP %d/%d  (%s)\n",
+			marker, member.GetName(), member.GetLevel(),
+			member.GetHealth(), member.GetMaxHealth(), member.GetMana(), member.GetMaxMana(), where))
+	}
+	sb.WriteString(fmt.Sprintf("\n* = leader. Loot: %s.", LootModeDescription(info.LootMode)))
+	return sb.String()
+}
+
+// executeGtell sends a message to everyone in the player's group
+func executeGtell(c *Command, p PlayerInterface) string {
+	if err := c.RequireArgs(1, "Usage: gtell <message>"); err != nil {
+		return err.Error()
+	}
+
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return "Internal error: invalid server type"
+	}
+
+	message := c.GetItemName()
+	if allowed, reason := p.CheckChatSpam(message); !allowed {
+		return reason
+	}
+
+	// Apply chat filter if enabled
+	if filter := server.GetChatFilter(); filter != nil && filter.IsEnabled() {
+		result := filter.Check(message)
+		if result.Violated {
+			logger.Always("CHAT_FILTER",
+				"player", p.GetName(),
+				"command", "gtell",
+				"original", message,
+				"matched", strings.Join(result.MatchedWords, ", "),
+				"mode", string(filter.Mode()))
+
+			if filter.IsBlockMode() {
+				return "Your message contains inappropriate language and was not sent."
+			}
+			message = result.Filtered
+		}
+	}
+
+	if err := server.SendPartyMessage(p.GetName(), message); err != nil {
+		return err.Error()
+	}
+	p.SendChannelGMCP("group", p.GetName(), message)
+
+	// AUDIT LOG - Always logged regardless of log level (security/moderation)
+	logger.Always("CHAT_GTELL",
+		"sender", p.GetName(),
+		"message", message)
+
+	return fmt.Sprintf("{group}You tell the group: \"%s\"{/}", color.Escape(message))
+}
+
+// executeLootRoll answers open need/greed rolls (need, greed or pass)
+func executeLootRoll(c *Command, p PlayerInterface) string {
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return "Internal error: invalid server type"
+	}
+
+	result, err := server.RollOnLoot(p.GetName(), c.Name)
+	if err != nil {
+		return err.Error()
+	}
+	return result
+}
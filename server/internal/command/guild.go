@@ -0,0 +1,813 @@
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/color"
+	"github.com/lawnchairsociety/opentowermud/server/internal/database"
+	"github.com/lawnchairsociety/opentowermud/server/internal/guild"
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+)
+
+// executeGuild handles the guild command and its subcommands.
+func executeGuild(c *Command, p PlayerInterface) string {
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return "Internal error: invalid server type"
+	}
+
+	db, ok := server.GetDatabase().(*database.Database)
+	if !ok {
+		return "Internal error: database not available"
+	}
+
+	if len(c.Args) == 0 {
+		return executeGuildInfo(p, db)
+	}
+
+	sub := strings.ToLower(c.Args[0])
+	args := c.Args[1:]
+	arg := strings.Join(args, " ")
+
+	switch sub {
+	case "create", "found":
+		if len(args) < 2 {
+			return fmt.Sprintf("Usage: guild create <tag> <name>  (costs %d gold)", guild.FoundingCost)
+		}
+		return executeGuildCreate(args[0], strings.Join(args[1:], " "), p, db)
+	case "roster", "members", "list":
+		return executeGuildRoster(p, server, db)
+	case "invite":
+		if arg == "" {
+			return "Usage: guild invite <player>"
+		}
+		return executeGuildInvite(arg, p, server, db)
+	case "accept", "join":
+		return executeGuildAccept(p, server, db)
+	case "decline":
+		return executeGuildDecline(p, server)
+	case "leave", "quit":
+		return executeGuildLeave(p, server, db)
+	case "kick", "remove":
+		if arg == "" {
+			return "Usage: guild kick <player>"
+		}
+		return executeGuildKick(arg, p, server, db)
+	case "promote", "demote":
+		if arg == "" {
+			return fmt.Sprintf("Usage: guild %s <player>", sub)
+		}
+		return executeGuildSetRank(arg, sub == "promote", p, server, db)
+	case "transfer":
+		if arg == "" {
+			return "Usage: guild transfer <player>"
+		}
+		return executeGuildTransfer(arg, p, server, db)
+	case "disband":
+		return executeGuildDisband(p, server, db)
+	case "bank":
+		return executeGuildBank(p, server, db)
+	case "deposit":
+		if arg == "" {
+			return "Usage: guild deposit <amount> gold | guild deposit <item>"
+		}
+		return executeGuildDeposit(args, p, server, db)
+	case "withdraw":
+		if arg == "" {
+			return "Usage: guild withdraw <amount> gold | guild withdraw <number|item>"
+		}
+		return executeGuildWithdraw(args, p, server, db)
+	default:
+		return "Unknown guild command. Use: guild, guild create, guild roster, guild invite, guild accept, guild decline, guild leave, guild kick, guild promote, guild demote, guild transfer, guild disband, guild bank, guild deposit, guild withdraw"
+	}
+}
+
+// loadGuildMembership reads the player's guild from the database (the source of
+// truth for ranks) and refreshes the copy cached on the player.
+// Returns a player-facing message if they aren't in a guild or the lookup failed.
+func loadGuildMembership(p PlayerInterface, db *database.Database) (*guild.Guild, *guild.Member, string) {
+	g, m, err := db.GetGuildMembership(p.GetCharacterID())
+	if err != nil {
+		logger.Error("Failed to get guild membership", "error", err, "player", p.GetName())
+		return nil, nil, "Failed to look up your guild."
+	}
+	if g == nil {
+		p.ClearGuild()
+		return nil, nil, "You aren't in a guild."
+	}
+	p.SetGuild(g.ID, g.Name, g.Tag, m.Rank)
+	return g, m, ""
+}
+
+// findRosterMember finds a guild member by name (case-insensitive).
+func findRosterMember(members []guild.Member, name string) (guild.Member, bool) {
+	for _, m := range members {
+		if strings.EqualFold(m.CharacterName, name) {
+			return m, true
+		}
+	}
+	return guild.Member{}, false
+}
+
+// findOnlinePlayerExact finds an online player by exact name (case-insensitive).
+// Unlike FindPlayer it never falls back to a prefix match, so an offline member
+// isn't confused with someone whose name starts the same way.
+func findOnlinePlayerExact(server ServerInterface, name string) (PlayerInterface, bool) {
+	target, ok := server.FindPlayer(name).(PlayerInterface)
+	if !ok || !strings.EqualFold(target.GetName(), name) {
+		return nil, false
+	}
+	return target, true
+}
+
+// notifyGuild sends a guild notice to every online member except exclude.
+func notifyGuild(server ServerInterface, db *database.Database, g *guild.Guild, message string, exclude string) {
+	members, err := db.GetGuildMembers(g.ID)
+	if err != nil {
+		logger.Error("Failed to get guild members", "error", err, "guild", g.Name)
+		return
+	}
+	for _, m := range members {
+		if strings.EqualFold(m.CharacterName, exclude) {
+			continue
+		}
+		if member, ok := findOnlinePlayerExact(server, m.CharacterName); ok {
+			member.SendMessage(fmt.Sprintf("\n{guild}[%s]{/} %s\n", g.Tag, message))
+		}
+	}
+}
+
+// executeGuildInfo shows the player's guild, or how to join one.
+func executeGuildInfo(p PlayerInterface, db *database.Database) string {
+	g, m, err := db.GetGuildMembership(p.GetCharacterID())
+	if err != nil {
+		logger.Error("Failed to get guild membership", "error", err, "player", p.GetName())
+		return "Failed to look up your guild."
+	}
+	if g == nil {
+		p.ClearGuild()
+		if inviteID, inviter := p.GetGuildInvite(); inviteID != 0 {
+			return fmt.Sprintf("You aren't in a guild. %s has invited you to theirs - type 'guild accept' or 'guild decline'.", inviter)
+		}
+		return fmt.Sprintf("You aren't in a guild. Ask an officer for an invite, or found your own with 'guild create <tag> <name>' (%d gold).", guild.FoundingCost)
+	}
+	p.SetGuild(g.ID, g.Name, g.Tag, m.Rank)
+
+	members, err := db.GetGuildMembers(g.ID)
+	if err != nil {
+		logger.Error("Failed to get guild members", "error", err, "guild", g.Name)
+		return "Failed to look up your guild."
+	}
+	bankItems, err := db.GetGuildBankItems(g.ID)
+	if err != nil {
+		logger.Error("Failed to get guild bank", "error", err, "guild", g.Name)
+		return "Failed to look up your guild."
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("\n=== %s [%s] ===\n", g.Name, g.Tag))
+	result.WriteString(fmt.Sprintf("Your rank:  %s\n", m.Rank.Title()))
+	result.WriteString(fmt.Sprintf("Members:    %d\n", len(members)))
+	result.WriteString(fmt.Sprintf("Guild bank: {gold}%d{/} gold, %d item(s)\n", g.BankGold, len(bankItems)))
+	result.WriteString("\nType 'guild roster' to see the members, 'gchat <message>' to talk to the guild.")
+	return result.String()
+}
+
+// executeGuildCreate founds a new guild with the player as founder.
+func executeGuildCreate(tag, name string, p PlayerInterface, db *database.Database) string {
+	tag = strings.ToUpper(tag)
+	if err := guild.ValidateTag(tag); err != nil {
+		return err.Error()
+	}
+	if err := guild.ValidateName(name); err != nil {
+		return err.Error()
+	}
+
+	if g, _, err := db.GetGuildMembership(p.GetCharacterID()); err != nil {
+		logger.Error("Failed to get guild membership", "error", err, "player", p.GetName())
+		return "Failed to look up your guild."
+	} else if g != nil {
+		return fmt.Sprintf("You are already a member of %s. Leave it first.", g.Name)
+	}
+
+	if p.GetGold() < guild.FoundingCost {
+		return fmt.Sprintf("Founding a guild costs %d gold. You have %d.", guild.FoundingCost, p.GetGold())
+	}
+
+	guildID, err := db.CreateGuild(name, tag, p.GetCharacterID(), p.GetName())
+	if err == database.ErrGuildExists {
+		return "That guild name or tag is already taken."
+	}
+	if err != nil {
+		logger.Error("Failed to create guild", "error", err, "player", p.GetName(), "guild", name)
+		return "Failed to found the guild."
+	}
+
+	p.SpendGold(guild.FoundingCost)
+	p.SetGuild(guildID, name, tag, guild.RankFounder)
+
+	logger.Info("Guild founded", "guild", name, "tag", tag, "founder", p.GetName())
+
+	return fmt.Sprintf("You pay %d gold and found %s [%s]!\nInvite members with 'guild invite <player>'.", guild.FoundingCost, name, tag)
+}
+
+// executeGuildRoster lists the members of the player's guild.
+func executeGuildRoster(p PlayerInterface, server ServerInterface, db *database.Database) string {
+	g, _, msg := loadGuildMembership(p, db)
+	if g == nil {
+		return msg
+	}
+
+	members, err := db.GetGuildMembers(g.ID)
+	if err != nil {
+		logger.Error("Failed to get guild members", "error", err, "guild", g.Name)
+		return "Failed to retrieve the roster."
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("\n=== %s [%s] Roster ===\n", g.Name, g.Tag))
+	online := 0
+	for _, m := range members {
+		status := ""
+		if _, ok := findOnlinePlayerExact(server, m.CharacterName); ok {
+			status = "online"
+			online++
+		}
+		result.WriteString(fmt.Sprintf("  %-8s {player}%-15s{/} %s\n", m.Rank.Title(), m.CharacterName, status))
+	}
+	result.WriteString(fmt.Sprintf("\n%d member(s), %d online.", len(members), online))
+	return result.String()
+}
+
+// executeGuildInvite invites an online player to join the guild.
+func executeGuildInvite(targetName string, p PlayerInterface, server ServerInterface, db *database.Database) string {
+	g, m, msg := loadGuildMembership(p, db)
+	if g == nil {
+		return msg
+	}
+	if !m.Rank.CanInvite() {
+		return "Only officers and the founder can invite new members."
+	}
+
+	target, ok := findOnlinePlayerExact(server, targetName)
+	if !ok {
+		return fmt.Sprintf("Player '%s' is not online.", targetName)
+	}
+	if target.GetCharacterID() == p.GetCharacterID() {
+		return "You are already in the guild."
+	}
+
+	if tg, _, err := db.GetGuildMembership(target.GetCharacterID()); err != nil {
+		logger.Error("Failed to get guild membership", "error", err, "player", target.GetName())
+		return "Failed to look up that player's guild."
+	} else if tg != nil {
+		return fmt.Sprintf("%s is already a member of %s.", target.GetName(), tg.Name)
+	}
+
+	members, err := db.GetGuildMembers(g.ID)
+	if err != nil {
+		logger.Error("Failed to get guild members", "error", err, "guild", g.Name)
+		return "Failed to check the roster."
+	}
+	if len(members) >= guild.MaxMembers {
+		return fmt.Sprintf("Your guild is full (%d members).", guild.MaxMembers)
+	}
+
+	target.SetGuildInvite(g.ID, p.GetName())
+
+	// Don't reveal an ignore - the invite just never arrives
+	if !target.IsIgnoring(p.GetName()) {
+		target.SendMessage(fmt.Sprintf("\n{player}%s{/} invites you to join the guild %s [%s]. Type 'guild accept' or 'guild decline'.\n", p.GetName(), g.Name, g.Tag))
+	}
+
+	return fmt.Sprintf("You invite %s to join %s.", target.GetName(), g.Name)
+}
+
+// executeGuildAccept joins the guild the player was invited to.
+func executeGuildAccept(p PlayerInterface, server ServerInterface, db *database.Database) string {
+	inviteID, _ := p.GetGuildInvite()
+	if inviteID == 0 {
+		return "You haven't been invited to a guild."
+	}
+	p.SetGuildInvite(0, "")
+
+	g, err := db.GetGuild(inviteID)
+	if err != nil {
+		logger.Error("Failed to get guild", "error", err, "guild_id", inviteID)
+		return "Failed to look up the guild."
+	}
+	if g == nil {
+		return "That guild no longer exists."
+	}
+
+	members, err := db.GetGuildMembers(g.ID)
+	if err != nil {
+		logger.Error("Failed to get guild members", "error", err, "guild", g.Name)
+		return "Failed to join the guild."
+	}
+	if len(members) >= guild.MaxMembers {
+		return fmt.Sprintf("%s is full.", g.Name)
+	}
+
+	err = db.AddGuildMember(g.ID, p.GetCharacterID(), p.GetName(), guild.RankMember)
+	if err == database.ErrAlreadyInGuild {
+		return "You are already in a guild. Leave it first."
+	}
+	if err != nil {
+		logger.Error("Failed to add guild member", "error", err, "player", p.GetName(), "guild", g.Name)
+		return "Failed to join the guild."
+	}
+
+	p.SetGuild(g.ID, g.Name, g.Tag, guild.RankMember)
+	notifyGuild(server, db, g, fmt.Sprintf("{player}%s{/} has joined the guild.", p.GetName()), p.GetName())
+
+	logger.Info("Guild joined", "guild", g.Name, "player", p.GetName())
+
+	return fmt.Sprintf("You join %s [%s]! Use 'gchat <message>' to talk to your guild.", g.Name, g.Tag)
+}
+
+// executeGuildDecline turns down a pending guild invite.
+func executeGuildDecline(p PlayerInterface, server ServerInterface) string {
+	inviteID, inviter := p.GetGuildInvite()
+	if inviteID == 0 {
+		return "You haven't been invited to a guild."
+	}
+	p.SetGuildInvite(0, "")
+
+	if target, ok := findOnlinePlayerExact(server, inviter); ok {
+		target.SendMessage(fmt.Sprintf("\n{player}%s{/} declines your guild invitation.\n", p.GetName()))
+	}
+	return fmt.Sprintf("You decline %s's guild invitation.", inviter)
+}
+
+// executeGuildLeave removes the player from their guild. A founder must hand
+// the guild on first, unless they are its last member.
+func executeGuildLeave(p PlayerInterface, server ServerInterface, db *database.Database) string {
+	g, m, msg := loadGuildMembership(p, db)
+	if g == nil {
+		return msg
+	}
+
+	if m.Rank == guild.RankFounder {
+		members, err := db.GetGuildMembers(g.ID)
+		if err != nil {
+			logger.Error("Failed to get guild members", "error", err, "guild", g.Name)
+			return "Failed to leave the guild."
+		}
+		if len(members) > 1 {
+			return "The founder can't leave while others remain. Use 'guild transfer <player>' first, or 'guild disband'."
+		}
+		return executeGuildDisband(p, server, db)
+	}
+
+	if err := db.RemoveGuildMember(g.ID, p.GetCharacterID()); err != nil {
+		logger.Error("Failed to remove guild member", "error", err, "player", p.GetName(), "guild", g.Name)
+		return "Failed to leave the guild."
+	}
+	p.ClearGuild()
+	notifyGuild(server, db, g, fmt.Sprintf("{player}%s{/} has left the guild.", p.GetName()), "")
+
+	logger.Info("Guild left", "guild", g.Name, "player", p.GetName())
+
+	return fmt.Sprintf("You leave %s.", g.Name)
+}
+
+// executeGuildKick removes a lower-ranked member from the guild. Works whether
+// or not they are online.
+func executeGuildKick(targetName string, p PlayerInterface, server ServerInterface, db *database.Database) string {
+	g, m, msg := loadGuildMembership(p, db)
+	if g == nil {
+		return msg
+	}
+
+	members, err := db.GetGuildMembers(g.ID)
+	if err != nil {
+		logger.Error("Failed to get guild members", "error", err, "guild", g.Name)
+		return "Failed to check the roster."
+	}
+	target, found := findRosterMember(members, targetName)
+	if !found {
+		return fmt.Sprintf("%s isn't in your guild.", targetName)
+	}
+	if target.CharacterID == p.GetCharacterID() {
+		return "Use 'guild leave' to leave the guild."
+	}
+	if !m.Rank.CanKick(target.Rank) {
+		return fmt.Sprintf("You don't have the rank to remove %s.", target.CharacterName)
+	}
+
+	if err := db.RemoveGuildMember(g.ID, target.CharacterID); err != nil {
+		logger.Error("Failed to remove guild member", "error", err, "player", target.CharacterName, "guild", g.Name)
+		return "Failed to remove that member."
+	}
+
+	if online, ok := findOnlinePlayerExact(server, target.CharacterName); ok {
+		online.ClearGuild()
+		online.SendMessage(fmt.Sprintf("\nYou have been removed from %s by {player}%s{/}.\n", g.Name, p.GetName()))
+	}
+	notifyGuild(server, db, g, fmt.Sprintf("{player}%s{/} has been removed from the guild by {player}%s{/}.", target.CharacterName, p.GetName()), p.GetName())
+
+	logger.Info("Guild member removed", "guild", g.Name, "player", target.CharacterName, "by", p.GetName())
+
+	return fmt.Sprintf("You remove %s from the guild.", target.CharacterName)
+}
+
+// executeGuildSetRank promotes a member to officer or demotes an officer to member.
+// Only the founder can change ranks.
+func executeGuildSetRank(targetName string, promote bool, p PlayerInterface, server ServerInterface, db *database.Database) string {
+	g, m, msg := loadGuildMembership(p, db)
+	if g == nil {
+		return msg
+	}
+	if m.Rank != guild.RankFounder {
+		return "Only the founder can change ranks."
+	}
+
+	members, err := db.GetGuildMembers(g.ID)
+	if err != nil {
+		logger.Error("Failed to get guild members", "error", err, "guild", g.Name)
+		return "Failed to check the roster."
+	}
+	target, found := findRosterMember(members, targetName)
+	if !found {
+		return fmt.Sprintf("%s isn't in your guild.", targetName)
+	}
+	if target.Rank == guild.RankFounder {
+		return "Use 'guild transfer <player>' to hand the guild to someone else."
+	}
+
+	newRank := target.Rank.Demoted()
+	verb := "demoted"
+	if promote {
+		newRank = target.Rank.Promoted()
+		verb = "promoted"
+	}
+	if newRank == target.Rank {
+		return fmt.Sprintf("%s is already %s.", target.CharacterName, strings.ToLower(target.Rank.Title()))
+	}
+
+	if err := db.SetGuildMemberRank(g.ID, target.CharacterID, newRank); err != nil {
+		logger.Error("Failed to set guild rank", "error", err, "player", target.CharacterName, "guild", g.Name)
+		return "Failed to change that member's rank."
+	}
+	if online, ok := findOnlinePlayerExact(server, target.CharacterName); ok {
+		online.SetGuild(g.ID, g.Name, g.Tag, newRank)
+	}
+	notifyGuild(server, db, g, fmt.Sprintf("{player}%s{/} has been %s to %s.", target.CharacterName, verb, newRank.Title()), p.GetName())
+
+	return fmt.Sprintf("You have %s %s to %s.", verb, target.CharacterName, newRank.Title())
+}
+
+// executeGuildTransfer hands the guild to another member. The old founder becomes an officer.
+func executeGuildTransfer(targetName string, p PlayerInterface, server ServerInterface, db *database.Database) string {
+	g, m, msg := loadGuildMembership(p, db)
+	if g == nil {
+		return msg
+	}
+	if m.Rank != guild.RankFounder {
+		return "Only the founder can hand the guild on."
+	}
+
+	members, err := db.GetGuildMembers(g.ID)
+	if err != nil {
+		logger.Error("Failed to get guild members", "error", err, "guild", g.Name)
+		return "Failed to check the roster."
+	}
+	target, found := findRosterMember(members, targetName)
+	if !found {
+		return fmt.Sprintf("%s isn't in your guild.", targetName)
+	}
+	if target.CharacterID == p.GetCharacterID() {
+		return "You already lead the guild."
+	}
+
+	if err := db.TransferGuildFounder(g.ID, p.GetCharacterID(), target.CharacterID); err != nil {
+		logger.Error("Failed to transfer guild", "error", err, "guild", g.Name, "to", target.CharacterName)
+		return "Failed to hand the guild on."
+	}
+	p.SetGuild(g.ID, g.Name, g.Tag, guild.RankOfficer)
+	if online, ok := findOnlinePlayerExact(server, target.CharacterName); ok {
+		online.SetGuild(g.ID, g.Name, g.Tag, guild.RankFounder)
+	}
+	notifyGuild(server, db, g, fmt.Sprintf("{player}%s{/} now leads the guild.", target.CharacterName), p.GetName())
+
+	logger.Info("Guild transferred", "guild", g.Name, "from", p.GetName(), "to", target.CharacterName)
+
+	return fmt.Sprintf("You hand leadership of %s to %s. You remain an officer.", g.Name, target.CharacterName)
+}
+
+// executeGuildDisband breaks up the guild. The guild bank must be emptied first.
+func executeGuildDisband(p PlayerInterface, server ServerInterface, db *database.Database) string {
+	g, m, msg := loadGuildMembership(p, db)
+	if g == nil {
+		return msg
+	}
+	if m.Rank != guild.RankFounder {
+		return "Only the founder can disband the guild."
+	}
+
+	bankItems, err := db.GetGuildBankItems(g.ID)
+	if err != nil {
+		logger.Error("Failed to get guild bank", "error", err, "guild", g.Name)
+		return "Failed to disband the guild."
+	}
+	if g.BankGold > 0 || len(bankItems) > 0 {
+		return "Empty the guild bank before disbanding, or its contents would be lost."
+	}
+
+	members, err := db.GetGuildMembers(g.ID)
+	if err != nil {
+		logger.Error("Failed to get guild members", "error", err, "guild", g.Name)
+		return "Failed to disband the guild."
+	}
+
+	if err := db.DeleteGuild(g.ID); err != nil {
+		logger.Error("Failed to delete guild", "error", err, "guild", g.Name)
+		return "Failed to disband the guild."
+	}
+
+	p.ClearGuild()
+	for _, member := range members {
+		if strings.EqualFold(member.CharacterName, p.GetName()) {
+			continue
+		}
+		if online, ok := findOnlinePlayerExact(server, member.CharacterName); ok {
+			online.ClearGuild()
+			online.SendMessage(fmt.Sprintf("\n{player}%s{/} has disbanded %s.\n", p.GetName(), g.Name))
+		}
+	}
+
+	logger.Info("Guild disbanded", "guild", g.Name, "by", p.GetName())
+
+	return fmt.Sprintf("You disband %s.", g.Name)
+}
+
+// executeGuildBank lists the gold and items in the guild bank.
+func executeGuildBank(p PlayerInterface, server ServerInterface, db *database.Database) string {
+	if msg := requireGuildBank(p); msg != "" {
+		return msg
+	}
+
+	g, _, msg := loadGuildMembership(p, db)
+	if g == nil {
+		return msg
+	}
+
+	bankItems, err := db.GetGuildBankItems(g.ID)
+	if err != nil {
+		logger.Error("Failed to get guild bank", "error", err, "guild", g.Name)
+		return "Failed to open the guild bank."
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("\n=== %s Guild Bank ===\n", g.Name))
+	result.WriteString(fmt.Sprintf("Gold: {gold}%d{/}\n", g.BankGold))
+	if len(bankItems) == 0 {
+		result.WriteString("\nNo items are stored.")
+		return result.String()
+	}
+
+	result.WriteString(fmt.Sprintf("\nItems (%d/%d):\n", len(bankItems), guild.MaxBankItems))
+	for i, bankItem := range bankItems {
+		name := bankItem.ItemID
+		if item := server.CreateItem(bankItem.ItemID); item != nil {
+			name = item.Name
+		}
+		result.WriteString(fmt.Sprintf("  %2d. {item}%-30s{/} (from %s)\n", i+1, name, bankItem.DepositedBy))
+	}
+	return result.String()
+}
+
+// requireGuildBank returns a message if the player isn't somewhere they can
+// reach the guild bank. Guild banking goes through the mailbox network.
+func requireGuildBank(p PlayerInterface) string {
+	room, ok := GetRoom(p)
+	if !ok {
+		return "Internal error: invalid room"
+	}
+	if !room.HasFeature("mailbox") {
+		return "You need to be at a mailbox to reach the guild bank."
+	}
+	// Any member can deposit or withdraw, hardcore or not
+	if p.IsHardcore() {
+		return "The guild bank is shared with every member, so hardcore characters can't use it."
+	}
+	return ""
+}
+
+// parseGoldAmount parses "<amount> gold" or "<amount>" into a gold amount.
+// Returns false if the arguments aren't a gold amount.
+func parseGoldAmount(args []string) (int, bool) {
+	if len(args) == 0 || len(args) > 2 {
+		return 0, false
+	}
+	if len(args) == 2 && strings.ToLower(args[1]) != "gold" {
+		return 0, false
+	}
+	amount, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, false
+	}
+	return amount, true
+}
+
+// executeGuildDeposit puts gold or an item into the guild bank. Any member can deposit.
+func executeGuildDeposit(args []string, p PlayerInterface, server ServerInterface, db *database.Database) string {
+	if msg := requireGuildBank(p); msg != "" {
+		return msg
+	}
+
+	g, _, msg := loadGuildMembership(p, db)
+	if g == nil {
+		return msg
+	}
+
+	if amount, isGold := parseGoldAmount(args); isGold {
+		if amount <= 0 {
+			return "You must deposit a positive amount of gold."
+		}
+		if !p.SpendGold(amount) {
+			return fmt.Sprintf("You don't have %d gold.", amount)
+		}
+		if err := db.DepositGuildGold(g.ID, amount); err != nil {
+			p.AddGold(amount)
+			logger.Error("Failed to deposit guild gold", "error", err, "player", p.GetName(), "guild", g.Name)
+			return "Failed to deposit the gold."
+		}
+		logger.Info("Guild gold deposited", "guild", g.Name, "player", p.GetName(), "amount", amount)
+		notifyGuild(server, db, g, fmt.Sprintf("{player}%s{/} deposited {gold}%d{/} gold in the guild bank.", p.GetName(), amount), p.GetName())
+		return fmt.Sprintf("You deposit {gold}%d{/} gold in the guild bank.", amount)
+	}
+
+	itemName := strings.Join(args, " ")
+	item, found := p.FindItem(itemName)
+	if !found {
+		return fmt.Sprintf("You don't have '%s' in your inventory.", itemName)
+	}
+	if item.Unique {
+		return fmt.Sprintf("The %s is unique and can't be stored in the guild bank.", item.Name)
+	}
+	if item.Wear > 0 {
+		return fmt.Sprintf("The %s is worn. Have it repaired before storing it in the guild bank.", item.Name)
+	}
+
+	bankItems, err := db.GetGuildBankItems(g.ID)
+	if err != nil {
+		logger.Error("Failed to get guild bank", "error", err, "guild", g.Name)
+		return "Failed to open the guild bank."
+	}
+	if len(bankItems) >= guild.MaxBankItems {
+		return fmt.Sprintf("The guild bank is full (%d items).", guild.MaxBankItems)
+	}
+
+	removed, ok := p.RemoveItem(item.Name)
+	if !ok {
+		return fmt.Sprintf("You don't have '%s' in your inventory.", itemName)
+	}
+	if err := db.DepositGuildItem(g.ID, removed.ID, p.GetName()); err != nil {
+		p.AddItem(removed)
+		logger.Error("Failed to deposit guild item", "error", err, "player", p.GetName(), "guild", g.Name, "item", removed.ID)
+		return "Failed to deposit the item."
+	}
+
+	logger.Info("Guild item deposited", "guild", g.Name, "player", p.GetName(), "item", removed.ID)
+	notifyGuild(server, db, g, fmt.Sprintf("{player}%s{/} deposited {item}%s{/} in the guild bank.", p.GetName(), removed.Name), p.GetName())
+	return fmt.Sprintf("You deposit {item}%s{/} in the guild bank.", removed.Name)
+}
+
+// executeGuildWithdraw takes gold or an item out of the guild bank. Officers and the founder only.
+func executeGuildWithdraw(args []string, p PlayerInterface, server ServerInterface, db *database.Database) string {
+	if msg := requireGuildBank(p); msg != "" {
+		return msg
+	}
+
+	g, m, msg := loadGuildMembership(p, db)
+	if g == nil {
+		return msg
+	}
+	if !m.Rank.CanWithdraw() {
+		return "Only officers and the founder can withdraw from the guild bank."
+	}
+
+	// A bare number is an item number from 'guild bank'; gold needs the "gold" suffix
+	if amount, isGold := parseGoldAmount(args); isGold && len(args) == 2 {
+		if amount <= 0 {
+			return "You must withdraw a positive amount of gold."
+		}
+		err := db.WithdrawGuildGold(g.ID, amount)
+		if err == database.ErrInsufficientGuildGold {
+			return fmt.Sprintf("The guild bank only holds %d gold.", g.BankGold)
+		}
+		if err != nil {
+			logger.Error("Failed to withdraw guild gold", "error", err, "player", p.GetName(), "guild", g.Name)
+			return "Failed to withdraw the gold."
+		}
+		p.AddGold(amount)
+		logger.Info("Guild gold withdrawn", "guild", g.Name, "player", p.GetName(), "amount", amount)
+		notifyGuild(server, db, g, fmt.Sprintf("{player}%s{/} withdrew {gold}%d{/} gold from the guild bank.", p.GetName(), amount), p.GetName())
+		return fmt.Sprintf("You withdraw {gold}%d{/} gold from the guild bank.", amount)
+	}
+
+	bankItems, err := db.GetGuildBankItems(g.ID)
+	if err != nil {
+		logger.Error("Failed to get guild bank", "error", err, "guild", g.Name)
+		return "Failed to open the guild bank."
+	}
+
+	// Find the item by its number in 'guild bank', or by name
+	query := strings.Join(args, " ")
+	var chosen *guild.BankItem
+	if index, err := strconv.Atoi(query); err == nil {
+		if index >= 1 && index <= len(bankItems) {
+			chosen = &bankItems[index-1]
+		}
+	} else {
+		queryLower := strings.ToLower(query)
+		for i := range bankItems {
+			item := server.CreateItem(bankItems[i].ItemID)
+			if item != nil && strings.Contains(strings.ToLower(item.Name), queryLower) {
+				chosen = &bankItems[i]
+				break
+			}
+		}
+	}
+	if chosen == nil {
+		return fmt.Sprintf("The guild bank doesn't hold '%s'. Type 'guild bank' to see what's stored.", query)
+	}
+
+	item := server.CreateItem(chosen.ItemID)
+	if item == nil {
+		logger.Warning("Unknown item in guild bank", "item_id", chosen.ItemID, "guild", g.Name)
+		return "That item can't be withdrawn."
+	}
+	if !p.CanCarry(item) {
+		return fmt.Sprintf("You can't carry the %s.", item.Name)
+	}
+
+	taken, err := db.WithdrawGuildItem(g.ID, chosen.ID)
+	if err != nil {
+		logger.Error("Failed to withdraw guild item", "error", err, "player", p.GetName(), "guild", g.Name, "item", chosen.ItemID)
+		return "Failed to withdraw the item."
+	}
+	if !taken {
+		return "Someone else just took that item."
+	}
+	p.AddItem(item)
+
+	logger.Info("Guild item withdrawn", "guild", g.Name, "player", p.GetName(), "item", chosen.ItemID)
+	notifyGuild(server, db, g, fmt.Sprintf("{player}%s{/} withdrew {item}%s{/} from the guild bank.", p.GetName(), item.Name), p.GetName())
+	return fmt.Sprintf("You withdraw {item}%s{/} from the guild bank.", item.Name)
+}
+
+// executeGchat sends a message to every online member of the player's guild.
+func executeGchat(c *Command, p PlayerInterface) string {
+	if err := c.RequireArgs(1, "Usage: gchat <message>"); err != nil {
+		return err.Error()
+	}
+
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return "Internal error: invalid server type"
+	}
+
+	guildID := p.GetGuildID()
+	if guildID == 0 {
+		return "You aren't in a guild."
+	}
+
+	message := c.GetItemName()
+	if allowed, reason := p.CheckChatSpam(message); !allowed {
+		return reason
+	}
+
+	// Apply chat filter if enabled
+	if filter := server.GetChatFilter(); filter != nil && filter.IsEnabled() {
+		result := filter.Check(message)
+		if result.Violated {
+			logger.Always("CHAT_FILTER",
+				"player", p.GetName(),
+				"command", "gchat",
+				"original", message,
+				"matched", strings.Join(result.MatchedWords, ", "),
+				"mode", string(filter.Mode()))
+
+			if filter.IsBlockMode() {
+				return "Your message contains inappropriate language and was not sent."
+			}
+			message = result.Filtered
+		}
+	}
+
+	server.SendGuildMessage(guildID, p.GetName(), message)
+	p.SendChannelGMCP("guild", p.GetName(), message)
+
+	// AUDIT LOG - Always logged regardless of log level (security/moderation)
+	logger.Always("CHAT_GUILD",
+		"sender", p.GetName(),
+		"guild", p.GetGuildName(),
+		"message", message)
+
+	return fmt.Sprintf("{guild}You tell the guild: \"%s\"{/}", color.Escape(message))
+}
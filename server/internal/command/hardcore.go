@@ -0,0 +1,125 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/database"
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+)
+
+// memorialListSize is how many recent deaths the memorial command lists.
+const memorialListSize = 10
+
+// FormatMemorial shows the memorial of a fallen hardcore character.
+func FormatMemorial(m *database.Memorial) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("\n=== In Memory of {player}%s{/} ===\n", m.Name))
+	sb.WriteString(fmt.Sprintf("Level %d %s %s, hardcore\n\n", m.Level, strings.Title(m.Race), strings.Title(m.PrimaryClass)))
+	sb.WriteString(fmt.Sprintf("  Slain by:        {warning}%s{/}\n", m.KilledBy))
+	sb.WriteString(fmt.Sprintf("  Fell in:         {room}%s{/}\n", m.DiedIn))
+	if m.FloorReached > 0 {
+		sb.WriteString(fmt.Sprintf("  Deepest floor:   %d\n", m.FloorReached))
+	} else {
+		sb.WriteString("  Deepest floor:   never entered a tower\n")
+	}
+	sb.WriteString(fmt.Sprintf("  Experience:      %d\n", m.Experience))
+	sb.WriteString(fmt.Sprintf("  Kills:           %d\n", m.Kills))
+	sb.WriteString(fmt.Sprintf("  Quests done:     %d\n", m.QuestsCompleted))
+	sb.WriteString(fmt.Sprintf("  Time played:     %s\n", formatPlayTime(m.PlaySeconds)))
+	sb.WriteString(fmt.Sprintf("  Lived:           %s to %s\n", m.BornAt.Local().Format("Jan 2, 2006"), m.DiedAt.Local().Format("Jan 2, 2006")))
+	return sb.String()
+}
+
+// formatPlayTime shows a play time in seconds as hours and minutes, e.g. "3h 12m"
+func formatPlayTime(seconds int64) string {
+	d := time.Duration(seconds) * time.Second
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh %02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// executeMemorial shows a fallen hardcore character's memorial, or the most
+// recent hardcore deaths
+// Usage: memorial [player]
+func executeMemorial(c *Command, p PlayerInterface) string {
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return "Internal error: invalid server type"
+	}
+	db, ok := server.GetDatabase().(*database.Database)
+	if !ok {
+		return "Internal error: database not available"
+	}
+
+	if len(c.Args) > 0 {
+		name := strings.Join(c.Args, " ")
+		m, err := db.GetMemorial(name)
+		if errors.Is(err, database.ErrMemorialNotFound) {
+			return fmt.Sprintf("No hardcore hero named '%s' has fallen.", name)
+		}
+		if err != nil {
+			logger.Error("Failed to get memorial", "error", err, "player", p.GetName(), "name", name)
+			return "Failed to load that memorial."
+		}
+		return FormatMemorial(m)
+	}
+
+	memorials, err := db.GetRecentMemorials(memorialListSize)
+	if err != nil {
+		logger.Error("Failed to get memorials", "error", err)
+		return "Failed to load the memorials."
+	}
+	if len(memorials) == 0 {
+		return "No hardcore hero has fallen. Yet."
+	}
+
+	var result strings.Builder
+	result.WriteString("\n=== The Fallen ===\n")
+	for _, m := range memorials {
+		result.WriteString(fmt.Sprintf("  {player}%-20s{/} level %-2d %-8s slain by %s (%s)\n",
+			m.Name, m.Level, strings.Title(m.PrimaryClass), m.KilledBy, formatMailTime(m.DiedAt)))
+	}
+	result.WriteString("\nType 'memorial <name>' to read a memorial.")
+	return result.String()
+}
+
+// executeHardcoreLeaderboard shows the highest level hardcore characters,
+// living and fallen
+func executeHardcoreLeaderboard(p PlayerInterface, db *database.Database) string {
+	standings, err := db.GetHardcoreLeaderboard(leaderboardSize)
+	if err != nil {
+		logger.Error("Failed to get hardcore leaderboard", "error", err)
+		return "Failed to load the leaderboard."
+	}
+	if len(standings) == 0 {
+		return "Nobody has braved hardcore mode yet."
+	}
+
+	var result strings.Builder
+	result.WriteString("\n=== Hardcore Leaderboard ===\n")
+	result.WriteString(fmt.Sprintf("  %-4s %-20s %5s %-8s %s\n", "Rank", "Name", "Level", "Class", "Status"))
+	for i, s := range standings {
+		status := "{heal}alive{/}"
+		if s.Fallen {
+			status = fmt.Sprintf("{warning}slain by %s{/}", s.KilledBy)
+			if s.FloorReached > 0 {
+				status += fmt.Sprintf(", floor %d", s.FloorReached)
+			}
+		}
+		result.WriteString(fmt.Sprintf("  %-4d {player}%-20s{/} %5d %-8s %s\n", i+1, s.Name, s.Level, strings.Title(s.PrimaryClass), status))
+	}
+	if !p.IsHardcore() {
+		result.WriteString("\nOnly characters created in hardcore mode are ranked here.\n")
+	}
+	return result.String()
+}
+
+// hardcoreApart returns true if one of the two characters is hardcore and the
+// other isn't, so they can't trade, give or mail each other anything
+func hardcoreApart(p PlayerInterface, otherHardcore bool) bool {
+	return p.IsHardcore() != otherHardcore
+}
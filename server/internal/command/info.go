@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/lawnchairsociety/opentowermud/server/internal/color"
 	"github.com/lawnchairsociety/opentowermud/server/internal/database"
 	"github.com/lawnchairsociety/opentowermud/server/internal/help"
 	"github.com/lawnchairsociety/opentowermud/server/internal/leveling"
@@ -38,6 +39,9 @@ func executeScore(c *Command, p PlayerInterface) string {
 	result.WriteString(fmt.Sprintf("Race: %s\n", p.GetRaceName()))
 	result.WriteString(fmt.Sprintf("Class: %s\n", p.GetClassLevelsSummary()))
 	result.WriteString(fmt.Sprintf("Active: %s (gaining XP)\n", p.GetActiveClassName()))
+	if p.IsHardcore() {
+		result.WriteString("Mode: {warning}Hardcore{/} (one life)\n")
+	}
 
 	// Level and XP section
 	level := p.GetLevel()
@@ -49,6 +53,9 @@ func executeScore(c *Command, p PlayerInterface) string {
 		xpNeeded := leveling.XPForLevel(level + 1)
 		result.WriteString(fmt.Sprintf("  |  XP: %d / %d\n", xp, xpNeeded))
 	}
+	if debt := p.GetXPDebt(); debt > 0 {
+		result.WriteString(fmt.Sprintf("XP Debt: %d (half of all XP earned pays it off)\n", debt))
+	}
 
 	// Health and Mana
 	result.WriteString(fmt.Sprintf("Health: %d / %d\n", p.GetHealth(), p.GetMaxHealth()))
@@ -171,6 +178,112 @@ func executePassword(c *Command, p PlayerInterface) string {
 	return "Password changed successfully."
 }
 
+// executePrompt shows or changes the player's status prompt
+func executePrompt(c *Command, p PlayerInterface) string {
+	if len(c.Args) == 0 {
+		return showPrompt(p)
+	}
+
+	format := strings.Join(c.Args, " ")
+
+	if strings.EqualFold(format, "default") || strings.EqualFold(format, "reset") {
+		format = ""
+	}
+
+	if err := p.SetPrompt(format); err != nil {
+		return fmt.Sprintf("Cannot set prompt: %v", err)
+	}
+
+	return fmt.Sprintf("Prompt set. Preview:\n%s", p.RenderPrompt(p.GetPrompt()))
+}
+
+// showPrompt displays the current prompt format, a preview, and the available tokens
+func showPrompt(p PlayerInterface) string {
+	var sb strings.Builder
+	sb.WriteString("=== Your Prompt ===\n\n")
+	sb.WriteString(fmt.Sprintf("Format:  %s\n", p.GetPrompt()))
+	sb.WriteString(fmt.Sprintf("Preview: %s\n\n", p.RenderPrompt(p.GetPrompt())))
+
+	sb.WriteString("Tokens:\n")
+	sb.WriteString("  %h / %H  current / max health\n")
+	sb.WriteString("  %m / %M  current / max mana\n")
+	sb.WriteString("  %x       experience points\n")
+	sb.WriteString("  %X       experience needed for next level\n")
+	sb.WriteString("  %g       gold\n")
+	sb.WriteString("  %e       exits (e.g. NSEU)\n")
+	sb.WriteString("  %r       room name\n")
+	sb.WriteString("  %t       combat target and its health (blank when not fighting)\n")
+	sb.WriteString("  %%       a literal %\n")
+
+	sb.WriteString("\nUse 'prompt <format>' to change it, or 'prompt default' to reset.")
+	return sb.String()
+}
+
+// executeColor shows or changes the player's color preferences
+func executeColor(c *Command, p PlayerInterface) string {
+	if len(c.Args) == 0 {
+		return showColors(p)
+	}
+
+	switch strings.ToLower(c.Args[0]) {
+	case "on":
+		p.SetColorEnabled(true)
+		return "Color is now {system}on{/}."
+	case "off":
+		p.SetColorEnabled(false)
+		return "Color is now off."
+	case "reset":
+		category := ""
+		if len(c.Args) > 1 {
+			category = c.Args[1]
+		}
+		if err := p.ResetColor(category); err != nil {
+			return fmt.Sprintf("Cannot reset color: %v", err)
+		}
+		if category == "" {
+			return "All colors restored to their defaults."
+		}
+		return fmt.Sprintf("Color for %s restored to its default.", strings.ToLower(category))
+	}
+
+	if err := c.RequireArgs(2, "Usage: color [on|off|reset [category]|<category> <color>]"); err != nil {
+		return err.Error()
+	}
+
+	category := strings.ToLower(c.Args[0])
+	if err := p.RemapColor(category, c.Args[1]); err != nil {
+		return fmt.Sprintf("Cannot set color: %v", err)
+	}
+
+	return fmt.Sprintf("%s will now be shown as %s.", color.Wrap(category, category), strings.ToLower(c.Args[1]))
+}
+
+// showColors lists the color categories with a sample of each
+func showColors(p PlayerInterface) string {
+	var sb strings.Builder
+	sb.WriteString("=== Color Settings ===\n\n")
+
+	if !p.IsColorEnabled() {
+		sb.WriteString("Color is off. Use 'color on' to enable it.\n")
+		return sb.String()
+	}
+
+	theme := p.GetColorTheme()
+	for _, category := range color.Categories() {
+		setting := "default"
+		if name, ok := theme[category]; ok {
+			setting = name
+		}
+		// Pad before wrapping so the markup doesn't count toward column width
+		sb.WriteString(fmt.Sprintf("  %s %-32s %s\n", color.Wrap(category, fmt.Sprintf("%-8s", category)), color.Describe(category), setting))
+	}
+
+	sb.WriteString(fmt.Sprintf("\nColors: %s, or an xterm-256 number (0-255)\n", strings.Join(color.ColorNames(), ", ")))
+	sb.WriteString("\nUse 'color <category> <color>' to remap, 'color reset [category]' to restore,\n")
+	sb.WriteString("or 'color off' to turn color off.")
+	return sb.String()
+}
+
 // executeRace shows race information
 func executeRace(c *Command, p PlayerInterface) string {
 	if len(c.Args) == 0 {
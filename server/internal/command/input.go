@@ -0,0 +1,135 @@
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxSpeedwalkRepeat is the largest repeat count allowed for one speedwalk step (e.g., "20n").
+const maxSpeedwalkRepeat = 20
+
+// speedwalkDirections maps speedwalk letters to movement commands.
+var speedwalkDirections = map[byte]string{
+	'n': "north",
+	's': "south",
+	'e': "east",
+	'w': "west",
+	'u': "up",
+	'd': "down",
+}
+
+// movementCommands are the commands that move the player. Queued movement
+// is held while the player is fighting.
+var movementCommands = map[string]bool{
+	"north": true, "n": true,
+	"south": true, "s": true,
+	"east": true, "e": true,
+	"west": true, "w": true,
+	"up": true, "u": true,
+	"down": true, "d": true,
+	"enter": true, "leave": true,
+	"go": true, "move": true, "walk": true,
+}
+
+// SplitInput splits a line of input into individual commands on separator and
+// expands speedwalk strings into one movement command per step.
+// An empty separator disables command stacking. Alias definitions aren't split.
+func SplitInput(line, separator string, speedwalk bool) []string {
+	// An alias definition keeps its separators; they split the alias when it runs
+	if ParseCommand(line).Name == "alias" {
+		return []string{strings.TrimSpace(line)}
+	}
+
+	parts := []string{line}
+	if separator != "" {
+		parts = strings.Split(line, separator)
+	}
+
+	commands := make([]string, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if speedwalk {
+			if steps := ExpandSpeedwalk(part); steps != nil {
+				commands = append(commands, steps...)
+				continue
+			}
+		}
+		commands = append(commands, part)
+	}
+	return commands
+}
+
+// ExpandSpeedwalk expands a speedwalk string like "3n2e" into movement commands
+// ("north", "north", "north", "east", "east").
+// Returns nil if the input isn't a speedwalk: a single word of direction letters
+// (n, s, e, w, u, d), each with an optional repeat count, that isn't already a command.
+// At least one step needs a count, so ordinary words made of direction letters
+// ("sun", "dude", "send") are left alone.
+func ExpandSpeedwalk(input string) []string {
+	word := strings.ToLower(input)
+	if word == "" || strings.ContainsAny(word, " \t") || commandRegistry[word] != nil {
+		return nil
+	}
+
+	var steps []string
+	counted := false
+	for i := 0; i < len(word); {
+		start := i
+		for i < len(word) && word[i] >= '0' && word[i] <= '9' {
+			i++
+		}
+		count := 1
+		if i > start {
+			n, err := strconv.Atoi(word[start:i])
+			if err != nil || n < 1 || n > maxSpeedwalkRepeat {
+				return nil
+			}
+			count = n
+			counted = true
+		}
+
+		if i >= len(word) {
+			return nil // Trailing count with no direction
+		}
+		direction, ok := speedwalkDirections[word[i]]
+		if !ok {
+			return nil
+		}
+		i++
+
+		for j := 0; j < count; j++ {
+			steps = append(steps, direction)
+		}
+	}
+	if !counted {
+		return nil
+	}
+	return steps
+}
+
+// IsMovementCommand returns true if the input is a command that moves the player.
+func IsMovementCommand(input string) bool {
+	return movementCommands[ParseCommand(input).Name]
+}
+
+// IsClearCommand returns true if the input is the clear command, which runs as
+// soon as it is typed instead of waiting its turn in the input queue.
+func IsClearCommand(input string) bool {
+	return ParseCommand(input).Name == "clear"
+}
+
+// executeClear drops any commands still waiting in the player's input queue
+func executeClear(c *Command, p PlayerInterface) string {
+	dropped := p.ClearInputQueue()
+	if dropped == 0 {
+		return "You have no commands queued."
+	}
+	if dropped == 1 {
+		return "Cleared 1 queued command."
+	}
+	return fmt.Sprintf("Cleared %d queued commands.", dropped)
+}
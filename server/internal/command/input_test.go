@@ -0,0 +1,74 @@
+package command
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestExpandSpeedwalk tests speedwalk expansion and the inputs that aren't speedwalks
+func TestExpandSpeedwalk(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"3n2e", []string{"north", "north", "north", "east", "east"}},
+		{"n2wu", []string{"north", "west", "west", "up"}},
+		{"2D", []string{"down", "down"}},
+		{"n", nil},    // Already a command
+		{"use", nil},  // Already a command, even though it's all direction letters
+		{"nwu", nil},  // No repeat count
+		{"sun", nil},  // A word made of direction letters isn't a speedwalk
+		{"dude", nil}, // Nor this one
+		{"3x", nil},   // Not a direction
+		{"3", nil},    // Count with no direction
+		{"21n", nil},  // Repeat count too large
+		{"0n", nil},   // Zero repeat
+		{"n e", nil},  // More than one word
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		if got := ExpandSpeedwalk(tt.input); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("ExpandSpeedwalk(%q) = %v, expected %v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+// TestSplitInput tests command stacking with and without speedwalk
+func TestSplitInput(t *testing.T) {
+	tests := []struct {
+		line      string
+		separator string
+		speedwalk bool
+		expected  []string
+	}{
+		{"n;n;e;open door", ";", true, []string{"n", "n", "e", "open door"}},
+		{"2n; ;say hi there", ";", true, []string{"north", "north", "say hi there"}},
+		{"2n;look", ";", false, []string{"2n", "look"}},
+		{"n|look", "|", true, []string{"n", "look"}},
+		{"n;look", "", true, []string{"n;look"}},
+		{"sun;2s", ";", true, []string{"sun", "south", "south"}},
+		{"alias tour 2n;e", ";", true, []string{"alias tour 2n;e"}}, // Alias definitions aren't split
+	}
+
+	for _, tt := range tests {
+		got := SplitInput(tt.line, tt.separator, tt.speedwalk)
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("SplitInput(%q, %q, %v) = %v, expected %v", tt.line, tt.separator, tt.speedwalk, got, tt.expected)
+		}
+	}
+}
+
+// TestIsMovementCommand tests which queued commands are held during a fight
+func TestIsMovementCommand(t *testing.T) {
+	for _, input := range []string{"n", "north", "go east", "Leave"} {
+		if !IsMovementCommand(input) {
+			t.Errorf("Expected %q to be a movement command", input)
+		}
+	}
+	for _, input := range []string{"look", "flee", "say north"} {
+		if IsMovementCommand(input) {
+			t.Errorf("Expected %q not to be a movement command", input)
+		}
+	}
+}
@@ -150,7 +150,7 @@ func executeInventory(c *Command, p PlayerInterface) string {
 	} else {
 		result += "\nYou are carrying:\n"
 		for _, item := range inventory {
-			result += fmt.Sprintf("  - %s (%.1f, %s)\n", item.Name, item.Weight, item.Type.String())
+			result += fmt.Sprintf("  - %s (%.1f, %s)%s\n", item.Name, item.Weight, item.Type.String(), formatCondition(item))
 		}
 	}
 
@@ -225,6 +225,7 @@ func executeEquipment(c *Command, p PlayerInterface) string {
 			if item.TwoHanded {
 				result += " [two-handed]"
 			}
+			result += formatCondition(item)
 			result += "\n"
 		}
 	}
@@ -232,6 +233,15 @@ func executeEquipment(c *Command, p PlayerInterface) string {
 	return result
 }
 
+// formatCondition returns a warning tag for a worn or broken item, or "" if it's undamaged
+func formatCondition(item *items.Item) string {
+	condition := item.Condition()
+	if condition == "" {
+		return ""
+	}
+	return fmt.Sprintf(" {warning}[%s %d%%]{/}", condition, item.Wear)
+}
+
 // executeWield equips a weapon from inventory
 func executeWield(c *Command, p PlayerInterface) string {
 	if err := c.RequireArgs(1, "Usage: wield <weapon>"); err != nil {
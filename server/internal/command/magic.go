@@ -3,13 +3,18 @@ package command
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/lawnchairsociety/opentowermud/server/internal/effects"
 	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
 	"github.com/lawnchairsociety/opentowermud/server/internal/npc"
 	"github.com/lawnchairsociety/opentowermud/server/internal/spells"
 	"github.com/lawnchairsociety/opentowermud/server/internal/stats"
 )
 
+// resurrectionWindow is how long after dying a player can be resurrected where they fell.
+const resurrectionWindow = 5 * time.Minute
+
 // executeCast handles casting spells
 func executeCast(c *Command, p PlayerInterface) string {
 	if err := c.RequireArgs(1, "Usage: cast <spell> [target]"); err != nil {
@@ -32,7 +37,7 @@ func executeCast(c *Command, p PlayerInterface) string {
 
 	// Look up the spell
 	spell, exists := registry.GetSpell(spellName)
-	if !exists {
+	if !exists || spell.NPCOnly {
 		return fmt.Sprintf("Unknown spell: '%s'. Type 'spells' to see your available spells.", spellName)
 	}
 
@@ -51,6 +56,10 @@ func executeCast(c *Command, p PlayerInterface) string {
 		return fmt.Sprintf("Not enough mana to cast %s. (Need %d, have %d)", spell.Name, spell.ManaCost, p.GetMana())
 	}
 
+	if p.IsStunned() {
+		return stunnedMessage(p)
+	}
+
 	// Check if spell is on cooldown
 	onCooldown, remaining := p.IsSpellOnCooldown(spell.ID)
 	if onCooldown {
@@ -67,6 +76,15 @@ func executeCast(c *Command, p PlayerInterface) string {
 	if spell.CanTargetRoomEnemies() {
 		return castRoomSpell(c, p, spell)
 	}
+	if spell.CanTargetRoomAllies() {
+		return castRoomAllySpell(c, p, spell)
+	}
+	if spell.CanTargetDeadAlly() {
+		if targetName == "" {
+			return fmt.Sprintf("Cast %s on whom? Usage: cast %s <player>", spell.Name, spell.Name)
+		}
+		return castResurrectSpell(c, p, spell, targetName)
+	}
 
 	// Determine how to cast based on target and spell capabilities
 	if targetName == "" {
@@ -166,6 +184,12 @@ func castSelfSpell(c *Command, p PlayerInterface, spell *spells.Spell) string {
 			if healed > 0 {
 				results = append(results, fmt.Sprintf("+%d HP", healed))
 			}
+		case spells.EffectBuff, spells.EffectPoison:
+			if result := applySpellStatus(p.Effects(), spell, effect, p.GetName()); result != "" {
+				results = append(results, result)
+			}
+		case spells.EffectCleanse:
+			results = append(results, cleanseResult(p.Effects().Cleanse()))
 		}
 	}
 
@@ -175,6 +199,9 @@ func castSelfSpell(c *Command, p PlayerInterface, spell *spells.Spell) string {
 	}
 
 	effectStr := strings.Join(results, ", ")
+	if !spell.HasHealEffect() {
+		return fmt.Sprintf("You cast %s on yourself. [%s]", spell.Name, effectStr)
+	}
 	return fmt.Sprintf("You cast %s on yourself.\nYou feel a warm glow as your wounds begin to mend. [%s]", spell.Name, effectStr)
 }
 
@@ -248,6 +275,12 @@ func castAllySpell(c *Command, p PlayerInterface, spell *spells.Spell, target Pl
 			if healed > 0 {
 				results = append(results, fmt.Sprintf("+%d HP", healed))
 			}
+		case spells.EffectBuff:
+			if result := applySpellStatus(target.Effects(), spell, effect, p.GetName()); result != "" {
+				results = append(results, result)
+			}
+		case spells.EffectCleanse:
+			results = append(results, cleanseResult(target.Effects().Cleanse()))
 		}
 	}
 
@@ -265,10 +298,225 @@ func castAllySpell(c *Command, p PlayerInterface, spell *spells.Spell, target Pl
 	return fmt.Sprintf("You cast %s on %s.", spell.Name, target.GetName())
 }
 
+// partyMembersInRoom returns the caster and any party members standing in the same room.
+// A caster who isn't in a party only gets themselves.
+func partyMembersInRoom(p PlayerInterface, server ServerInterface, room RoomInterface) []PlayerInterface {
+	members := []PlayerInterface{p}
+	info, ok := server.GetPartyInfo(p.GetName())
+	if !ok {
+		return members
+	}
+	for _, name := range info.Members {
+		if strings.EqualFold(name, p.GetName()) {
+			continue
+		}
+		member, ok := server.FindPlayer(name).(PlayerInterface)
+		if !ok || member.IsLinkDead() {
+			continue
+		}
+		if memberRoom, ok := GetRoom(member); ok && memberRoom.GetID() == room.GetID() {
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+// castRoomAllySpell handles spells that affect the caster's party in the room
+func castRoomAllySpell(c *Command, p PlayerInterface, spell *spells.Spell) string {
+	room, ok := GetRoom(p)
+	if !ok {
+		return "Error: You are not in a valid room."
+	}
+
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return "Internal error: invalid server type"
+	}
+
+	// Deduct mana
+	if !p.UseMana(spell.ManaCost) {
+		return "Not enough mana!"
+	}
+
+	// Record spell cast in statistics
+	p.RecordSpellCast()
+
+	// Start cooldown
+	if spell.Cooldown > 0 {
+		p.StartSpellCooldown(spell.ID, spell.Cooldown)
+	}
+
+	targets := partyMembersInRoom(p, server, room)
+
+	logger.Debug("Spell cast (room ally)",
+		"player", p.GetName(),
+		"spell", spell.Name,
+		"target_count", len(targets),
+		"mana_cost", spell.ManaCost,
+		"cooldown", spell.Cooldown)
+
+	// Get WIS modifier for healing
+	wisMod := p.GetWisdomMod()
+
+	// Each member rolls their own healing
+	var affected []string
+	for _, target := range targets {
+		healed := 0
+		var statuses []string
+		for _, effect := range spell.Effects {
+			if effect.Target != spells.TargetRoomAlly {
+				continue
+			}
+
+			switch effect.Type {
+			case spells.EffectHeal:
+				var healAmount int
+				if effect.Dice != "" {
+					healAmount = stats.ParseDiceWithBonus(effect.Dice, wisMod)
+				} else {
+					healAmount = effect.Amount + wisMod
+				}
+				if healAmount < 1 {
+					healAmount = 1
+				}
+				healed += target.Heal(healAmount)
+			case spells.EffectHealPercent:
+				// Heal based on CASTER's max HP (scales with caster's level)
+				healed += target.Heal((p.GetMaxHealth() * effect.Amount) / 100)
+			case spells.EffectBuff:
+				if result := applySpellStatus(target.Effects(), spell, effect, p.GetName()); result != "" {
+					statuses = append(statuses, result)
+				}
+			case spells.EffectCleanse:
+				statuses = append(statuses, cleanseResult(target.Effects().Cleanse()))
+			}
+		}
+
+		// Heals show the health restored; other spells show what took hold
+		var parts []string
+		if spell.HasHealEffect() {
+			parts = append(parts, fmt.Sprintf("+%d HP", healed))
+		}
+		parts = append(parts, statuses...)
+		summary := strings.Join(parts, ", ")
+
+		if target == p {
+			affected = append(affected, "you "+summary)
+			continue
+		}
+		affected = append(affected, target.GetName()+" "+summary)
+		target.SendMessage(fmt.Sprintf("%s casts %s! %s washes over you. [%s]\n", p.GetName(), spell.Name, roomAllyFlavor(spell), summary))
+	}
+
+	// Broadcast to room
+	server.BroadcastToRoom(room.GetID(), fmt.Sprintf("%s casts %s! %s fills the room.\n", p.GetName(), spell.Name, roomAllyFlavor(spell)), p)
+
+	return fmt.Sprintf("You cast %s!\n%s washes over your group. [%s]", spell.Name, roomAllyFlavor(spell), strings.Join(affected, ", "))
+}
+
+// roomAllyFlavor names what a party spell fills the room with
+func roomAllyFlavor(spell *spells.Spell) string {
+	if spell.HasHealEffect() {
+		return "Healing light"
+	}
+	return "A shimmering aura"
+}
+
+// castResurrectSpell handles spells that bring a fallen party member back.
+// Players respawn in town when they die, so resurrection calls them back to
+// where they fell - the caster must be standing in that room.
+func castResurrectSpell(c *Command, p PlayerInterface, spell *spells.Spell, targetName string) string {
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return "Internal error: invalid server type"
+	}
+
+	room, ok := GetRoom(p)
+	if !ok {
+		return "Error: You are not in a valid room."
+	}
+
+	target, ok := server.FindPlayer(targetName).(PlayerInterface)
+	if !ok {
+		return fmt.Sprintf("Player '%s' is not online.", targetName)
+	}
+	if target == p {
+		return "You can't resurrect yourself."
+	}
+
+	info, inParty := server.GetPartyInfo(p.GetName())
+	if !inParty || !containsName(info.Members, target.GetName()) {
+		return fmt.Sprintf("You can only resurrect members of your group, and %s isn't one.", target.GetName())
+	}
+
+	deathRoom, diedAt := target.GetLastDeath()
+	if deathRoom == "" || time.Since(diedAt) > resurrectionWindow {
+		return fmt.Sprintf("%s has not fallen recently.", target.GetName())
+	}
+	if deathRoom != room.GetID() {
+		return fmt.Sprintf("%s didn't fall here. You must stand where they died.", target.GetName())
+	}
+	if target.IsInCombat() {
+		return fmt.Sprintf("%s is fighting and can't answer your call.", target.GetName())
+	}
+
+	// Deduct mana
+	if !p.UseMana(spell.ManaCost) {
+		return "Not enough mana!"
+	}
+
+	// Record spell cast in statistics
+	p.RecordSpellCast()
+
+	// Start cooldown
+	if spell.Cooldown > 0 {
+		p.StartSpellCooldown(spell.ID, spell.Cooldown)
+	}
+
+	logger.Debug("Spell cast (resurrect)",
+		"player", p.GetName(),
+		"spell", spell.Name,
+		"target", target.GetName(),
+		"mana_cost", spell.ManaCost,
+		"cooldown", spell.Cooldown)
+
+	// Restore the target to at least the spell's percentage of their health
+	percent := 0
+	for _, effect := range spell.Effects {
+		if effect.Target == spells.TargetDeadAlly && effect.Type == spells.EffectResurrect {
+			percent = effect.Amount
+		}
+	}
+	if minHealth := (target.GetMaxHealth() * percent) / 100; target.GetHealth() < minHealth {
+		target.Heal(minHealth - target.GetHealth())
+	}
+	target.ClearLastDeath()
+
+	// Call the target back to where they fell (announce before they arrive)
+	server.BroadcastToRoom(room.GetID(), fmt.Sprintf("%s casts %s! %s rises where they fell.\n", p.GetName(), spell.Name, target.GetName()), p)
+	if targetRoom, ok := GetRoom(target); ok && targetRoom.GetID() != room.GetID() {
+		server.BroadcastToRoom(targetRoom.GetID(), fmt.Sprintf("%s fades away in a column of light.\n", target.GetName()), target)
+		target.MoveTo(room)
+	}
+	target.SendMessage(fmt.Sprintf("\n%s has resurrected you! You return to where you fell.\n\n%s", p.GetName(), room.GetDescriptionForPlayer(target.GetName())))
+
+	return fmt.Sprintf("You cast %s. %s rises where they fell.", spell.Name, target.GetName())
+}
+
+// containsName returns true if names contains name (case-insensitive).
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
 // castEnemySpell handles spells that target NPCs/enemies
 func castEnemySpell(c *Command, p PlayerInterface, spell *spells.Spell, targetNPC *npc.NPC, room RoomInterface) string {
-	// Check if NPC is attackable
-	if spell.HasDamageEffect() && !targetNPC.IsAttackable() {
+	// Check if NPC is attackable (every enemy effect is hostile)
+	if !targetNPC.IsAttackable() {
 		return fmt.Sprintf("You can't attack %s!", targetNPC.GetName())
 	}
 
@@ -301,6 +549,7 @@ func castEnemySpell(c *Command, p PlayerInterface, spell *spells.Spell, targetNP
 	// Apply effects
 	var results []string
 	totalDamage := 0
+	hostile := false
 
 	// Get INT modifier for spell damage
 	intMod := p.GetIntelligenceMod()
@@ -330,14 +579,12 @@ func castEnemySpell(c *Command, p PlayerInterface, spell *spells.Spell, targetNP
 			actualDamage := targetNPC.TakeMagicDamage(damage)
 			totalDamage += actualDamage
 			results = append(results, fmt.Sprintf("%d damage", actualDamage))
-		case spells.EffectRoot:
-			// Root prevents fleeing for duration seconds
-			duration := effect.Duration
-			if duration <= 0 {
-				duration = 30 // Default 30 seconds if not specified
+		case spells.EffectRoot, spells.EffectStun, spells.EffectDebuff, spells.EffectPoison:
+			// Root prevents fleeing, stun stops attacks, debuffs weaken, poison hurts every round
+			if result := applySpellStatus(targetNPC.Effects(), spell, effect, p.GetName()); result != "" {
+				results = append(results, result)
 			}
-			targetNPC.Root(duration)
-			results = append(results, fmt.Sprintf("rooted for %d seconds", duration))
+			hostile = true
 		}
 	}
 
@@ -350,7 +597,7 @@ func castEnemySpell(c *Command, p PlayerInterface, spell *spells.Spell, targetNP
 		if totalDamage > 0 {
 			result.WriteString(fmt.Sprintf("A burst of magical energy strikes %s for %s!", targetNPC.GetName(), effectStr))
 		} else {
-			result.WriteString(fmt.Sprintf("%s is %s!", targetNPC.GetName(), effectStr))
+			result.WriteString(fmt.Sprintf("%s is afflicted: %s.", targetNPC.GetName(), effectStr))
 		}
 	}
 
@@ -358,7 +605,7 @@ func castEnemySpell(c *Command, p PlayerInterface, spell *spells.Spell, targetNP
 	server.BroadcastToRoom(room.GetID(), fmt.Sprintf("%s casts %s at %s!\n", p.GetName(), spell.Name, targetNPC.GetName()), p)
 
 	// If we dealt damage or applied a hostile effect, initiate combat
-	if (spell.HasDamageEffect() && totalDamage > 0) || spell.HasRootEffect() {
+	if (spell.HasDamageEffect() && totalDamage > 0) || hostile {
 		if !p.IsInCombat() {
 			p.StartCombat(targetNPC.GetName())
 			targetNPC.StartCombat(p.GetName())
@@ -369,6 +616,33 @@ func castEnemySpell(c *Command, p PlayerInterface, spell *spells.Spell, targetNP
 	return result.String()
 }
 
+// applySpellStatus puts a spell effect's buff, debuff, poison, stun or root on a
+// target. Returns what took hold, for the caster, or "" if the effect isn't one
+// of those.
+func applySpellStatus(target *effects.Set, spell *spells.Spell, effect spells.SpellEffect, source string) string {
+	status, seconds, ok := effect.Status(spell)
+	if !ok {
+		return ""
+	}
+	status.Source = source
+	if !target.Apply(status, seconds) {
+		return fmt.Sprintf("immune to %s", status.Kind)
+	}
+	return fmt.Sprintf("%s for %d seconds", status.Describe(), seconds)
+}
+
+// cleanseResult describes what a cleanse removed
+func cleanseResult(removed []effects.Effect) string {
+	if len(removed) == 0 {
+		return "nothing to cleanse"
+	}
+	names := make([]string, len(removed))
+	for i, e := range removed {
+		names[i] = e.Name
+	}
+	return "cleansed " + strings.Join(names, ", ")
+}
+
 // castRoomSpell handles spells that affect all enemies in the room
 func castRoomSpell(c *Command, p PlayerInterface, spell *spells.Spell) string {
 	room, ok := GetRoom(p)
@@ -420,6 +694,7 @@ func castRoomSpell(c *Command, p PlayerInterface, spell *spells.Spell) string {
 
 	// Apply effects to all targets
 	var affectedNames []string
+	var statusResults []string
 	for _, targetNPC := range targetNPCs {
 		for _, effect := range spell.Effects {
 			if effect.Target != spells.TargetRoomEnemy {
@@ -428,8 +703,20 @@ func castRoomSpell(c *Command, p PlayerInterface, spell *spells.Spell) string {
 
 			switch effect.Type {
 			case spells.EffectStun:
-				targetNPC.Stun(effect.Amount)
-				affectedNames = append(affectedNames, targetNPC.GetName())
+				if targetNPC.Effects().Apply(effects.Effect{ID: spell.ID, Name: spell.Name, Kind: effects.KindStun, Source: p.GetName()}, effect.Amount) {
+					affectedNames = append(affectedNames, targetNPC.GetName())
+				}
+			case spells.EffectDebuff, spells.EffectPoison, spells.EffectRoot:
+				if result := applySpellStatus(targetNPC.Effects(), spell, effect, p.GetName()); result != "" {
+					statusResults = append(statusResults, fmt.Sprintf("%s: %s", targetNPC.GetName(), result))
+				}
+				// Poison keeps hurting, so the victims fight back (and the caster gets the kill)
+				if effect.Type == spells.EffectPoison {
+					targetNPC.StartCombat(p.GetName())
+					if !p.IsInCombat() {
+						p.StartCombat(targetNPC.GetName())
+					}
+				}
 			case spells.EffectDamage:
 				var damage int
 				if effect.Dice != "" {
@@ -467,6 +754,9 @@ func castRoomSpell(c *Command, p PlayerInterface, spell *spells.Spell) string {
 		result.WriteString(fmt.Sprintf("A blinding flash of light erupts from your hands!\n"))
 		result.WriteString(fmt.Sprintf("Stunned for %d seconds: %s", stunDuration, strings.Join(affectedNames, ", ")))
 	}
+	if len(statusResults) > 0 {
+		result.WriteString(strings.Join(statusResults, "\n"))
+	}
 
 	// Broadcast to room
 	server.BroadcastToRoom(room.GetID(), fmt.Sprintf("%s casts %s! A blinding flash of light fills the room!\n", p.GetName(), spell.Name), p)
@@ -300,6 +300,9 @@ func executeMailSend(args string, p PlayerInterface) string {
 	if recipientID == p.GetCharacterID() {
 		return "You cannot send mail to yourself."
 	}
+	if msg := checkMailRecipientMode(p, db, recipientID, recipientName); msg != "" {
+		return msg
+	}
 
 	mailCount, err := db.GetMailCount(recipientID)
 	if err != nil {
@@ -360,6 +363,9 @@ func executeMailSendFull(args string, p PlayerInterface, server ServerInterface,
 	if recipientID == p.GetCharacterID() {
 		return "You cannot send mail to yourself."
 	}
+	if msg := checkMailRecipientMode(p, db, recipientID, recipientName); msg != "" {
+		return msg
+	}
 
 	// Check if recipient's mailbox is full
 	mailCount, err := db.GetMailCount(recipientID)
@@ -601,6 +607,24 @@ func executeMailReply(idStr string, p PlayerInterface) string {
 
 // Helper functions
 
+// checkMailRecipientMode returns why the player can't mail the recipient, or
+// "" if they can. Fallen hardcore characters get no mail, and hardcore
+// characters only exchange mail with each other.
+func checkMailRecipientMode(p PlayerInterface, db *database.Database, recipientID int64, recipientName string) string {
+	hardcore, retired, err := db.GetCharacterMode(recipientID)
+	if err != nil {
+		logger.Error("Failed to look up recipient mode", "error", err, "recipient", recipientName)
+		return "Failed to look up recipient."
+	}
+	if retired {
+		return fmt.Sprintf("%s has fallen and will never read another letter.", recipientName)
+	}
+	if hardcoreApart(p, hardcore) {
+		return "Hardcore characters can only exchange mail with other hardcore characters."
+	}
+	return ""
+}
+
 func formatMailTime(t time.Time) string {
 	now := time.Now()
 	diff := now.Sub(t)
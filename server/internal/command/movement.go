@@ -3,6 +3,7 @@ package command
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
@@ -64,6 +65,9 @@ func executeLook(c *Command, p PlayerInterface) string {
 			desc += stallInfo
 		}
 
+		// Append corpses waiting to be recovered
+		desc += getCorpsesInRoom(server, room)
+
 		return desc
 	}
 
@@ -162,6 +166,11 @@ func formatPlayerDescription(target PlayerInterface) string {
 		sb.WriteString(fmt.Sprintf("%s, a level %d %s %s.\n", name, level, raceName, className))
 	}
 
+	// Show guild membership
+	if guildName := target.GetGuildName(); guildName != "" {
+		sb.WriteString(fmt.Sprintf("%s of %s [%s].\n", target.GetGuildRank().Title(), guildName, target.GetGuildTag()))
+	}
+
 	// Show if they have a stall open
 	if target.IsStallOpen() {
 		stallItems := target.GetStallInventory()
@@ -923,9 +932,32 @@ func getPlayersWithStallsInRoom(currentPlayer PlayerInterface, server ServerInte
 		}
 	}
 
+	// Stalls left open by offline players
+	unattended := server.GetUnattendedStalls(room.GetID())
+	owners := make([]string, 0, len(unattended))
+	for ownerName := range unattended {
+		owners = append(owners, ownerName)
+	}
+	sort.Strings(owners)
+	for _, ownerName := range owners {
+		stallOwners = append(stallOwners, fmt.Sprintf("%s (%d items, unattended)", ownerName, unattended[ownerName]))
+	}
+
 	if len(stallOwners) == 0 {
 		return ""
 	}
 
 	return "\nPlayer stalls: " + strings.Join(stallOwners, ", ") + "\n"
 }
+
+// getCorpsesInRoom returns a line for each player corpse lying in the room
+func getCorpsesInRoom(server ServerInterface, room RoomInterface) string {
+	var sb strings.Builder
+	for _, owner := range server.GetCorpseOwnersInRoom(room.GetID()) {
+		sb.WriteString(fmt.Sprintf("\nThe corpse of {player}%s{/} lies here.", owner))
+	}
+	if sb.Len() == 0 {
+		return ""
+	}
+	return sb.String() + "\n"
+}
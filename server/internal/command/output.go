@@ -0,0 +1,74 @@
+package command
+
+// Message kinds classify output so structured clients (e.g., the browser client in
+// JSON mode) can tell a room description from a tell from a combat round.
+// Plain-text clients ignore the kind.
+const (
+	MessageRoom   = "room"
+	MessageCombat = "combat"
+	MessageChat   = "chat"
+	MessagePrompt = "prompt"
+	MessageSystem = "system"
+	MessageVitals = "vitals"
+)
+
+// commandOutputKinds maps command names to the kind of output they produce.
+// Commands not listed produce MessageSystem output.
+var commandOutputKinds = map[string]string{
+	// Room display and movement
+	"look":   MessageRoom,
+	"l":      MessageRoom,
+	"go":     MessageRoom,
+	"move":   MessageRoom,
+	"walk":   MessageRoom,
+	"north":  MessageRoom,
+	"n":      MessageRoom,
+	"south":  MessageRoom,
+	"s":      MessageRoom,
+	"east":   MessageRoom,
+	"e":      MessageRoom,
+	"west":   MessageRoom,
+	"w":      MessageRoom,
+	"up":     MessageRoom,
+	"u":      MessageRoom,
+	"down":   MessageRoom,
+	"d":      MessageRoom,
+	"enter":  MessageRoom,
+	"leave":  MessageRoom,
+	"exits":  MessageRoom,
+	"portal": MessageRoom,
+
+	// Chat
+	"say":   MessageChat,
+	"tell":  MessageChat,
+	"shout": MessageChat,
+	"yell":  MessageChat,
+	"emote": MessageChat,
+	"me":    MessageChat,
+	"gtell": MessageChat,
+	"gt":    MessageChat,
+	"gchat": MessageChat,
+	"gc":    MessageChat,
+
+	// Combat
+	"attack": MessageCombat,
+	"kill":   MessageCombat,
+	"hit":    MessageCombat,
+	"flee":   MessageCombat,
+	"defend": MessageCombat,
+	"cast":   MessageCombat,
+	"need":   MessageCombat,
+	"greed":  MessageCombat,
+	"pass":   MessageCombat,
+}
+
+// OutputKind returns the message kind for this command's output.
+func (c *Command) OutputKind() string {
+	if kind, ok := commandOutputKinds[c.Name]; ok {
+		return kind
+	}
+	if c.kind != "" {
+		return c.kind
+	}
+	return MessageSystem
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/lawnchairsociety/opentowermud/server/internal/color"
 	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
 )
 
@@ -56,8 +57,9 @@ func executeSay(c *Command, p PlayerInterface) string {
 		}
 	}
 
-	broadcastMsg := fmt.Sprintf("%s says: \"%s\"\n", p.GetName(), filteredMessage)
+	broadcastMsg := fmt.Sprintf("{say}%s says: \"%s\"{/}\n", p.GetName(), color.Escape(filteredMessage))
 	server.BroadcastToRoomFromPlayer(room.GetID(), broadcastMsg, p, p.GetName())
+	server.SendChannelToRoom(room.GetID(), "say", p.GetName(), filteredMessage)
 
 	// AUDIT LOG - Always logged regardless of log level (security/moderation)
 	logger.Always("CHAT_SAY",
@@ -65,7 +67,7 @@ func executeSay(c *Command, p PlayerInterface) string {
 		"room", room.GetID(),
 		"message", filteredMessage)
 
-	return fmt.Sprintf("You say: \"%s\"", filteredMessage)
+	return fmt.Sprintf("{say}You say: \"%s\"{/}", color.Escape(filteredMessage))
 }
 
 // executeWho lists all online players
@@ -84,18 +86,26 @@ func executeWho(c *Command, p PlayerInterface) string {
 
 	result := "Online Players:\n"
 	for _, playerName := range players {
-		// Try to get the player's title
+		entry := playerName
+		// Try to get the player's guild, title and connection state
 		playerIface := server.FindPlayer(playerName)
 		if playerIface != nil {
 			if player, ok := playerIface.(PlayerInterface); ok {
-				title := player.GetActiveTitle()
-				if title != "" {
-					result += fmt.Sprintf("  - %s (%s)\n", playerName, title)
-					continue
+				if tag := player.GetGuildTag(); tag != "" {
+					entry += fmt.Sprintf(" [%s]", tag)
+				}
+				if title := player.GetActiveTitle(); title != "" {
+					entry += fmt.Sprintf(" (%s)", title)
+				}
+				if player.IsHardcore() {
+					entry += " [hardcore]"
+				}
+				if player.IsLinkDead() {
+					entry += " [link-dead]"
 				}
 			}
 		}
-		result += fmt.Sprintf("  - %s\n", playerName)
+		result += fmt.Sprintf("  - %s\n", entry)
 	}
 	return result
 }
@@ -179,11 +189,18 @@ func executeTell(c *Command, p PlayerInterface) string {
 			"recipient", target.GetName(),
 			"message", filteredMessage)
 		// Pretend message was sent (don't reveal ignore status)
-		return fmt.Sprintf("You tell %s: \"%s\"", target.GetName(), filteredMessage)
+		return fmt.Sprintf("{tell}You tell %s: \"%s\"{/}", target.GetName(), color.Escape(filteredMessage))
+	}
+
+	// Link-dead players can't see messages until they reconnect
+	if target.IsLinkDead() {
+		return fmt.Sprintf("%s is link-dead and can't hear you right now.", target.GetName())
 	}
 
 	// Send message to target
-	target.SendMessage(fmt.Sprintf("%s tells you: \"%s\"\n", p.GetName(), filteredMessage))
+	target.SendTyped(MessageChat, fmt.Sprintf("{tell}%s tells you: \"%s\"{/}\n", p.GetName(), color.Escape(filteredMessage)))
+	target.SendChannelGMCP("tell", p.GetName(), filteredMessage)
+	p.SendChannelGMCP("tell", p.GetName(), filteredMessage)
 
 	// AUDIT LOG - Always logged regardless of log level (security/moderation)
 	logger.Always("CHAT_TELL",
@@ -191,7 +208,7 @@ func executeTell(c *Command, p PlayerInterface) string {
 		"recipient", target.GetName(),
 		"message", filteredMessage)
 
-	return fmt.Sprintf("You tell %s: \"%s\"", target.GetName(), filteredMessage)
+	return fmt.Sprintf("{tell}You tell %s: \"%s\"{/}", target.GetName(), color.Escape(filteredMessage))
 }
 
 // executeShout broadcasts a message to all players on the same floor
@@ -244,8 +261,9 @@ func executeShout(c *Command, p PlayerInterface) string {
 	}
 
 	floor := room.GetFloor()
-	broadcastMsg := fmt.Sprintf("%s shouts: \"%s\"\n", p.GetName(), filteredMessage)
+	broadcastMsg := fmt.Sprintf("{shout}%s shouts: \"%s\"{/}\n", p.GetName(), color.Escape(filteredMessage))
 	server.BroadcastToFloorFromPlayer(floor, broadcastMsg, p, p.GetName())
+	server.SendChannelToFloor(floor, "shout", p.GetName(), filteredMessage)
 
 	// AUDIT LOG - Always logged regardless of log level (security/moderation)
 	logger.Always("CHAT_SHOUT",
@@ -253,7 +271,7 @@ func executeShout(c *Command, p PlayerInterface) string {
 		"floor", floor,
 		"message", filteredMessage)
 
-	return fmt.Sprintf("You shout: \"%s\"", filteredMessage)
+	return fmt.Sprintf("{shout}You shout: \"%s\"{/}", color.Escape(filteredMessage))
 }
 
 // executeEmote performs a custom action visible to everyone in the room
@@ -306,7 +324,7 @@ func executeEmote(c *Command, p PlayerInterface) string {
 	}
 
 	// Format: "PlayerName laughs" (no quotes around action)
-	broadcastMsg := fmt.Sprintf("%s %s\n", p.GetName(), filteredAction)
+	broadcastMsg := fmt.Sprintf("{player}%s{/} %s\n", p.GetName(), color.Escape(filteredAction))
 	server.BroadcastToRoomFromPlayer(room.GetID(), broadcastMsg, p, p.GetName())
 
 	// AUDIT LOG - Always logged regardless of log level (security/moderation)
@@ -315,7 +333,7 @@ func executeEmote(c *Command, p PlayerInterface) string {
 		"room", room.GetID(),
 		"action", filteredAction)
 
-	return fmt.Sprintf("%s %s", p.GetName(), filteredAction)
+	return fmt.Sprintf("%s %s", p.GetName(), color.Escape(filteredAction))
 }
 
 // executeQuit disconnects the player (progress is auto-saved on disconnect)
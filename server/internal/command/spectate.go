@@ -0,0 +1,70 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+)
+
+// spectatorCommands are the commands a spectator can use. Anything that acts
+// on the world is blocked until they stop watching.
+var spectatorCommands = map[string]bool{
+	"spectate": true,
+	"help":     true,
+	"who":      true,
+	"tell":     true,
+	"gtell":    true,
+	"gt":       true,
+	"gchat":    true,
+	"gc":       true,
+	"channels": true,
+	"channel":  true,
+	"time":     true,
+	"score":    true,
+	"sc":       true,
+	"prompt":   true,
+	"color":    true,
+	"colour":   true,
+	"clear":    true,
+	"quit":     true,
+	"exit":     true,
+}
+
+// isSpectating returns true if the player is watching a fight read-only.
+func isSpectating(p PlayerInterface) bool {
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return false
+	}
+	target, spectating := server.GetWatchTarget(p.GetName())
+	return target != "" && spectating
+}
+
+// executeSpectate watches another player's boss fight, or stops watching
+func executeSpectate(c *Command, p PlayerInterface) string {
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return "Internal error: invalid server type"
+	}
+
+	if len(c.Args) == 0 {
+		if target, spectating := server.GetWatchTarget(p.GetName()); target != "" && spectating {
+			return fmt.Sprintf("You are spectating %s's fight. Type 'spectate stop' to stop watching.", target)
+		}
+		return "Usage: spectate <player> | spectate stop\nWatch another player's boss fight as it happens."
+	}
+
+	arg := strings.ToLower(c.Args[0])
+	if arg == "stop" || arg == "off" {
+		target, spectating := server.GetWatchTarget(p.GetName())
+		if target == "" || !spectating {
+			return "You aren't spectating anyone."
+		}
+		server.StopWatching(p.GetName())
+		return fmt.Sprintf("You stop spectating %s.", target)
+	}
+
+	if err := server.StartSpectate(p.GetName(), c.Args[0]); err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("You are now spectating %s's fight. Type 'spectate stop' to stop watching.", c.Args[0])
+}
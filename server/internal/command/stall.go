@@ -51,8 +51,11 @@ Other players can use:
 
 Notes:
   - You can only open a stall in the city (floor 0)
-  - Your stall closes automatically if you leave the room or disconnect
-  - Items in your stall are not in your inventory until you remove them`
+  - Your stall closes automatically if you leave the room
+  - An open stall stays open when you log out; sales are paid by mail
+    and you'll get a report of them when you return
+  - Items in your stall are not in your inventory until you remove them
+  - Your stall is kept between sessions, open or closed`
 }
 
 // executeStallOpen opens the player's stall for business
@@ -166,6 +169,10 @@ func executeStallAdd(c *Command, p PlayerInterface) string {
 	if !found {
 		return fmt.Sprintf("You don't have '%s' in your inventory.", itemName)
 	}
+	// A saved stall doesn't keep wear, so only sound goods can be sold
+	if item.Wear > 0 {
+		return fmt.Sprintf("Your %s is worn. Have it repaired before you sell it.", item.Name)
+	}
 
 	// Remove from inventory and add to stall
 	removedItem, removed := p.RemoveItem(item.Name)
@@ -278,6 +285,10 @@ func executeBrowse(c *Command, p PlayerInterface) string {
 	// Find the target player
 	targetIface := server.FindPlayer(targetName)
 	if targetIface == nil {
+		// Their stall may still be open without them
+		if ownerName, stallItems, ok := server.GetUnattendedStall(room.GetID(), targetName); ok {
+			return formatStall(ownerName, stallItems, true, p)
+		}
 		return fmt.Sprintf("Player '%s' is not online.", targetName)
 	}
 
@@ -307,7 +318,15 @@ func executeBrowse(c *Command, p PlayerInterface) string {
 		return fmt.Sprintf("%s's stall is empty.", target.GetName())
 	}
 
-	result := fmt.Sprintf("\n=== %s's Stall ===\n", target.GetName())
+	return formatStall(target.GetName(), stallItems, false, p)
+}
+
+// formatStall shows the items for sale in a player's stall
+func formatStall(ownerName string, stallItems []*StallItem, unattended bool, p PlayerInterface) string {
+	result := fmt.Sprintf("\n=== %s's Stall ===\n", ownerName)
+	if unattended {
+		result += fmt.Sprintf("%s is away. Payment for anything you buy will be sent to them by mail.\n", ownerName)
+	}
 	result += fmt.Sprintf("Your gold: %d\n\n", p.GetGold())
 	result += "Items for sale:\n"
 
@@ -315,7 +334,7 @@ func executeBrowse(c *Command, p PlayerInterface) string {
 		result += fmt.Sprintf("  %-25s %5d gold - %s\n", stallItem.Item.Name, stallItem.Price, stallItem.Item.Description)
 	}
 
-	result += fmt.Sprintf("\nTo purchase: purchase <item> from %s", target.GetName())
+	result += fmt.Sprintf("\nTo purchase: purchase <item> from %s", ownerName)
 
 	return result
 }
@@ -358,7 +377,8 @@ func executePurchase(c *Command, p PlayerInterface) string {
 	// Find the target player
 	targetIface := server.FindPlayer(targetName)
 	if targetIface == nil {
-		return fmt.Sprintf("Player '%s' is not online.", targetName)
+		// Buy from their unattended stall instead
+		return executePurchaseUnattended(p, server, targetName, itemName)
 	}
 
 	target, ok := targetIface.(PlayerInterface)
@@ -386,6 +406,9 @@ func executePurchase(c *Command, p PlayerInterface) string {
 	if !target.IsStallOpen() {
 		return fmt.Sprintf("%s does not have a stall open.", target.GetName())
 	}
+	if hardcoreApart(p, target.IsHardcore()) {
+		return "Hardcore characters can only trade with other hardcore characters."
+	}
 
 	// Find the item in the target's stall
 	stallItem, found := target.FindInStall(itemName)
@@ -431,3 +454,18 @@ func executePurchase(c *Command, p PlayerInterface) string {
 	return fmt.Sprintf("You purchase %s from %s for %d gold.\nGold remaining: %d",
 		removedStallItem.Item.Name, target.GetName(), removedStallItem.Price, p.GetGold())
 }
+
+// executePurchaseUnattended buys an item from an offline player's stall
+func executePurchaseUnattended(p PlayerInterface, server ServerInterface, ownerName, itemName string) string {
+	stallItem, err := server.BuyFromUnattendedStall(p.GetName(), ownerName, itemName)
+	if err != nil {
+		return err.Error()
+	}
+
+	if saveErr := server.SavePlayer(p); saveErr != nil {
+		logger.Warning("Failed to save player after stall purchase", "player", p.GetName(), "error", saveErr)
+	}
+
+	return fmt.Sprintf("You purchase %s from the unattended stall for %d gold. The payment will be mailed to its owner.\nGold remaining: %d",
+		stallItem.Item.Name, stallItem.Price, p.GetGold())
+}
@@ -0,0 +1,153 @@
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TradeOffer is one side of a trade window.
+type TradeOffer struct {
+	Items    []string // Names of the offered items
+	Gold     int
+	Accepted bool
+}
+
+// TradeWindow describes a player's open trade.
+type TradeWindow struct {
+	Partner string
+	Mine    TradeOffer
+	Theirs  TradeOffer
+}
+
+const tradeUsage = "Usage: trade <player> | trade offer <item>|<amount> gold | trade remove <item>|gold | trade accept | trade cancel"
+
+// executeTrade handles the trade command and its subcommands
+func executeTrade(c *Command, p PlayerInterface) string {
+	server, ok := p.GetServer().(ServerInterface)
+	if !ok {
+		return "Internal error: invalid server type"
+	}
+
+	if len(c.Args) == 0 {
+		return showTradeWindow(p, server)
+	}
+
+	sub := strings.ToLower(c.Args[0])
+	arg := strings.Join(c.Args[1:], " ")
+
+	switch sub {
+	case "offer", "add":
+		if arg == "" {
+			return "Usage: trade offer <item> or trade offer <amount> gold"
+		}
+		if amount, isGold := parseTradeGold(c.Args[1:]); isGold {
+			if amount <= 0 {
+				return "Invalid amount. Usage: trade offer <amount> gold"
+			}
+			if err := server.OfferTradeGold(p.GetName(), amount); err != nil {
+				return err.Error()
+			}
+			return fmt.Sprintf("You offer {gold}%d{/} gold.", amount)
+		}
+		name, err := server.OfferTradeItem(p.GetName(), arg)
+		if err != nil {
+			return err.Error()
+		}
+		return fmt.Sprintf("You offer {item}%s{/}.", name)
+
+	case "remove", "withdraw":
+		if arg == "" {
+			return "Usage: trade remove <item> or trade remove gold"
+		}
+		if strings.EqualFold(arg, "gold") {
+			if err := server.OfferTradeGold(p.GetName(), 0); err != nil {
+				return err.Error()
+			}
+			return "You take back your gold."
+		}
+		name, err := server.WithdrawTradeItem(p.GetName(), arg)
+		if err != nil {
+			return err.Error()
+		}
+		return fmt.Sprintf("You take back {item}%s{/}.", name)
+
+	case "accept":
+		completed, err := server.AcceptTrade(p.GetName())
+		if err != nil {
+			return "The trade could not be completed: " + err.Error()
+		}
+		if completed {
+			return "The trade is complete."
+		}
+		window, _ := server.GetTradeWindow(p.GetName())
+		return fmt.Sprintf("You accept the trade. Waiting for %s to accept.", window.Partner)
+
+	case "cancel", "decline":
+		if err := server.CancelTrade(p.GetName()); err != nil {
+			return err.Error()
+		}
+		return "You cancel the trade."
+
+	default:
+		if len(c.Args) > 1 {
+			return tradeUsage
+		}
+		target := c.Args[0]
+		opened, err := server.RequestTrade(p.GetName(), target)
+		if err != nil {
+			return err.Error()
+		}
+		if opened {
+			window, _ := server.GetTradeWindow(p.GetName())
+			return fmt.Sprintf("You open a trade with %s. Use 'trade offer' to put up items or gold.", window.Partner)
+		}
+		return fmt.Sprintf("You ask %s to trade.", target)
+	}
+}
+
+// parseTradeGold recognises "<amount> gold". Returns false if the args name an item.
+func parseTradeGold(args []string) (int, bool) {
+	if len(args) != 2 || !strings.EqualFold(args[1], "gold") {
+		return 0, false
+	}
+	amount, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, false
+	}
+	return amount, true
+}
+
+// showTradeWindow shows both sides of the player's open trade
+func showTradeWindow(p PlayerInterface, server ServerInterface) string {
+	window, ok := server.GetTradeWindow(p.GetName())
+	if !ok {
+		return "You aren't trading with anyone.\n" + tradeUsage
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Trading with {player}%s{/}:\n", window.Partner))
+	writeTradeOffer(&result, "You offer", window.Mine)
+	writeTradeOffer(&result, window.Partner+" offers", window.Theirs)
+	result.WriteString("\nAny change to either offer withdraws both acceptances.")
+	return result.String()
+}
+
+// writeTradeOffer writes one side of a trade window
+func writeTradeOffer(result *strings.Builder, heading string, offer TradeOffer) {
+	status := "not accepted"
+	if offer.Accepted {
+		status = "accepted"
+	}
+	result.WriteString(fmt.Sprintf("\n%s (%s):\n", heading, status))
+	if len(offer.Items) == 0 && offer.Gold == 0 {
+		result.WriteString("  Nothing\n")
+		return
+	}
+	for _, name := range offer.Items {
+		result.WriteString(fmt.Sprintf("  {item}%s{/}\n", name))
+	}
+	if offer.Gold > 0 {
+		result.WriteString(fmt.Sprintf("  {gold}%d{/} gold\n", offer.Gold))
+	}
+}
@@ -20,6 +20,10 @@ type ServerConfig struct {
 	Game        GameConfig        `yaml:"game"`
 	Website     WebsiteConfig     `yaml:"website"`
 	Database    DatabaseConfig    `yaml:"database"`
+	Admin       AdminConfig       `yaml:"admin"`
+	TLS         TLSConfig         `yaml:"tls"`
+	Input       InputConfig       `yaml:"input"`
+	Death       DeathConfig       `yaml:"death"`
 }
 
 // DatabaseConfig holds database connection settings.
@@ -84,6 +88,7 @@ type PathsConfig struct {
 	Logging    string `yaml:"logging"`
 	ChatFilter string `yaml:"chat_filter"`
 	NameFilter string `yaml:"name_filter"`
+	Channels   string `yaml:"channels"`
 }
 
 // GameConfig holds game-specific configuration.
@@ -103,6 +108,96 @@ type SessionConfig struct {
 	// AutoSaveIntervalMinutes is how often player progress is automatically saved.
 	// 0 means auto-save is disabled (players must save manually).
 	AutoSaveIntervalMinutes int `yaml:"auto_save_interval_minutes"`
+
+	// LinkDeadGraceSeconds is how long a player whose connection dropped stays in
+	// the world waiting to reconnect before being saved and removed.
+	// 0 means players are removed as soon as their connection drops.
+	LinkDeadGraceSeconds int `yaml:"linkdead_grace_seconds"`
+}
+
+// TLSConfig holds settings for the optional TLS telnet listener.
+type TLSConfig struct {
+	// Enabled starts a second telnet listener that requires TLS ("telnet over TLS").
+	// The plain telnet port stays open.
+	Enabled bool `yaml:"enabled"`
+
+	// Port is the TLS telnet port (default: 4001).
+	Port int `yaml:"port"`
+
+	// CertFile and KeyFile are the PEM-encoded certificate chain and private key.
+	// Both are re-read on SIGHUP, so renewed certificates take effect without a restart.
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+}
+
+// InputConfig holds settings for command stacking and speedwalk.
+type InputConfig struct {
+	// CommandSeparator splits one line into several queued commands (e.g., "n;n;open door").
+	// Empty disables command stacking.
+	CommandSeparator string `yaml:"command_separator"`
+
+	// Speedwalk expands direction strings like "3n2e" into one move per step.
+	Speedwalk bool `yaml:"speedwalk"`
+
+	// QueueDelayMs is the pause between queued commands, so a fight can interrupt a walk.
+	QueueDelayMs int `yaml:"queue_delay_ms"`
+
+	// MaxQueued is the most commands a player can have waiting. Extra commands are dropped.
+	// 0 means no limit (not recommended).
+	MaxQueued int `yaml:"max_queued"`
+}
+
+// DeathConfig holds the penalties for dying. Every penalty is off by default,
+// so a death only sends the player back to their tower's starting room.
+type DeathConfig struct {
+	// XPDebtPercent is how much of the experience needed for the player's next
+	// level becomes a debt. Half of all experience earned pays the debt off
+	// until it is cleared. 0 disables XP debt.
+	XPDebtPercent int `yaml:"xp_debt_percent"`
+
+	// GoldLossPercent is how much of the gold the player carries is lost.
+	// Gold in the bank is safe. 0 disables gold loss.
+	GoldLossPercent int `yaml:"gold_loss_percent"`
+
+	// DurabilityLossPercent is how much wear each equipped item takes.
+	// Items at 100% wear are broken and give no benefit until repaired.
+	// 0 disables durability loss.
+	DurabilityLossPercent int `yaml:"durability_loss_percent"`
+
+	// Corpse leaves the player's inventory in a corpse where they died.
+	// It must be recovered with "corpse recover" before it decays.
+	Corpse bool `yaml:"corpse"`
+
+	// CorpseMinutes is how long a corpse lasts before it decays, along with
+	// everything in it.
+	CorpseMinutes int `yaml:"corpse_minutes"`
+}
+
+// Snoop notification policies for AdminConfig.SnoopNotify.
+const (
+	SnoopNotifyAlways = "always" // Target is told when snooping starts and stops
+	SnoopNotifyNever  = "never"  // Snooping is silent
+	SnoopNotifyAdmins = "admins" // Only admins are told, so admins can't silently snoop each other
+)
+
+// AdminConfig holds settings for admin tools.
+type AdminConfig struct {
+	// SnoopNotify controls whether a player is told when an admin snoops them:
+	// "always" (default), "never", or "admins".
+	SnoopNotify string `yaml:"snoop_notify"`
+}
+
+// NotifySnoopTarget returns true if a snooped player should be told about it.
+// Unknown policies fall back to "always".
+func (c *AdminConfig) NotifySnoopTarget(targetIsAdmin bool) bool {
+	switch strings.ToLower(c.SnoopNotify) {
+	case SnoopNotifyNever:
+		return false
+	case SnoopNotifyAdmins:
+		return targetIsAdmin
+	default:
+		return true
+	}
 }
 
 // RateLimitConfig holds rate limiting settings for login attempts.
@@ -181,8 +276,9 @@ func DefaultConfig() *ServerConfig {
 			MaxLockoutSeconds: 300, // Default: 5 minute max lockout
 		},
 		Session: SessionConfig{
-			IdleTimeoutMinutes:      30, // Default: 30 minutes idle timeout
-			AutoSaveIntervalMinutes: 5,  // Default: auto-save every 5 minutes
+			IdleTimeoutMinutes:      30,  // Default: 30 minutes idle timeout
+			AutoSaveIntervalMinutes: 5,   // Default: auto-save every 5 minutes
+			LinkDeadGraceSeconds:    120, // Default: 2 minutes to reconnect
 		},
 		Paths: PathsConfig{
 			DataDir:    "data",
@@ -200,6 +296,7 @@ func DefaultConfig() *ServerConfig {
 			Logging:    "data/logging.yaml",
 			ChatFilter: "data/chat_filter.yaml",
 			NameFilter: "data/name_filter.yaml",
+			Channels:   "data/channels.yaml",
 		},
 		Game: GameConfig{
 			Seed:          0,                 // 0 = random seed based on time
@@ -209,6 +306,22 @@ func DefaultConfig() *ServerConfig {
 		Website: WebsiteConfig{
 			URL: "", // Empty = in-game registration enabled
 		},
+		Admin: AdminConfig{
+			SnoopNotify: SnoopNotifyAlways,
+		},
+		TLS: TLSConfig{
+			Enabled: false, // Plain telnet only by default
+			Port:    4001,
+		},
+		Input: InputConfig{
+			CommandSeparator: ";",
+			Speedwalk:        true,
+			QueueDelayMs:     250, // Default: 4 queued commands per second
+			MaxQueued:        50,
+		},
+		Death: DeathConfig{
+			CorpseMinutes: 30, // Only used when corpses are enabled
+		},
 		Database: DatabaseConfig{
 			Driver:     "sqlite", // Default to SQLite for backward compatibility
 			SQLitePath: "data/opentowermud.db",
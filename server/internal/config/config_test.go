@@ -274,3 +274,53 @@ func TestItoa(t *testing.T) {
 		}
 	}
 }
+
+func TestNotifySnoopTarget(t *testing.T) {
+	tests := []struct {
+		policy        string
+		targetIsAdmin bool
+		expected      bool
+	}{
+		{SnoopNotifyAlways, false, true},
+		{SnoopNotifyNever, false, false},
+		{SnoopNotifyNever, true, false},
+		{SnoopNotifyAdmins, false, false},
+		{SnoopNotifyAdmins, true, true},
+		{"", false, true},      // Unset falls back to always
+		{"bogus", false, true}, // Unknown falls back to always
+	}
+
+	for _, tt := range tests {
+		cfg := AdminConfig{SnoopNotify: tt.policy}
+		if got := cfg.NotifySnoopTarget(tt.targetIsAdmin); got != tt.expected {
+			t.Errorf("NotifySnoopTarget(%q, admin=%v) = %v, want %v", tt.policy, tt.targetIsAdmin, got, tt.expected)
+		}
+	}
+}
+
+func TestLoadConfig_DeathPolicy(t *testing.T) {
+	if d := DefaultConfig().Death; d.XPDebtPercent != 0 || d.GoldLossPercent != 0 || d.DurabilityLossPercent != 0 || d.Corpse {
+		t.Errorf("expected every death penalty to be off by default, got %+v", d)
+	}
+
+	configPath := filepath.Join(t.TempDir(), "server.yaml")
+	content := `
+death:
+  xp_debt_percent: 10
+  corpse: true
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Death.XPDebtPercent != 10 || !cfg.Death.Corpse {
+		t.Errorf("expected XP debt and corpses to be enabled, got %+v", cfg.Death)
+	}
+	if cfg.Death.CorpseMinutes != 30 {
+		t.Errorf("expected corpses to keep the default 30 minutes, got %d", cfg.Death.CorpseMinutes)
+	}
+}
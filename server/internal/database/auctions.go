@@ -0,0 +1,419 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/auction"
+)
+
+// auctionColumns lists the auctions columns in the order scanAuction reads them.
+const auctionColumns = `id, seller_id, seller_name, item_id, item_name, item_type, item_slot, item_tier,
+	min_bid, buyout, current_bid, bidder_id, bidder_name, listed_at, expires_at`
+
+// scanner is satisfied by both *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanAuction reads a listing selected with auctionColumns.
+func scanAuction(row scanner) (*auction.Listing, error) {
+	l := &auction.Listing{}
+	err := row.Scan(&l.ID, &l.SellerID, &l.SellerName, &l.ItemID, &l.ItemName, &l.ItemType, &l.ItemSlot, &l.ItemTier,
+		&l.MinBid, &l.Buyout, &l.CurrentBid, &l.BidderID, &l.BidderName, &l.ListedAt, &l.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// CreateAuction lists an item on the auction house for the given duration.
+// Returns the new listing's ID.
+func (d *Database) CreateAuction(l *auction.Listing, duration time.Duration) (int64, error) {
+	if l.Buyout <= 0 || l.MinBid < 0 || (l.MinBid > 0 && l.MinBid >= l.Buyout) {
+		return 0, auction.ErrInvalidPrice
+	}
+
+	// Stored in UTC so expiry times compare correctly as text in SQLite
+	now := time.Now().UTC()
+	query := `INSERT INTO auctions (seller_id, seller_name, item_id, item_name, item_type, item_slot, item_tier,
+		min_bid, buyout, listed_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	args := []interface{}{l.SellerID, l.SellerName, l.ItemID, l.ItemName, l.ItemType, l.ItemSlot, l.ItemTier,
+		l.MinBid, l.Buyout, now, now.Add(duration)}
+
+	var id int64
+	if d.dialect.SupportsLastInsertID() {
+		result, err := d.db.Exec(d.qb.Build(query), args...)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert auction: %w", err)
+		}
+		id, err = result.LastInsertId()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get auction ID: %w", err)
+		}
+	} else {
+		// PostgreSQL: use RETURNING clause
+		if err := d.db.QueryRow(d.qb.BuildWithReturning(query, "id"), args...).Scan(&id); err != nil {
+			return 0, fmt.Errorf("failed to insert auction: %w", err)
+		}
+	}
+
+	return id, nil
+}
+
+// GetAuction returns a listing by ID, or nil if it doesn't exist.
+func (d *Database) GetAuction(id int64) (*auction.Listing, error) {
+	l, err := scanAuction(d.db.QueryRow(d.qb.Build(`SELECT `+auctionColumns+` FROM auctions WHERE id = ?`), id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query auction: %w", err)
+	}
+	return l, nil
+}
+
+// SearchAuctions returns the active listings matching a filter, ending soonest first.
+func (d *Database) SearchAuctions(f auction.SearchFilter, limit int) ([]*auction.Listing, error) {
+	conditions := []string{"expires_at > ?"}
+	args := []interface{}{time.Now().UTC()}
+
+	if f.Name != "" {
+		conditions = append(conditions, "LOWER(item_name) LIKE ?")
+		args = append(args, "%"+strings.ToLower(f.Name)+"%")
+	}
+	if f.ItemType != "" {
+		conditions = append(conditions, "item_type = ?")
+		args = append(args, strings.ToLower(f.ItemType))
+	}
+	if f.Slot != "" {
+		conditions = append(conditions, "item_slot = ?")
+		args = append(args, strings.ToLower(f.Slot))
+	}
+	if f.Tier > 0 {
+		conditions = append(conditions, "item_tier = ?")
+		args = append(args, f.Tier)
+	}
+	if f.SellerID != 0 {
+		conditions = append(conditions, "seller_id = ?")
+		args = append(args, f.SellerID)
+	}
+	if f.BidderID != 0 {
+		conditions = append(conditions, "bidder_id = ?")
+		args = append(args, f.BidderID)
+	}
+	args = append(args, limit)
+
+	rows, err := d.db.Query(d.qb.Build(`SELECT `+auctionColumns+` FROM auctions
+		WHERE `+strings.Join(conditions, " AND ")+`
+		ORDER BY expires_at ASC, id ASC
+		LIMIT ?`), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search auctions: %w", err)
+	}
+	defer rows.Close()
+
+	var listings []*auction.Listing
+	for rows.Next() {
+		l, err := scanAuction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan auction: %w", err)
+		}
+		listings = append(listings, l)
+	}
+	return listings, rows.Err()
+}
+
+// CountAuctionsBySeller returns how many listings a seller has up.
+func (d *Database) CountAuctionsBySeller(sellerID int64) (int, error) {
+	var count int
+	err := d.db.QueryRow(d.qb.Build(`SELECT COUNT(*) FROM auctions WHERE seller_id = ?`), sellerID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count auctions: %w", err)
+	}
+	return count, nil
+}
+
+// getActiveAuctionTx loads a listing that hasn't expired within a transaction.
+func (d *Database) getActiveAuctionTx(tx *sql.Tx, id int64) (*auction.Listing, error) {
+	l, err := scanAuction(tx.QueryRow(d.qb.Build(`SELECT `+auctionColumns+` FROM auctions WHERE id = ?`), id))
+	if err == sql.ErrNoRows {
+		return nil, auction.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query auction: %w", err)
+	}
+	if !l.ExpiresAt.After(time.Now()) {
+		return nil, auction.ErrNotFound
+	}
+	return l, nil
+}
+
+// deleteAuctionTx removes a listing, failing if another transaction got to it first.
+func (d *Database) deleteAuctionTx(tx *sql.Tx, id int64) error {
+	result, err := tx.Exec(d.qb.Build(`DELETE FROM auctions WHERE id = ?`), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete auction: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return auction.ErrNotFound
+	}
+	return nil
+}
+
+// sendAuctionMailTx delivers auction house mail within a transaction. The
+// recipient is recorded as the sender too, since the house isn't a character.
+func (d *Database) sendAuctionMailTx(tx *sql.Tx, recipientID int64, recipientName, subject, body string, gold int, itemIDs []string) error {
+	_, err := d.insertMail(tx, recipientID, auction.HouseName, recipientID, recipientName, subject, body, gold, itemIDs)
+	return err
+}
+
+// refundBidTx returns the high bidder's gold when they are outbid or the listing goes away.
+func (d *Database) refundBidTx(tx *sql.Tx, l *auction.Listing, reason string) error {
+	if !l.HasBid() {
+		return nil
+	}
+	return d.sendAuctionMailTx(tx, l.BidderID, l.BidderName,
+		"Outbid: "+l.ItemName,
+		fmt.Sprintf("%s Your bid of %d gold on %s is returned.", reason, l.CurrentBid, l.ItemName),
+		l.CurrentBid, nil)
+}
+
+// completeSaleTx mails the item to the buyer and the proceeds to the seller.
+func (d *Database) completeSaleTx(tx *sql.Tx, l *auction.Listing, buyerID int64, buyerName string, price int) error {
+	if err := d.sendAuctionMailTx(tx, buyerID, buyerName,
+		"Won: "+l.ItemName,
+		fmt.Sprintf("You won %s for %d gold. The item is attached.", l.ItemName, price),
+		0, []string{l.ItemID}); err != nil {
+		return err
+	}
+	proceeds := auction.SaleProceeds(price)
+	return d.sendAuctionMailTx(tx, l.SellerID, l.SellerName,
+		"Sold: "+l.ItemName,
+		fmt.Sprintf("%s bought your %s for %d gold. After the house cut of %d%%, %d gold is attached.",
+			buyerName, l.ItemName, price, auction.HouseCutPercent, proceeds),
+		proceeds, nil)
+}
+
+// PlaceBid records a bid whose gold the bidder has already handed over. The
+// previous high bidder is refunded by mail.
+// Returns the listing as it was before the bid.
+func (d *Database) PlaceBid(id, bidderID int64, bidderName string, amount int) (*auction.Listing, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	l, err := d.getActiveAuctionTx(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case l.SellerID == bidderID:
+		return nil, auction.ErrOwnAuction
+	case !l.AcceptsBids():
+		return nil, auction.ErrNoBidding
+	case l.BidderID == bidderID:
+		return nil, auction.ErrHighBidder
+	case amount < l.NextMinBid():
+		return nil, auction.ErrBidTooLow
+	}
+
+	// The current_bid check guards against a bid that landed since we read the row
+	result, err := tx.Exec(d.qb.Build(`UPDATE auctions SET current_bid = ?, bidder_id = ?, bidder_name = ?
+		WHERE id = ? AND current_bid = ?`),
+		amount, bidderID, bidderName, id, l.CurrentBid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update bid: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, auction.ErrBidTooLow
+	}
+
+	if err := d.refundBidTx(tx, l, fmt.Sprintf("%s has outbid you.", bidderName)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return l, nil
+}
+
+// BuyoutAuction sells a listing at its buyout price to a buyer who has already
+// paid. The item and proceeds are delivered by mail and any bidder is refunded.
+// Returns the listing that was bought.
+func (d *Database) BuyoutAuction(id, buyerID int64, buyerName string) (*auction.Listing, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	l, err := d.getActiveAuctionTx(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if l.SellerID == buyerID {
+		return nil, auction.ErrOwnAuction
+	}
+	if err := d.deleteAuctionTx(tx, id); err != nil {
+		return nil, err
+	}
+	if err := d.refundBidTx(tx, l, fmt.Sprintf("%s bought %s outright.", buyerName, l.ItemName)); err != nil {
+		return nil, err
+	}
+	if err := d.completeSaleTx(tx, l, buyerID, buyerName, l.Buyout); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return l, nil
+}
+
+// CancelAuction takes down a listing that has no bids and mails the item back
+// to the seller.
+func (d *Database) CancelAuction(id, sellerID int64) (*auction.Listing, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	l, err := d.getActiveAuctionTx(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if l.SellerID != sellerID {
+		return nil, auction.ErrNotSeller
+	}
+	if l.HasBid() {
+		return nil, auction.ErrHasBids
+	}
+	if err := d.deleteAuctionTx(tx, id); err != nil {
+		return nil, err
+	}
+	if err := d.sendAuctionMailTx(tx, l.SellerID, l.SellerName,
+		"Cancelled: "+l.ItemName,
+		fmt.Sprintf("Your auction of %s was cancelled. The item is attached.", l.ItemName),
+		0, []string{l.ItemID}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return l, nil
+}
+
+// ExpireAuctions closes every listing whose time is up. Listings with a bid
+// are sold to the high bidder; the rest return their item to the seller.
+// Returns the listings that were closed.
+func (d *Database) ExpireAuctions() ([]*auction.Listing, error) {
+	rows, err := d.db.Query(d.qb.Build(`SELECT `+auctionColumns+` FROM auctions WHERE expires_at <= ? ORDER BY expires_at ASC`),
+		time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expired auctions: %w", err)
+	}
+	var expired []*auction.Listing
+	for rows.Next() {
+		l, err := scanAuction(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan auction: %w", err)
+		}
+		expired = append(expired, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var closed []*auction.Listing
+	for _, l := range expired {
+		if err := d.closeExpiredAuction(l); err != nil {
+			if errors.Is(err, auction.ErrNotFound) {
+				continue // Bought out or cancelled in the meantime
+			}
+			return closed, err
+		}
+		closed = append(closed, l)
+	}
+	return closed, nil
+}
+
+// closeExpiredAuction settles one expired listing in its own transaction.
+func (d *Database) closeExpiredAuction(l *auction.Listing) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := d.deleteAuctionTx(tx, l.ID); err != nil {
+		return err
+	}
+	if l.HasBid() {
+		err = d.completeSaleTx(tx, l, l.BidderID, l.BidderName, l.CurrentBid)
+	} else {
+		err = d.sendAuctionMailTx(tx, l.SellerID, l.SellerName,
+			"Expired: "+l.ItemName,
+			fmt.Sprintf("Your auction of %s ended without a sale. The item is attached.", l.ItemName),
+			0, []string{l.ItemID})
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// cancelCharacterAuctions settles a character's auction house business before
+// the character is deleted: bidders on their listings are refunded, and their
+// own bids are withdrawn (the escrowed gold goes with the character).
+func (d *Database) cancelCharacterAuctions(characterID int64) error {
+	rows, err := d.db.Query(d.qb.Build(`SELECT `+auctionColumns+` FROM auctions WHERE seller_id = ? AND bidder_id <> 0`), characterID)
+	if err != nil {
+		return fmt.Errorf("failed to query character auctions: %w", err)
+	}
+	var withBids []*auction.Listing
+	for rows.Next() {
+		l, err := scanAuction(rows)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan auction: %w", err)
+		}
+		withBids = append(withBids, l)
+	}
+	rows.Close()
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, l := range withBids {
+		if err := d.refundBidTx(tx, l, fmt.Sprintf("The auction of %s was withdrawn.", l.ItemName)); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(d.qb.Build(`DELETE FROM auctions WHERE seller_id = ?`), characterID); err != nil {
+		return fmt.Errorf("failed to delete character auctions: %w", err)
+	}
+	if _, err := tx.Exec(d.qb.Build(`UPDATE auctions SET current_bid = 0, bidder_id = 0, bidder_name = '' WHERE bidder_id = ?`), characterID); err != nil {
+		return fmt.Errorf("failed to withdraw character bids: %w", err)
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,227 @@
+package database
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/auction"
+	"github.com/lawnchairsociety/opentowermud/server/internal/mail"
+)
+
+func TestAuctionOperations(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	var chars []*Character
+	for _, name := range []string{"Seller", "Bidder", "Rival"} {
+		account, err := db.CreateAccount(name+"_acct", "password123")
+		if err != nil {
+			t.Fatalf("Failed to create account: %v", err)
+		}
+		char, err := db.CreateCharacter(account.ID, name)
+		if err != nil {
+			t.Fatalf("Failed to create character: %v", err)
+		}
+		chars = append(chars, char)
+	}
+	seller, bidder, rival := chars[0], chars[1], chars[2]
+
+	list := func(itemID, name string, minBid, buyout int) int64 {
+		t.Helper()
+		id, err := db.CreateAuction(&auction.Listing{
+			SellerID: seller.ID, SellerName: "Seller",
+			ItemID: itemID, ItemName: name, ItemType: "weapon", ItemSlot: "weapon", ItemTier: 2,
+			MinBid: minBid, Buyout: buyout,
+		}, auction.DefaultDuration)
+		if err != nil {
+			t.Fatalf("Failed to create auction: %v", err)
+		}
+		return id
+	}
+	mailCount := func(charID int64) int {
+		t.Helper()
+		count, err := db.GetMailCount(charID)
+		if err != nil {
+			t.Fatalf("Failed to count mail: %v", err)
+		}
+		return count
+	}
+
+	t.Run("CreateRejectsBadPrices", func(t *testing.T) {
+		_, err := db.CreateAuction(&auction.Listing{SellerID: seller.ID, ItemID: "x", ItemName: "x", MinBid: 50, Buyout: 50}, time.Hour)
+		if !errors.Is(err, auction.ErrInvalidPrice) {
+			t.Errorf("Expected ErrInvalidPrice for a starting bid at the buyout, got %v", err)
+		}
+	})
+
+	t.Run("SearchFilters", func(t *testing.T) {
+		list("long_sword", "long sword", 0, 100)
+		listings, err := db.SearchAuctions(auction.SearchFilter{Name: "SWORD", Tier: 2}, 10)
+		if err != nil {
+			t.Fatalf("Failed to search: %v", err)
+		}
+		if len(listings) != 1 || listings[0].ItemName != "long sword" {
+			t.Fatalf("Expected to find the long sword, got %v", listings)
+		}
+		if listings, _ := db.SearchAuctions(auction.SearchFilter{Slot: "head"}, 10); len(listings) != 0 {
+			t.Errorf("Expected no head slot listings, got %d", len(listings))
+		}
+	})
+
+	t.Run("BiddingRefundsPreviousBidder", func(t *testing.T) {
+		id := list("axe", "axe", 10, 100)
+
+		if _, err := db.PlaceBid(id, seller.ID, "Seller", 20); !errors.Is(err, auction.ErrOwnAuction) {
+			t.Errorf("Expected ErrOwnAuction, got %v", err)
+		}
+		if _, err := db.PlaceBid(id, bidder.ID, "Bidder", 5); !errors.Is(err, auction.ErrBidTooLow) {
+			t.Errorf("Expected ErrBidTooLow, got %v", err)
+		}
+		if _, err := db.PlaceBid(id, bidder.ID, "Bidder", 10); err != nil {
+			t.Fatalf("Failed to place bid: %v", err)
+		}
+		if _, err := db.PlaceBid(id, rival.ID, "Rival", 10); !errors.Is(err, auction.ErrBidTooLow) {
+			t.Errorf("Expected a matching bid to be too low, got %v", err)
+		}
+
+		before := mailCount(bidder.ID)
+		if _, err := db.PlaceBid(id, rival.ID, "Rival", 20); err != nil {
+			t.Fatalf("Failed to outbid: %v", err)
+		}
+		if mailCount(bidder.ID) != before+1 {
+			t.Error("Expected the outbid player to be mailed a refund")
+		}
+
+		l, _ := db.GetAuction(id)
+		if l.CurrentBid != 20 || l.BidderName != "Rival" {
+			t.Errorf("Expected Rival's bid of 20, got %d by %s", l.CurrentBid, l.BidderName)
+		}
+	})
+
+	t.Run("BuyoutOnlyRejectsBids", func(t *testing.T) {
+		id := list("mace", "mace", 0, 100)
+		if _, err := db.PlaceBid(id, bidder.ID, "Bidder", 50); !errors.Is(err, auction.ErrNoBidding) {
+			t.Errorf("Expected ErrNoBidding, got %v", err)
+		}
+	})
+
+	t.Run("BuyoutDeliversByMail", func(t *testing.T) {
+		id := list("dagger", "dagger", 10, 200)
+		if _, err := db.PlaceBid(id, rival.ID, "Rival", 50); err != nil {
+			t.Fatalf("Failed to place bid: %v", err)
+		}
+		sellerMail, buyerMail, rivalMail := mailCount(seller.ID), mailCount(bidder.ID), mailCount(rival.ID)
+
+		if _, err := db.BuyoutAuction(id, bidder.ID, "Bidder"); err != nil {
+			t.Fatalf("Failed to buy out: %v", err)
+		}
+		if _, err := db.BuyoutAuction(id, rival.ID, "Rival"); !errors.Is(err, auction.ErrNotFound) {
+			t.Errorf("Expected a second buyout to find nothing, got %v", err)
+		}
+
+		if mailCount(seller.ID) != sellerMail+1 || mailCount(bidder.ID) != buyerMail+1 || mailCount(rival.ID) != rivalMail+1 {
+			t.Fatal("Expected mail to the seller, the buyer and the refunded bidder")
+		}
+
+		m := findMail(t, db, seller.ID, "Sold: dagger")
+		if m.SenderName != auction.HouseName || m.GoldAttached != auction.SaleProceeds(200) {
+			t.Errorf("Expected %d gold from the auction house, got %d from %s", auction.SaleProceeds(200), m.GoldAttached, m.SenderName)
+		}
+	})
+
+	t.Run("CancelOnlyWithoutBids", func(t *testing.T) {
+		id := list("spear", "spear", 10, 100)
+		if _, err := db.CancelAuction(id, bidder.ID); !errors.Is(err, auction.ErrNotSeller) {
+			t.Errorf("Expected ErrNotSeller, got %v", err)
+		}
+		if _, err := db.PlaceBid(id, bidder.ID, "Bidder", 10); err != nil {
+			t.Fatalf("Failed to place bid: %v", err)
+		}
+		if _, err := db.CancelAuction(id, seller.ID); !errors.Is(err, auction.ErrHasBids) {
+			t.Errorf("Expected ErrHasBids, got %v", err)
+		}
+
+		id = list("club", "club", 0, 100)
+		if _, err := db.CancelAuction(id, seller.ID); err != nil {
+			t.Fatalf("Failed to cancel: %v", err)
+		}
+	})
+
+	t.Run("ExpiredAuctionsSettle", func(t *testing.T) {
+		sold := list("bow", "bow", 10, 100)
+		unsold := list("staff", "staff", 10, 100)
+		if _, err := db.PlaceBid(sold, bidder.ID, "Bidder", 30); err != nil {
+			t.Fatalf("Failed to place bid: %v", err)
+		}
+		past := time.Now().UTC().Add(-time.Minute)
+		if _, err := db.db.Exec(db.qb.Build(`UPDATE auctions SET expires_at = ? WHERE id IN (?, ?)`), past, sold, unsold); err != nil {
+			t.Fatalf("Failed to age auctions: %v", err)
+		}
+
+		if _, err := db.PlaceBid(unsold, bidder.ID, "Bidder", 50); !errors.Is(err, auction.ErrNotFound) {
+			t.Errorf("Expected bids on an expired auction to fail, got %v", err)
+		}
+
+		closed, err := db.ExpireAuctions()
+		if err != nil {
+			t.Fatalf("Failed to expire auctions: %v", err)
+		}
+		if len(closed) != 2 {
+			t.Fatalf("Expected 2 auctions closed, got %d", len(closed))
+		}
+		if l, _ := db.GetAuction(sold); l != nil {
+			t.Error("Expected the sold auction to be removed")
+		}
+
+		m := findMail(t, db, bidder.ID, "Won: bow")
+		if len(m.Items) != 1 || m.Items[0].ItemID != "bow" {
+			t.Errorf("Expected the winner to be mailed the bow, got %+v", m.Items)
+		}
+	})
+
+	t.Run("DeletingSellerRefundsBidders", func(t *testing.T) {
+		id := list("halberd", "halberd", 10, 100)
+		if _, err := db.PlaceBid(id, rival.ID, "Rival", 40); err != nil {
+			t.Fatalf("Failed to place bid: %v", err)
+		}
+		before := mailCount(rival.ID)
+
+		if err := db.DeleteCharacter(seller.ID); err != nil {
+			t.Fatalf("Failed to delete character: %v", err)
+		}
+		// Rival is refunded for the halberd and for the axe bid still standing
+		if mailCount(rival.ID) != before+2 {
+			t.Errorf("Expected 2 refunds when the seller is deleted, got %d", mailCount(rival.ID)-before)
+		}
+		if count, _ := db.CountAuctionsBySeller(seller.ID); count != 0 {
+			t.Errorf("Expected the seller's auctions to be removed, got %d", count)
+		}
+	})
+}
+
+// findMail returns a character's mail with the given subject.
+func findMail(t *testing.T, db *Database, charID int64, subject string) *mail.Mail {
+	t.Helper()
+	summaries, err := db.GetMailbox(charID)
+	if err != nil {
+		t.Fatalf("Failed to get mailbox: %v", err)
+	}
+	for _, s := range summaries {
+		if s.Subject != subject {
+			continue
+		}
+		mailID, _ := db.GetMailIDByIndex(charID, s.ID)
+		m, err := db.GetMail(mailID, charID)
+		if err != nil || m == nil {
+			t.Fatalf("Failed to get mail: %v", err)
+		}
+		return m
+	}
+	t.Fatalf("No mail with subject %q", subject)
+	return nil
+}
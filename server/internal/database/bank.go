@@ -0,0 +1,165 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/bank"
+)
+
+// execer is satisfied by both *sql.DB and *sql.Tx.
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// ensureVault creates an account's vault if it doesn't exist yet.
+func (d *Database) ensureVault(exec execer, accountID int64) error {
+	_, err := exec.Exec(d.qb.Build(`INSERT INTO bank_vaults (account_id, gold, slots) VALUES (?, 0, ?)
+		ON CONFLICT (account_id) DO NOTHING`), accountID, bank.BaseSlots)
+	if err != nil {
+		return fmt.Errorf("failed to create vault: %w", err)
+	}
+	return nil
+}
+
+// GetVault returns an account's bank vault, creating an empty one on first use.
+func (d *Database) GetVault(accountID int64) (*bank.Vault, error) {
+	if err := d.ensureVault(d.db, accountID); err != nil {
+		return nil, err
+	}
+
+	v := &bank.Vault{AccountID: accountID}
+	err := d.db.QueryRow(d.qb.Build(`SELECT gold, slots FROM bank_vaults WHERE account_id = ?`), accountID).
+		Scan(&v.Gold, &v.Slots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vault: %w", err)
+	}
+	return v, nil
+}
+
+// DepositBankGold adds gold to an account's vault.
+func (d *Database) DepositBankGold(accountID int64, amount int) error {
+	if err := d.ensureVault(d.db, accountID); err != nil {
+		return err
+	}
+	_, err := d.db.Exec(d.qb.Build(`UPDATE bank_vaults SET gold = gold + ? WHERE account_id = ?`), amount, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to deposit gold: %w", err)
+	}
+	return nil
+}
+
+// WithdrawBankGold takes gold from an account's vault.
+// Returns bank.ErrInsufficientGold if the vault holds less than amount.
+func (d *Database) WithdrawBankGold(accountID int64, amount int) error {
+	result, err := d.db.Exec(d.qb.Build(`UPDATE bank_vaults SET gold = gold - ? WHERE account_id = ? AND gold >= ?`),
+		amount, accountID, amount)
+	if err != nil {
+		return fmt.Errorf("failed to withdraw gold: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return bank.ErrInsufficientGold
+	}
+	return nil
+}
+
+// DepositBankItem stores an item in an account's vault.
+// Returns bank.ErrVaultFull if every slot is taken.
+func (d *Database) DepositBankItem(accountID int64, itemID, depositedBy string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := d.ensureVault(tx, accountID); err != nil {
+		return err
+	}
+
+	var slots, used int
+	err = tx.QueryRow(d.qb.Build(`SELECT slots, (SELECT COUNT(*) FROM bank_items WHERE account_id = ?)
+		FROM bank_vaults WHERE account_id = ?`), accountID, accountID).Scan(&slots, &used)
+	if err != nil {
+		return fmt.Errorf("failed to check vault space: %w", err)
+	}
+	if used >= slots {
+		return bank.ErrVaultFull
+	}
+
+	_, err = tx.Exec(d.qb.Build(`INSERT INTO bank_items (account_id, item_id, deposited_by) VALUES (?, ?, ?)`),
+		accountID, itemID, depositedBy)
+	if err != nil {
+		return fmt.Errorf("failed to deposit item: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetBankItems returns the items stored in an account's vault, oldest first.
+func (d *Database) GetBankItems(accountID int64) ([]bank.Item, error) {
+	rows, err := d.db.Query(d.qb.Build(`
+		SELECT id, account_id, item_id, deposited_by
+		FROM bank_items
+		WHERE account_id = ?
+		ORDER BY id`),
+		accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query vault: %w", err)
+	}
+	defer rows.Close()
+
+	var bankItems []bank.Item
+	for rows.Next() {
+		var item bank.Item
+		if err := rows.Scan(&item.ID, &item.AccountID, &item.ItemID, &item.DepositedBy); err != nil {
+			return nil, fmt.Errorf("failed to scan vault item: %w", err)
+		}
+		bankItems = append(bankItems, item)
+	}
+
+	return bankItems, nil
+}
+
+// WithdrawBankItem removes a stored item from an account's vault.
+// Returns false if the item was already taken.
+func (d *Database) WithdrawBankItem(accountID, bankItemID int64) (bool, error) {
+	result, err := d.db.Exec(d.qb.Build(`DELETE FROM bank_items WHERE id = ? AND account_id = ?`), bankItemID, accountID)
+	if err != nil {
+		return false, fmt.Errorf("failed to withdraw item: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// ExpandVault adds an expansion's worth of slots to a vault that currently has
+// fromSlots. Returns false if the vault changed size since it was read, so the
+// caller doesn't charge twice for one expansion.
+func (d *Database) ExpandVault(accountID int64, fromSlots int) (bool, error) {
+	if fromSlots+bank.SlotsPerExpansion > bank.MaxSlots {
+		return false, bank.ErrMaxSlots
+	}
+
+	result, err := d.db.Exec(d.qb.Build(`UPDATE bank_vaults SET slots = slots + ? WHERE account_id = ? AND slots = ?`),
+		bank.SlotsPerExpansion, accountID, fromSlots)
+	if err != nil {
+		return false, fmt.Errorf("failed to expand vault: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/bank"
+)
+
+func TestBankOperations(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	account, err := db.CreateAccount("banker", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+
+	t.Run("NewVault", func(t *testing.T) {
+		v, err := db.GetVault(account.ID)
+		if err != nil {
+			t.Fatalf("Failed to get vault: %v", err)
+		}
+		if v.Gold != 0 || v.Slots != bank.BaseSlots {
+			t.Errorf("Expected an empty vault with %d slots, got %+v", bank.BaseSlots, v)
+		}
+	})
+
+	t.Run("Gold", func(t *testing.T) {
+		if err := db.DepositBankGold(account.ID, 150); err != nil {
+			t.Fatalf("Failed to deposit gold: %v", err)
+		}
+		if err := db.WithdrawBankGold(account.ID, 200); !errors.Is(err, bank.ErrInsufficientGold) {
+			t.Errorf("Expected ErrInsufficientGold, got %v", err)
+		}
+		if err := db.WithdrawBankGold(account.ID, 100); err != nil {
+			t.Fatalf("Failed to withdraw gold: %v", err)
+		}
+		if v, _ := db.GetVault(account.ID); v.Gold != 50 {
+			t.Errorf("Expected 50 gold left, got %d", v.Gold)
+		}
+	})
+
+	t.Run("ItemsRespectSlots", func(t *testing.T) {
+		for i := 0; i < bank.BaseSlots; i++ {
+			if err := db.DepositBankItem(account.ID, "torch", "Alice"); err != nil {
+				t.Fatalf("Failed to deposit item %d: %v", i, err)
+			}
+		}
+		if err := db.DepositBankItem(account.ID, "torch", "Alice"); !errors.Is(err, bank.ErrVaultFull) {
+			t.Errorf("Expected ErrVaultFull, got %v", err)
+		}
+
+		bankItems, err := db.GetBankItems(account.ID)
+		if err != nil || len(bankItems) != bank.BaseSlots {
+			t.Fatalf("Expected %d items, got %d (%v)", bank.BaseSlots, len(bankItems), err)
+		}
+		if taken, err := db.WithdrawBankItem(account.ID, bankItems[0].ID); err != nil || !taken {
+			t.Fatalf("Failed to withdraw item: %v", err)
+		}
+		if taken, _ := db.WithdrawBankItem(account.ID, bankItems[0].ID); taken {
+			t.Error("Expected a withdrawn item not to be taken twice")
+		}
+	})
+
+	t.Run("Expand", func(t *testing.T) {
+		if ok, err := db.ExpandVault(account.ID, bank.BaseSlots); err != nil || !ok {
+			t.Fatalf("Failed to expand vault: %v", err)
+		}
+		if ok, _ := db.ExpandVault(account.ID, bank.BaseSlots); ok {
+			t.Error("Expected a stale expansion to be refused")
+		}
+		if v, _ := db.GetVault(account.ID); v.Slots != bank.BaseSlots+bank.SlotsPerExpansion {
+			t.Errorf("Expected %d slots, got %d", bank.BaseSlots+bank.SlotsPerExpansion, v.Slots)
+		}
+		if _, err := db.ExpandVault(account.ID, bank.MaxSlots); !errors.Is(err, bank.ErrMaxSlots) {
+			t.Errorf("Expected ErrMaxSlots, got %v", err)
+		}
+	})
+}
@@ -66,6 +66,18 @@ type Character struct {
 	TalkedToLoreNPCs      string // Comma-separated list of lore NPC IDs talked to
 	// Statistics for website
 	Statistics string // JSON-serialized player statistics
+	// Player preferences
+	Prompt     string // Custom prompt format (empty = server default)
+	ColorPrefs   string // JSON-serialized color preferences (empty = defaults)
+	ChannelPrefs string // JSON-serialized chat channel preferences (empty = defaults)
+	HideLocation bool   // Hide tower and floor from friends lists
+	Aliases      string // JSON-serialized command aliases (name -> expansion)
+	// Death penalties
+	XPDebt   int    // Experience owed from deaths
+	ItemWear string // JSON-serialized wear of damaged inventory and equipment
+	// Hardcore mode
+	Hardcore bool // Chosen at creation: one life, no dealings with normal characters
+	Retired  bool // A hardcore character that died; kept only as a memorial
 	CreatedAt  time.Time
 	LastPlayed *time.Time
 }
@@ -91,11 +103,12 @@ func (d *Database) CreateCharacterWithClass(accountID int64, name string, primar
 // CreateCharacterWithClassAndRace creates a new character with specified class, race, and ability scores.
 // Uses the default home tower ("human").
 func (d *Database) CreateCharacterWithClassAndRace(accountID int64, name string, primaryClass string, race string, str, dex, con, int_, wis, cha int) (*Character, error) {
-	return d.CreateCharacterFull(accountID, name, primaryClass, race, "human", str, dex, con, int_, wis, cha)
+	return d.CreateCharacterFull(accountID, name, primaryClass, race, "human", false, str, dex, con, int_, wis, cha)
 }
 
-// CreateCharacterFull creates a new character with all customizable options including home tower.
-func (d *Database) CreateCharacterFull(accountID int64, name string, primaryClass string, race string, homeTower string, str, dex, con, int_, wis, cha int) (*Character, error) {
+// CreateCharacterFull creates a new character with all customizable options including home tower
+// and hardcore mode.
+func (d *Database) CreateCharacterFull(accountID int64, name string, primaryClass string, race string, homeTower string, hardcore bool, str, dex, con, int_, wis, cha int) (*Character, error) {
 	name = strings.TrimSpace(name)
 	if name == "" {
 		return nil, errors.New("character name cannot be empty")
@@ -127,15 +140,15 @@ func (d *Database) CreateCharacterFull(accountID int64, name string, primaryClas
 	var id int64
 	query := `INSERT INTO characters (account_id, name, health, max_health, mana, max_mana,
 		                         strength, dexterity, constitution, intelligence, wisdom, charisma,
-		                         primary_class, class_levels, active_class, race, home_tower, learned_spells)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		                         primary_class, class_levels, active_class, race, home_tower, learned_spells, hardcore)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	if d.dialect.SupportsLastInsertID() {
 		result, err := d.db.Exec(
 			d.qb.Build(query),
 			accountID, name, startingHP, startingHP, startingMana, startingMana,
 			str, dex, con, int_, wis, cha,
-			primaryClass, classLevels, primaryClass, race, homeTower, "",
+			primaryClass, classLevels, primaryClass, race, homeTower, "", boolToInt(hardcore),
 		)
 		if err != nil {
 			if d.dialect.IsDuplicateKeyError(err) {
@@ -154,7 +167,7 @@ func (d *Database) CreateCharacterFull(accountID int64, name string, primaryClas
 			d.qb.BuildWithReturning(query, "id"),
 			accountID, name, startingHP, startingHP, startingMana, startingMana,
 			str, dex, con, int_, wis, cha,
-			primaryClass, classLevels, primaryClass, race, homeTower, "",
+			primaryClass, classLevels, primaryClass, race, homeTower, "", boolToInt(hardcore),
 		).Scan(&id)
 		if err != nil {
 			if d.dialect.IsDuplicateKeyError(err) {
@@ -196,6 +209,7 @@ func (d *Database) CreateCharacterFull(accountID int64, name string, primaryClas
 		ActiveClass:    primaryClass,
 		Race:           race,
 		HomeTower:      homeTower,
+		Hardcore:       hardcore,
 		CreatedAt:      time.Now(),
 	}, nil
 }
@@ -268,6 +282,10 @@ func (d *Database) GetCharactersByAccount(accountID int64) ([]*Character, error)
 		        COALESCE(earned_titles, ''), COALESCE(active_title, ''),
 		        COALESCE(visited_labyrinth_gates, ''), COALESCE(talked_to_lore_npcs, ''),
 		        COALESCE(statistics, '{}'),
+		        COALESCE(prompt, ''), COALESCE(color_prefs, ''), COALESCE(channel_prefs, ''),
+		        COALESCE(hide_location, 0), COALESCE(aliases, ''),
+		        COALESCE(xp_debt, 0), COALESCE(item_wear, ''),
+		        COALESCE(hardcore, 0), COALESCE(retired, 0),
 		        created_at, last_played
 		 FROM characters WHERE account_id = ? ORDER BY last_played DESC NULLS LAST, name`),
 		accountID,
@@ -307,6 +325,10 @@ func (d *Database) GetCharacterByName(name string) (*Character, error) {
 		        COALESCE(earned_titles, ''), COALESCE(active_title, ''),
 		        COALESCE(visited_labyrinth_gates, ''), COALESCE(talked_to_lore_npcs, ''),
 		        COALESCE(statistics, '{}'),
+		        COALESCE(prompt, ''), COALESCE(color_prefs, ''), COALESCE(channel_prefs, ''),
+		        COALESCE(hide_location, 0), COALESCE(aliases, ''),
+		        COALESCE(xp_debt, 0), COALESCE(item_wear, ''),
+		        COALESCE(hardcore, 0), COALESCE(retired, 0),
 		        created_at, last_played
 		 FROM characters WHERE name = ?`),
 		name,
@@ -337,6 +359,10 @@ func (d *Database) GetCharacterByID(id int64) (*Character, error) {
 		        COALESCE(earned_titles, ''), COALESCE(active_title, ''),
 		        COALESCE(visited_labyrinth_gates, ''), COALESCE(talked_to_lore_npcs, ''),
 		        COALESCE(statistics, '{}'),
+		        COALESCE(prompt, ''), COALESCE(color_prefs, ''), COALESCE(channel_prefs, ''),
+		        COALESCE(hide_location, 0), COALESCE(aliases, ''),
+		        COALESCE(xp_debt, 0), COALESCE(item_wear, ''),
+		        COALESCE(hardcore, 0), COALESCE(retired, 0),
 		        created_at, last_played
 		 FROM characters WHERE id = ?`),
 		id,
@@ -390,6 +416,13 @@ func (d *Database) SaveCharacter(c *Character) error {
 			visited_labyrinth_gates = ?,
 			talked_to_lore_npcs = ?,
 			statistics = ?,
+			prompt = ?,
+			color_prefs = ?,
+			channel_prefs = ?,
+			hide_location = ?,
+			aliases = ?,
+			xp_debt = ?,
+			item_wear = ?,
 			last_played = CURRENT_TIMESTAMP
 		 WHERE id = ?`),
 		c.RoomID, c.Health, c.MaxHealth, c.Mana, c.MaxMana,
@@ -399,6 +432,8 @@ func (d *Database) SaveCharacter(c *Character) error {
 		c.CraftingSkills, c.KnownRecipes,
 		c.QuestLog, c.QuestInventory, c.EarnedTitles, c.ActiveTitle,
 		c.VisitedLabyrinthGates, c.TalkedToLoreNPCs, c.Statistics,
+		c.Prompt, c.ColorPrefs, c.ChannelPrefs, boolToInt(c.HideLocation), c.Aliases,
+		c.XPDebt, c.ItemWear,
 		c.ID,
 	)
 	if err != nil {
@@ -409,6 +444,11 @@ func (d *Database) SaveCharacter(c *Character) error {
 
 // DeleteCharacter removes a character and all associated data.
 func (d *Database) DeleteCharacter(characterID int64) error {
+	// Refund bidders on the character's auctions before they're removed
+	if err := d.cancelCharacterAuctions(characterID); err != nil {
+		return err
+	}
+
 	// Delete mail where character is sender or recipient (mail FK lacks ON DELETE CASCADE)
 	if _, err := d.db.Exec(d.qb.Build("DELETE FROM mail WHERE sender_id = ? OR recipient_id = ?"), characterID, characterID); err != nil {
 		return fmt.Errorf("failed to delete character mail: %w", err)
@@ -467,6 +507,7 @@ type OnlineChecker interface {
 func scanCharacter(rows *sql.Rows) (*Character, error) {
 	var c Character
 	var lastPlayed sql.NullTime
+	var hideLocation, hardcore, retired int
 
 	err := rows.Scan(
 		&c.ID, &c.AccountID, &c.Name, &c.RoomID,
@@ -479,6 +520,10 @@ func scanCharacter(rows *sql.Rows) (*Character, error) {
 		&c.CraftingSkills, &c.KnownRecipes,
 		&c.QuestLog, &c.QuestInventory, &c.TrophyCase, &c.EarnedTitles, &c.ActiveTitle,
 		&c.VisitedLabyrinthGates, &c.TalkedToLoreNPCs, &c.Statistics,
+		&c.Prompt, &c.ColorPrefs, &c.ChannelPrefs,
+		&hideLocation, &c.Aliases,
+		&c.XPDebt, &c.ItemWear,
+		&hardcore, &retired,
 		&c.CreatedAt, &lastPlayed,
 	)
 	if err != nil {
@@ -488,6 +533,9 @@ func scanCharacter(rows *sql.Rows) (*Character, error) {
 	if lastPlayed.Valid {
 		c.LastPlayed = &lastPlayed.Time
 	}
+	c.HideLocation = hideLocation != 0
+	c.Hardcore = hardcore != 0
+	c.Retired = retired != 0
 
 	return &c, nil
 }
@@ -496,6 +544,7 @@ func scanCharacter(rows *sql.Rows) (*Character, error) {
 func scanCharacterRow(row *sql.Row) (*Character, error) {
 	var c Character
 	var lastPlayed sql.NullTime
+	var hideLocation, hardcore, retired int
 
 	err := row.Scan(
 		&c.ID, &c.AccountID, &c.Name, &c.RoomID,
@@ -508,6 +557,10 @@ func scanCharacterRow(row *sql.Row) (*Character, error) {
 		&c.CraftingSkills, &c.KnownRecipes,
 		&c.QuestLog, &c.QuestInventory, &c.TrophyCase, &c.EarnedTitles, &c.ActiveTitle,
 		&c.VisitedLabyrinthGates, &c.TalkedToLoreNPCs, &c.Statistics,
+		&c.Prompt, &c.ColorPrefs, &c.ChannelPrefs,
+		&hideLocation, &c.Aliases,
+		&c.XPDebt, &c.ItemWear,
+		&hardcore, &retired,
 		&c.CreatedAt, &lastPlayed,
 	)
 	if err != nil {
@@ -517,7 +570,18 @@ func scanCharacterRow(row *sql.Row) (*Character, error) {
 	if lastPlayed.Valid {
 		c.LastPlayed = &lastPlayed.Time
 	}
+	c.HideLocation = hideLocation != 0
+	c.Hardcore = hardcore != 0
+	c.Retired = retired != 0
 
 	return &c, nil
 }
 
+
+// boolToInt converts a flag for the INTEGER columns both dialects store booleans in.
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
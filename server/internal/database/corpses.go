@@ -0,0 +1,145 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrCorpseGone is returned when a corpse has already been recovered or has decayed.
+var ErrCorpseGone = errors.New("corpse no longer exists")
+
+// Corpse is a dead player's inventory left where they fell.
+type Corpse struct {
+	ID          int64
+	CharacterID int64
+	OwnerName   string
+	RoomID      string
+	Items       []CorpseItem
+	ExpiresAt   time.Time
+}
+
+// CorpseItem is an item lying in a corpse.
+type CorpseItem struct {
+	ItemID string // References items.yaml
+	Wear   int
+}
+
+// CreateCorpse saves a corpse and its items. Returns the new corpse's ID.
+func (d *Database) CreateCorpse(c *Corpse) (int64, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Stored in UTC so expiry times compare correctly as text in SQLite
+	query := `INSERT INTO corpses (character_id, owner_name, room_id, expires_at) VALUES (?, ?, ?, ?)`
+	args := []interface{}{c.CharacterID, c.OwnerName, c.RoomID, c.ExpiresAt.UTC()}
+
+	var id int64
+	if d.dialect.SupportsLastInsertID() {
+		result, err := tx.Exec(d.qb.Build(query), args...)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert corpse: %w", err)
+		}
+		id, err = result.LastInsertId()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get corpse ID: %w", err)
+		}
+	} else {
+		// PostgreSQL: use RETURNING clause
+		if err := tx.QueryRow(d.qb.BuildWithReturning(query, "id"), args...).Scan(&id); err != nil {
+			return 0, fmt.Errorf("failed to insert corpse: %w", err)
+		}
+	}
+
+	for _, item := range c.Items {
+		_, err := tx.Exec(d.qb.Build(`INSERT INTO corpse_items (corpse_id, item_id, wear) VALUES (?, ?, ?)`),
+			id, item.ItemID, item.Wear)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert corpse item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return id, nil
+}
+
+// GetCorpses returns every saved corpse, oldest first, including ones that
+// have expired but not yet been removed.
+func (d *Database) GetCorpses() ([]*Corpse, error) {
+	rows, err := d.db.Query(`SELECT id, character_id, owner_name, room_id, expires_at FROM corpses ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query corpses: %w", err)
+	}
+	var corpses []*Corpse
+	for rows.Next() {
+		c := &Corpse{}
+		if err := rows.Scan(&c.ID, &c.CharacterID, &c.OwnerName, &c.RoomID, &c.ExpiresAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan corpse: %w", err)
+		}
+		corpses = append(corpses, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read corpses: %w", err)
+	}
+
+	// Load items once the corpses query is closed so the two don't hold connections at once
+	for _, c := range corpses {
+		c.Items, err = d.loadCorpseItems(c.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return corpses, nil
+}
+
+// loadCorpseItems returns the items in a saved corpse.
+func (d *Database) loadCorpseItems(corpseID int64) ([]CorpseItem, error) {
+	rows, err := d.db.Query(d.qb.Build(`SELECT item_id, wear FROM corpse_items WHERE corpse_id = ? ORDER BY id`), corpseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query corpse items: %w", err)
+	}
+	defer rows.Close()
+
+	var corpseItems []CorpseItem
+	for rows.Next() {
+		var item CorpseItem
+		if err := rows.Scan(&item.ItemID, &item.Wear); err != nil {
+			return nil, fmt.Errorf("failed to scan corpse item: %w", err)
+		}
+		corpseItems = append(corpseItems, item)
+	}
+	return corpseItems, rows.Err()
+}
+
+// DeleteCorpse removes a corpse and its items, once recovered or decayed.
+// Returns ErrCorpseGone if it was already removed.
+func (d *Database) DeleteCorpse(id int64) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(d.qb.Build(`DELETE FROM corpse_items WHERE corpse_id = ?`), id); err != nil {
+		return fmt.Errorf("failed to remove corpse items: %w", err)
+	}
+	result, err := tx.Exec(d.qb.Build(`DELETE FROM corpses WHERE id = ?`), id)
+	if err != nil {
+		return fmt.Errorf("failed to remove corpse: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrCorpseGone
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
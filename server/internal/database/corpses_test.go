@@ -0,0 +1,93 @@
+package database
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCorpseOperations(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	account, err := db.CreateAccount("corpse_acct", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	owner, err := db.CreateCharacter(account.ID, "Fallen")
+	if err != nil {
+		t.Fatalf("Failed to create character: %v", err)
+	}
+
+	expires := time.Now().Add(30 * time.Minute)
+	id, err := db.CreateCorpse(&Corpse{
+		CharacterID: owner.ID,
+		OwnerName:   "Fallen",
+		RoomID:      "human_f3_r2_4",
+		ExpiresAt:   expires,
+		Items: []CorpseItem{
+			{ItemID: "long_sword", Wear: 40},
+			{ItemID: "health_potion"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create corpse: %v", err)
+	}
+
+	corpses, err := db.GetCorpses()
+	if err != nil || len(corpses) != 1 {
+		t.Fatalf("Expected one corpse, got %d (%v)", len(corpses), err)
+	}
+	c := corpses[0]
+	if c.ID != id || c.OwnerName != "Fallen" || c.RoomID != "human_f3_r2_4" {
+		t.Errorf("Unexpected corpse: %+v", c)
+	}
+	if len(c.Items) != 2 || c.Items[0].ItemID != "long_sword" || c.Items[0].Wear != 40 {
+		t.Errorf("Unexpected corpse items: %+v", c.Items)
+	}
+	if c.ExpiresAt.Sub(expires).Abs() > time.Second {
+		t.Errorf("Expected the corpse to expire at %v, got %v", expires, c.ExpiresAt)
+	}
+
+	if err := db.DeleteCorpse(id); err != nil {
+		t.Fatalf("Failed to delete corpse: %v", err)
+	}
+	if err := db.DeleteCorpse(id); !errors.Is(err, ErrCorpseGone) {
+		t.Errorf("Expected ErrCorpseGone deleting twice, got %v", err)
+	}
+	if corpses, _ := db.GetCorpses(); len(corpses) != 0 {
+		t.Errorf("Expected no corpses after recovery, got %d", len(corpses))
+	}
+}
+
+func TestCharacterDeathPenaltiesPersist(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	account, _ := db.CreateAccount("debt_acct", "password123")
+	char, err := db.CreateCharacter(account.ID, "Debtor")
+	if err != nil {
+		t.Fatalf("Failed to create character: %v", err)
+	}
+
+	char.XPDebt = 120
+	char.ItemWear = `{"equipment":{"weapon":30}}`
+	if err := db.SaveCharacterFull(char, []string{"long_sword"}, map[string]string{"weapon": "rusty_dagger"}); err != nil {
+		t.Fatalf("Failed to save character: %v", err)
+	}
+
+	loaded, err := db.GetCharacterByID(char.ID)
+	if err != nil {
+		t.Fatalf("Failed to load character: %v", err)
+	}
+	if loaded.XPDebt != 120 || loaded.ItemWear != char.ItemWear {
+		t.Errorf("Expected XP debt 120 and item wear %q, got %d and %q", char.ItemWear, loaded.XPDebt, loaded.ItemWear)
+	}
+}
@@ -249,6 +249,157 @@ func (d *Database) migrateSQLite() error {
 		`CREATE INDEX IF NOT EXISTS idx_boss_kills_tower ON boss_kills(tower_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_boss_kills_player ON boss_kills(player_name)`,
 		`CREATE INDEX IF NOT EXISTS idx_boss_kills_first ON boss_kills(tower_id, is_first_kill)`,
+
+		// Guild tables
+		`CREATE TABLE IF NOT EXISTS guilds (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT UNIQUE NOT NULL COLLATE NOCASE,
+			tag TEXT UNIQUE NOT NULL COLLATE NOCASE,
+			bank_gold INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS guild_members (
+			character_id INTEGER PRIMARY KEY REFERENCES characters(id) ON DELETE CASCADE,
+			guild_id INTEGER NOT NULL REFERENCES guilds(id) ON DELETE CASCADE,
+			character_name TEXT NOT NULL,
+			member_rank TEXT NOT NULL DEFAULT 'member',
+			joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS guild_bank_items (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			guild_id INTEGER NOT NULL REFERENCES guilds(id) ON DELETE CASCADE,
+			item_id TEXT NOT NULL,
+			deposited_by TEXT NOT NULL,
+			deposited_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_guild_members_guild ON guild_members(guild_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_guild_bank_items_guild ON guild_bank_items(guild_id)`,
+
+		// Trade audit log (names, not IDs, so records outlive deleted characters)
+		`CREATE TABLE IF NOT EXISTS trade_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			player1 TEXT NOT NULL COLLATE NOCASE,
+			player1_items TEXT NOT NULL DEFAULT '',
+			player1_gold INTEGER NOT NULL DEFAULT 0,
+			player2 TEXT NOT NULL COLLATE NOCASE,
+			player2_items TEXT NOT NULL DEFAULT '',
+			player2_gold INTEGER NOT NULL DEFAULT 0,
+			room_id TEXT NOT NULL DEFAULT '',
+			traded_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_trade_log_player1 ON trade_log(player1)`,
+		`CREATE INDEX IF NOT EXISTS idx_trade_log_player2 ON trade_log(player2)`,
+
+		// Auction house
+		`CREATE TABLE IF NOT EXISTS auctions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			seller_id INTEGER NOT NULL REFERENCES characters(id) ON DELETE CASCADE,
+			seller_name TEXT NOT NULL,
+			item_id TEXT NOT NULL,
+			item_name TEXT NOT NULL,
+			item_type TEXT NOT NULL DEFAULT '',
+			item_slot TEXT NOT NULL DEFAULT '',
+			item_tier INTEGER NOT NULL DEFAULT 0,
+			min_bid INTEGER NOT NULL DEFAULT 0,
+			buyout INTEGER NOT NULL,
+			current_bid INTEGER NOT NULL DEFAULT 0,
+			bidder_id INTEGER NOT NULL DEFAULT 0,
+			bidder_name TEXT NOT NULL DEFAULT '',
+			listed_at DATETIME NOT NULL,
+			expires_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_auctions_expires ON auctions(expires_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_auctions_seller ON auctions(seller_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_auctions_bidder ON auctions(bidder_id)`,
+		// Player stalls (kept between sessions; open stalls sell while the owner is offline)
+		`CREATE TABLE IF NOT EXISTS stalls (
+			character_id INTEGER PRIMARY KEY REFERENCES characters(id) ON DELETE CASCADE,
+			character_name TEXT NOT NULL,
+			room_id TEXT NOT NULL,
+			is_open INTEGER NOT NULL DEFAULT 0
+		)`,
+		`CREATE TABLE IF NOT EXISTS stall_items (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			character_id INTEGER NOT NULL REFERENCES characters(id) ON DELETE CASCADE,
+			item_id TEXT NOT NULL,
+			price INTEGER NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_stall_items_character ON stall_items(character_id)`,
+		`CREATE TABLE IF NOT EXISTS stall_sales (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			seller_id INTEGER NOT NULL REFERENCES characters(id) ON DELETE CASCADE,
+			buyer_name TEXT NOT NULL,
+			item_name TEXT NOT NULL,
+			price INTEGER NOT NULL,
+			sold_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_stall_sales_seller ON stall_sales(seller_id)`,
+		// Bank vaults (shared by every character on an account)
+		`CREATE TABLE IF NOT EXISTS bank_vaults (
+			account_id INTEGER PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+			gold INTEGER NOT NULL DEFAULT 0,
+			slots INTEGER NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS bank_items (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+			item_id TEXT NOT NULL,
+			deposited_by TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_bank_items_account ON bank_items(account_id)`,
+		// Friends lists (one-way: adding someone doesn't add you to theirs)
+		`CREATE TABLE IF NOT EXISTS friends (
+			character_id INTEGER NOT NULL REFERENCES characters(id) ON DELETE CASCADE,
+			friend_id INTEGER NOT NULL REFERENCES characters(id) ON DELETE CASCADE,
+			added_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (character_id, friend_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_friends_friend ON friends(friend_id)`,
+		// Ranked duel ratings (Elo), one row per character that has fought a ranked duel
+		`CREATE TABLE IF NOT EXISTS duel_ratings (
+			character_id INTEGER PRIMARY KEY REFERENCES characters(id) ON DELETE CASCADE,
+			rating INTEGER NOT NULL DEFAULT 1000,
+			wins INTEGER NOT NULL DEFAULT 0,
+			losses INTEGER NOT NULL DEFAULT 0,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_duel_ratings_rating ON duel_ratings(rating)`,
+		// Player corpses left by deaths, holding the inventory until recovered or decayed
+		`CREATE TABLE IF NOT EXISTS corpses (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			character_id INTEGER NOT NULL REFERENCES characters(id) ON DELETE CASCADE,
+			owner_name TEXT NOT NULL,
+			room_id TEXT NOT NULL,
+			expires_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_corpses_character ON corpses(character_id)`,
+		`CREATE TABLE IF NOT EXISTS corpse_items (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			corpse_id INTEGER NOT NULL REFERENCES corpses(id) ON DELETE CASCADE,
+			item_id TEXT NOT NULL,
+			wear INTEGER NOT NULL DEFAULT 0
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_corpse_items_corpse ON corpse_items(corpse_id)`,
+		// Memorials of fallen hardcore characters. No foreign key: a memorial
+		// outlives its character being deleted
+		`CREATE TABLE IF NOT EXISTS memorials (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			character_id INTEGER NOT NULL UNIQUE,
+			name TEXT NOT NULL COLLATE NOCASE,
+			level INTEGER NOT NULL,
+			experience INTEGER NOT NULL,
+			primary_class TEXT NOT NULL,
+			race TEXT NOT NULL,
+			killed_by TEXT NOT NULL,
+			died_in TEXT NOT NULL,
+			floor_reached INTEGER NOT NULL DEFAULT 0,
+			kills INTEGER NOT NULL DEFAULT 0,
+			quests_completed INTEGER NOT NULL DEFAULT 0,
+			play_seconds INTEGER NOT NULL DEFAULT 0,
+			born_at DATETIME NOT NULL,
+			died_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_memorials_name ON memorials(name)`,
 	}
 
 	// Run safe migrations for new columns (ignore errors if columns already exist)
@@ -272,6 +423,18 @@ func (d *Database) migrateSQLite() error {
 		`ALTER TABLE characters ADD COLUMN talked_to_lore_npcs TEXT NOT NULL DEFAULT ''`,
 		// Character statistics for website
 		`ALTER TABLE characters ADD COLUMN statistics TEXT NOT NULL DEFAULT '{}'`,
+		// Player preferences
+		`ALTER TABLE characters ADD COLUMN prompt TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE characters ADD COLUMN color_prefs TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE characters ADD COLUMN channel_prefs TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE characters ADD COLUMN hide_location INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE characters ADD COLUMN aliases TEXT NOT NULL DEFAULT ''`,
+		// Death penalties
+		`ALTER TABLE characters ADD COLUMN xp_debt INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE characters ADD COLUMN item_wear TEXT NOT NULL DEFAULT ''`,
+		// Hardcore characters
+		`ALTER TABLE characters ADD COLUMN hardcore INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE characters ADD COLUMN retired INTEGER NOT NULL DEFAULT 0`,
 		// Web sessions table for companion website
 		`CREATE TABLE IF NOT EXISTS web_sessions (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -354,6 +517,15 @@ func (d *Database) migratePostgres() error {
 			visited_labyrinth_gates TEXT NOT NULL DEFAULT '',
 			talked_to_lore_npcs TEXT NOT NULL DEFAULT '',
 			statistics TEXT NOT NULL DEFAULT '{}',
+			prompt TEXT NOT NULL DEFAULT '',
+			color_prefs TEXT NOT NULL DEFAULT '',
+			channel_prefs TEXT NOT NULL DEFAULT '',
+			hide_location INTEGER NOT NULL DEFAULT 0,
+			aliases TEXT NOT NULL DEFAULT '',
+			xp_debt INTEGER NOT NULL DEFAULT 0,
+			item_wear TEXT NOT NULL DEFAULT '',
+			hardcore INTEGER NOT NULL DEFAULT 0,
+			retired INTEGER NOT NULL DEFAULT 0,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			last_played TIMESTAMP
 		)`,
@@ -431,6 +603,173 @@ func (d *Database) migratePostgres() error {
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_web_sessions_token ON web_sessions(token)`,
 		`CREATE INDEX IF NOT EXISTS idx_web_sessions_expires ON web_sessions(expires_at)`,
+
+		// Guild tables
+		`CREATE TABLE IF NOT EXISTS guilds (
+			id SERIAL PRIMARY KEY,
+			name CITEXT UNIQUE NOT NULL,
+			tag CITEXT UNIQUE NOT NULL,
+			bank_gold INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS guild_members (
+			character_id INTEGER PRIMARY KEY REFERENCES characters(id) ON DELETE CASCADE,
+			guild_id INTEGER NOT NULL REFERENCES guilds(id) ON DELETE CASCADE,
+			character_name TEXT NOT NULL,
+			member_rank TEXT NOT NULL DEFAULT 'member',
+			joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS guild_bank_items (
+			id SERIAL PRIMARY KEY,
+			guild_id INTEGER NOT NULL REFERENCES guilds(id) ON DELETE CASCADE,
+			item_id TEXT NOT NULL,
+			deposited_by TEXT NOT NULL,
+			deposited_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_guild_members_guild ON guild_members(guild_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_guild_bank_items_guild ON guild_bank_items(guild_id)`,
+
+		// Trade audit log (names, not IDs, so records outlive deleted characters)
+		`CREATE TABLE IF NOT EXISTS trade_log (
+			id SERIAL PRIMARY KEY,
+			player1 CITEXT NOT NULL,
+			player1_items TEXT NOT NULL DEFAULT '',
+			player1_gold INTEGER NOT NULL DEFAULT 0,
+			player2 CITEXT NOT NULL,
+			player2_items TEXT NOT NULL DEFAULT '',
+			player2_gold INTEGER NOT NULL DEFAULT 0,
+			room_id TEXT NOT NULL DEFAULT '',
+			traded_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_trade_log_player1 ON trade_log(player1)`,
+		`CREATE INDEX IF NOT EXISTS idx_trade_log_player2 ON trade_log(player2)`,
+
+		// Auction house
+		`CREATE TABLE IF NOT EXISTS auctions (
+			id SERIAL PRIMARY KEY,
+			seller_id INTEGER NOT NULL REFERENCES characters(id) ON DELETE CASCADE,
+			seller_name TEXT NOT NULL,
+			item_id TEXT NOT NULL,
+			item_name TEXT NOT NULL,
+			item_type TEXT NOT NULL DEFAULT '',
+			item_slot TEXT NOT NULL DEFAULT '',
+			item_tier INTEGER NOT NULL DEFAULT 0,
+			min_bid INTEGER NOT NULL DEFAULT 0,
+			buyout INTEGER NOT NULL,
+			current_bid INTEGER NOT NULL DEFAULT 0,
+			bidder_id INTEGER NOT NULL DEFAULT 0,
+			bidder_name TEXT NOT NULL DEFAULT '',
+			listed_at TIMESTAMP NOT NULL,
+			expires_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_auctions_expires ON auctions(expires_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_auctions_seller ON auctions(seller_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_auctions_bidder ON auctions(bidder_id)`,
+
+		// Player stalls
+		`CREATE TABLE IF NOT EXISTS stalls (
+			character_id INTEGER PRIMARY KEY REFERENCES characters(id) ON DELETE CASCADE,
+			character_name TEXT NOT NULL,
+			room_id TEXT NOT NULL,
+			is_open INTEGER NOT NULL DEFAULT 0
+		)`,
+		`CREATE TABLE IF NOT EXISTS stall_items (
+			id SERIAL PRIMARY KEY,
+			character_id INTEGER NOT NULL REFERENCES characters(id) ON DELETE CASCADE,
+			item_id TEXT NOT NULL,
+			price INTEGER NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_stall_items_character ON stall_items(character_id)`,
+		`CREATE TABLE IF NOT EXISTS stall_sales (
+			id SERIAL PRIMARY KEY,
+			seller_id INTEGER NOT NULL REFERENCES characters(id) ON DELETE CASCADE,
+			buyer_name TEXT NOT NULL,
+			item_name TEXT NOT NULL,
+			price INTEGER NOT NULL,
+			sold_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_stall_sales_seller ON stall_sales(seller_id)`,
+
+		// Bank vaults
+		`CREATE TABLE IF NOT EXISTS bank_vaults (
+			account_id INTEGER PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+			gold INTEGER NOT NULL DEFAULT 0,
+			slots INTEGER NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS bank_items (
+			id SERIAL PRIMARY KEY,
+			account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+			item_id TEXT NOT NULL,
+			deposited_by TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_bank_items_account ON bank_items(account_id)`,
+
+		// Friends lists
+		`CREATE TABLE IF NOT EXISTS friends (
+			character_id INTEGER NOT NULL REFERENCES characters(id) ON DELETE CASCADE,
+			friend_id INTEGER NOT NULL REFERENCES characters(id) ON DELETE CASCADE,
+			added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (character_id, friend_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_friends_friend ON friends(friend_id)`,
+
+		// Ranked duel ratings
+		`CREATE TABLE IF NOT EXISTS duel_ratings (
+			character_id INTEGER PRIMARY KEY REFERENCES characters(id) ON DELETE CASCADE,
+			rating INTEGER NOT NULL DEFAULT 1000,
+			wins INTEGER NOT NULL DEFAULT 0,
+			losses INTEGER NOT NULL DEFAULT 0,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_duel_ratings_rating ON duel_ratings(rating)`,
+
+		// Player corpses
+		`CREATE TABLE IF NOT EXISTS corpses (
+			id SERIAL PRIMARY KEY,
+			character_id INTEGER NOT NULL REFERENCES characters(id) ON DELETE CASCADE,
+			owner_name TEXT NOT NULL,
+			room_id TEXT NOT NULL,
+			expires_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_corpses_character ON corpses(character_id)`,
+		`CREATE TABLE IF NOT EXISTS corpse_items (
+			id SERIAL PRIMARY KEY,
+			corpse_id INTEGER NOT NULL REFERENCES corpses(id) ON DELETE CASCADE,
+			item_id TEXT NOT NULL,
+			wear INTEGER NOT NULL DEFAULT 0
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_corpse_items_corpse ON corpse_items(corpse_id)`,
+
+		// Memorials of fallen hardcore characters (kept if the character is deleted)
+		`CREATE TABLE IF NOT EXISTS memorials (
+			id SERIAL PRIMARY KEY,
+			character_id INTEGER NOT NULL UNIQUE,
+			name CITEXT NOT NULL,
+			level INTEGER NOT NULL,
+			experience INTEGER NOT NULL,
+			primary_class TEXT NOT NULL,
+			race TEXT NOT NULL,
+			killed_by TEXT NOT NULL,
+			died_in TEXT NOT NULL,
+			floor_reached INTEGER NOT NULL DEFAULT 0,
+			kills INTEGER NOT NULL DEFAULT 0,
+			quests_completed INTEGER NOT NULL DEFAULT 0,
+			play_seconds INTEGER NOT NULL DEFAULT 0,
+			born_at TIMESTAMP NOT NULL,
+			died_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_memorials_name ON memorials(name)`,
+
+		// Columns added after the initial schema (for existing databases)
+		`ALTER TABLE characters ADD COLUMN IF NOT EXISTS prompt TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE characters ADD COLUMN IF NOT EXISTS color_prefs TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE characters ADD COLUMN IF NOT EXISTS channel_prefs TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE characters ADD COLUMN IF NOT EXISTS hide_location INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE characters ADD COLUMN IF NOT EXISTS aliases TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE characters ADD COLUMN IF NOT EXISTS xp_debt INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE characters ADD COLUMN IF NOT EXISTS item_wear TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE characters ADD COLUMN IF NOT EXISTS hardcore INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE characters ADD COLUMN IF NOT EXISTS retired INTEGER NOT NULL DEFAULT 0`,
 	}
 
 	for _, m := range migrations {
@@ -127,6 +127,7 @@ func TestMigration_CharactersTableSchema(t *testing.T) {
 		"gold", "key_ring", "primary_class", "class_levels", "active_class",
 		"race", "crafting_skills", "known_recipes",
 		"quest_log", "quest_inventory", "earned_titles", "active_title",
+		"prompt", "color_prefs", "channel_prefs",
 	}
 
 	for _, col := range columns {
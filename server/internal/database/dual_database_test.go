@@ -29,6 +29,7 @@ func getDualTestDatabases(t *testing.T) map[string]*Database {
 		} else {
 			// Clean up PostgreSQL tables
 			tables := []string{
+				"memorials", "corpse_items", "corpses", "duel_ratings", "friends", "bank_items", "bank_vaults", "stall_sales", "stall_items", "stalls", "auctions", "trade_log", "guild_bank_items", "guild_members", "guilds",
 				"mail_items", "mail", "equipment", "inventory",
 				"characters", "boss_kills", "web_sessions", "accounts",
 			}
@@ -44,6 +45,7 @@ func getDualTestDatabases(t *testing.T) map[string]*Database {
 			if name == "postgres" {
 				// Clean up PostgreSQL tables before closing
 				tables := []string{
+					"memorials", "corpse_items", "corpses", "duel_ratings", "friends", "bank_items", "bank_vaults", "stall_sales", "stall_items", "stalls", "auctions", "trade_log", "guild_bank_items", "guild_members", "guilds",
 					"mail_items", "mail", "equipment", "inventory",
 					"characters", "boss_kills", "web_sessions", "accounts",
 				}
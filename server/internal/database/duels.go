@@ -0,0 +1,103 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/duel"
+)
+
+// ensureDuelRating creates a character's duel rating if it doesn't exist yet.
+func (d *Database) ensureDuelRating(exec execer, characterID int64) error {
+	_, err := exec.Exec(d.qb.Build(`INSERT INTO duel_ratings (character_id, rating) VALUES (?, ?)
+		ON CONFLICT (character_id) DO NOTHING`), characterID, duel.DefaultRating)
+	if err != nil {
+		return fmt.Errorf("failed to create duel rating: %w", err)
+	}
+	return nil
+}
+
+// GetDuelRating returns a character's ranked duel record. Characters that have
+// never fought a ranked duel get the default rating.
+func (d *Database) GetDuelRating(characterID int64) (*duel.Rating, error) {
+	r := &duel.Rating{CharacterID: characterID}
+	err := d.db.QueryRow(d.qb.Build(`
+		SELECT c.name, COALESCE(r.rating, ?), COALESCE(r.wins, 0), COALESCE(r.losses, 0)
+		FROM characters c LEFT JOIN duel_ratings r ON r.character_id = c.id
+		WHERE c.id = ?`),
+		duel.DefaultRating, characterID).Scan(&r.Name, &r.Rating, &r.Wins, &r.Losses)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCharacterNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get duel rating: %w", err)
+	}
+	return r, nil
+}
+
+// RecordDuelResult adjusts both characters' ratings after a ranked duel and
+// returns the new records.
+func (d *Database) RecordDuelResult(winnerID, loserID int64) (winner, loser *duel.Rating, err error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	winner = &duel.Rating{CharacterID: winnerID}
+	loser = &duel.Rating{CharacterID: loserID}
+	for _, r := range []*duel.Rating{winner, loser} {
+		if err := d.ensureDuelRating(tx, r.CharacterID); err != nil {
+			return nil, nil, err
+		}
+		err := tx.QueryRow(d.qb.Build(`
+			SELECT c.name, r.rating, r.wins, r.losses
+			FROM duel_ratings r JOIN characters c ON c.id = r.character_id
+			WHERE r.character_id = ?`),
+			r.CharacterID).Scan(&r.Name, &r.Rating, &r.Wins, &r.Losses)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get duel rating: %w", err)
+		}
+	}
+
+	winner.Rating, loser.Rating = duel.Adjust(winner.Rating, loser.Rating)
+	winner.Wins++
+	loser.Losses++
+	for _, r := range []*duel.Rating{winner, loser} {
+		_, err := tx.Exec(d.qb.Build(`UPDATE duel_ratings SET rating = ?, wins = ?, losses = ?, updated_at = CURRENT_TIMESTAMP
+			WHERE character_id = ?`), r.Rating, r.Wins, r.Losses, r.CharacterID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to update duel rating: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return winner, loser, nil
+}
+
+// GetDuelLeaderboard returns the highest rated duelists.
+func (d *Database) GetDuelLeaderboard(limit int) ([]duel.Rating, error) {
+	rows, err := d.db.Query(d.qb.Build(`
+		SELECT r.character_id, c.name, r.rating, r.wins, r.losses
+		FROM duel_ratings r JOIN characters c ON c.id = r.character_id
+		ORDER BY r.rating DESC, r.wins DESC, c.name
+		LIMIT ?`),
+		limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query duel leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	var ratings []duel.Rating
+	for rows.Next() {
+		var r duel.Rating
+		if err := rows.Scan(&r.CharacterID, &r.Name, &r.Rating, &r.Wins, &r.Losses); err != nil {
+			return nil, fmt.Errorf("failed to scan duel rating: %w", err)
+		}
+		ratings = append(ratings, r)
+	}
+	return ratings, rows.Err()
+}
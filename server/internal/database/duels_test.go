@@ -0,0 +1,65 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/duel"
+)
+
+func TestDuelRatings(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	account, err := db.CreateAccount("duelist", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	alice, _ := db.CreateCharacter(account.ID, "Alice")
+	bob, _ := db.CreateCharacter(account.ID, "Bob")
+
+	t.Run("DefaultRating", func(t *testing.T) {
+		r, err := db.GetDuelRating(alice.ID)
+		if err != nil {
+			t.Fatalf("Failed to get duel rating: %v", err)
+		}
+		if r.Name != "Alice" || r.Rating != duel.DefaultRating || r.Matches() != 0 {
+			t.Errorf("Expected an unranked Alice at %d, got %+v", duel.DefaultRating, r)
+		}
+		if _, err := db.GetDuelRating(9999); err != ErrCharacterNotFound {
+			t.Errorf("Expected ErrCharacterNotFound, got %v", err)
+		}
+	})
+
+	t.Run("RecordResult", func(t *testing.T) {
+		winner, loser, err := db.RecordDuelResult(alice.ID, bob.ID)
+		if err != nil {
+			t.Fatalf("Failed to record duel: %v", err)
+		}
+		wantWinner, wantLoser := duel.Adjust(duel.DefaultRating, duel.DefaultRating)
+		if winner.Rating != wantWinner || winner.Wins != 1 || loser.Rating != wantLoser || loser.Losses != 1 {
+			t.Errorf("Unexpected result: winner %+v, loser %+v", winner, loser)
+		}
+
+		if _, _, err := db.RecordDuelResult(alice.ID, bob.ID); err != nil {
+			t.Fatalf("Failed to record duel: %v", err)
+		}
+		r, _ := db.GetDuelRating(bob.ID)
+		if r.Losses != 2 || r.Rating >= wantLoser {
+			t.Errorf("Expected Bob to drop below %d after two losses, got %+v", wantLoser, r)
+		}
+	})
+
+	t.Run("Leaderboard", func(t *testing.T) {
+		board, err := db.GetDuelLeaderboard(10)
+		if err != nil {
+			t.Fatalf("Failed to get leaderboard: %v", err)
+		}
+		if len(board) != 2 || board[0].Name != "Alice" || board[1].Name != "Bob" {
+			t.Errorf("Expected Alice then Bob, got %+v", board)
+		}
+	})
+}
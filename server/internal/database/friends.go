@@ -0,0 +1,116 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MaxFriends is the most characters a friends list can hold.
+const MaxFriends = 50
+
+// ErrFriendsListFull is returned when a friends list already holds MaxFriends characters.
+var ErrFriendsListFull = errors.New("friends list is full")
+
+// Friend is a character on a friends list.
+type Friend struct {
+	ID           int64
+	Name         string
+	LastPlayed   *time.Time // Last time the friend saved (nil if never played)
+	HideLocation bool       // Friend has hidden their tower and floor
+}
+
+// AddFriend puts a character on another character's friends list.
+// Returns false if they were already on it, or ErrFriendsListFull.
+func (d *Database) AddFriend(characterID, friendID int64) (bool, error) {
+	var count int
+	err := d.db.QueryRow(d.qb.Build(`SELECT COUNT(*) FROM friends WHERE character_id = ?`), characterID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to count friends: %w", err)
+	}
+	if count >= MaxFriends {
+		return false, ErrFriendsListFull
+	}
+
+	result, err := d.db.Exec(d.qb.Build(`INSERT INTO friends (character_id, friend_id) VALUES (?, ?)
+		ON CONFLICT (character_id, friend_id) DO NOTHING`), characterID, friendID)
+	if err != nil {
+		return false, fmt.Errorf("failed to add friend: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// RemoveFriend takes a character off another character's friends list.
+// Returns false if they weren't on it.
+func (d *Database) RemoveFriend(characterID, friendID int64) (bool, error) {
+	result, err := d.db.Exec(d.qb.Build(`DELETE FROM friends WHERE character_id = ? AND friend_id = ?`), characterID, friendID)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove friend: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// GetFriends returns a character's friends list, sorted by name.
+func (d *Database) GetFriends(characterID int64) ([]Friend, error) {
+	rows, err := d.db.Query(d.qb.Build(`
+		SELECT c.id, c.name, c.last_played, COALESCE(c.hide_location, 0)
+		FROM friends f JOIN characters c ON c.id = f.friend_id
+		WHERE f.character_id = ?
+		ORDER BY c.name`),
+		characterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query friends: %w", err)
+	}
+	defer rows.Close()
+
+	var friends []Friend
+	for rows.Next() {
+		var f Friend
+		var lastPlayed sql.NullTime
+		var hideLocation int
+		if err := rows.Scan(&f.ID, &f.Name, &lastPlayed, &hideLocation); err != nil {
+			return nil, fmt.Errorf("failed to scan friend: %w", err)
+		}
+		if lastPlayed.Valid {
+			f.LastPlayed = &lastPlayed.Time
+		}
+		f.HideLocation = hideLocation != 0
+		friends = append(friends, f)
+	}
+	return friends, rows.Err()
+}
+
+// GetFriendedBy returns the names of the characters that have a character on
+// their friends list, so they can be told when it logs in or out.
+func (d *Database) GetFriendedBy(characterID int64) ([]string, error) {
+	rows, err := d.db.Query(d.qb.Build(`
+		SELECT c.name
+		FROM friends f JOIN characters c ON c.id = f.character_id
+		WHERE f.friend_id = ?`),
+		characterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query friended by: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan friended by: %w", err)
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestFriendOperations(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	account, err := db.CreateAccount("friendly", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	alice, _ := db.CreateCharacter(account.ID, "Alice")
+	bob, _ := db.CreateCharacter(account.ID, "Bob")
+	carol, _ := db.CreateCharacter(account.ID, "Carol")
+
+	t.Run("AddAndList", func(t *testing.T) {
+		for _, friendID := range []int64{carol.ID, bob.ID} {
+			if added, err := db.AddFriend(alice.ID, friendID); err != nil || !added {
+				t.Fatalf("Failed to add friend: %v", err)
+			}
+		}
+		if added, _ := db.AddFriend(alice.ID, bob.ID); added {
+			t.Error("Expected adding a friend twice to be refused")
+		}
+
+		friends, err := db.GetFriends(alice.ID)
+		if err != nil {
+			t.Fatalf("Failed to get friends: %v", err)
+		}
+		if len(friends) != 2 || friends[0].Name != "Bob" || friends[1].Name != "Carol" {
+			t.Errorf("Expected Bob and Carol, got %+v", friends)
+		}
+		if friends[0].LastPlayed != nil {
+			t.Error("Expected a character that never played to have no last played time")
+		}
+	})
+
+	t.Run("FriendedBy", func(t *testing.T) {
+		names, err := db.GetFriendedBy(bob.ID)
+		if err != nil || len(names) != 1 || names[0] != "Alice" {
+			t.Errorf("Expected only Alice to have Bob as a friend, got %v (%v)", names, err)
+		}
+		if names, _ := db.GetFriendedBy(alice.ID); len(names) != 0 {
+			t.Errorf("Expected friends lists to be one-way, got %v", names)
+		}
+	})
+
+	t.Run("HiddenLocation", func(t *testing.T) {
+		carol.HideLocation = true
+		if err := db.SaveCharacter(carol); err != nil {
+			t.Fatalf("Failed to save character: %v", err)
+		}
+		friends, _ := db.GetFriends(alice.ID)
+		if !friends[1].HideLocation || friends[1].LastPlayed == nil {
+			t.Errorf("Expected Carol's location to be hidden with a last played time, got %+v", friends[1])
+		}
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		if removed, err := db.RemoveFriend(alice.ID, bob.ID); err != nil || !removed {
+			t.Fatalf("Failed to remove friend: %v", err)
+		}
+		if removed, _ := db.RemoveFriend(alice.ID, bob.ID); removed {
+			t.Error("Expected removing a friend twice to report nothing removed")
+		}
+	})
+
+	t.Run("Full", func(t *testing.T) {
+		for i := 0; i < MaxFriends; i++ {
+			c, err := db.CreateCharacter(account.ID, fmt.Sprintf("Friend%02d", i))
+			if err != nil {
+				t.Fatalf("Failed to create character: %v", err)
+			}
+			_, err = db.AddFriend(bob.ID, c.ID)
+			if err != nil {
+				t.Fatalf("Failed to add friend %d: %v", i, err)
+			}
+		}
+		if _, err := db.AddFriend(bob.ID, alice.ID); !errors.Is(err, ErrFriendsListFull) {
+			t.Errorf("Expected ErrFriendsListFull, got %v", err)
+		}
+	})
+}
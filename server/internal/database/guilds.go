@@ -0,0 +1,277 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/guild"
+)
+
+// ErrGuildExists is returned when a guild name or tag is already taken.
+var ErrGuildExists = errors.New("guild name or tag already taken")
+
+// ErrAlreadyInGuild is returned when a character already belongs to a guild.
+var ErrAlreadyInGuild = errors.New("character is already in a guild")
+
+// ErrInsufficientGuildGold is returned when the guild bank can't cover a withdrawal.
+var ErrInsufficientGuildGold = errors.New("not enough gold in the guild bank")
+
+// CreateGuild creates a new guild with the given character as its founder.
+func (d *Database) CreateGuild(name, tag string, founderID int64, founderName string) (int64, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var guildID int64
+	query := `INSERT INTO guilds (name, tag) VALUES (?, ?)`
+
+	if d.dialect.SupportsLastInsertID() {
+		result, err := tx.Exec(d.qb.Build(query), name, tag)
+		if err != nil {
+			if d.dialect.IsDuplicateKeyError(err) {
+				return 0, ErrGuildExists
+			}
+			return 0, fmt.Errorf("failed to insert guild: %w", err)
+		}
+
+		guildID, err = result.LastInsertId()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get guild ID: %w", err)
+		}
+	} else {
+		// PostgreSQL: use RETURNING clause
+		err := tx.QueryRow(d.qb.BuildWithReturning(query, "id"), name, tag).Scan(&guildID)
+		if err != nil {
+			if d.dialect.IsDuplicateKeyError(err) {
+				return 0, ErrGuildExists
+			}
+			return 0, fmt.Errorf("failed to insert guild: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(d.qb.Build(`INSERT INTO guild_members (character_id, guild_id, character_name, member_rank) VALUES (?, ?, ?, ?)`),
+		founderID, guildID, founderName, string(guild.RankFounder))
+	if err != nil {
+		if d.dialect.IsDuplicateKeyError(err) {
+			return 0, ErrAlreadyInGuild
+		}
+		return 0, fmt.Errorf("failed to insert guild founder: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return guildID, nil
+}
+
+// GetGuild returns a guild by ID, or nil if it doesn't exist.
+func (d *Database) GetGuild(guildID int64) (*guild.Guild, error) {
+	var g guild.Guild
+	err := d.db.QueryRow(d.qb.Build(`SELECT id, name, tag, bank_gold FROM guilds WHERE id = ?`), guildID).
+		Scan(&g.ID, &g.Name, &g.Tag, &g.BankGold)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query guild: %w", err)
+	}
+	return &g, nil
+}
+
+// GetGuildMembership returns the guild a character belongs to and their membership.
+// Returns nil, nil if the character isn't in a guild.
+func (d *Database) GetGuildMembership(characterID int64) (*guild.Guild, *guild.Member, error) {
+	var g guild.Guild
+	var m guild.Member
+	var rank string
+
+	err := d.db.QueryRow(d.qb.Build(`
+		SELECT g.id, g.name, g.tag, g.bank_gold, gm.character_id, gm.character_name, gm.member_rank
+		FROM guild_members gm
+		JOIN guilds g ON g.id = gm.guild_id
+		WHERE gm.character_id = ?`),
+		characterID).Scan(&g.ID, &g.Name, &g.Tag, &g.BankGold, &m.CharacterID, &m.CharacterName, &rank)
+	if err == sql.ErrNoRows {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query guild membership: %w", err)
+	}
+
+	m.GuildID = g.ID
+	m.Rank = guild.Rank(rank)
+	return &g, &m, nil
+}
+
+// GetGuildMembers returns a guild's roster, highest rank first.
+func (d *Database) GetGuildMembers(guildID int64) ([]guild.Member, error) {
+	rows, err := d.db.Query(d.qb.Build(`
+		SELECT character_id, character_name, member_rank
+		FROM guild_members
+		WHERE guild_id = ?
+		ORDER BY CASE member_rank WHEN 'founder' THEN 0 WHEN 'officer' THEN 1 ELSE 2 END, character_name`),
+		guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query guild members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []guild.Member
+	for rows.Next() {
+		m := guild.Member{GuildID: guildID}
+		var rank string
+		if err := rows.Scan(&m.CharacterID, &m.CharacterName, &rank); err != nil {
+			return nil, fmt.Errorf("failed to scan guild member: %w", err)
+		}
+		m.Rank = guild.Rank(rank)
+		members = append(members, m)
+	}
+
+	return members, nil
+}
+
+// AddGuildMember adds a character to a guild at the given rank.
+func (d *Database) AddGuildMember(guildID, characterID int64, characterName string, rank guild.Rank) error {
+	_, err := d.db.Exec(d.qb.Build(`INSERT INTO guild_members (character_id, guild_id, character_name, member_rank) VALUES (?, ?, ?, ?)`),
+		characterID, guildID, characterName, string(rank))
+	if err != nil {
+		if d.dialect.IsDuplicateKeyError(err) {
+			return ErrAlreadyInGuild
+		}
+		return fmt.Errorf("failed to add guild member: %w", err)
+	}
+	return nil
+}
+
+// RemoveGuildMember removes a character from a guild.
+func (d *Database) RemoveGuildMember(guildID, characterID int64) error {
+	_, err := d.db.Exec(d.qb.Build(`DELETE FROM guild_members WHERE guild_id = ? AND character_id = ?`), guildID, characterID)
+	if err != nil {
+		return fmt.Errorf("failed to remove guild member: %w", err)
+	}
+	return nil
+}
+
+// SetGuildMemberRank changes a member's rank.
+func (d *Database) SetGuildMemberRank(guildID, characterID int64, rank guild.Rank) error {
+	_, err := d.db.Exec(d.qb.Build(`UPDATE guild_members SET member_rank = ? WHERE guild_id = ? AND character_id = ?`),
+		string(rank), guildID, characterID)
+	if err != nil {
+		return fmt.Errorf("failed to set guild member rank: %w", err)
+	}
+	return nil
+}
+
+// TransferGuildFounder makes another member the founder. The old founder becomes an officer.
+func (d *Database) TransferGuildFounder(guildID, fromID, toID int64) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	update := d.qb.Build(`UPDATE guild_members SET member_rank = ? WHERE guild_id = ? AND character_id = ?`)
+	if _, err := tx.Exec(update, string(guild.RankOfficer), guildID, fromID); err != nil {
+		return fmt.Errorf("failed to demote old founder: %w", err)
+	}
+	if _, err := tx.Exec(update, string(guild.RankFounder), guildID, toID); err != nil {
+		return fmt.Errorf("failed to promote new founder: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// DeleteGuild deletes a guild along with its roster and bank.
+func (d *Database) DeleteGuild(guildID int64) error {
+	_, err := d.db.Exec(d.qb.Build(`DELETE FROM guilds WHERE id = ?`), guildID)
+	if err != nil {
+		return fmt.Errorf("failed to delete guild: %w", err)
+	}
+	return nil
+}
+
+// DepositGuildGold adds gold to a guild's bank.
+func (d *Database) DepositGuildGold(guildID int64, amount int) error {
+	_, err := d.db.Exec(d.qb.Build(`UPDATE guilds SET bank_gold = bank_gold + ? WHERE id = ?`), amount, guildID)
+	if err != nil {
+		return fmt.Errorf("failed to deposit guild gold: %w", err)
+	}
+	return nil
+}
+
+// WithdrawGuildGold takes gold from a guild's bank.
+// Returns ErrInsufficientGuildGold if the bank holds less than amount.
+func (d *Database) WithdrawGuildGold(guildID int64, amount int) error {
+	result, err := d.db.Exec(d.qb.Build(`UPDATE guilds SET bank_gold = bank_gold - ? WHERE id = ? AND bank_gold >= ?`),
+		amount, guildID, amount)
+	if err != nil {
+		return fmt.Errorf("failed to withdraw guild gold: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrInsufficientGuildGold
+	}
+	return nil
+}
+
+// DepositGuildItem stores an item in a guild's bank.
+// Items are stored by template ID, the same way as mail attachments.
+func (d *Database) DepositGuildItem(guildID int64, itemID string, depositedBy string) error {
+	_, err := d.db.Exec(d.qb.Build(`INSERT INTO guild_bank_items (guild_id, item_id, deposited_by) VALUES (?, ?, ?)`),
+		guildID, itemID, depositedBy)
+	if err != nil {
+		return fmt.Errorf("failed to deposit guild item: %w", err)
+	}
+	return nil
+}
+
+// GetGuildBankItems returns the items stored in a guild's bank, oldest first.
+func (d *Database) GetGuildBankItems(guildID int64) ([]guild.BankItem, error) {
+	rows, err := d.db.Query(d.qb.Build(`
+		SELECT id, guild_id, item_id, deposited_by
+		FROM guild_bank_items
+		WHERE guild_id = ?
+		ORDER BY id`),
+		guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query guild bank: %w", err)
+	}
+	defer rows.Close()
+
+	var bankItems []guild.BankItem
+	for rows.Next() {
+		var item guild.BankItem
+		if err := rows.Scan(&item.ID, &item.GuildID, &item.ItemID, &item.DepositedBy); err != nil {
+			return nil, fmt.Errorf("failed to scan guild bank item: %w", err)
+		}
+		bankItems = append(bankItems, item)
+	}
+
+	return bankItems, nil
+}
+
+// WithdrawGuildItem removes a stored item from a guild's bank.
+// Returns false if the item was already taken.
+func (d *Database) WithdrawGuildItem(guildID, bankItemID int64) (bool, error) {
+	result, err := d.db.Exec(d.qb.Build(`DELETE FROM guild_bank_items WHERE id = ? AND guild_id = ?`), bankItemID, guildID)
+	if err != nil {
+		return false, fmt.Errorf("failed to withdraw guild item: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
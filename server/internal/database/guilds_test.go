@@ -0,0 +1,154 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/guild"
+)
+
+func TestGuildOperations(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	// Create two test characters
+	account1, err := db.CreateAccount("founder", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create account1: %v", err)
+	}
+	founder, err := db.CreateCharacter(account1.ID, "FounderChar")
+	if err != nil {
+		t.Fatalf("Failed to create founder: %v", err)
+	}
+
+	account2, err := db.CreateAccount("recruit", "password456")
+	if err != nil {
+		t.Fatalf("Failed to create account2: %v", err)
+	}
+	recruit, err := db.CreateCharacter(account2.ID, "RecruitChar")
+	if err != nil {
+		t.Fatalf("Failed to create recruit: %v", err)
+	}
+
+	guildID, err := db.CreateGuild("Iron Vanguard", "IRON", founder.ID, founder.Name)
+	if err != nil {
+		t.Fatalf("Failed to create guild: %v", err)
+	}
+
+	t.Run("DuplicateNameOrTag", func(t *testing.T) {
+		if _, err := db.CreateGuild("iron vanguard", "OTHER", recruit.ID, recruit.Name); err != ErrGuildExists {
+			t.Errorf("Expected ErrGuildExists for duplicate name, got %v", err)
+		}
+		if _, err := db.CreateGuild("Other Guild", "iron", recruit.ID, recruit.Name); err != ErrGuildExists {
+			t.Errorf("Expected ErrGuildExists for duplicate tag, got %v", err)
+		}
+	})
+
+	t.Run("FounderMembership", func(t *testing.T) {
+		g, m, err := db.GetGuildMembership(founder.ID)
+		if err != nil {
+			t.Fatalf("Failed to get membership: %v", err)
+		}
+		if g == nil || g.Name != "Iron Vanguard" || g.Tag != "IRON" {
+			t.Fatalf("Expected Iron Vanguard [IRON], got %+v", g)
+		}
+		if m.Rank != guild.RankFounder {
+			t.Errorf("Expected founder rank, got %s", m.Rank)
+		}
+
+		g, m, err = db.GetGuildMembership(recruit.ID)
+		if err != nil || g != nil || m != nil {
+			t.Errorf("Expected no membership for recruit, got %+v %+v %v", g, m, err)
+		}
+	})
+
+	t.Run("RosterAndRanks", func(t *testing.T) {
+		if err := db.AddGuildMember(guildID, recruit.ID, recruit.Name, guild.RankMember); err != nil {
+			t.Fatalf("Failed to add member: %v", err)
+		}
+		if err := db.AddGuildMember(guildID, recruit.ID, recruit.Name, guild.RankMember); err != ErrAlreadyInGuild {
+			t.Errorf("Expected ErrAlreadyInGuild, got %v", err)
+		}
+
+		if err := db.SetGuildMemberRank(guildID, recruit.ID, guild.RankOfficer); err != nil {
+			t.Fatalf("Failed to set rank: %v", err)
+		}
+		members, err := db.GetGuildMembers(guildID)
+		if err != nil {
+			t.Fatalf("Failed to get members: %v", err)
+		}
+		if len(members) != 2 || members[0].CharacterName != "FounderChar" || members[1].Rank != guild.RankOfficer {
+			t.Errorf("Expected founder then officer, got %+v", members)
+		}
+
+		if err := db.TransferGuildFounder(guildID, founder.ID, recruit.ID); err != nil {
+			t.Fatalf("Failed to transfer founder: %v", err)
+		}
+		_, m, _ := db.GetGuildMembership(recruit.ID)
+		if m.Rank != guild.RankFounder {
+			t.Errorf("Expected recruit to be founder after transfer, got %s", m.Rank)
+		}
+		_, m, _ = db.GetGuildMembership(founder.ID)
+		if m.Rank != guild.RankOfficer {
+			t.Errorf("Expected old founder to be an officer, got %s", m.Rank)
+		}
+	})
+
+	t.Run("BankGold", func(t *testing.T) {
+		if err := db.DepositGuildGold(guildID, 150); err != nil {
+			t.Fatalf("Failed to deposit gold: %v", err)
+		}
+		if err := db.WithdrawGuildGold(guildID, 200); err != ErrInsufficientGuildGold {
+			t.Errorf("Expected ErrInsufficientGuildGold, got %v", err)
+		}
+		if err := db.WithdrawGuildGold(guildID, 100); err != nil {
+			t.Fatalf("Failed to withdraw gold: %v", err)
+		}
+		g, _ := db.GetGuild(guildID)
+		if g.BankGold != 50 {
+			t.Errorf("Expected 50 gold left, got %d", g.BankGold)
+		}
+	})
+
+	t.Run("BankItems", func(t *testing.T) {
+		if err := db.DepositGuildItem(guildID, "rusty_sword", founder.Name); err != nil {
+			t.Fatalf("Failed to deposit item: %v", err)
+		}
+		if err := db.DepositGuildItem(guildID, "bandage", recruit.Name); err != nil {
+			t.Fatalf("Failed to deposit item: %v", err)
+		}
+
+		bank, err := db.GetGuildBankItems(guildID)
+		if err != nil {
+			t.Fatalf("Failed to get bank items: %v", err)
+		}
+		if len(bank) != 2 || bank[0].ItemID != "rusty_sword" || bank[0].DepositedBy != "FounderChar" {
+			t.Fatalf("Unexpected bank contents: %+v", bank)
+		}
+
+		taken, err := db.WithdrawGuildItem(guildID, bank[0].ID)
+		if err != nil || !taken {
+			t.Fatalf("Expected to withdraw item, got %v %v", taken, err)
+		}
+		taken, _ = db.WithdrawGuildItem(guildID, bank[0].ID)
+		if taken {
+			t.Error("Expected a second withdrawal of the same item to fail")
+		}
+	})
+
+	t.Run("DeleteGuildCascades", func(t *testing.T) {
+		if err := db.DeleteGuild(guildID); err != nil {
+			t.Fatalf("Failed to delete guild: %v", err)
+		}
+		if g, m, _ := db.GetGuildMembership(recruit.ID); g != nil || m != nil {
+			t.Error("Expected membership to be removed with the guild")
+		}
+		bank, _ := db.GetGuildBankItems(guildID)
+		if len(bank) != 0 {
+			t.Errorf("Expected bank to be removed with the guild, got %d items", len(bank))
+		}
+	})
+}
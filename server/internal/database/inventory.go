@@ -55,10 +55,10 @@ func (d *Database) SaveInventory(characterID int64, itemIDs []string) error {
 	return nil
 }
 
-// LoadInventory retrieves all inventory item IDs for a character.
+// LoadInventory retrieves all inventory item IDs for a character, in the order they were saved.
 func (d *Database) LoadInventory(characterID int64) ([]string, error) {
 	rows, err := d.db.Query(
-		d.qb.Build("SELECT item_id FROM inventory WHERE character_id = ?"),
+		d.qb.Build("SELECT item_id FROM inventory WHERE character_id = ? ORDER BY id"),
 		characterID,
 	)
 	if err != nil {
@@ -191,6 +191,13 @@ func (d *Database) SaveCharacterFull(c *Character, inventoryIDs []string, equipm
 			visited_labyrinth_gates = ?,
 			talked_to_lore_npcs = ?,
 			statistics = ?,
+			prompt = ?,
+			color_prefs = ?,
+			channel_prefs = ?,
+			hide_location = ?,
+			aliases = ?,
+			xp_debt = ?,
+			item_wear = ?,
 			last_played = CURRENT_TIMESTAMP
 		 WHERE id = ?`),
 		c.RoomID, c.Health, c.MaxHealth, c.Mana, c.MaxMana,
@@ -200,6 +207,8 @@ func (d *Database) SaveCharacterFull(c *Character, inventoryIDs []string, equipm
 		c.CraftingSkills, c.KnownRecipes,
 		c.QuestLog, c.QuestInventory, c.TrophyCase, c.EarnedTitles, c.ActiveTitle,
 		c.VisitedLabyrinthGates, c.TalkedToLoreNPCs, c.Statistics,
+		c.Prompt, c.ColorPrefs, c.ChannelPrefs, boolToInt(c.HideLocation), c.Aliases,
+		c.XPDebt, c.ItemWear,
 		c.ID,
 	)
 	if err != nil {
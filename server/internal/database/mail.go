@@ -18,6 +18,23 @@ func (d *Database) SendMail(senderID int64, senderName string, recipientID int64
 	}
 	defer tx.Rollback()
 
+	mailID, err := d.insertMail(tx, senderID, senderName, recipientID, recipientName, subject, body, goldAmount, itemIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return mailID, nil
+}
+
+// insertMail writes a mail message and its item attachments within a transaction,
+// so other features can deliver mail atomically with their own changes.
+func (d *Database) insertMail(tx *sql.Tx, senderID int64, senderName string, recipientID int64, recipientName string,
+	subject, body string, goldAmount int, itemIDs []string) (int64, error) {
+
 	// Insert the mail record
 	var mailID int64
 	query := `INSERT INTO mail (sender_id, sender_name, recipient_id, recipient_name, subject, body, gold_attached)
@@ -45,16 +62,12 @@ func (d *Database) SendMail(senderID int64, senderName string, recipientID int64
 
 	// Insert item attachments
 	for _, itemID := range itemIDs {
-		_, err = tx.Exec(d.qb.Build(`INSERT INTO mail_items (mail_id, item_id) VALUES (?, ?)`), mailID, itemID)
+		_, err := tx.Exec(d.qb.Build(`INSERT INTO mail_items (mail_id, item_id) VALUES (?, ?)`), mailID, itemID)
 		if err != nil {
 			return 0, fmt.Errorf("failed to insert mail item: %w", err)
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
-		return 0, fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
 	return mailID, nil
 }
 
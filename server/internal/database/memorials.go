@@ -0,0 +1,203 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrMemorialNotFound is returned when no fallen hardcore character has the name.
+var ErrMemorialNotFound = errors.New("memorial not found")
+
+// ErrNotHardcore is returned when retiring a character that isn't a living
+// hardcore character.
+var ErrNotHardcore = errors.New("character is not a living hardcore character")
+
+// Memorial is the permanent record of a hardcore character's death. It is
+// written once and never changed.
+type Memorial struct {
+	ID              int64
+	CharacterID     int64
+	Name            string
+	Level           int
+	Experience      int
+	PrimaryClass    string
+	Race            string
+	KilledBy        string
+	DiedIn          string // Name of the room they died in
+	FloorReached    int    // Deepest tower floor they reached
+	Kills           int
+	QuestsCompleted int
+	PlaySeconds     int64
+	BornAt          time.Time
+	DiedAt          time.Time
+}
+
+// HardcoreStanding is one row of the hardcore leaderboard: a living hardcore
+// character, or the memorial of a fallen one.
+type HardcoreStanding struct {
+	Name         string
+	Level        int
+	Experience   int
+	PrimaryClass string
+	Fallen       bool
+	KilledBy     string
+	FloorReached int // Only known for the fallen
+}
+
+// RetireCharacter marks a hardcore character as dead and writes their memorial.
+// Returns ErrNotHardcore if the character isn't hardcore or has already died.
+func (d *Database) RetireCharacter(m *Memorial) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(d.qb.Build(`UPDATE characters SET retired = 1
+		WHERE id = ? AND hardcore = 1 AND retired = 0`), m.CharacterID)
+	if err != nil {
+		return fmt.Errorf("failed to retire character: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	} else if rows == 0 {
+		return ErrNotHardcore
+	}
+
+	if m.DiedAt.IsZero() {
+		m.DiedAt = time.Now()
+	}
+	if m.BornAt.IsZero() {
+		err := tx.QueryRow(d.qb.Build(`SELECT created_at FROM characters WHERE id = ?`), m.CharacterID).Scan(&m.BornAt)
+		if err != nil {
+			return fmt.Errorf("failed to get character creation time: %w", err)
+		}
+	}
+	query := `INSERT INTO memorials (character_id, name, level, experience, primary_class, race,
+		killed_by, died_in, floor_reached, kills, quests_completed, play_seconds, born_at, died_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	args := []interface{}{m.CharacterID, m.Name, m.Level, m.Experience, m.PrimaryClass, m.Race,
+		m.KilledBy, m.DiedIn, m.FloorReached, m.Kills, m.QuestsCompleted, m.PlaySeconds,
+		m.BornAt.UTC(), m.DiedAt.UTC()}
+
+	if d.dialect.SupportsLastInsertID() {
+		result, err := tx.Exec(d.qb.Build(query), args...)
+		if err != nil {
+			return fmt.Errorf("failed to create memorial: %w", err)
+		}
+		if m.ID, err = result.LastInsertId(); err != nil {
+			return fmt.Errorf("failed to get memorial ID: %w", err)
+		}
+	} else if err := tx.QueryRow(d.qb.BuildWithReturning(query, "id"), args...).Scan(&m.ID); err != nil {
+		return fmt.Errorf("failed to create memorial: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// memorialColumns are the columns scanMemorial reads, in order.
+const memorialColumns = `id, character_id, name, level, experience, primary_class, race,
+	killed_by, died_in, floor_reached, kills, quests_completed, play_seconds,
+	born_at, died_at`
+
+// GetMemorial returns the memorial of the fallen hardcore character with the
+// name. If the name has fallen more than once, the latest death is returned.
+func (d *Database) GetMemorial(name string) (*Memorial, error) {
+	row := d.db.QueryRow(d.qb.Build(`SELECT `+memorialColumns+`
+		FROM memorials WHERE name = ? ORDER BY died_at DESC, id DESC LIMIT 1`), name)
+	m, err := scanMemorial(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMemorialNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get memorial: %w", err)
+	}
+	return m, nil
+}
+
+// GetRecentMemorials returns the latest hardcore deaths, newest first.
+func (d *Database) GetRecentMemorials(limit int) ([]*Memorial, error) {
+	rows, err := d.db.Query(d.qb.Build(`SELECT `+memorialColumns+`
+		FROM memorials ORDER BY died_at DESC, id DESC LIMIT ?`), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query memorials: %w", err)
+	}
+	defer rows.Close()
+
+	var memorials []*Memorial
+	for rows.Next() {
+		m, err := scanMemorial(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan memorial: %w", err)
+		}
+		memorials = append(memorials, m)
+	}
+	return memorials, rows.Err()
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanMemorial scans a memorial from a *sql.Row or *sql.Rows.
+func scanMemorial(row rowScanner) (*Memorial, error) {
+	var m Memorial
+	err := row.Scan(&m.ID, &m.CharacterID, &m.Name, &m.Level, &m.Experience, &m.PrimaryClass, &m.Race,
+		&m.KilledBy, &m.DiedIn, &m.FloorReached, &m.Kills, &m.QuestsCompleted, &m.PlaySeconds,
+		&m.BornAt, &m.DiedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// GetHardcoreLeaderboard returns the highest level hardcore characters, living
+// and fallen.
+func (d *Database) GetHardcoreLeaderboard(limit int) ([]HardcoreStanding, error) {
+	rows, err := d.db.Query(d.qb.Build(`
+		SELECT name, level, experience, primary_class, 0 AS fallen, '' AS killed_by, 0 AS floor_reached
+		FROM characters WHERE hardcore = 1 AND retired = 0
+		UNION ALL
+		SELECT name, level, experience, primary_class, 1, killed_by, floor_reached
+		FROM memorials
+		ORDER BY level DESC, experience DESC, name
+		LIMIT ?`),
+		limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query hardcore leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	var standings []HardcoreStanding
+	for rows.Next() {
+		var s HardcoreStanding
+		var fallen int
+		if err := rows.Scan(&s.Name, &s.Level, &s.Experience, &s.PrimaryClass, &fallen, &s.KilledBy, &s.FloorReached); err != nil {
+			return nil, fmt.Errorf("failed to scan hardcore standing: %w", err)
+		}
+		s.Fallen = fallen != 0
+		standings = append(standings, s)
+	}
+	return standings, rows.Err()
+}
+
+// GetCharacterMode reports whether a character is hardcore, and whether they
+// have fallen.
+func (d *Database) GetCharacterMode(characterID int64) (hardcore, retired bool, err error) {
+	var hc, ret int
+	err = d.db.QueryRow(d.qb.Build(`SELECT COALESCE(hardcore, 0), COALESCE(retired, 0)
+		FROM characters WHERE id = ?`), characterID).Scan(&hc, &ret)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, false, ErrCharacterNotFound
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("failed to get character mode: %w", err)
+	}
+	return hc != 0, ret != 0, nil
+}
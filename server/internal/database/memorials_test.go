@@ -0,0 +1,104 @@
+package database
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHardcoreMemorials(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	account, err := db.CreateAccount("hardcore_acct", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	brave, err := db.CreateCharacterFull(account.ID, "Brave", "warrior", "dwarf", "dwarf", true, 15, 14, 13, 12, 10, 8)
+	if err != nil {
+		t.Fatalf("Failed to create hardcore character: %v", err)
+	}
+	if _, err := db.CreateCharacterFull(account.ID, "Steady", "cleric", "elf", "elf", true, 15, 14, 13, 12, 10, 8); err != nil {
+		t.Fatalf("Failed to create hardcore character: %v", err)
+	}
+	normal, err := db.CreateCharacter(account.ID, "Normal")
+	if err != nil {
+		t.Fatalf("Failed to create character: %v", err)
+	}
+
+	if hardcore, retired, err := db.GetCharacterMode(brave.ID); err != nil || !hardcore || retired {
+		t.Fatalf("Expected Brave to be a living hardcore character, got %v %v (%v)", hardcore, retired, err)
+	}
+	if hardcore, _, _ := db.GetCharacterMode(normal.ID); hardcore {
+		t.Error("Expected a normal character not to be hardcore")
+	}
+
+	// Brave outlevels Steady, then dies
+	brave.Level, brave.Experience = 5, 900
+	if err := db.SaveCharacter(brave); err != nil {
+		t.Fatalf("Failed to save character: %v", err)
+	}
+	born := time.Now().Add(-time.Hour)
+	memorial := &Memorial{
+		CharacterID:  brave.ID,
+		Name:         "Brave",
+		Level:        5,
+		Experience:   900,
+		PrimaryClass: "warrior",
+		Race:         "dwarf",
+		KilledBy:     "a cave troll",
+		DiedIn:       "Flooded Cavern",
+		FloorReached: 7,
+		Kills:        42,
+		PlaySeconds:  3600,
+		BornAt:       born,
+	}
+	if err := db.RetireCharacter(memorial); err != nil {
+		t.Fatalf("Failed to retire character: %v", err)
+	}
+	if err := db.RetireCharacter(&Memorial{CharacterID: brave.ID}); !errors.Is(err, ErrNotHardcore) {
+		t.Errorf("Expected ErrNotHardcore retiring twice, got %v", err)
+	}
+	if err := db.RetireCharacter(&Memorial{CharacterID: normal.ID}); !errors.Is(err, ErrNotHardcore) {
+		t.Errorf("Expected ErrNotHardcore retiring a normal character, got %v", err)
+	}
+
+	loaded, err := db.GetCharacterByID(brave.ID)
+	if err != nil || !loaded.Hardcore || !loaded.Retired {
+		t.Fatalf("Expected Brave to be retired, got %+v (%v)", loaded, err)
+	}
+
+	m, err := db.GetMemorial("brave")
+	if err != nil {
+		t.Fatalf("Failed to get memorial: %v", err)
+	}
+	if m.KilledBy != "a cave troll" || m.FloorReached != 7 || m.Level != 5 || m.Kills != 42 {
+		t.Errorf("Unexpected memorial: %+v", m)
+	}
+	if m.BornAt.Sub(born).Abs() > time.Second {
+		t.Errorf("Expected the memorial to keep the character's creation time, got %v", m.BornAt)
+	}
+	if _, err := db.GetMemorial("Steady"); !errors.Is(err, ErrMemorialNotFound) {
+		t.Errorf("Expected ErrMemorialNotFound for a living character, got %v", err)
+	}
+
+	board, err := db.GetHardcoreLeaderboard(10)
+	if err != nil {
+		t.Fatalf("Failed to get hardcore leaderboard: %v", err)
+	}
+	if len(board) != 2 || board[0].Name != "Brave" || !board[0].Fallen || board[1].Name != "Steady" || board[1].Fallen {
+		t.Errorf("Expected fallen Brave above living Steady and no normal characters, got %+v", board)
+	}
+
+	// The memorial outlives the character
+	if err := db.DeleteCharacter(brave.ID); err != nil {
+		t.Fatalf("Failed to delete character: %v", err)
+	}
+	if recent, err := db.GetRecentMemorials(10); err != nil || len(recent) != 1 || recent[0].Name != "Brave" {
+		t.Errorf("Expected Brave's memorial to survive deletion, got %v (%v)", recent, err)
+	}
+}
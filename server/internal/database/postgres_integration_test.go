@@ -82,6 +82,7 @@ func setupPostgresTestDB(t *testing.T, cfg *Config) *Database {
 
 	// Clean up test data (in reverse dependency order)
 	tables := []string{
+		"memorials", "corpse_items", "corpses", "duel_ratings", "friends", "bank_items", "bank_vaults", "stall_sales", "stall_items", "stalls", "auctions", "trade_log", "guild_bank_items", "guild_members", "guilds",
 		"mail_items", "mail", "equipment", "inventory",
 		"characters", "boss_kills", "web_sessions", "accounts",
 	}
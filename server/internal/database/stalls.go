@@ -0,0 +1,240 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrStallItemGone is returned when a stall item has already been sold or taken back.
+var ErrStallItemGone = errors.New("stall item no longer for sale")
+
+// Stall is a player's stall as saved between sessions.
+type Stall struct {
+	CharacterID   int64
+	CharacterName string
+	RoomID        string // Room the stall was last set up in
+	Open          bool   // Open stalls keep selling while the owner is offline
+	Items         []StallItem
+}
+
+// StallItem is an item for sale in a saved stall.
+type StallItem struct {
+	ID     int64  // Database ID, used to buy the item while the owner is offline
+	ItemID string // References items.yaml
+	Price  int
+}
+
+// StallSale is an item sold from a stall while its owner was offline.
+type StallSale struct {
+	BuyerName string
+	ItemName  string
+	Price     int
+}
+
+// SaveStall replaces a character's saved stall. A closed, empty stall is removed.
+func (d *Database) SaveStall(characterID int64, characterName, roomID string, open bool, stallItems []StallItem) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(d.qb.Build(`DELETE FROM stall_items WHERE character_id = ?`), characterID); err != nil {
+		return fmt.Errorf("failed to clear stall items: %w", err)
+	}
+	if _, err := tx.Exec(d.qb.Build(`DELETE FROM stalls WHERE character_id = ?`), characterID); err != nil {
+		return fmt.Errorf("failed to clear stall: %w", err)
+	}
+
+	if open || len(stallItems) > 0 {
+		isOpen := 0
+		if open {
+			isOpen = 1
+		}
+		_, err := tx.Exec(d.qb.Build(`INSERT INTO stalls (character_id, character_name, room_id, is_open) VALUES (?, ?, ?, ?)`),
+			characterID, characterName, roomID, isOpen)
+		if err != nil {
+			return fmt.Errorf("failed to insert stall: %w", err)
+		}
+		for _, item := range stallItems {
+			_, err := tx.Exec(d.qb.Build(`INSERT INTO stall_items (character_id, item_id, price) VALUES (?, ?, ?)`),
+				characterID, item.ItemID, item.Price)
+			if err != nil {
+				return fmt.Errorf("failed to insert stall item: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// LoadStall returns a character's saved stall, or nil if they don't have one.
+func (d *Database) LoadStall(characterID int64) (*Stall, error) {
+	s := &Stall{CharacterID: characterID}
+	var isOpen int
+	err := d.db.QueryRow(d.qb.Build(`SELECT character_name, room_id, is_open FROM stalls WHERE character_id = ?`), characterID).
+		Scan(&s.CharacterName, &s.RoomID, &isOpen)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stall: %w", err)
+	}
+	s.Open = isOpen != 0
+
+	s.Items, err = d.loadStallItems(characterID)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// GetOpenStalls returns every open stall that still has items for sale.
+func (d *Database) GetOpenStalls() ([]*Stall, error) {
+	rows, err := d.db.Query(`SELECT character_id, character_name, room_id FROM stalls WHERE is_open = 1`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stalls: %w", err)
+	}
+	var stalls []*Stall
+	for rows.Next() {
+		s := &Stall{Open: true}
+		if err := rows.Scan(&s.CharacterID, &s.CharacterName, &s.RoomID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan stall: %w", err)
+		}
+		stalls = append(stalls, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read stalls: %w", err)
+	}
+
+	// Load items once the stalls query is closed so the two don't hold connections at once
+	result := make([]*Stall, 0, len(stalls))
+	for _, s := range stalls {
+		s.Items, err = d.loadStallItems(s.CharacterID)
+		if err != nil {
+			return nil, err
+		}
+		if len(s.Items) > 0 {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+// loadStallItems returns the items in a character's saved stall.
+func (d *Database) loadStallItems(characterID int64) ([]StallItem, error) {
+	rows, err := d.db.Query(d.qb.Build(`SELECT id, item_id, price FROM stall_items WHERE character_id = ? ORDER BY id`), characterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stall items: %w", err)
+	}
+	defer rows.Close()
+
+	var stallItems []StallItem
+	for rows.Next() {
+		var item StallItem
+		if err := rows.Scan(&item.ID, &item.ItemID, &item.Price); err != nil {
+			return nil, fmt.Errorf("failed to scan stall item: %w", err)
+		}
+		stallItems = append(stallItems, item)
+	}
+	return stallItems, rows.Err()
+}
+
+// BuyFromStall sells an item from an offline player's stall. The item is taken
+// off the stall, the price is mailed to the owner, and the sale is recorded for
+// their next login. The buyer is expected to have paid already.
+func (d *Database) BuyFromStall(stallItemID int64, buyerName, itemName string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var sellerID int64
+	var sellerName string
+	var price int
+	err = tx.QueryRow(d.qb.Build(`SELECT si.character_id, s.character_name, si.price
+		FROM stall_items si JOIN stalls s ON s.character_id = si.character_id
+		WHERE si.id = ?`), stallItemID).Scan(&sellerID, &sellerName, &price)
+	if err == sql.ErrNoRows {
+		return ErrStallItemGone
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get stall item: %w", err)
+	}
+
+	result, err := tx.Exec(d.qb.Build(`DELETE FROM stall_items WHERE id = ?`), stallItemID)
+	if err != nil {
+		return fmt.Errorf("failed to remove stall item: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrStallItemGone
+	}
+
+	// Proceeds arrive as mail from the buyer; the owner is the sender of record so
+	// the letter isn't tied to the buyer's character
+	_, err = d.insertMail(tx, sellerID, buyerName, sellerID, sellerName,
+		"Stall sale: "+itemName,
+		fmt.Sprintf("%s bought your %s from your stall for %d gold. The gold is attached.", buyerName, itemName, price),
+		price, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(d.qb.Build(`INSERT INTO stall_sales (seller_id, buyer_name, item_name, price) VALUES (?, ?, ?, ?)`),
+		sellerID, buyerName, itemName, price)
+	if err != nil {
+		return fmt.Errorf("failed to record stall sale: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// TakeStallSales returns the sales made from a character's stall since they
+// last logged in, and clears them so each sale is reported once.
+func (d *Database) TakeStallSales(characterID int64) ([]StallSale, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(d.qb.Build(`SELECT buyer_name, item_name, price FROM stall_sales WHERE seller_id = ? ORDER BY id`), characterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stall sales: %w", err)
+	}
+	var sales []StallSale
+	for rows.Next() {
+		var sale StallSale
+		if err := rows.Scan(&sale.BuyerName, &sale.ItemName, &sale.Price); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan stall sale: %w", err)
+		}
+		sales = append(sales, sale)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read stall sales: %w", err)
+	}
+
+	if len(sales) == 0 {
+		return nil, nil
+	}
+	if _, err := tx.Exec(d.qb.Build(`DELETE FROM stall_sales WHERE seller_id = ?`), characterID); err != nil {
+		return nil, fmt.Errorf("failed to clear stall sales: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return sales, nil
+}
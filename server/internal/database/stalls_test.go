@@ -0,0 +1,103 @@
+package database
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestStallOperations(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	account, err := db.CreateAccount("vendor_acct", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	owner, err := db.CreateCharacter(account.ID, "Vendor")
+	if err != nil {
+		t.Fatalf("Failed to create character: %v", err)
+	}
+
+	t.Run("SaveAndLoad", func(t *testing.T) {
+		if s, err := db.LoadStall(owner.ID); err != nil || s != nil {
+			t.Fatalf("Expected no stall, got %v, %v", s, err)
+		}
+
+		err := db.SaveStall(owner.ID, "Vendor", "town_square", false, []StallItem{
+			{ItemID: "long_sword", Price: 100},
+			{ItemID: "rusty_dagger", Price: 5},
+		})
+		if err != nil {
+			t.Fatalf("Failed to save stall: %v", err)
+		}
+
+		s, err := db.LoadStall(owner.ID)
+		if err != nil || s == nil {
+			t.Fatalf("Failed to load stall: %v", err)
+		}
+		if s.Open || s.RoomID != "town_square" || len(s.Items) != 2 || s.Items[0].Price != 100 {
+			t.Errorf("Unexpected stall: %+v", s)
+		}
+
+		if stalls, _ := db.GetOpenStalls(); len(stalls) != 0 {
+			t.Errorf("Expected a closed stall not to be listed as open, got %d", len(stalls))
+		}
+	})
+
+	t.Run("ClosedEmptyStallRemoved", func(t *testing.T) {
+		if err := db.SaveStall(owner.ID, "Vendor", "town_square", false, nil); err != nil {
+			t.Fatalf("Failed to save stall: %v", err)
+		}
+		if s, _ := db.LoadStall(owner.ID); s != nil {
+			t.Errorf("Expected the empty stall to be removed, got %+v", s)
+		}
+	})
+
+	t.Run("OfflineSaleMailsProceeds", func(t *testing.T) {
+		err := db.SaveStall(owner.ID, "Vendor", "town_square", true, []StallItem{
+			{ItemID: "long_sword", Price: 100},
+			{ItemID: "rusty_dagger", Price: 5},
+		})
+		if err != nil {
+			t.Fatalf("Failed to save stall: %v", err)
+		}
+
+		stalls, err := db.GetOpenStalls()
+		if err != nil || len(stalls) != 1 {
+			t.Fatalf("Expected 1 open stall, got %d (%v)", len(stalls), err)
+		}
+		sword := stalls[0].Items[0]
+
+		if err := db.BuyFromStall(sword.ID, "Buyer", "long sword"); err != nil {
+			t.Fatalf("Failed to buy from stall: %v", err)
+		}
+		if err := db.BuyFromStall(sword.ID, "Rival", "long sword"); !errors.Is(err, ErrStallItemGone) {
+			t.Errorf("Expected ErrStallItemGone buying a sold item, got %v", err)
+		}
+
+		m := findMail(t, db, owner.ID, "Stall sale: long sword")
+		if m.GoldAttached != 100 || m.SenderName != "Buyer" {
+			t.Errorf("Expected 100 gold from Buyer, got %d from %s", m.GoldAttached, m.SenderName)
+		}
+		if s, _ := db.LoadStall(owner.ID); len(s.Items) != 1 {
+			t.Errorf("Expected 1 item left in the stall, got %d", len(s.Items))
+		}
+	})
+
+	t.Run("SalesReportedOnce", func(t *testing.T) {
+		sales, err := db.TakeStallSales(owner.ID)
+		if err != nil {
+			t.Fatalf("Failed to take stall sales: %v", err)
+		}
+		if len(sales) != 1 || sales[0].BuyerName != "Buyer" || sales[0].Price != 100 {
+			t.Errorf("Unexpected sales: %+v", sales)
+		}
+		if sales, _ := db.TakeStallSales(owner.ID); len(sales) != 0 {
+			t.Errorf("Expected sales to be reported once, got %d", len(sales))
+		}
+	})
+}
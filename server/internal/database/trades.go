@@ -0,0 +1,72 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TradeRecord is an entry in the trade audit log.
+type TradeRecord struct {
+	ID           int64
+	Player1      string
+	Player1Items []string // Item IDs player 1 handed over
+	Player1Gold  int
+	Player2      string
+	Player2Items []string // Item IDs player 2 handed over
+	Player2Gold  int
+	RoomID       string
+	TradedAt     time.Time
+}
+
+// LogTrade records a completed trade in the audit log.
+func (d *Database) LogTrade(r *TradeRecord) error {
+	_, err := d.db.Exec(d.qb.Build(`
+		INSERT INTO trade_log (player1, player1_items, player1_gold, player2, player2_items, player2_gold, room_id, traded_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`),
+		r.Player1, strings.Join(r.Player1Items, ","), r.Player1Gold,
+		r.Player2, strings.Join(r.Player2Items, ","), r.Player2Gold,
+		r.RoomID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to log trade: %w", err)
+	}
+	return nil
+}
+
+// GetTradesForPlayer returns a player's most recent trades, newest first.
+func (d *Database) GetTradesForPlayer(name string, limit int) ([]*TradeRecord, error) {
+	rows, err := d.db.Query(d.qb.Build(`
+		SELECT id, player1, player1_items, player1_gold, player2, player2_items, player2_gold, room_id, traded_at
+		FROM trade_log
+		WHERE player1 = ? OR player2 = ?
+		ORDER BY traded_at DESC, id DESC
+		LIMIT ?`),
+		name, name, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trade log: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*TradeRecord
+	for rows.Next() {
+		r := &TradeRecord{}
+		var items1, items2 string
+		if err := rows.Scan(&r.ID, &r.Player1, &items1, &r.Player1Gold,
+			&r.Player2, &items2, &r.Player2Gold, &r.RoomID, &r.TradedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan trade record: %w", err)
+		}
+		r.Player1Items = splitItemIDs(items1)
+		r.Player2Items = splitItemIDs(items2)
+		records = append(records, r)
+	}
+
+	return records, rows.Err()
+}
+
+// splitItemIDs splits a comma-separated list of item IDs.
+func splitItemIDs(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestTradeLog(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	trades := []*TradeRecord{
+		{Player1: "Alice", Player1Items: []string{"rusty_sword", "bandage"}, Player2: "Bob", Player2Gold: 50, RoomID: "town_square"},
+		{Player1: "Carol", Player1Gold: 10, Player2: "Alice", Player2Items: []string{"torch"}, RoomID: "tavern"},
+		{Player1: "Bob", Player1Gold: 5, Player2: "Carol", RoomID: "tavern"},
+	}
+	for _, r := range trades {
+		if err := db.LogTrade(r); err != nil {
+			t.Fatalf("Failed to log trade: %v", err)
+		}
+	}
+
+	records, err := db.GetTradesForPlayer("alice", 10)
+	if err != nil {
+		t.Fatalf("Failed to get trades: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 trades for Alice, got %d", len(records))
+	}
+
+	// Newest first
+	if records[0].Player1 != "Carol" || len(records[0].Player2Items) != 1 || records[0].Player2Items[0] != "torch" {
+		t.Errorf("Unexpected newest trade: %+v", records[0])
+	}
+	if got := records[1].Player1Items; len(got) != 2 || got[0] != "rusty_sword" || got[1] != "bandage" {
+		t.Errorf("Expected [rusty_sword bandage], got %v", got)
+	}
+	if records[1].Player2Gold != 50 || records[1].Player2Items != nil {
+		t.Errorf("Expected Bob's side to be 50 gold and no items, got %+v", records[1])
+	}
+	if records[1].TradedAt.IsZero() {
+		t.Error("Expected a trade time")
+	}
+
+	limited, _ := db.GetTradesForPlayer("Alice", 1)
+	if len(limited) != 1 {
+		t.Errorf("Expected limit to apply, got %d trades", len(limited))
+	}
+}
@@ -0,0 +1,45 @@
+// Package duel provides the ranked ratings for player duels. Duels fought in
+// an arena room change both fighters' Elo ratings.
+package duel
+
+import "math"
+
+// Rating constants.
+const (
+	DefaultRating = 1000 // Rating every character starts with
+	KFactor       = 32   // Most points a single duel can move a rating
+	MinRating     = 100  // Ratings never drop below this
+)
+
+// Rating is a character's ranked duel record.
+type Rating struct {
+	CharacterID int64
+	Name        string
+	Rating      int
+	Wins        int
+	Losses      int
+}
+
+// Matches returns the number of ranked duels fought.
+func (r *Rating) Matches() int {
+	return r.Wins + r.Losses
+}
+
+// ExpectedScore returns the chance (0-1) a player rated a beats one rated b.
+func ExpectedScore(a, b int) float64 {
+	return 1 / (1 + math.Pow(10, float64(b-a)/400))
+}
+
+// Adjust returns the new ratings after the winner beats the loser. The winner
+// always gains at least one point, and the loser loses the same amount.
+func Adjust(winner, loser int) (newWinner, newLoser int) {
+	change := int(math.Round(KFactor * (1 - ExpectedScore(winner, loser))))
+	if change < 1 {
+		change = 1
+	}
+	newLoser = loser - change
+	if newLoser < MinRating {
+		newLoser = MinRating
+	}
+	return winner + change, newLoser
+}
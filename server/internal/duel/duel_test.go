@@ -0,0 +1,25 @@
+package duel
+
+import "testing"
+
+func TestAdjust(t *testing.T) {
+	tests := []struct {
+		name                  string
+		winner, loser         int
+		wantWinner, wantLoser int
+	}{
+		{"even match", 1000, 1000, 1016, 984},
+		{"favourite wins", 1400, 1000, 1403, 997},
+		{"underdog wins", 1000, 1400, 1029, 1371},
+		{"huge favourite still gains a point", 2600, 1000, 2601, 999},
+		{"loser stops at the floor", MinRating, MinRating, MinRating + 16, MinRating},
+	}
+
+	for _, tt := range tests {
+		gotWinner, gotLoser := Adjust(tt.winner, tt.loser)
+		if gotWinner != tt.wantWinner || gotLoser != tt.wantLoser {
+			t.Errorf("%s: Adjust(%d, %d) = (%d, %d), want (%d, %d)",
+				tt.name, tt.winner, tt.loser, gotWinner, gotLoser, tt.wantWinner, tt.wantLoser)
+		}
+	}
+}
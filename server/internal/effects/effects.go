@@ -0,0 +1,389 @@
+// Package effects provides the timed status effects carried by players and
+// NPCs: buffs, debuffs, poison, stuns and roots. A Set holds everything
+// affecting one creature and applies the stacking rules, dispel categories and
+// immunities shared by both.
+package effects
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Kind is what sort of status effect something is.
+type Kind string
+
+const (
+	KindBuff   Kind = "buff"   // Helpful stat change
+	KindDebuff Kind = "debuff" // Harmful stat change
+	KindPoison Kind = "poison" // Damage every combat round
+	KindStun   Kind = "stun"   // Can't act
+	KindRoot   Kind = "root"   // Can't flee
+)
+
+// Category groups effects for cleansing and immunities.
+type Category string
+
+const (
+	CategoryMagic   Category = "magic"   // Buffs and debuffs
+	CategoryPoison  Category = "poison"  // Poisons
+	CategoryControl Category = "control" // Stuns and roots
+)
+
+// Category returns the dispel category an effect of this kind belongs to.
+func (k Kind) Category() Category {
+	switch k {
+	case KindPoison:
+		return CategoryPoison
+	case KindStun, KindRoot:
+		return CategoryControl
+	default:
+		return CategoryMagic
+	}
+}
+
+// Harmful returns true for effects a cleanse removes (everything but buffs).
+func (k Kind) Harmful() bool {
+	return k != KindBuff
+}
+
+// Stat is what a buff or debuff changes.
+type Stat string
+
+const (
+	StatAC      Stat = "ac"      // +/- armor class
+	StatHit     Stat = "hit"     // +/- to attack rolls
+	StatDamage  Stat = "damage"  // +/- percent damage dealt
+	StatTaken   Stat = "taken"   // +/- percent damage taken
+	StatRegen   Stat = "regen"   // Health restored every combat round
+	StatCoating Stat = "coating" // Extra weapon damage dice for the next few hits
+)
+
+// Effect is one status effect on a creature.
+type Effect struct {
+	ID        string    // Identifies the effect for stacking, usually the spell ID (default: kind and stat)
+	Name      string    // Shown by the affects command
+	Kind      Kind      // buff, debuff, poison, stun or root
+	Stat      Stat      // Stat a buff or debuff changes
+	Amount    int       // Stat change per stack, or damage per round for poison
+	Dice      string    // Dice rolled each round for poison, or each hit for a coating (replaces Amount)
+	Stacks    int       // Times applied, for effects that stack
+	MaxStacks int       // Most stacks allowed (0 or 1 = doesn't stack)
+	Charges   int       // Uses left for effects used up on hit (0 = lasts until it expires)
+	Source    string    // Who applied it
+	Expires   time.Time // When it wears off
+}
+
+// Active returns true until the effect wears off.
+func (e Effect) Active() bool {
+	return time.Now().Before(e.Expires)
+}
+
+// Remaining returns the whole seconds left on the effect, rounded up.
+func (e Effect) Remaining() int {
+	remaining := time.Until(e.Expires)
+	if remaining <= 0 {
+		return 0
+	}
+	return int((remaining + time.Second - 1) / time.Second)
+}
+
+// Modifier returns the effect's total stat change across all its stacks.
+func (e Effect) Modifier() int {
+	return e.Amount * e.Stacks
+}
+
+// Describe returns what the effect does, e.g. "+2 AC" or "3 poison damage per round".
+func (e Effect) Describe() string {
+	switch e.Kind {
+	case KindStun:
+		return "can't act"
+	case KindRoot:
+		return "can't flee"
+	case KindPoison:
+		return fmt.Sprintf("%s poison damage per round", e.amountText())
+	}
+	switch e.Stat {
+	case StatAC:
+		return fmt.Sprintf("%+d AC", e.Modifier())
+	case StatHit:
+		return fmt.Sprintf("%+d to hit", e.Modifier())
+	case StatDamage:
+		return fmt.Sprintf("%+d%% damage dealt", e.Modifier())
+	case StatTaken:
+		return fmt.Sprintf("%+d%% damage taken", e.Modifier())
+	case StatRegen:
+		return fmt.Sprintf("%+d health per round", e.Modifier())
+	case StatCoating:
+		return fmt.Sprintf("+%s poison damage on your next %d hits", e.amountText(), e.Charges)
+	}
+	return fmt.Sprintf("%+d %s", e.Modifier(), e.Stat)
+}
+
+// amountText returns the effect's dice, or its flat amount if it has none.
+func (e Effect) amountText() string {
+	if e.Dice != "" {
+		return e.Dice
+	}
+	return fmt.Sprintf("%d", e.Amount)
+}
+
+// Ticks returns true for effects that do something every combat round.
+func (e Effect) Ticks() bool {
+	return e.Kind == KindPoison || e.Stat == StatRegen
+}
+
+// key returns what identifies the effect for stacking.
+func (e Effect) key() string {
+	if e.ID != "" {
+		return e.ID
+	}
+	if e.Stat != "" {
+		return string(e.Kind) + ":" + string(e.Stat)
+	}
+	return string(e.Kind)
+}
+
+// strength returns how strong the effect is, for deciding which of two
+// applications of the same effect wins.
+func (e Effect) strength() int {
+	if e.Amount < 0 {
+		return -e.Amount
+	}
+	return e.Amount
+}
+
+// Set is every status effect on one creature. The zero value is ready to use.
+type Set struct {
+	mu       sync.Mutex
+	effects  map[string]*Effect
+	immunity map[Category]bool
+}
+
+// SetImmunities replaces the categories of harmful effects the creature shrugs off.
+func (s *Set) SetImmunities(categories ...Category) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.immunity = make(map[Category]bool, len(categories))
+	for _, category := range categories {
+		s.immunity[category] = true
+	}
+}
+
+// IsImmune returns true if the creature shrugs off effects of the given category.
+func (s *Set) IsImmune(category Category) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.immunity[category]
+}
+
+// Apply puts an effect on the creature for the given number of seconds.
+// Reapplying an effect that stacks adds a stack, up to its MaxStacks. Otherwise
+// a stronger application replaces a weaker one, and an equal or weaker one only
+// refreshes the duration. Either way the effect lasts until the later of the
+// two expiry times. Returns false if the creature is immune.
+func (s *Set) Apply(e Effect, seconds int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e.Kind.Harmful() && s.immunity[e.Kind.Category()] {
+		return false
+	}
+	if e.Stacks < 1 {
+		e.Stacks = 1
+	}
+	e.Expires = time.Now().Add(time.Duration(seconds) * time.Second)
+
+	if s.effects == nil {
+		s.effects = make(map[string]*Effect)
+	}
+	key := e.key()
+	existing, exists := s.effects[key]
+	if !exists || !existing.Active() {
+		s.effects[key] = &e
+		return true
+	}
+
+	if e.Expires.Before(existing.Expires) {
+		e.Expires = existing.Expires
+	}
+	if e.Charges < existing.Charges {
+		e.Charges = existing.Charges
+	}
+	switch {
+	case e.MaxStacks > 1:
+		e.Stacks = existing.Stacks + 1
+		if e.Stacks > e.MaxStacks {
+			e.Stacks = e.MaxStacks
+		}
+		if e.strength() < existing.strength() {
+			e.Amount = existing.Amount
+		}
+	case e.strength() <= existing.strength():
+		existing.Expires = e.Expires
+		existing.Charges = e.Charges
+		return true
+	}
+	s.effects[key] = &e
+	return true
+}
+
+// Has returns true if an effect of the given kind is active.
+func (s *Set) Has(kind Kind) bool {
+	return s.Remaining(kind) > 0
+}
+
+// Remaining returns the seconds left on the longest active effect of the
+// given kind, or 0 if there is none.
+func (s *Set) Remaining(kind Kind) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	longest := 0
+	for _, e := range s.effects {
+		if e.Kind == kind && e.Active() {
+			if remaining := e.Remaining(); remaining > longest {
+				longest = remaining
+			}
+		}
+	}
+	return longest
+}
+
+// Modifier returns the total change to a stat from every active buff and debuff.
+func (s *Set) Modifier(stat Stat) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	total := 0
+	for _, e := range s.effects {
+		if e.Stat == stat && e.Active() && (e.Kind == KindBuff || e.Kind == KindDebuff) {
+			total += e.Modifier()
+		}
+	}
+	return total
+}
+
+// Tick drops effects that have worn off, then calls fn with each remaining
+// effect that does something every combat round (poisons and regeneration).
+// fn is called without the set locked, so it may change the set.
+func (s *Set) Tick(fn func(Effect)) {
+	s.mu.Lock()
+	var ticking []Effect
+	for key, e := range s.effects {
+		if !e.Active() {
+			delete(s.effects, key)
+			continue
+		}
+		if e.Ticks() {
+			ticking = append(ticking, *e)
+		}
+	}
+	s.mu.Unlock()
+
+	sortEffects(ticking)
+	for _, e := range ticking {
+		fn(e)
+	}
+}
+
+// Consume uses up one charge of an active effect on the given stat, removing
+// the effect once its charges run out. Returns the effect as it was before the
+// charge was used, and false if there is none.
+func (s *Set) Consume(stat Stat) (Effect, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, e := range s.effects {
+		if e.Stat != stat || !e.Active() {
+			continue
+		}
+		used := *e
+		if e.Charges > 0 {
+			e.Charges--
+			if e.Charges == 0 {
+				delete(s.effects, key)
+			}
+		}
+		return used, true
+	}
+	return Effect{}, false
+}
+
+// Cleanse removes harmful effects in the given categories, or every harmful
+// effect if none are given. Returns the effects removed.
+func (s *Set) Cleanse(categories ...Category) []Effect {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var removed []Effect
+	for key, e := range s.effects {
+		if !e.Kind.Harmful() || !inCategories(e.Kind.Category(), categories) {
+			continue
+		}
+		if e.Active() {
+			removed = append(removed, *e)
+		}
+		delete(s.effects, key)
+	}
+	sortEffects(removed)
+	return removed
+}
+
+// Remove takes off every effect of the given kind.
+func (s *Set) Remove(kind Kind) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, e := range s.effects {
+		if e.Kind == kind {
+			delete(s.effects, key)
+		}
+	}
+}
+
+// Clear removes every effect (on death or reset). Immunities are kept.
+func (s *Set) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.effects = nil
+}
+
+// List returns the active effects, sorted by kind and then name.
+func (s *Set) List() []Effect {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var list []Effect
+	for _, e := range s.effects {
+		if e.Active() {
+			list = append(list, *e)
+		}
+	}
+	sortEffects(list)
+	return list
+}
+
+// inCategories returns true if category is one of categories, or categories is empty.
+func inCategories(category Category, categories []Category) bool {
+	if len(categories) == 0 {
+		return true
+	}
+	for _, c := range categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// kindOrder is the order effects are listed in.
+var kindOrder = map[Kind]int{KindBuff: 0, KindDebuff: 1, KindPoison: 2, KindStun: 3, KindRoot: 4}
+
+// sortEffects sorts effects by kind and then name, so lists and ticks are stable.
+func sortEffects(list []Effect) {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Kind != list[j].Kind {
+			return kindOrder[list[i].Kind] < kindOrder[list[j].Kind]
+		}
+		if list[i].Name != list[j].Name {
+			return list[i].Name < list[j].Name
+		}
+		return list[i].key() < list[j].key()
+	})
+}
@@ -0,0 +1,218 @@
+package effects
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKindCategory(t *testing.T) {
+	tests := []struct {
+		kind     Kind
+		category Category
+		harmful  bool
+	}{
+		{KindBuff, CategoryMagic, false},
+		{KindDebuff, CategoryMagic, true},
+		{KindPoison, CategoryPoison, true},
+		{KindStun, CategoryControl, true},
+		{KindRoot, CategoryControl, true},
+	}
+	for _, tt := range tests {
+		if got := tt.kind.Category(); got != tt.category {
+			t.Errorf("%s.Category() = %s, want %s", tt.kind, got, tt.category)
+		}
+		if got := tt.kind.Harmful(); got != tt.harmful {
+			t.Errorf("%s.Harmful() = %v, want %v", tt.kind, got, tt.harmful)
+		}
+	}
+}
+
+func TestApplyStacking(t *testing.T) {
+	tests := []struct {
+		name       string
+		first      Effect
+		second     Effect
+		wantAmount int
+		wantStacks int
+		wantMod    int
+	}{
+		{
+			"stronger replaces weaker",
+			Effect{ID: "venom", Kind: KindPoison, Amount: 2},
+			Effect{ID: "venom", Kind: KindPoison, Amount: 5},
+			5, 1, 0,
+		},
+		{
+			"weaker only refreshes",
+			Effect{ID: "venom", Kind: KindPoison, Amount: 5},
+			Effect{ID: "venom", Kind: KindPoison, Amount: 2},
+			5, 1, 0,
+		},
+		{
+			"stacking effect adds a stack",
+			Effect{ID: "sunder", Kind: KindDebuff, Stat: StatAC, Amount: -1, MaxStacks: 3},
+			Effect{ID: "sunder", Kind: KindDebuff, Stat: StatAC, Amount: -1, MaxStacks: 3},
+			-1, 2, -2,
+		},
+		{
+			"different effects on the same stat add up",
+			Effect{ID: "shield", Kind: KindBuff, Stat: StatAC, Amount: 2},
+			Effect{ID: "protection", Kind: KindBuff, Stat: StatAC, Amount: 1},
+			2, 1, 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s Set
+			s.Apply(tt.first, 10)
+			s.Apply(tt.second, 10)
+			got := s.List()
+			var first Effect
+			for _, e := range got {
+				if e.ID == tt.first.ID {
+					first = e
+				}
+			}
+			if first.Amount != tt.wantAmount || first.Stacks != tt.wantStacks {
+				t.Errorf("got amount %d with %d stacks, want %d with %d", first.Amount, first.Stacks, tt.wantAmount, tt.wantStacks)
+			}
+			if tt.wantMod != 0 {
+				if mod := s.Modifier(tt.first.Stat); mod != tt.wantMod {
+					t.Errorf("Modifier() = %d, want %d", mod, tt.wantMod)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyStackCap(t *testing.T) {
+	var s Set
+	for i := 0; i < 5; i++ {
+		s.Apply(Effect{ID: "sunder", Kind: KindDebuff, Stat: StatAC, Amount: -1, MaxStacks: 3}, 10)
+	}
+	if mod := s.Modifier(StatAC); mod != -3 {
+		t.Errorf("Modifier() = %d, want -3 at the stack cap", mod)
+	}
+}
+
+func TestApplyKeepsLongerDuration(t *testing.T) {
+	var s Set
+	s.Apply(Effect{Kind: KindStun}, 10)
+	s.Apply(Effect{Kind: KindStun}, 2)
+	if remaining := s.Remaining(KindStun); remaining != 10 {
+		t.Errorf("Remaining() = %d, want the longer stun (10)", remaining)
+	}
+}
+
+func TestImmunities(t *testing.T) {
+	var s Set
+	s.SetImmunities(CategoryPoison)
+	if s.Apply(Effect{Kind: KindPoison, Amount: 3}, 10) {
+		t.Error("Expected an immune creature to shrug off poison")
+	}
+	if s.Has(KindPoison) {
+		t.Error("Expected no poison on an immune creature")
+	}
+	if !s.Apply(Effect{Kind: KindStun}, 10) || !s.Has(KindStun) {
+		t.Error("Expected immunity to poison not to stop a stun")
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	var s Set
+	s.Apply(Effect{Kind: KindBuff, Stat: StatHit, Amount: 2}, 0)
+	if s.Has(KindBuff) || s.Modifier(StatHit) != 0 {
+		t.Error("Expected an expired buff to have no effect")
+	}
+	if len(s.List()) != 0 {
+		t.Error("Expected expired effects not to be listed")
+	}
+}
+
+func TestTick(t *testing.T) {
+	var s Set
+	s.Apply(Effect{ID: "venom", Kind: KindPoison, Amount: 3, Source: "spider"}, 10)
+	s.Apply(Effect{ID: "renew", Kind: KindBuff, Stat: StatRegen, Amount: 2}, 10)
+	s.Apply(Effect{ID: "shield", Kind: KindBuff, Stat: StatAC, Amount: 2}, 10)
+	s.Apply(Effect{ID: "old", Kind: KindPoison, Amount: 9}, 0)
+
+	var ticked []string
+	s.Tick(func(e Effect) {
+		ticked = append(ticked, e.ID)
+		if e.Kind == KindPoison {
+			s.Cleanse(CategoryPoison) // Callbacks may change the set
+		}
+	})
+	if len(ticked) != 2 || ticked[0] != "renew" || ticked[1] != "venom" {
+		t.Errorf("Tick() called %v, want [renew venom]", ticked)
+	}
+	if s.Has(KindPoison) {
+		t.Error("Expected the callback's cleanse to remove the poison")
+	}
+}
+
+func TestConsume(t *testing.T) {
+	var s Set
+	s.Apply(Effect{ID: "poison_blade", Kind: KindBuff, Stat: StatCoating, Dice: "1d4", Charges: 2}, 60)
+	for i := 0; i < 2; i++ {
+		if e, ok := s.Consume(StatCoating); !ok || e.Dice != "1d4" {
+			t.Fatalf("Consume() #%d = %+v, %v; want the coating", i+1, e, ok)
+		}
+	}
+	if _, ok := s.Consume(StatCoating); ok {
+		t.Error("Expected the coating to be used up after its charges")
+	}
+}
+
+func TestCleanse(t *testing.T) {
+	var s Set
+	s.Apply(Effect{ID: "shield", Kind: KindBuff, Stat: StatAC, Amount: 2}, 10)
+	s.Apply(Effect{ID: "mark", Kind: KindDebuff, Stat: StatTaken, Amount: 50}, 10)
+	s.Apply(Effect{ID: "venom", Kind: KindPoison, Amount: 3}, 10)
+	s.Apply(Effect{Kind: KindStun}, 10)
+
+	removed := s.Cleanse(CategoryPoison)
+	if len(removed) != 1 || removed[0].ID != "venom" {
+		t.Errorf("Cleanse(poison) removed %v, want only the venom", removed)
+	}
+	if !s.Has(KindStun) || !s.Has(KindDebuff) {
+		t.Error("Expected a poison cleanse to leave other effects")
+	}
+
+	removed = s.Cleanse()
+	if len(removed) != 2 {
+		t.Errorf("Cleanse() removed %d effects, want the debuff and the stun", len(removed))
+	}
+	if !s.Has(KindBuff) {
+		t.Error("Expected a cleanse to leave buffs alone")
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	e := Effect{Expires: time.Now().Add(2500 * time.Millisecond)}
+	if got := e.Remaining(); got != 3 {
+		t.Errorf("Remaining() = %d, want 3 (rounded up)", got)
+	}
+	if got := (Effect{}).Remaining(); got != 0 {
+		t.Errorf("Remaining() = %d for an expired effect, want 0", got)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		effect Effect
+		want   string
+	}{
+		{Effect{Kind: KindBuff, Stat: StatAC, Amount: 2, Stacks: 1}, "+2 AC"},
+		{Effect{Kind: KindDebuff, Stat: StatHit, Amount: -1, Stacks: 3}, "-3 to hit"},
+		{Effect{Kind: KindDebuff, Stat: StatTaken, Amount: 50, Stacks: 1}, "+50% damage taken"},
+		{Effect{Kind: KindPoison, Dice: "1d4"}, "1d4 poison damage per round"},
+		{Effect{Kind: KindBuff, Stat: StatCoating, Dice: "1d4", Charges: 2}, "+1d4 poison damage on your next 2 hits"},
+		{Effect{Kind: KindStun}, "can't act"},
+	}
+	for _, tt := range tests {
+		if got := tt.effect.Describe(); got != tt.want {
+			t.Errorf("Describe() = %q, want %q", got, tt.want)
+		}
+	}
+}
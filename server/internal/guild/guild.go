@@ -0,0 +1,154 @@
+// Package guild provides persistent player guilds with ranks, a roster and a shared bank.
+package guild
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Rank is a member's standing within their guild.
+type Rank string
+
+// Guild ranks, from lowest to highest.
+const (
+	RankMember  Rank = "member"
+	RankOfficer Rank = "officer"
+	RankFounder Rank = "founder"
+)
+
+// Guild represents a player guild.
+type Guild struct {
+	ID       int64
+	Name     string
+	Tag      string // Short tag shown next to member names, e.g. [RAID]
+	BankGold int
+}
+
+// Member represents a character's membership in a guild.
+type Member struct {
+	GuildID       int64
+	CharacterID   int64
+	CharacterName string
+	Rank          Rank
+}
+
+// BankItem represents an item stored in a guild bank.
+type BankItem struct {
+	ID          int64
+	GuildID     int64
+	ItemID      string // References items.yaml
+	DepositedBy string
+}
+
+// Guild system constants.
+const (
+	MinNameLen   = 3
+	MaxNameLen   = 30
+	MinTagLen    = 2
+	MaxTagLen    = 5
+	MaxMembers   = 50   // Maximum members per guild
+	MaxBankItems = 100  // Maximum items stored in a guild bank
+	FoundingCost = 1000 // Gold it costs to found a guild
+)
+
+// level returns the rank's position for comparisons.
+func (r Rank) level() int {
+	switch r {
+	case RankFounder:
+		return 3
+	case RankOfficer:
+		return 2
+	case RankMember:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// IsValid returns true if r is a known rank.
+func (r Rank) IsValid() bool {
+	return r.level() > 0
+}
+
+// Title returns the rank's display name.
+func (r Rank) Title() string {
+	switch r {
+	case RankFounder:
+		return "Founder"
+	case RankOfficer:
+		return "Officer"
+	default:
+		return "Member"
+	}
+}
+
+// Outranks returns true if r is a higher rank than other.
+func (r Rank) Outranks(other Rank) bool {
+	return r.level() > other.level()
+}
+
+// CanInvite returns true if the rank may invite new members.
+func (r Rank) CanInvite() bool {
+	return r.level() >= RankOfficer.level()
+}
+
+// CanKick returns true if the rank may remove a member of the target rank.
+// Officers can remove members; only the founder can remove officers.
+func (r Rank) CanKick(target Rank) bool {
+	return r.CanInvite() && r.Outranks(target)
+}
+
+// CanWithdraw returns true if the rank may take gold and items from the guild bank.
+func (r Rank) CanWithdraw() bool {
+	return r.level() >= RankOfficer.level()
+}
+
+// Promoted returns the next rank up, or r if it can't be promoted further.
+// Founder is never reached by promotion - it passes with 'guild transfer'.
+func (r Rank) Promoted() Rank {
+	if r == RankMember {
+		return RankOfficer
+	}
+	return r
+}
+
+// Demoted returns the next rank down, or r if it can't be demoted further.
+func (r Rank) Demoted() Rank {
+	if r == RankOfficer {
+		return RankMember
+	}
+	return r
+}
+
+// ValidateName checks that a guild name is an acceptable length and uses
+// only letters, spaces, apostrophes and hyphens.
+func ValidateName(name string) error {
+	if len(name) < MinNameLen || len(name) > MaxNameLen {
+		return fmt.Errorf("Guild names must be %d to %d characters long.", MinNameLen, MaxNameLen)
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && r != ' ' && r != '\'' && r != '-' {
+			return errors.New("Guild names may only contain letters, spaces, apostrophes and hyphens.")
+		}
+	}
+	if strings.Contains(name, "  ") || strings.TrimSpace(name) != name {
+		return errors.New("Guild names can't have leading, trailing or double spaces.")
+	}
+	return nil
+}
+
+// ValidateTag checks that a guild tag is an acceptable length and uses only
+// letters and digits.
+func ValidateTag(tag string) error {
+	if len(tag) < MinTagLen || len(tag) > MaxTagLen {
+		return fmt.Errorf("Guild tags must be %d to %d characters long.", MinTagLen, MaxTagLen)
+	}
+	for _, r := range tag {
+		if r > unicode.MaxASCII || (!unicode.IsLetter(r) && !unicode.IsDigit(r)) {
+			return errors.New("Guild tags may only contain letters and digits.")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package guild
+
+import "testing"
+
+func TestRankPermissions(t *testing.T) {
+	tests := []struct {
+		rank        Rank
+		canInvite   bool
+		canWithdraw bool
+	}{
+		{RankMember, false, false},
+		{RankOfficer, true, true},
+		{RankFounder, true, true},
+		{Rank("bogus"), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.rank), func(t *testing.T) {
+			if got := tt.rank.CanInvite(); got != tt.canInvite {
+				t.Errorf("CanInvite() = %v, want %v", got, tt.canInvite)
+			}
+			if got := tt.rank.CanWithdraw(); got != tt.canWithdraw {
+				t.Errorf("CanWithdraw() = %v, want %v", got, tt.canWithdraw)
+			}
+		})
+	}
+}
+
+func TestRankCanKick(t *testing.T) {
+	tests := []struct {
+		name     string
+		rank     Rank
+		target   Rank
+		expected bool
+	}{
+		{"member kicks member", RankMember, RankMember, false},
+		{"officer kicks member", RankOfficer, RankMember, true},
+		{"officer kicks officer", RankOfficer, RankOfficer, false},
+		{"founder kicks officer", RankFounder, RankOfficer, true},
+		{"officer kicks founder", RankOfficer, RankFounder, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rank.CanKick(tt.target); got != tt.expected {
+				t.Errorf("%s.CanKick(%s) = %v, want %v", tt.rank, tt.target, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRankPromoteDemote(t *testing.T) {
+	tests := []struct {
+		rank     Rank
+		promoted Rank
+		demoted  Rank
+	}{
+		{RankMember, RankOfficer, RankMember},
+		{RankOfficer, RankOfficer, RankMember},
+		{RankFounder, RankFounder, RankFounder},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.rank), func(t *testing.T) {
+			if got := tt.rank.Promoted(); got != tt.promoted {
+				t.Errorf("Promoted() = %s, want %s", got, tt.promoted)
+			}
+			if got := tt.rank.Demoted(); got != tt.demoted {
+				t.Errorf("Demoted() = %s, want %s", got, tt.demoted)
+			}
+		})
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"Iron Vanguard", true},
+		{"Knights of Ni'", true},
+		{"Blood-Oath", true},
+		{"Ab", false},
+		{"This Guild Name Is Far Too Long To Fit", false},
+		{"Raiders2", false},
+		{" Raiders", false},
+		{"Iron  Vanguard", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateName(tt.name)
+			if (err == nil) != tt.valid {
+				t.Errorf("ValidateName(%q) error = %v, want valid=%v", tt.name, err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestValidateTag(t *testing.T) {
+	tests := []struct {
+		tag   string
+		valid bool
+	}{
+		{"RAID", true},
+		{"IV2", true},
+		{"X", false},
+		{"TOOLONG", false},
+		{"R-D", false},
+		{"ÄÖÜ", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			err := ValidateTag(tt.tag)
+			if (err == nil) != tt.valid {
+				t.Errorf("ValidateTag(%q) error = %v, want valid=%v", tt.tag, err, tt.valid)
+			}
+		})
+	}
+}
@@ -260,3 +260,36 @@ func TestNewTreasureKey(t *testing.T) {
 		t.Error("TreasureKey Description should not be empty")
 	}
 }
+
+func TestWear(t *testing.T) {
+	tests := []struct {
+		wear          int
+		add           int
+		wantWear      int
+		wantBroke     bool
+		wantCondition string
+		wantCost      int
+	}{
+		{0, 0, 0, false, "", 0},
+		{0, 25, 25, false, "worn", 25},
+		{40, 25, 65, false, "badly worn", 65},
+		{90, 25, 100, true, "broken", 100},
+		{100, 25, 100, false, "broken", 100}, // Already broken
+	}
+
+	for _, tc := range tests {
+		item := NewArmor("chain shirt", "Test armor", 10, 200, 4, SlotBody)
+		item.Wear = tc.wear
+		broke := item.AddWear(tc.add)
+
+		if item.Wear != tc.wantWear || broke != tc.wantBroke {
+			t.Errorf("AddWear(%d) at %d%%: wear = %d, broke = %v; want %d, %v", tc.add, tc.wear, item.Wear, broke, tc.wantWear, tc.wantBroke)
+		}
+		if got := item.Condition(); got != tc.wantCondition {
+			t.Errorf("Condition() at %d%% = %q, want %q", item.Wear, got, tc.wantCondition)
+		}
+		if got := item.RepairCost(); got != tc.wantCost {
+			t.Errorf("RepairCost() at %d%% = %d, want %d", item.Wear, got, tc.wantCost)
+		}
+	}
+}
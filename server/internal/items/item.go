@@ -58,6 +58,7 @@ type Item struct {
 	Weight      float64
 	Type        ItemType
 	Value       int // Gold value
+	Tier        int // Loot tier (1=common ... 5=legendary, 0 = not a loot item)
 	// Equipment stats (optional, only for equippable items)
 	Slot       EquipmentSlot
 	Armor      int    // Damage reduction for armor
@@ -75,8 +76,13 @@ type Item struct {
 	ManaAmount int  // MP restored when consumed
 	// Unique item flag - player can only have one of these
 	Unique bool // If true, player can only possess one instance of this item
+	// Durability (per instance, not from YAML)
+	Wear int // Percent worn, 0 = pristine, MaxWear = broken
 }
 
+// MaxWear is the wear at which an item breaks.
+const MaxWear = 100
+
 // NewItem creates a new item with the given properties
 func NewItem(name, description string, weight float64, itemType ItemType, value int) *Item {
 	return &Item{
@@ -178,6 +184,51 @@ func (i *Item) String() string {
 	return fmt.Sprintf("%s (%s, %.1f, %d gold)", i.Name, i.Type.String(), i.Weight, i.Value)
 }
 
+// IsBroken returns true if the item is too worn to give any benefit
+func (i *Item) IsBroken() bool {
+	return i.Wear >= MaxWear
+}
+
+// AddWear wears the item down by a percentage, capped at MaxWear.
+// Returns true if this wear broke the item.
+func (i *Item) AddWear(percent int) bool {
+	if percent <= 0 || i.IsBroken() {
+		return false
+	}
+	i.Wear += percent
+	if i.Wear > MaxWear {
+		i.Wear = MaxWear
+	}
+	return i.IsBroken()
+}
+
+// Condition describes how worn the item is, or "" if it's undamaged
+func (i *Item) Condition() string {
+	switch {
+	case i.Wear <= 0:
+		return ""
+	case i.IsBroken():
+		return "broken"
+	case i.Wear >= 50:
+		return "badly worn"
+	default:
+		return "worn"
+	}
+}
+
+// RepairCost returns the gold a shop charges to repair the item's wear.
+// Fixing a broken item costs half its value.
+func (i *Item) RepairCost() int {
+	if i.Wear <= 0 {
+		return 0
+	}
+	cost := i.Value * i.Wear / (MaxWear * 2)
+	if cost < 1 {
+		cost = 1
+	}
+	return cost
+}
+
 // IsFinesse returns true if this weapon can use DEX instead of STR
 func (i *Item) IsFinesse() bool {
 	return i.WeaponType == "finesse"
@@ -121,6 +121,7 @@ func CreateItemFromDefinition(id string, def ItemDefinition) *Item {
 
 	// Set the unique identifier
 	item.ID = id
+	item.Tier = def.Tier
 
 	// Set equipment fields if provided
 	if def.Slot != "" {
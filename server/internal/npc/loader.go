@@ -23,8 +23,17 @@ type ShopItemYAML struct {
 	Price int    `yaml:"price"` // Price in gold (0 = use item's base value)
 }
 
+// AbilityYAML represents a combat ability in YAML format
+type AbilityYAML struct {
+	Spell    string  `yaml:"spell"`    // Spell ID (from spells.yaml)
+	Cooldown int     `yaml:"cooldown"` // Seconds between uses (0 = the spell's own cooldown)
+	Chance   float64 `yaml:"chance"`   // Percentage chance to use it when ready (0-100, 0 = always)
+	HPBelow  float64 `yaml:"hp_below"` // Only used at or below this percentage of max health (0 = at any health)
+}
+
 // NPCDefinition represents an NPC definition from the YAML file
 type NPCDefinition struct {
+	ID               string          `yaml:"-"` // Key in the npcs map; filled in by the GetMobs/GetBosses lookups
 	Name             string          `yaml:"name"`
 	Description      string          `yaml:"description"`
 	Level            int             `yaml:"level"`
@@ -50,10 +59,13 @@ type NPCDefinition struct {
 	TurnInQuests     []string        `yaml:"turn_in_quests"`    // Quest IDs that can be turned in to this NPC
 	LoreNPC          bool            `yaml:"lore_npc"`          // Is this a labyrinth lore NPC?
 	GuideNPC         bool            `yaml:"guide_npc"`         // Is this a city guide NPC? (provides tutorial)
+	Auctioneer       bool            `yaml:"auctioneer"`        // Does this NPC run the auction house?
+	Banker           bool            `yaml:"banker"`            // Does this NPC run the bank vault?
 	Locations        []string        `yaml:"locations"`         // Room IDs where this NPC spawns
 	RespawnMedian    int             `yaml:"respawn_median"`    // Median respawn time in seconds
 	RespawnVariation int             `yaml:"respawn_variation"` // Variation in respawn time (+/- seconds)
 	TowerTags        []string        `yaml:"tower_tags"`        // Tower tags for themed spawning (e.g., "shared", "human", "arcane")
+	Abilities        []AbilityYAML   `yaml:"abilities"`         // Spells used in combat, in priority order
 }
 
 // NPCsConfig represents the structure of the npcs.yaml file
@@ -171,9 +183,34 @@ func CreateNPCFromDefinition(def NPCDefinition, roomID string) *NPC {
 	if def.GuideNPC {
 		npc.SetGuideNPC(true)
 	}
+	// Set auctioneer flag for auction house NPCs
+	if def.Auctioneer {
+		npc.SetAuctioneer(true)
+	}
+	// Set banker flag for bank vault NPCs
+	if def.Banker {
+		npc.SetBanker(true)
+	}
+	if len(def.Abilities) > 0 {
+		npc.SetAbilities(CreateAbilitiesFromDefinition(def))
+	}
 	return npc
 }
 
+// CreateAbilitiesFromDefinition converts the YAML abilities of an NPCDefinition
+func CreateAbilitiesFromDefinition(def NPCDefinition) []Ability {
+	abilities := make([]Ability, len(def.Abilities))
+	for i, entry := range def.Abilities {
+		abilities[i] = Ability{
+			SpellID:  entry.Spell,
+			Cooldown: entry.Cooldown,
+			Chance:   entry.Chance,
+			HPBelow:  entry.HPBelow,
+		}
+	}
+	return abilities
+}
+
 // CreateNPCFromDefinitionWithID creates an NPC from an NPCDefinition and stores the definition ID
 func CreateNPCFromDefinitionWithID(npcID string, def NPCDefinition, roomID string) *NPC {
 	npc := CreateNPCFromDefinition(def, roomID)
@@ -297,8 +334,9 @@ func LoadNPCsFromDirectories(dirs ...string) (*NPCsConfig, error) {
 // GetMobsByTier returns all non-boss mob definitions for a given tier
 func (config *NPCsConfig) GetMobsByTier(tier int) []NPCDefinition {
 	var mobs []NPCDefinition
-	for _, def := range config.NPCs {
+	for id, def := range config.NPCs {
 		if def.Tier == tier && !def.Boss && def.Attackable {
+			def.ID = id
 			mobs = append(mobs, def)
 		}
 	}
@@ -308,8 +346,9 @@ func (config *NPCsConfig) GetMobsByTier(tier int) []NPCDefinition {
 // GetBossesByTier returns all boss mob definitions for a given tier
 func (config *NPCsConfig) GetBossesByTier(tier int) []NPCDefinition {
 	var bosses []NPCDefinition
-	for _, def := range config.NPCs {
+	for id, def := range config.NPCs {
 		if def.Tier == tier && def.Boss {
+			def.ID = id
 			bosses = append(bosses, def)
 		}
 	}
@@ -367,8 +406,9 @@ func mobMatchesTags(def NPCDefinition, tags []string) bool {
 // that match at least one of the provided tags.
 func (config *NPCsConfig) GetMobsByTierAndTags(tier int, tags []string) []NPCDefinition {
 	var mobs []NPCDefinition
-	for _, def := range config.NPCs {
+	for id, def := range config.NPCs {
 		if def.Tier == tier && !def.Boss && def.Attackable && mobMatchesTags(def, tags) {
+			def.ID = id
 			mobs = append(mobs, def)
 		}
 	}
@@ -379,8 +419,9 @@ func (config *NPCsConfig) GetMobsByTierAndTags(tier int, tags []string) []NPCDef
 // that match at least one of the provided tags.
 func (config *NPCsConfig) GetBossesByTierAndTags(tier int, tags []string) []NPCDefinition {
 	var bosses []NPCDefinition
-	for _, def := range config.NPCs {
+	for id, def := range config.NPCs {
 		if def.Tier == tier && def.Boss && mobMatchesTags(def, tags) {
+			def.ID = id
 			bosses = append(bosses, def)
 		}
 	}
@@ -5,6 +5,8 @@ import (
 	"math/rand"
 	"sync"
 	"time"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/effects"
 )
 
 // LootEntry represents an item that can drop with a percentage chance
@@ -19,6 +21,14 @@ type ShopItem struct {
 	Price    int    // Price in gold (0 = use item's base value)
 }
 
+// Ability is a spell an NPC uses in combat in place of its melee attack
+type Ability struct {
+	SpellID  string  // Spell to use (from spells.yaml)
+	Cooldown int     // Seconds before it can be used again (0 = the spell's own cooldown)
+	Chance   float64 // Percentage chance to use it each round it's ready (0 = always)
+	HPBelow  float64 // Only used at or below this percentage of max health (0 = at any health)
+}
+
 // MobType represents the creature type for class bonuses (favored enemy, smite)
 type MobType string
 
@@ -58,8 +68,6 @@ type NPC struct {
 	OriginalRoomID   string          // Room where NPC originally spawned
 	DeathTime        time.Time       // When this NPC died
 	RespawnTime      time.Time       // When this NPC should respawn
-	StunEndTime      time.Time       // When stun effect expires
-	RootEndTime      time.Time       // When root effect expires (prevents fleeing)
 	FleeThreshold    float64         // HP percentage at which mob will flee (0.0-1.0, 0 = never)
 	IsBoss           bool            // Is this a boss mob?
 	Floor            int             // Tower floor this mob is on (for boss key drops)
@@ -72,8 +80,18 @@ type NPC struct {
 	TurnInQuests     []string        // Quest IDs that can be turned in to this NPC
 	LoreNPC          bool            // Is this a labyrinth lore NPC?
 	GuideNPC         bool            // Is this a city guide NPC? (provides tutorial)
+	Auctioneer       bool            // Does this NPC run the auction house?
+	Banker           bool            // Does this NPC run the bank vault?
 	NPCID            string          // Original NPC definition ID (for tracking)
 	mu               sync.RWMutex
+
+	// Combat abilities, guarded by mu
+	Abilities        []Ability            // Spells used in combat, in priority order
+	abilityCooldowns map[string]time.Time // Spell ID -> when the ability can be used again
+	enrageBonus      int                  // Percent extra attack damage while enraged (0 = calm)
+
+	// Status effects (stun, root, poison, debuffs) - the set has its own lock
+	effects effects.Set
 }
 
 // NewNPC creates a new NPC with the given properties
@@ -193,8 +211,8 @@ func (n *NPC) TakeDamage(damage int) int {
 	n.mu.Lock()
 	defer n.mu.Unlock()
 
-	// Apply armor reduction
-	actualDamage := damage - n.Armor
+	// Apply armor reduction, then damage taken debuffs (marked for death)
+	actualDamage := applyPercent(damage-n.Armor, n.effects.Modifier(effects.StatTaken))
 	if actualDamage < 1 {
 		actualDamage = 1 // Minimum 1 damage
 	}
@@ -212,6 +230,7 @@ func (n *NPC) TakeMagicDamage(damage int) int {
 	n.mu.Lock()
 	defer n.mu.Unlock()
 
+	damage = applyPercent(damage, n.effects.Modifier(effects.StatTaken))
 	if damage < 1 {
 		damage = 1 // Minimum 1 damage
 	}
@@ -224,11 +243,30 @@ func (n *NPC) TakeMagicDamage(damage int) int {
 	return damage
 }
 
+// Heal restores health to the NPC, capped at MaxHealth, and returns the amount healed
+func (n *NPC) Heal(amount int) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	oldHealth := n.Health
+	n.Health += amount
+	if n.Health > n.MaxHealth {
+		n.Health = n.MaxHealth
+	}
+	return n.Health - oldHealth
+}
+
 // GetAttackDamage returns the damage this NPC deals in combat
 func (n *NPC) GetAttackDamage() int {
 	n.mu.RLock()
 	defer n.mu.RUnlock()
-	return n.Damage
+	damage := n.Damage + n.Damage*n.enrageBonus/100
+	return applyPercent(damage, n.effects.Modifier(effects.StatDamage))
+}
+
+// GetHitBonus returns the NPC's attack roll bonus from buffs and debuffs
+func (n *NPC) GetHitBonus() int {
+	return n.effects.Modifier(effects.StatHit)
 }
 
 // GetArmorClass returns the NPC's armor class (10 + armor bonus)
@@ -236,7 +274,7 @@ func (n *NPC) GetAttackDamage() int {
 func (n *NPC) GetArmorClass() int {
 	n.mu.RLock()
 	defer n.mu.RUnlock()
-	return 10 + n.Armor
+	return 10 + n.Armor + n.effects.Modifier(effects.StatAC)
 }
 
 // RollLoot performs percentage-based loot rolls and returns items that dropped
@@ -424,58 +462,122 @@ func (n *NPC) Reset() {
 	n.ThreatTable = make(map[string]int)
 	n.DeathTime = time.Time{}
 	n.RespawnTime = time.Time{}
-	n.StunEndTime = time.Time{}
-	n.RootEndTime = time.Time{}
+	n.abilityCooldowns = nil
+	n.enrageBonus = 0
+	n.effects.Clear()
+}
+
+// Effects returns the NPC's status effects
+func (n *NPC) Effects() *effects.Set {
+	return &n.effects
 }
 
 // Stun applies a stun effect to the NPC for the given duration in seconds
 func (n *NPC) Stun(durationSeconds int) {
-	n.mu.Lock()
-	defer n.mu.Unlock()
-	n.StunEndTime = time.Now().Add(time.Duration(durationSeconds) * time.Second)
+	n.effects.Apply(effects.Effect{Name: "Stunned", Kind: effects.KindStun}, durationSeconds)
 }
 
 // IsStunned returns true if the NPC is currently stunned
 func (n *NPC) IsStunned() bool {
-	n.mu.RLock()
-	defer n.mu.RUnlock()
-	return time.Now().Before(n.StunEndTime)
+	return n.effects.Has(effects.KindStun)
 }
 
 // GetStunRemaining returns the seconds remaining on the stun, or 0 if not stunned
 func (n *NPC) GetStunRemaining() int {
-	n.mu.RLock()
-	defer n.mu.RUnlock()
-	remaining := time.Until(n.StunEndTime)
-	if remaining <= 0 {
-		return 0
-	}
-	return int(remaining.Seconds())
+	return n.effects.Remaining(effects.KindStun)
 }
 
 // Root applies a root effect to the NPC for the given duration in seconds
 func (n *NPC) Root(durationSeconds int) {
-	n.mu.Lock()
-	defer n.mu.Unlock()
-	n.RootEndTime = time.Now().Add(time.Duration(durationSeconds) * time.Second)
+	n.effects.Apply(effects.Effect{Name: "Rooted", Kind: effects.KindRoot}, durationSeconds)
 }
 
 // IsRooted returns true if the NPC is currently rooted (cannot flee)
 func (n *NPC) IsRooted() bool {
-	n.mu.RLock()
-	defer n.mu.RUnlock()
-	return time.Now().Before(n.RootEndTime)
+	return n.effects.Has(effects.KindRoot)
 }
 
 // GetRootRemaining returns the seconds remaining on the root, or 0 if not rooted
 func (n *NPC) GetRootRemaining() int {
+	return n.effects.Remaining(effects.KindRoot)
+}
+
+// SetAbilities sets the spells this NPC uses in combat
+func (n *NPC) SetAbilities(abilities []Ability) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.Abilities = abilities
+}
+
+// GetAbilities returns a copy of the NPC's combat abilities
+func (n *NPC) GetAbilities() []Ability {
 	n.mu.RLock()
 	defer n.mu.RUnlock()
-	remaining := time.Until(n.RootEndTime)
-	if remaining <= 0 {
-		return 0
+	abilities := make([]Ability, len(n.Abilities))
+	copy(abilities, n.Abilities)
+	return abilities
+}
+
+// HasAbilities returns true if the NPC has any combat abilities
+func (n *NPC) HasAbilities() bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return len(n.Abilities) > 0
+}
+
+// ChooseAbility picks the ability to use this round: the first one, in priority
+// order, that is off cooldown, whose health trigger has been reached and whose
+// chance roll succeeds. Returns false if the NPC should make a normal attack.
+func (n *NPC) ChooseAbility() (Ability, bool) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	now := time.Now()
+	for _, ability := range n.Abilities {
+		if now.Before(n.abilityCooldowns[ability.SpellID]) {
+			continue
+		}
+		if ability.HPBelow > 0 && float64(n.Health)*100 > ability.HPBelow*float64(n.MaxHealth) {
+			continue
+		}
+		if ability.Chance > 0 && rand.Float64()*100 >= ability.Chance {
+			continue
+		}
+		return ability, true
 	}
-	return int(remaining.Seconds())
+	return Ability{}, false
+}
+
+// StartAbilityCooldown stops the NPC using an ability for the given number of seconds
+func (n *NPC) StartAbilityCooldown(spellID string, seconds int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.abilityCooldowns == nil {
+		n.abilityCooldowns = make(map[string]time.Time)
+	}
+	n.abilityCooldowns[spellID] = time.Now().Add(time.Duration(seconds) * time.Second)
+}
+
+// Enrage makes the NPC deal the given percentage of extra attack damage until it resets
+func (n *NPC) Enrage(percent int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.enrageBonus = percent
+}
+
+// IsEnraged returns true if the NPC has been enraged
+func (n *NPC) IsEnraged() bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.enrageBonus > 0
+}
+
+// applyPercent changes amount by the given percentage (50 = half again, -50 = half)
+func applyPercent(amount, percent int) int {
+	if percent == 0 {
+		return amount
+	}
+	return amount + amount*percent/100
 }
 
 // GetFleeThreshold returns the HP percentage at which this mob will flee
@@ -511,7 +613,7 @@ func (n *NPC) ShouldFlee() bool {
 	}
 
 	// Check if rooted
-	if time.Now().Before(n.RootEndTime) {
+	if n.effects.Has(effects.KindRoot) {
 		return false
 	}
 
@@ -622,6 +724,19 @@ func (n *NPC) SetMobType(mobType MobType) {
 	n.mu.Lock()
 	defer n.mu.Unlock()
 	n.MobType = mobType
+	n.effects.SetImmunities(MobTypeImmunities(mobType)...)
+}
+
+// MobTypeImmunities returns the categories of status effects a mob type shrugs off
+func MobTypeImmunities(mobType MobType) []effects.Category {
+	switch mobType {
+	case MobTypeUndead:
+		return []effects.Category{effects.CategoryPoison} // No blood to poison
+	case MobTypeConstruct:
+		return []effects.Category{effects.CategoryPoison} // Stone and iron don't sicken
+	default:
+		return nil
+	}
 }
 
 // IsBeast returns true if this NPC is of type beast (for ranger's favored enemy)
@@ -879,6 +994,34 @@ func (n *NPC) SetGuideNPC(isGuideNPC bool) {
 	n.GuideNPC = isGuideNPC
 }
 
+// IsAuctioneer returns true if this NPC runs the auction house
+func (n *NPC) IsAuctioneer() bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.Auctioneer
+}
+
+// SetAuctioneer sets whether this NPC runs the auction house
+func (n *NPC) SetAuctioneer(isAuctioneer bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.Auctioneer = isAuctioneer
+}
+
+// IsBanker returns true if this NPC runs the bank vault
+func (n *NPC) IsBanker() bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.Banker
+}
+
+// SetBanker sets whether this NPC runs the bank vault
+func (n *NPC) SetBanker(isBanker bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.Banker = isBanker
+}
+
 // GetNPCID returns the original NPC definition ID
 func (n *NPC) GetNPCID() string {
 	n.mu.RLock()
@@ -361,6 +361,78 @@ func TestLootTableFromDefinition(t *testing.T) {
 	}
 }
 
+// ==================== Ability Tests ====================
+
+func TestAbilitiesFromDefinition(t *testing.T) {
+	def := NPCDefinition{
+		Name:   "test boss",
+		Health: 100,
+		Abilities: []AbilityYAML{
+			{Spell: "regenerate", HPBelow: 40},
+			{Spell: "flame_wave", Cooldown: 10, Chance: 50},
+		},
+	}
+
+	abilities := CreateNPCFromDefinition(def, "test_room").GetAbilities()
+	if len(abilities) != 2 {
+		t.Fatalf("Expected 2 abilities, got %d", len(abilities))
+	}
+	if abilities[0] != (Ability{SpellID: "regenerate", HPBelow: 40}) {
+		t.Errorf("First ability incorrect: %+v", abilities[0])
+	}
+	if abilities[1] != (Ability{SpellID: "flame_wave", Cooldown: 10, Chance: 50}) {
+		t.Errorf("Second ability incorrect: %+v", abilities[1])
+	}
+}
+
+func TestNPCChooseAbility(t *testing.T) {
+	npc := NewNPC("test boss", "A test boss", 5, 100, 10, 0, 50, true, true, "test_room", 0, 0)
+	if _, ok := npc.ChooseAbility(); ok {
+		t.Error("Expected an NPC without abilities to choose none")
+	}
+
+	npc.SetAbilities([]Ability{
+		{SpellID: "regenerate", HPBelow: 40},
+		{SpellID: "flame_wave"},
+	})
+
+	// Healthy: the heal isn't triggered yet, so the first usable ability is next
+	if ability, ok := npc.ChooseAbility(); !ok || ability.SpellID != "flame_wave" {
+		t.Errorf("Expected flame_wave at full health, got %q (%v)", ability.SpellID, ok)
+	}
+
+	// At the threshold the heal takes priority
+	npc.TakeMagicDamage(60)
+	if ability, ok := npc.ChooseAbility(); !ok || ability.SpellID != "regenerate" {
+		t.Errorf("Expected regenerate at 40%% health, got %q (%v)", ability.SpellID, ok)
+	}
+
+	// Abilities on cooldown are skipped
+	npc.StartAbilityCooldown("regenerate", 60)
+	npc.StartAbilityCooldown("flame_wave", 60)
+	if ability, ok := npc.ChooseAbility(); ok {
+		t.Errorf("Expected no ability while both are on cooldown, got %q", ability.SpellID)
+	}
+
+	// Resetting the NPC clears cooldowns
+	npc.Reset()
+	if _, ok := npc.ChooseAbility(); !ok {
+		t.Error("Expected reset to clear ability cooldowns")
+	}
+}
+
+func TestNPCHeal(t *testing.T) {
+	npc := NewNPC("test orc", "A test orc", 3, 40, 8, 0, 30, true, true, "test_room", 0, 0)
+	npc.TakeMagicDamage(10)
+
+	if healed := npc.Heal(4); healed != 4 || npc.GetHealth() != 34 {
+		t.Errorf("Expected to heal 4 to 34 HP, healed %d to %d HP", healed, npc.GetHealth())
+	}
+	if healed := npc.Heal(100); healed != 6 || npc.GetHealth() != 40 {
+		t.Errorf("Expected healing to stop at max health, healed %d to %d HP", healed, npc.GetHealth())
+	}
+}
+
 // ==================== Quest Giver Tests ====================
 
 func TestNPCQuestGiver_Basic(t *testing.T) {
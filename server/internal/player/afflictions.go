@@ -0,0 +1,51 @@
+package player
+
+import "github.com/lawnchairsociety/opentowermud/server/internal/effects"
+
+// Effects returns the player's status effects.
+func (p *Player) Effects() *effects.Set {
+	return &p.effects
+}
+
+// Stun stops the player attacking, casting or fleeing for the given number of seconds.
+func (p *Player) Stun(seconds int) {
+	p.effects.Apply(effects.Effect{Name: "Stunned", Kind: effects.KindStun}, seconds)
+}
+
+// IsStunned returns true while the player is stunned.
+func (p *Player) IsStunned() bool {
+	return p.effects.Has(effects.KindStun)
+}
+
+// GetStunRemaining returns the seconds left on the player's stun, or 0 if not stunned.
+func (p *Player) GetStunRemaining() int {
+	return p.effects.Remaining(effects.KindStun)
+}
+
+// Poison makes the player take damagePerRound each combat round for the given
+// number of seconds. A stronger poison replaces a weaker one; an equal or weaker
+// one only refreshes the duration.
+func (p *Player) Poison(source string, damagePerRound, seconds int) {
+	if damagePerRound < 1 {
+		damagePerRound = 1
+	}
+	p.effects.Apply(effects.Effect{Name: "Poisoned", Kind: effects.KindPoison, Amount: damagePerRound, Source: source}, seconds)
+}
+
+// IsPoisoned returns true while the player is poisoned.
+func (p *Player) IsPoisoned() bool {
+	return p.effects.Has(effects.KindPoison)
+}
+
+// ClearAfflictions removes every status effect, helpful or not (on death).
+func (p *Player) ClearAfflictions() {
+	p.effects.Clear()
+}
+
+// applyPercent changes amount by the given percentage (50 = half again, -50 = half).
+func applyPercent(amount, percent int) int {
+	if percent == 0 {
+		return amount
+	}
+	return amount + amount*percent/100
+}
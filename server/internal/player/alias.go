@@ -0,0 +1,116 @@
+package player
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+)
+
+// MaxAliases is the most aliases a player may define.
+const MaxAliases = 50
+
+// MaxAliasLength is the longest expansion an alias may have.
+const MaxAliasLength = 200
+
+// GetAlias returns the expansion of one of the player's aliases.
+func (p *Player) GetAlias(name string) (string, bool) {
+	p.aliasMu.Lock()
+	defer p.aliasMu.Unlock()
+	expansion, ok := p.aliases[strings.ToLower(name)]
+	return expansion, ok
+}
+
+// SetAlias defines or replaces an alias.
+// Returns an error if the name is taken by speedwalk, the alias is too long or
+// the player has too many.
+func (p *Player) SetAlias(name, expansion string) error {
+	name = strings.ToLower(name)
+	expansion = strings.TrimSpace(expansion)
+	if name == "" || strings.ContainsAny(name, " \t$") {
+		return errors.New("alias names must be a single word without '$'")
+	}
+	// Speedwalks are expanded before aliases are looked up, so the alias would never run
+	if command.ExpandSpeedwalk(name) != nil {
+		return errors.New("alias names can't be speedwalks like 3n2e")
+	}
+	if expansion == "" {
+		return errors.New("alias cannot be empty")
+	}
+	if len(expansion) > MaxAliasLength {
+		return fmt.Errorf("alias cannot be longer than %d characters", MaxAliasLength)
+	}
+	if strings.ContainsAny(expansion, "\r\n") {
+		return errors.New("alias cannot contain line breaks")
+	}
+
+	p.aliasMu.Lock()
+	defer p.aliasMu.Unlock()
+	if _, exists := p.aliases[name]; !exists && len(p.aliases) >= MaxAliases {
+		return fmt.Errorf("you can't have more than %d aliases", MaxAliases)
+	}
+	if p.aliases == nil {
+		p.aliases = make(map[string]string)
+	}
+	p.aliases[name] = expansion
+	return nil
+}
+
+// RemoveAlias deletes an alias. Returns false if there was no such alias.
+func (p *Player) RemoveAlias(name string) bool {
+	p.aliasMu.Lock()
+	defer p.aliasMu.Unlock()
+	name = strings.ToLower(name)
+	if _, ok := p.aliases[name]; !ok {
+		return false
+	}
+	delete(p.aliases, name)
+	return true
+}
+
+// GetAliases returns a copy of the player's aliases (name -> expansion).
+func (p *Player) GetAliases() map[string]string {
+	p.aliasMu.Lock()
+	defer p.aliasMu.Unlock()
+	aliases := make(map[string]string, len(p.aliases))
+	for name, expansion := range p.aliases {
+		aliases[name] = expansion
+	}
+	return aliases
+}
+
+// GetAliasesJSON returns the aliases for database storage. No aliases encode as "".
+func (p *Player) GetAliasesJSON() string {
+	p.aliasMu.Lock()
+	defer p.aliasMu.Unlock()
+	if len(p.aliases) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(p.aliases)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// SetAliasesFromJSON restores aliases from database storage.
+func (p *Player) SetAliasesFromJSON(data string) error {
+	var aliases map[string]string
+	if data != "" {
+		if err := json.Unmarshal([]byte(data), &aliases); err != nil {
+			return fmt.Errorf("failed to parse aliases: %w", err)
+		}
+	}
+	p.aliasMu.Lock()
+	defer p.aliasMu.Unlock()
+	p.aliases = aliases
+	return nil
+}
+
+// SplitCommands splits a line into commands using the player's command
+// separator and speedwalk settings, as if they had typed it.
+func (p *Player) SplitCommands(line string) []string {
+	return command.SplitInput(line, p.inputConfig.CommandSeparator, p.inputConfig.Speedwalk)
+}
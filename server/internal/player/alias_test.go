@@ -0,0 +1,95 @@
+package player
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+)
+
+// TestAlias_ExpandsIntoQueue tests that the first command of an alias runs at
+// once and the rest go to the front of the input queue
+func TestAlias_ExpandsIntoQueue(t *testing.T) {
+	p := createQueueTestPlayer()
+	p.inputQueue = []string{"look"}
+	if err := p.SetAlias("tour", "alias;2n;e $1"); err != nil {
+		t.Fatalf("SetAlias failed: %v", err)
+	}
+
+	result := command.ParseCommand("tour quickly").Execute(p, p.world)
+	if !strings.Contains(result, "tour") {
+		t.Errorf("Expected the first command to list aliases, got %q", result)
+	}
+	expected := []string{"north", "north", "e quickly", "look"}
+	if !reflect.DeepEqual(p.inputQueue, expected) {
+		t.Errorf("Expected queue %v, got %v", expected, p.inputQueue)
+	}
+}
+
+// TestAlias_Loop tests that aliases that expand into each other are refused
+func TestAlias_Loop(t *testing.T) {
+	p := createQueueTestPlayer()
+	_ = p.SetAlias("ping", "pong")
+	_ = p.SetAlias("pong", "ping")
+
+	result := command.ParseCommand("ping").Execute(p, p.world)
+	if !strings.Contains(result, "expands into itself") {
+		t.Errorf("Expected an alias loop to be refused, got %q", result)
+	}
+	if len(p.inputQueue) != 0 {
+		t.Errorf("Expected nothing to be queued, got %v", p.inputQueue)
+	}
+}
+
+// TestAlias_SpeedwalkNames tests that speedwalks can't be shadowed by aliases,
+// and that aliases made of direction letters aren't mistaken for speedwalks
+func TestAlias_SpeedwalkNames(t *testing.T) {
+	p := createQueueTestPlayer()
+
+	if err := p.SetAlias("2n", "look"); err == nil {
+		t.Error("Expected a speedwalk alias name to be refused")
+	}
+	for _, name := range []string{"sun", "ne", "dd"} {
+		if err := p.SetAlias(name, "say "+name); err != nil {
+			t.Fatalf("SetAlias(%q) failed: %v", name, err)
+		}
+	}
+
+	p.queueInput("sun;ne;dd")
+	expected := []string{"sun", "ne", "dd"}
+	if !reflect.DeepEqual(p.inputQueue, expected) {
+		t.Errorf("Expected the aliases to be queued as typed, got %v", p.inputQueue)
+	}
+}
+
+// TestSetAlias_Limits tests the alias cap, validation and storage round trip
+func TestSetAlias_Limits(t *testing.T) {
+	p := createTestPlayer()
+
+	if err := p.SetAlias("two words", "look"); err == nil {
+		t.Error("Expected a multi-word alias name to be refused")
+	}
+	if err := p.SetAlias("long", strings.Repeat("x", MaxAliasLength+1)); err == nil {
+		t.Error("Expected an overlong alias to be refused")
+	}
+	for i := 0; i < MaxAliases; i++ {
+		if err := p.SetAlias("a"+strings.Repeat("x", i), "look"); err != nil {
+			t.Fatalf("SetAlias %d failed: %v", i, err)
+		}
+	}
+	if err := p.SetAlias("onemore", "look"); err == nil {
+		t.Error("Expected aliases beyond the cap to be refused")
+	}
+	if err := p.SetAlias("a", "inventory"); err != nil {
+		t.Errorf("Expected replacing an alias at the cap to work, got %v", err)
+	}
+
+	restored := createTestPlayer()
+	if err := restored.SetAliasesFromJSON(p.GetAliasesJSON()); err != nil {
+		t.Fatalf("SetAliasesFromJSON failed: %v", err)
+	}
+	if expansion, ok := restored.GetAlias("A"); !ok || expansion != "inventory" {
+		t.Errorf("Expected alias 'a' to survive storage, got %q", expansion)
+	}
+}
@@ -0,0 +1,73 @@
+package player
+
+import "github.com/lawnchairsociety/opentowermud/server/internal/channels"
+
+// CanUseChannel returns true if the player's race and level allow them on the channel.
+func (p *Player) CanUseChannel(ch *channels.Channel) bool {
+	return ch.Allows(string(p.GetRace()), p.GetLevel())
+}
+
+// IsOnChannel returns true if the player is allowed on and has joined the channel.
+func (p *Player) IsOnChannel(ch *channels.Channel) bool {
+	if !p.CanUseChannel(ch) {
+		return false
+	}
+	p.channelMu.Lock()
+	defer p.channelMu.Unlock()
+	return p.channelPrefs.IsMember(ch)
+}
+
+// IsListeningToChannel returns true if the player is on the channel and hasn't muted it.
+func (p *Player) IsListeningToChannel(ch *channels.Channel) bool {
+	if !p.IsOnChannel(ch) {
+		return false
+	}
+	return !p.IsChannelMuted(ch)
+}
+
+// IsChannelMuted returns true if the player has muted the channel.
+func (p *Player) IsChannelMuted(ch *channels.Channel) bool {
+	p.channelMu.Lock()
+	defer p.channelMu.Unlock()
+	return p.channelPrefs.IsMuted(ch)
+}
+
+// JoinChannel puts the player on the channel.
+func (p *Player) JoinChannel(ch *channels.Channel) {
+	p.channelMu.Lock()
+	defer p.channelMu.Unlock()
+	p.channelPrefs.Join(ch)
+}
+
+// LeaveChannel takes the player off the channel.
+func (p *Player) LeaveChannel(ch *channels.Channel) {
+	p.channelMu.Lock()
+	defer p.channelMu.Unlock()
+	p.channelPrefs.Leave(ch)
+}
+
+// SetChannelMuted mutes or unmutes the channel for the player.
+func (p *Player) SetChannelMuted(ch *channels.Channel, muted bool) {
+	p.channelMu.Lock()
+	defer p.channelMu.Unlock()
+	p.channelPrefs.SetMuted(ch, muted)
+}
+
+// GetChannelPreferencesJSON returns the channel preferences for database storage.
+func (p *Player) GetChannelPreferencesJSON() string {
+	p.channelMu.Lock()
+	defer p.channelMu.Unlock()
+	return p.channelPrefs.ToJSON()
+}
+
+// SetChannelPreferencesFromJSON restores channel preferences from database storage.
+func (p *Player) SetChannelPreferencesFromJSON(data string) error {
+	prefs, err := channels.ParsePreferences(data)
+	if err != nil {
+		return err
+	}
+	p.channelMu.Lock()
+	defer p.channelMu.Unlock()
+	p.channelPrefs = prefs
+	return nil
+}
@@ -0,0 +1,64 @@
+package player
+
+import (
+	"github.com/lawnchairsociety/opentowermud/server/internal/color"
+)
+
+// ColorClient is implemented by clients that can display colored output.
+// Clients that don't implement it receive plain text.
+type ColorClient interface {
+	ColorMode() color.Mode
+}
+
+// renderColor converts color markup in a message for the player's client and preferences.
+func (p *Player) renderColor(message string) string {
+	mode := color.ModeNone
+	if cc, ok := p.getClient().(ColorClient); ok && !p.colorPrefs.Disabled {
+		mode = cc.ColorMode()
+	}
+	return color.Render(message, mode, p.colorPrefs.Theme)
+}
+
+// IsColorEnabled returns true if the player wants colored output.
+func (p *Player) IsColorEnabled() bool {
+	return !p.colorPrefs.Disabled
+}
+
+// SetColorEnabled turns colored output on or off.
+func (p *Player) SetColorEnabled(enabled bool) {
+	p.colorPrefs.Disabled = !enabled
+}
+
+// RemapColor changes the color used for a markup category (e.g., "npc" -> "red").
+func (p *Player) RemapColor(category, colorName string) error {
+	return p.colorPrefs.Remap(category, colorName)
+}
+
+// ResetColor restores a category's default color, or all categories if category is "".
+func (p *Player) ResetColor(category string) error {
+	return p.colorPrefs.Reset(category)
+}
+
+// GetColorTheme returns the player's remapped categories (category -> color name).
+func (p *Player) GetColorTheme() map[string]string {
+	theme := make(map[string]string, len(p.colorPrefs.Theme))
+	for category, name := range p.colorPrefs.Theme {
+		theme[category] = name
+	}
+	return theme
+}
+
+// GetColorPreferencesJSON returns the color preferences for database storage.
+func (p *Player) GetColorPreferencesJSON() string {
+	return p.colorPrefs.ToJSON()
+}
+
+// SetColorPreferencesFromJSON restores color preferences from database storage.
+func (p *Player) SetColorPreferencesFromJSON(data string) error {
+	prefs, err := color.ParsePreferences(data)
+	if err != nil {
+		return err
+	}
+	p.colorPrefs = prefs
+	return nil
+}
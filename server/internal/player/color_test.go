@@ -0,0 +1,66 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/color"
+)
+
+// colorClient is a fakeClient that reports a color mode.
+type colorClient struct {
+	fakeClient
+	mode color.Mode
+}
+
+func (c *colorClient) ColorMode() color.Mode { return c.mode }
+
+// TestSendMessage_RendersColor tests that markup is rendered per client and player preference
+func TestSendMessage_RendersColor(t *testing.T) {
+	client := &colorClient{mode: color.ModeANSI}
+	p := createTestPlayer()
+	p.client = client
+
+	p.SendMessage("{npc}goblin{/}")
+	if client.lines[0] != "\x1b[33mgoblin\x1b[0m" {
+		t.Errorf("Expected ANSI output, got %q", client.lines[0])
+	}
+
+	p.RemapColor("npc", "blue")
+	p.SendMessage("{npc}goblin{/}")
+	if client.lines[1] != "\x1b[34mgoblin\x1b[0m" {
+		t.Errorf("Expected remapped color, got %q", client.lines[1])
+	}
+
+	p.SetColorEnabled(false)
+	p.SendMessage("{npc}goblin{/}")
+	if client.lines[2] != "goblin" {
+		t.Errorf("Expected plain text with color off, got %q", client.lines[2])
+	}
+
+	// Clients without color support always get plain text
+	plain := &fakeClient{}
+	p.client = plain
+	p.SetColorEnabled(true)
+	p.SendMessage("{npc}goblin{/}")
+	if plain.lines[0] != "goblin" {
+		t.Errorf("Expected markup stripped for a plain client, got %q", plain.lines[0])
+	}
+}
+
+// TestColorPreferences_Persistence tests that color preferences survive a save/load round trip
+func TestColorPreferences_Persistence(t *testing.T) {
+	p := createTestPlayer()
+	p.RemapColor("damage", "208")
+	p.SetColorEnabled(false)
+
+	loaded := createTestPlayer()
+	if err := loaded.SetColorPreferencesFromJSON(p.GetColorPreferencesJSON()); err != nil {
+		t.Fatalf("SetColorPreferencesFromJSON failed: %v", err)
+	}
+	if loaded.IsColorEnabled() {
+		t.Error("Expected color to stay disabled after reload")
+	}
+	if loaded.GetColorTheme()["damage"] != "208" {
+		t.Errorf("Expected damage remap to survive reload, got %v", loaded.GetColorTheme())
+	}
+}
@@ -0,0 +1,166 @@
+package player
+
+import (
+	"encoding/json"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/items"
+	"github.com/lawnchairsociety/opentowermud/server/internal/leveling"
+)
+
+// ==================== XP DEBT ====================
+
+// GetXPDebt returns how much experience the player owes from dying
+func (p *Player) GetXPDebt() int {
+	return p.xpDebt
+}
+
+// SetXPDebt sets the player's XP debt (used for persistence)
+func (p *Player) SetXPDebt(debt int) {
+	if debt < 0 {
+		debt = 0
+	}
+	p.xpDebt = debt
+}
+
+// IncurXPDebt adds a percentage of the experience needed for the player's next
+// level to their debt, and returns how much was added. The total debt never
+// grows past one level's worth, so dying again and again doesn't bury a player.
+func (p *Player) IncurXPDebt(percent int) int {
+	if percent <= 0 {
+		return 0
+	}
+	levelXP := leveling.XPForLevel(p.Level+1) - leveling.XPForLevel(p.Level)
+	if p.Level >= leveling.MaxPlayerLevel {
+		levelXP = leveling.XPForLevel(p.Level) - leveling.XPForLevel(p.Level-1)
+	}
+
+	debt := levelXP * percent / 100
+	if p.xpDebt+debt > levelXP {
+		debt = levelXP - p.xpDebt
+	}
+	if debt <= 0 {
+		return 0
+	}
+	p.xpDebt += debt
+	return debt
+}
+
+// payXPDebt puts half of an experience gain (rounded up) toward the player's
+// debt and returns what's left for them to keep
+func (p *Player) payXPDebt(xp int) int {
+	if p.xpDebt <= 0 || xp <= 0 {
+		return xp
+	}
+	payment := (xp + 1) / 2
+	if payment > p.xpDebt {
+		payment = p.xpDebt
+	}
+	p.xpDebt -= payment
+	return xp - payment
+}
+
+// ==================== DURABILITY ====================
+
+// WearEquipment wears down every equipped item by a percentage and returns
+// the items this broke
+func (p *Player) WearEquipment(percent int) []*items.Item {
+	var broken []*items.Item
+	for _, item := range p.Equipment {
+		if item != nil && item.AddWear(percent) {
+			broken = append(broken, item)
+		}
+	}
+	return broken
+}
+
+// GetWornItems returns the player's equipped and carried items that need repair
+func (p *Player) GetWornItems() []*items.Item {
+	var worn []*items.Item
+	for slot := items.SlotHead; slot <= items.SlotHeld; slot++ {
+		if item := p.Equipment[slot]; item != nil && item.Wear > 0 {
+			worn = append(worn, item)
+		}
+	}
+	for _, item := range p.Inventory {
+		if item.Wear > 0 {
+			worn = append(worn, item)
+		}
+	}
+	return worn
+}
+
+// itemWear is the saved wear of a player's damaged items. Inventory wear is
+// listed per item ID in inventory order, so copies of the same item keep
+// their own wear.
+type itemWear struct {
+	Equipment map[string]int   `json:"equipment,omitempty"` // slot -> wear
+	Inventory map[string][]int `json:"inventory,omitempty"` // item ID -> wear of each copy
+}
+
+// GetItemWearJSON returns the wear of the player's items as JSON (for
+// persistence), or "" if nothing is worn
+func (p *Player) GetItemWearJSON() string {
+	wear := itemWear{Equipment: make(map[string]int), Inventory: make(map[string][]int)}
+	for slot, item := range p.Equipment {
+		if item != nil && item.Wear > 0 {
+			wear.Equipment[slot.String()] = item.Wear
+		}
+	}
+
+	worn := make(map[string]bool)
+	for _, item := range p.Inventory {
+		wear.Inventory[item.ID] = append(wear.Inventory[item.ID], item.Wear)
+		if item.Wear > 0 {
+			worn[item.ID] = true
+		}
+	}
+	for id := range wear.Inventory {
+		if !worn[id] {
+			delete(wear.Inventory, id)
+		}
+	}
+
+	if len(wear.Equipment) == 0 && len(wear.Inventory) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(wear)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// SetItemWearFromJSON restores the wear of the player's items (used for
+// persistence). Call it after the inventory and equipment are loaded.
+func (p *Player) SetItemWearFromJSON(data string) {
+	if data == "" {
+		return
+	}
+	var wear itemWear
+	if err := json.Unmarshal([]byte(data), &wear); err != nil {
+		return
+	}
+
+	for slotName, w := range wear.Equipment {
+		if item := p.Equipment[items.StringToEquipmentSlot(slotName)]; item != nil {
+			item.Wear = w
+		}
+	}
+	for _, item := range p.Inventory {
+		if copies := wear.Inventory[item.ID]; len(copies) > 0 {
+			item.Wear = copies[0]
+			wear.Inventory[item.ID] = copies[1:]
+		}
+	}
+}
+
+// ==================== CORPSES ====================
+
+// TakeInventory empties the player's inventory and returns everything that was
+// in it (for leaving in a corpse). Equipment, the key ring and quest items are
+// kept apart from the inventory, so they stay with the player.
+func (p *Player) TakeInventory() []*items.Item {
+	taken := p.Inventory
+	p.Inventory = make([]*items.Item, 0)
+	return taken
+}
@@ -0,0 +1,15 @@
+package player
+
+// IsLocationHidden returns true if the player hides their tower and floor from friends lists.
+func (p *Player) IsLocationHidden() bool {
+	p.friendMu.Lock()
+	defer p.friendMu.Unlock()
+	return p.hideLocation
+}
+
+// SetLocationHidden sets whether the player hides their tower and floor from friends lists.
+func (p *Player) SetLocationHidden(hidden bool) {
+	p.friendMu.Lock()
+	defer p.friendMu.Unlock()
+	p.hideLocation = hidden
+}
@@ -0,0 +1,205 @@
+package player
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+)
+
+// GMCP package names pushed by the server.
+const (
+	GMCPCharVitals      = "Char.Vitals"
+	GMCPCharStatus      = "Char.Status"
+	GMCPRoomInfo        = "Room.Info"
+	GMCPCharItemsInv    = "Char.Items.Inv"
+	GMCPCommChannelText = "Comm.Channel.Text"
+)
+
+// GMCPClient is implemented by clients that can receive out-of-band GMCP data.
+// Telnet clients send packages as subnegotiation, WebSocket clients as JSON frames.
+type GMCPClient interface {
+	GMCPEnabled() bool
+	SendGMCP(pkg string, data interface{}) error
+}
+
+// GMCPVitals is the payload for Char.Vitals.
+type GMCPVitals struct {
+	HP    int `json:"hp"`
+	MaxHP int `json:"maxhp"`
+	MP    int `json:"mp"`
+	MaxMP int `json:"maxmp"`
+}
+
+// GMCPStatus is the payload for Char.Status.
+type GMCPStatus struct {
+	Name      string `json:"name"`
+	Level     int    `json:"level"`
+	Class     string `json:"class"`
+	Race      string `json:"race"`
+	XP        int    `json:"xp"`
+	Gold      int    `json:"gold"`
+	State     string `json:"state"`
+	Title     string `json:"title,omitempty"`
+	InCombat  bool   `json:"in_combat"`
+	Target    string `json:"target,omitempty"`
+	HomeTower string `json:"home_tower"`
+}
+
+// GMCPRoom is the payload for Room.Info.
+type GMCPRoom struct {
+	ID    string            `json:"id"`
+	Name  string            `json:"name"`
+	Floor int               `json:"floor"`
+	Tower string            `json:"tower,omitempty"`
+	Exits map[string]string `json:"exits"` // direction -> destination room ID
+}
+
+// GMCPItem is a single inventory entry in Char.Items.Inv.
+type GMCPItem struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// GMCPChannelText is the payload for Comm.Channel.Text.
+type GMCPChannelText struct {
+	Channel string `json:"channel"`
+	Talker  string `json:"talker"`
+	Text    string `json:"text"`
+}
+
+// gmcpTowerRoomPattern matches tower room IDs like "elf_f5_r10_7" and captures the tower ID.
+var gmcpTowerRoomPattern = regexp.MustCompile(`^([a-z]+)_f\d+_`)
+
+// gmcpClient returns the player's client as a GMCPClient if GMCP is active.
+func (p *Player) gmcpClient() GMCPClient {
+	client := p.getClient()
+	if p.disconnected || client == nil || p.IsLinkDead() {
+		return nil
+	}
+	gc, ok := client.(GMCPClient)
+	if !ok || !gc.GMCPEnabled() {
+		return nil
+	}
+	return gc
+}
+
+// SendGMCP sends a GMCP package to the player if their client supports it.
+func (p *Player) SendGMCP(pkg string, data interface{}) {
+	if gc := p.gmcpClient(); gc != nil {
+		gc.SendGMCP(pkg, data)
+	}
+}
+
+// SendChannelGMCP sends a Comm.Channel.Text package for a chat message the player received.
+func (p *Player) SendChannelGMCP(channel, talker, text string) {
+	p.SendGMCP(GMCPCommChannelText, GMCPChannelText{
+		Channel: channel,
+		Talker:  talker,
+		Text:    text,
+	})
+}
+
+// UpdateGMCP pushes any GMCP packages whose contents changed since they were last sent.
+// Called after each command and on the regen/combat ticks, so clients see state
+// changes without polling.
+func (p *Player) UpdateGMCP() {
+	gc := p.gmcpClient()
+	if gc == nil {
+		return
+	}
+
+	packages := []struct {
+		name string
+		data interface{}
+	}{
+		{GMCPCharVitals, p.buildGMCPVitals()},
+		{GMCPCharStatus, p.buildGMCPStatus()},
+		{GMCPRoomInfo, p.buildGMCPRoom()},
+		{GMCPCharItemsInv, p.buildGMCPInventory()},
+	}
+
+	p.gmcpMu.Lock()
+	defer p.gmcpMu.Unlock()
+
+	if p.gmcpSent == nil {
+		p.gmcpSent = make(map[string]string)
+	}
+
+	for _, pkg := range packages {
+		encoded, err := json.Marshal(pkg.data)
+		if err != nil {
+			continue
+		}
+		if p.gmcpSent[pkg.name] == string(encoded) {
+			continue
+		}
+		if err := gc.SendGMCP(pkg.name, pkg.data); err == nil {
+			p.gmcpSent[pkg.name] = string(encoded)
+		}
+	}
+}
+
+// buildGMCPVitals builds the Char.Vitals payload.
+func (p *Player) buildGMCPVitals() GMCPVitals {
+	return GMCPVitals{
+		HP:    p.Health,
+		MaxHP: p.MaxHealth,
+		MP:    p.Mana,
+		MaxMP: p.MaxMana,
+	}
+}
+
+// buildGMCPStatus builds the Char.Status payload.
+func (p *Player) buildGMCPStatus() GMCPStatus {
+	return GMCPStatus{
+		Name:      p.Name,
+		Level:     p.Level,
+		Class:     p.GetActiveClassName(),
+		Race:      p.GetRaceName(),
+		XP:        p.Experience,
+		Gold:      p.Gold,
+		State:     p.State.String(),
+		Title:     p.activeTitle,
+		InCombat:  p.InCombat,
+		Target:    p.CombatTarget,
+		HomeTower: p.GetHomeTowerString(),
+	}
+}
+
+// buildGMCPRoom builds the Room.Info payload.
+func (p *Player) buildGMCPRoom() GMCPRoom {
+	info := GMCPRoom{Exits: make(map[string]string)}
+	room := p.CurrentRoom
+	if room == nil {
+		return info
+	}
+
+	info.ID = room.GetID()
+	info.Name = room.Name
+	info.Floor = room.GetFloor()
+	if matches := gmcpTowerRoomPattern.FindStringSubmatch(info.ID); len(matches) >= 2 {
+		info.Tower = matches[1]
+	} else if p.world != nil {
+		_, info.Tower = p.world.FindRoomWithTowerID(info.ID)
+	}
+
+	info.Exits = room.GetExitIDs()
+	return info
+}
+
+// buildGMCPInventory builds the Char.Items.Inv payload, sorted by name for stable diffs.
+func (p *Player) buildGMCPInventory() []GMCPItem {
+	inv := make([]GMCPItem, 0, len(p.Inventory))
+	for _, item := range p.Inventory {
+		inv = append(inv, GMCPItem{
+			ID:   item.ID,
+			Name: item.Name,
+			Type: item.Type.String(),
+		})
+	}
+	sort.SliceStable(inv, func(i, j int) bool {
+		return inv[i].Name < inv[j].Name
+	})
+	return inv
+}
@@ -0,0 +1,84 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/items"
+	"github.com/lawnchairsociety/opentowermud/server/internal/world"
+)
+
+// fakeClient is a Client that records output and optionally speaks GMCP.
+type fakeClient struct {
+	lines []string
+	gmcp  bool
+	sent  []string // GMCP package names in send order
+	data  map[string]interface{}
+}
+
+func (c *fakeClient) ReadLine() (string, error)      { return "", nil }
+func (c *fakeClient) WriteLine(message string) error { c.lines = append(c.lines, message); return nil }
+func (c *fakeClient) Close() error                   { return nil }
+func (c *fakeClient) RemoteAddr() string             { return "127.0.0.1:1234" }
+func (c *fakeClient) GMCPEnabled() bool              { return c.gmcp }
+func (c *fakeClient) SendGMCP(pkg string, data interface{}) error {
+	c.sent = append(c.sent, pkg)
+	if c.data == nil {
+		c.data = make(map[string]interface{})
+	}
+	c.data[pkg] = data
+	return nil
+}
+
+func TestUpdateGMCP_SendsOnlyChanges(t *testing.T) {
+	client := &fakeClient{gmcp: true}
+	p := createTestPlayer()
+	p.client = client
+	p.CurrentRoom = world.NewRoom("elf_f3_r1_1", "Dusty Hall", "A hall.", world.RoomTypeRoom)
+
+	p.UpdateGMCP()
+	if len(client.sent) != 4 {
+		t.Fatalf("Expected 4 packages on first update, got %v", client.sent)
+	}
+
+	room, ok := client.data[GMCPRoomInfo].(GMCPRoom)
+	if !ok {
+		t.Fatalf("Expected GMCPRoom payload, got %T", client.data[GMCPRoomInfo])
+	}
+	if room.Tower != "elf" || room.ID != "elf_f3_r1_1" {
+		t.Errorf("Expected tower elf and room ID elf_f3_r1_1, got %q / %q", room.Tower, room.ID)
+	}
+
+	// Nothing changed - nothing should be sent
+	client.sent = nil
+	p.UpdateGMCP()
+	if len(client.sent) != 0 {
+		t.Errorf("Expected no packages when nothing changed, got %v", client.sent)
+	}
+
+	// Only vitals changed
+	p.Health = 5
+	p.UpdateGMCP()
+	if len(client.sent) != 1 || client.sent[0] != GMCPCharVitals {
+		t.Errorf("Expected only Char.Vitals after damage, got %v", client.sent)
+	}
+
+	// Inventory changed
+	client.sent = nil
+	p.AddItem(&items.Item{ID: "bandage", Name: "bandage", Type: items.Misc})
+	p.UpdateGMCP()
+	if len(client.sent) != 1 || client.sent[0] != GMCPCharItemsInv {
+		t.Errorf("Expected only Char.Items.Inv after pickup, got %v", client.sent)
+	}
+}
+
+func TestUpdateGMCP_DisabledClient(t *testing.T) {
+	client := &fakeClient{gmcp: false}
+	p := createTestPlayer()
+	p.client = client
+
+	p.UpdateGMCP()
+	p.SendChannelGMCP("say", "Bob", "hello")
+	if len(client.sent) != 0 {
+		t.Errorf("Expected no GMCP for a client without GMCP, got %v", client.sent)
+	}
+}
@@ -0,0 +1,61 @@
+package player
+
+import "github.com/lawnchairsociety/opentowermud/server/internal/guild"
+
+// SetGuild records the player's guild membership.
+func (p *Player) SetGuild(guildID int64, name, tag string, rank guild.Rank) {
+	p.guildMu.Lock()
+	defer p.guildMu.Unlock()
+	p.guildID = guildID
+	p.guildName = name
+	p.guildTag = tag
+	p.guildRank = rank
+}
+
+// ClearGuild removes the player's guild membership.
+func (p *Player) ClearGuild() {
+	p.SetGuild(0, "", "", "")
+}
+
+// GetGuildID returns the ID of the player's guild (0 if not in one).
+func (p *Player) GetGuildID() int64 {
+	p.guildMu.Lock()
+	defer p.guildMu.Unlock()
+	return p.guildID
+}
+
+// GetGuildName returns the name of the player's guild.
+func (p *Player) GetGuildName() string {
+	p.guildMu.Lock()
+	defer p.guildMu.Unlock()
+	return p.guildName
+}
+
+// GetGuildTag returns the player's guild tag ("" if not in a guild).
+func (p *Player) GetGuildTag() string {
+	p.guildMu.Lock()
+	defer p.guildMu.Unlock()
+	return p.guildTag
+}
+
+// GetGuildRank returns the player's rank in their guild.
+func (p *Player) GetGuildRank() guild.Rank {
+	p.guildMu.Lock()
+	defer p.guildMu.Unlock()
+	return p.guildRank
+}
+
+// SetGuildInvite records a pending guild invite, replacing any earlier one.
+func (p *Player) SetGuildInvite(guildID int64, inviter string) {
+	p.guildMu.Lock()
+	defer p.guildMu.Unlock()
+	p.guildInvite = guildID
+	p.guildInviter = inviter
+}
+
+// GetGuildInvite returns the pending guild invite and who sent it (0 if none).
+func (p *Player) GetGuildInvite() (int64, string) {
+	p.guildMu.Lock()
+	defer p.guildMu.Unlock()
+	return p.guildInvite, p.guildInviter
+}
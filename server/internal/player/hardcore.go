@@ -0,0 +1,37 @@
+package player
+
+import "time"
+
+// ==================== HARDCORE MODE ====================
+
+// IsHardcore returns true if the player chose hardcore mode: one life, and no
+// trading or mail with normal characters
+func (p *Player) IsHardcore() bool {
+	return p.hardcore
+}
+
+// SetHardcore sets whether the player is hardcore (used for persistence)
+func (p *Player) SetHardcore(hardcore bool) {
+	p.hardcore = hardcore
+}
+
+// IsRetired returns true if the player died in hardcore mode. A retired
+// character is never saved again: their memorial is the last word.
+func (p *Player) IsRetired() bool {
+	return p.retired
+}
+
+// Retire marks a hardcore player as dead for good
+func (p *Player) Retire() {
+	p.retired = true
+}
+
+// GetPlayTimeSeconds returns the player's total time played, including the
+// current session
+func (p *Player) GetPlayTimeSeconds() int64 {
+	total := p.GetStatistics().GetTotalPlayTimeSeconds()
+	if !p.loginTime.IsZero() {
+		total += int64(time.Since(p.loginTime).Seconds())
+	}
+	return total
+}
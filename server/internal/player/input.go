@@ -0,0 +1,116 @@
+package player
+
+import (
+	"time"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+)
+
+// heldRecheckInterval is how often held movement checks whether the fight is over.
+const heldRecheckInterval = 500 * time.Millisecond
+
+// readLines reads from the client on its own goroutine, so the command loop can
+// run queued commands while waiting for input. The channel is closed when the
+// connection drops.
+func readLines(client Client, done <-chan struct{}) <-chan string {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		for {
+			line, err := client.ReadLine()
+			if err != nil {
+				return
+			}
+			select {
+			case lines <- line:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return lines
+}
+
+// queueDelay returns the pause between queued commands.
+func (p *Player) queueDelay() time.Duration {
+	return time.Duration(p.inputConfig.QueueDelayMs) * time.Millisecond
+}
+
+// queueInput splits a typed line into commands and adds them to the input queue.
+// "clear" runs immediately rather than waiting its turn. Commands typed while a
+// fight is holding queued movement go ahead of the held movement.
+func (p *Player) queueInput(input string) {
+	commands := command.SplitInput(input, p.inputConfig.CommandSeparator, p.inputConfig.Speedwalk)
+
+	var added []string
+	for _, cmd := range commands {
+		if command.IsClearCommand(cmd) {
+			added = nil
+			p.executeInput(cmd)
+			continue
+		}
+		added = append(added, cmd)
+	}
+
+	if p.movementHeld() {
+		p.inputQueue = append(added, p.inputQueue...)
+	} else {
+		p.inputQueue = append(p.inputQueue, added...)
+	}
+
+	if limit := p.inputConfig.MaxQueued; limit > 0 && len(p.inputQueue) > limit {
+		p.inputQueue = p.inputQueue[:limit]
+		p.SendMessage("{warning}Too many commands queued - the rest were dropped.{/}\n")
+	}
+}
+
+// QueueCommands puts commands at the front of the input queue, so they run
+// next, one per queue delay. Used for the rest of a multi-command alias.
+func (p *Player) QueueCommands(commands []string) {
+	if len(commands) == 0 {
+		return
+	}
+	p.inputQueue = append(append([]string(nil), commands...), p.inputQueue...)
+
+	if limit := p.inputConfig.MaxQueued; limit > 0 && len(p.inputQueue) > limit {
+		p.inputQueue = p.inputQueue[:limit]
+		p.SendMessage("{warning}Too many commands queued - the rest were dropped.{/}\n")
+	}
+}
+
+// movementHeld returns true if the next queued command is movement and the
+// player is fighting. Held movement resumes when the fight ends.
+func (p *Player) movementHeld() bool {
+	return p.InCombat && len(p.inputQueue) > 0 && command.IsMovementCommand(p.inputQueue[0])
+}
+
+// runQueuedInput runs the next queued command once the queue delay has passed.
+func (p *Player) runQueuedInput() {
+	if len(p.inputQueue) == 0 || time.Now().Before(p.nextQueuedAt) {
+		return
+	}
+
+	if p.movementHeld() {
+		if !p.queueHeld {
+			p.queueHeld = true
+			p.SendMessage("{warning}You stop in your tracks to fight. Your queued movement will continue afterwards ('clear' to cancel).{/}\n")
+		}
+		p.nextQueuedAt = time.Now().Add(heldRecheckInterval)
+		return
+	}
+	p.queueHeld = false
+
+	cmd := p.inputQueue[0]
+	p.inputQueue = p.inputQueue[1:]
+	p.executeInput(cmd)
+	p.nextQueuedAt = time.Now().Add(p.queueDelay())
+}
+
+// ClearInputQueue drops any commands waiting in the input queue.
+// Returns the number of commands dropped.
+func (p *Player) ClearInputQueue() int {
+	dropped := len(p.inputQueue)
+	p.inputQueue = nil
+	p.queueHeld = false
+	return dropped
+}
@@ -0,0 +1,84 @@
+package player
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/config"
+	"github.com/lawnchairsociety/opentowermud/server/internal/world"
+)
+
+// createQueueTestPlayer creates a player with the default input settings
+func createQueueTestPlayer() *Player {
+	p := createTestPlayer()
+	p.inputConfig = config.DefaultConfig().Input
+	p.CurrentRoom = world.NewRoom("hall", "Dusty Hall", "A hall.", world.RoomTypeRoom)
+	return p
+}
+
+// TestQueueInput_StackingAndSpeedwalk tests that a line is split and expanded into the queue
+func TestQueueInput_StackingAndSpeedwalk(t *testing.T) {
+	p := createQueueTestPlayer()
+
+	p.queueInput("2n;open door")
+	p.queueInput("e")
+
+	expected := []string{"north", "north", "open door", "e"}
+	if !reflect.DeepEqual(p.inputQueue, expected) {
+		t.Errorf("Expected queue %v, got %v", expected, p.inputQueue)
+	}
+}
+
+// TestQueueInput_Clear tests that clear flushes the queue immediately
+func TestQueueInput_Clear(t *testing.T) {
+	p := createQueueTestPlayer()
+
+	p.queueInput("4n")
+	p.queueInput("clear")
+	if len(p.inputQueue) != 0 {
+		t.Fatalf("Expected clear to flush the queue, got %v", p.inputQueue)
+	}
+
+	// Commands before clear on the same line are dropped, commands after it are kept
+	p.queueInput("n;clear;look")
+	if !reflect.DeepEqual(p.inputQueue, []string{"look"}) {
+		t.Errorf("Expected queue [look], got %v", p.inputQueue)
+	}
+}
+
+// TestQueueInput_MaxQueued tests that commands beyond the limit are dropped
+func TestQueueInput_MaxQueued(t *testing.T) {
+	p := createQueueTestPlayer()
+	p.inputConfig.MaxQueued = 5
+
+	p.queueInput("20n")
+	if len(p.inputQueue) != 5 {
+		t.Errorf("Expected queue capped at 5, got %d", len(p.inputQueue))
+	}
+}
+
+// TestQueueInput_HeldByCombat tests that a fight holds queued movement and
+// that commands typed during the fight go ahead of it
+func TestQueueInput_HeldByCombat(t *testing.T) {
+	p := createQueueTestPlayer()
+	p.queueInput("2n")
+	p.InCombat = true
+
+	p.runQueuedInput()
+	if len(p.inputQueue) != 2 || !p.queueHeld {
+		t.Fatalf("Expected movement to be held during combat, queue %v", p.inputQueue)
+	}
+
+	p.queueInput("flee")
+	expected := []string{"flee", "north", "north"}
+	if !reflect.DeepEqual(p.inputQueue, expected) {
+		t.Errorf("Expected queue %v, got %v", expected, p.inputQueue)
+	}
+
+	if dropped := p.ClearInputQueue(); dropped != 3 {
+		t.Errorf("Expected 3 commands dropped, got %d", dropped)
+	}
+	if p.queueHeld {
+		t.Error("Expected clear to reset the held flag")
+	}
+}
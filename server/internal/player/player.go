@@ -4,12 +4,18 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/lawnchairsociety/opentowermud/server/internal/antispam"
+	"github.com/lawnchairsociety/opentowermud/server/internal/channels"
 	"github.com/lawnchairsociety/opentowermud/server/internal/class"
+	"github.com/lawnchairsociety/opentowermud/server/internal/color"
 	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+	"github.com/lawnchairsociety/opentowermud/server/internal/config"
 	"github.com/lawnchairsociety/opentowermud/server/internal/crafting"
+	"github.com/lawnchairsociety/opentowermud/server/internal/effects"
+	"github.com/lawnchairsociety/opentowermud/server/internal/guild"
 	"github.com/lawnchairsociety/opentowermud/server/internal/items"
 	"github.com/lawnchairsociety/opentowermud/server/internal/leveling"
 	"github.com/lawnchairsociety/opentowermud/server/internal/npc"
@@ -36,6 +42,19 @@ type Client interface {
 	RemoteAddr() string
 }
 
+// TypedWriter is implemented by clients that can deliver messages tagged with
+// their kind (room, combat, chat, prompt, ...), such as WebSocket clients in JSON mode.
+type TypedWriter interface {
+	WriteTyped(kind string, message string) error
+}
+
+// TrafficCounter is implemented by clients that track how many bytes they send.
+type TrafficCounter interface {
+	// TrafficStats returns bytes written before compression, bytes sent on the
+	// wire, and whether output compression is active.
+	TrafficStats() (raw, sent int64, compressed bool)
+}
+
 // ServerInterface defines methods needed from the server
 type ServerInterface interface {
 	GetOnlinePlayers() []string
@@ -43,6 +62,7 @@ type ServerInterface interface {
 	BroadcastToRoom(roomID string, message string, exclude interface{})
 	IsPilgrimMode() bool
 	GetAntispamConfig() *antispam.Config // Returns antispam config from chat filter
+	GetInputConfig() *config.InputConfig // Returns command stacking/speedwalk settings
 }
 
 // PlayerState represents the current state of a player
@@ -143,9 +163,56 @@ type Player struct {
 	// Tower run tracking for "unkillable" achievement
 	currentTowerRun  string // Tower ID of current run (empty if not in tower)
 	deathsDuringRun  int    // Deaths during current tower run
+	// Where the player last died (for resurrection)
+	lastDeathRoom string
+	lastDeathAt   time.Time
+	// Experience owed from death penalties (persisted with the character)
+	xpDebt int
+	// Hardcore mode: one life, chosen at creation (persisted with the character)
+	hardcore bool
+	retired  bool // Died in hardcore mode; only the memorial remains
 	// Session tracking
+	clientMu      sync.RWMutex  // Guards client, link-dead and takeover state (client is swapped on reconnect)
+	linkDead      bool          // Connection dropped; player stays in the world awaiting reconnect
+	linkDeadSince time.Time     // When the connection dropped
+	takingOver    bool          // A new connection is waiting for this one's command loop to exit
+	sessionDone   chan struct{} // Closed when the current connection's command loop exits
 	lastActivity time.Time // Last time player sent input (for idle timeout)
 	loginTime    time.Time // When the player logged in (for play time tracking)
+	// Custom prompt format (empty = DefaultPrompt)
+	prompt string
+	// Color preferences (on/off and category remapping)
+	colorPrefs color.Preferences
+	// Guild membership (loaded at login) and any pending guild invite
+	guildMu      sync.Mutex
+	guildID      int64
+	guildName    string
+	guildTag     string
+	guildRank    guild.Rank
+	guildInvite  int64  // Guild ID of a pending invite (0 = none)
+	guildInviter string // Who sent the pending invite
+	// Chat channel membership (joined/left/muted, persisted with the character)
+	channelMu    sync.Mutex
+	channelPrefs channels.Preferences
+	// Friends list privacy (persisted with the character)
+	friendMu     sync.Mutex
+	hideLocation bool // Hide tower and floor from other players' friends lists
+	// Command aliases (name -> expansion, persisted with the character)
+	aliasMu sync.Mutex
+	aliases map[string]string
+	// Status effects (buffs, debuffs, poison, stun) - the set has its own lock
+	effects effects.Set
+	// Defensive stance from the defend command
+	defendMu       sync.Mutex
+	defendingUntil time.Time // Braced against room-wide attacks, and not attacking, until then
+	// GMCP - last payload sent per package, so only changes are pushed
+	gmcpSent map[string]string
+	gmcpMu   sync.Mutex
+	// Input queue (command stacking and speedwalk) - only touched by the command loop
+	inputConfig  config.InputConfig
+	inputQueue   []string  // Commands waiting to run
+	nextQueuedAt time.Time // Earliest time the next queued command may run
+	queueHeld    bool      // Queued movement is held by a fight (player already told)
 }
 
 func NewPlayer(name string, client Client, world *world.World, server ServerInterface) *Player {
@@ -211,6 +278,7 @@ func NewPlayer(name string, client Client, world *world.World, server ServerInte
 		// Session tracking
 		lastActivity: time.Now(),
 		loginTime:    time.Now(),
+		sessionDone:  make(chan struct{}),
 	}
 
 	// Initialize anti-spam tracker with config from server
@@ -223,6 +291,14 @@ func NewPlayer(name string, client Client, world *world.World, server ServerInte
 		p.spamTracker = antispam.NewTracker(antispam.DefaultConfig())
 	}
 
+	// Input queue settings from server config
+	p.inputConfig = config.DefaultConfig().Input
+	if server != nil {
+		if cfg := server.GetInputConfig(); cfg != nil {
+			p.inputConfig = *cfg
+		}
+	}
+
 	// Add player to starting room
 	p.CurrentRoom.AddPlayer(name)
 
@@ -236,42 +312,92 @@ func (p *Player) HandleSession() {
 	}
 	p.SendMessage(p.CurrentRoom.GetDescription())
 	p.SendMessage("\nType 'help' for a list of commands.\n\n")
+	p.UpdateGMCP()
+
+	p.commandLoop()
+}
+
+// commandLoop reads input into the input queue and runs queued commands until
+// the player quits or the connection drops.
+func (p *Player) commandLoop() {
+	p.clientMu.RLock()
+	client, sessionDone := p.client, p.sessionDone
+	p.clientMu.RUnlock()
+	// A connection taking the player over waits for this before reattaching
+	defer close(sessionDone)
 
-	for {
-		if p.disconnected {
-			break
+	done := make(chan struct{})
+	defer close(done)
+	lines := readLines(client, done)
+
+	for !p.disconnected && !p.isTakingOver() {
+		// Only wake up for the queue when something is waiting in it
+		var next <-chan time.Time
+		if len(p.inputQueue) > 0 {
+			next = time.After(time.Until(p.nextQueuedAt))
 		}
 
-		line, err := p.client.ReadLine()
-		if err != nil {
-			// Connection closed or error
-			break
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				// Connection closed or error
+				return
+			}
+
+			input := strings.TrimSpace(line)
+			if input == "" {
+				continue
+			}
+
+			// Update activity timestamp for idle tracking
+			p.lastActivity = time.Now()
+			p.queueInput(input)
+		case <-next:
 		}
 
-		input := strings.TrimSpace(line)
-		if input == "" {
-			continue
+		// Once another connection is taking over, nothing more runs on this one
+		if p.isTakingOver() {
+			return
 		}
+		p.runQueuedInput()
+	}
+}
 
-		// Update activity timestamp for idle tracking
-		p.lastActivity = time.Now()
+// executeInput parses and executes a single command, then shows the prompt.
+func (p *Player) executeInput(input string) {
+	cmd := command.ParseCommand(input)
+	result := cmd.Execute(p, p.world)
+	p.SendTyped(cmd.OutputKind(), result+"\n")
 
-		// Parse and execute command
-		cmd := command.ParseCommand(input)
-		result := cmd.Execute(p, p.world)
-		p.SendMessage(result + "\n")
+	// Show status prompt
+	p.SendTyped(command.MessagePrompt, p.GetStatusPrompt())
 
-		// Show status prompt
-		p.SendMessage(p.GetStatusPrompt())
-	}
+	// Push any GMCP packages that changed
+	p.UpdateGMCP()
 }
 
 func (p *Player) SendMessage(message string) {
+	p.SendTyped(command.MessageSystem, message)
+}
+
+// SendTyped sends a message tagged with its kind (see command.MessageRoom etc.).
+// Clients without structured output receive it as a plain message.
+func (p *Player) SendTyped(kind string, message string) {
 	if p.disconnected {
 		return
 	}
 
-	p.client.WriteLine(message)
+	client := p.getClient()
+	if client == nil || p.IsLinkDead() {
+		return
+	}
+
+	message = p.renderColor(message)
+	if tw, ok := client.(TypedWriter); ok {
+		tw.WriteTyped(kind, message)
+		return
+	}
+	client.WriteLine(message)
 }
 
 func (p *Player) Disconnect() {
@@ -281,20 +407,14 @@ func (p *Player) Disconnect() {
 		playTimeSeconds := int64(time.Since(p.loginTime).Seconds())
 		p.AddPlayTime(playTimeSeconds)
 	}
-	// Close stall and return items to inventory
-	if p.stallOpen {
-		for _, stallItem := range p.stallInventory {
-			p.Inventory = append(p.Inventory, stallItem.Item)
-		}
-		p.stallInventory = make([]*command.StallItem, 0)
-		p.stallOpen = false
-	}
+	// The stall is left as it is: it's saved with the character, and an open
+	// stall keeps selling while the player is offline
 	// Remove player from current room
 	if p.CurrentRoom != nil {
 		p.CurrentRoom.RemovePlayer(p.Name)
 	}
-	if p.client != nil {
-		p.client.Close()
+	if client := p.getClient(); client != nil {
+		client.Close()
 	}
 }
 
@@ -597,13 +717,9 @@ func (p *Player) GetExperience() int {
 	return p.Experience
 }
 
-// GetStatusPrompt returns a status line showing HP, MP, and current room
+// GetStatusPrompt returns the player's rendered prompt (see SetPrompt for the format)
 func (p *Player) GetStatusPrompt() string {
-	roomName := "Unknown"
-	if p.CurrentRoom != nil {
-		roomName = p.CurrentRoom.Name
-	}
-	return fmt.Sprintf("\n[HP: %d/%d | MP: %d/%d | %s]", p.Health, p.MaxHealth, p.Mana, p.MaxMana, roomName)
+	return "\n" + p.RenderPrompt(p.GetPrompt())
 }
 
 // Regenerate applies health and mana regeneration based on the player's state
@@ -815,9 +931,9 @@ func (p *Player) isWearingHeavyArmor() bool {
 	return false
 }
 
-// GetArmorClass returns the player's AC (10 + total armor)
+// GetArmorClass returns the player's AC (10 + total armor + AC buffs and debuffs)
 func (p *Player) GetArmorClass() int {
-	return 10 + p.GetEffectiveArmor()
+	return 10 + p.GetEffectiveArmor() + p.effects.Modifier(effects.StatAC)
 }
 
 // GetEffectiveArmor returns total armor including class bonuses
@@ -825,7 +941,7 @@ func (p *Player) GetEffectiveArmor() int {
 	// Base armor from equipment
 	totalArmor := 0
 	for _, item := range p.Equipment {
-		if item != nil {
+		if item != nil && !item.IsBroken() {
 			totalArmor += item.Armor
 		}
 	}
@@ -862,8 +978,8 @@ func (p *Player) TakeDamage(damage int) int {
 	// Get effective armor including class bonuses
 	totalArmor := p.GetEffectiveArmor()
 
-	// Apply armor reduction
-	actualDamage := damage - totalArmor
+	// Apply armor reduction, then damage taken buffs and debuffs
+	actualDamage := applyPercent(damage-totalArmor, p.effects.Modifier(effects.StatTaken))
 	if actualDamage < 1 {
 		actualDamage = 1 // Minimum 1 damage
 	}
@@ -941,8 +1057,8 @@ func (p *Player) GetAttackDamage() int {
 	// Get the appropriate modifier based on weapon type
 	attackMod, _ := p.getWeaponAttackMod()
 
-	// Check for equipped weapon with dice notation
-	if weapon, hasWeapon := p.Equipment[items.SlotWeapon]; hasWeapon {
+	// Check for equipped weapon with dice notation (a broken weapon fights like bare hands)
+	if weapon, hasWeapon := p.Equipment[items.SlotWeapon]; hasWeapon && !weapon.IsBroken() {
 		if weapon.DamageDice != "" {
 			// Roll weapon dice + attack modifier (STR or DEX)
 			damage := stats.ParseDiceWithBonus(weapon.DamageDice, attackMod)
@@ -968,19 +1084,24 @@ func (p *Player) GetAttackDamage() int {
 }
 
 // RollAttack rolls a d20 + attack modifier for attack
-// Uses STR for melee, DEX for ranged, higher of STR/DEX for finesse
+// Uses STR for melee, DEX for ranged, higher of STR/DEX for finesse, plus hit buffs and debuffs
 // Returns the roll result and the breakdown string for display
 func (p *Player) RollAttack() (int, string) {
 	d20 := stats.D20()
 	attackMod, statName := p.getWeaponAttackMod()
-	total := d20 + attackMod
+	effectMod := p.effects.Modifier(effects.StatHit)
+	total := d20 + attackMod + effectMod
 
 	var breakdown string
 	if attackMod >= 0 {
-		breakdown = fmt.Sprintf("d20+%d(%s) = %d", attackMod, statName, total)
+		breakdown = fmt.Sprintf("d20+%d(%s)", attackMod, statName)
 	} else {
-		breakdown = fmt.Sprintf("d20%d(%s) = %d", attackMod, statName, total)
+		breakdown = fmt.Sprintf("d20%d(%s)", attackMod, statName)
+	}
+	if effectMod != 0 {
+		breakdown += fmt.Sprintf("%+d(effects)", effectMod)
 	}
+	breakdown += fmt.Sprintf(" = %d", total)
 
 	return total, breakdown
 }
@@ -1039,7 +1160,16 @@ func (p *Player) GetAttackDamageAgainst(target *npc.NPC, isSneakAttack bool) int
 		}
 	}
 
-	totalDamage := baseDamage + bonusDamage
+	// Damage buffs and debuffs
+	totalDamage := applyPercent(baseDamage+bonusDamage, p.effects.Modifier(effects.StatDamage))
+
+	// Weapon coatings (poison blade) add their dice, unless the target can't be poisoned
+	if target != nil && !target.Effects().IsImmune(effects.CategoryPoison) {
+		if coating, ok := p.effects.Consume(effects.StatCoating); ok {
+			totalDamage += stats.ParseDice(coating.Dice)
+		}
+	}
+
 	if totalDamage < 1 {
 		totalDamage = 1
 	}
@@ -1116,8 +1246,9 @@ func (p *Player) CanMultishot() bool {
 }
 
 // GainExperience adds experience points to the player and returns level-up info if leveled
+// While the player has XP debt, half of what they earn pays it off instead.
 func (p *Player) GainExperience(xp int) []leveling.LevelUpInfo {
-	p.Experience += xp
+	p.Experience += p.payXPDebt(xp)
 
 	var levelUps []leveling.LevelUpInfo
 
@@ -1355,12 +1486,21 @@ func (p *Player) SetCharisma(val int) {
 
 // GetRemoteAddr returns the player's remote address string (for admin commands)
 func (p *Player) GetRemoteAddr() string {
-	if p.client != nil {
-		return p.client.RemoteAddr()
+	if client := p.getClient(); client != nil {
+		return client.RemoteAddr()
 	}
 	return ""
 }
 
+// GetTrafficStats returns the connection's output byte counters.
+// Returns zeros if the client doesn't track traffic.
+func (p *Player) GetTrafficStats() (raw, sent int64, compressed bool) {
+	if tc, ok := p.getClient().(TrafficCounter); ok {
+		return tc.TrafficStats()
+	}
+	return 0, 0, false
+}
+
 // ==================== TOWER METHODS ====================
 
 // GetHomeTower returns the player's home tower ID
@@ -1699,6 +1839,7 @@ func (p *Player) CanCastSpellForClass(allowedClasses []string, requiredLevel int
 
 // TakeMagicDamage applies damage to the player without armor reduction (for spell damage).
 func (p *Player) TakeMagicDamage(damage int) int {
+	damage = applyPercent(damage, p.effects.Modifier(effects.StatTaken))
 	if damage < 1 {
 		damage = 1
 	}
@@ -2811,6 +2952,27 @@ func (p *Player) GetDeathsDuringRun() int {
 	return p.deathsDuringRun
 }
 
+// ==================== DEATH LOCATION ====================
+
+// RecordDeathLocation remembers where the player died, so a party member can
+// resurrect them there.
+func (p *Player) RecordDeathLocation(roomID string) {
+	p.lastDeathRoom = roomID
+	p.lastDeathAt = time.Now()
+}
+
+// GetLastDeath returns the room the player last died in and when.
+// Returns an empty room ID if there is no death on record.
+func (p *Player) GetLastDeath() (string, time.Time) {
+	return p.lastDeathRoom, p.lastDeathAt
+}
+
+// ClearLastDeath forgets the player's last death (after a resurrection).
+func (p *Player) ClearLastDeath() {
+	p.lastDeathRoom = ""
+	p.lastDeathAt = time.Time{}
+}
+
 // ==================== STALL METHODS ====================
 
 // IsStallOpen returns whether the player's stall is open for business
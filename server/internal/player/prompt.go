@@ -0,0 +1,148 @@
+package player
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/leveling"
+)
+
+// DefaultPrompt is the prompt format used when a player hasn't set their own.
+const DefaultPrompt = "[HP: %h/%H | MP: %m/%M | %r]"
+
+// MaxPromptLength is the longest prompt format a player may set.
+const MaxPromptLength = 120
+
+// promptExitOrder is the order exits appear in the %e token.
+var promptExitOrder = []string{"north", "south", "east", "west", "up", "down"}
+
+// GetPrompt returns the player's prompt format, or DefaultPrompt if unset.
+func (p *Player) GetPrompt() string {
+	if p.prompt == "" {
+		return DefaultPrompt
+	}
+	return p.prompt
+}
+
+// GetCustomPrompt returns the player's own prompt format, or "" if using the default.
+func (p *Player) GetCustomPrompt() string {
+	return p.prompt
+}
+
+// SetPrompt sets the player's prompt format. An empty format restores the default.
+func (p *Player) SetPrompt(format string) error {
+	format = strings.TrimSpace(format)
+	if len(format) > MaxPromptLength {
+		return fmt.Errorf("prompt cannot be longer than %d characters", MaxPromptLength)
+	}
+	if strings.ContainsAny(format, "\r\n") {
+		return errors.New("prompt cannot contain line breaks")
+	}
+	if format == DefaultPrompt {
+		format = ""
+	}
+	p.prompt = format
+	return nil
+}
+
+// RenderPrompt expands the tokens in a prompt format using the player's current state.
+// Unknown tokens are left as-is.
+func (p *Player) RenderPrompt(format string) string {
+	var sb strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 >= len(format) {
+			sb.WriteByte(format[i])
+			continue
+		}
+
+		i++
+		switch format[i] {
+		case 'h':
+			sb.WriteString(fmt.Sprintf("%d", p.Health))
+		case 'H':
+			sb.WriteString(fmt.Sprintf("%d", p.MaxHealth))
+		case 'm':
+			sb.WriteString(fmt.Sprintf("%d", p.Mana))
+		case 'M':
+			sb.WriteString(fmt.Sprintf("%d", p.MaxMana))
+		case 'x':
+			sb.WriteString(fmt.Sprintf("%d", p.Experience))
+		case 'X':
+			sb.WriteString(fmt.Sprintf("%d", p.promptXPToGo()))
+		case 'g':
+			sb.WriteString(fmt.Sprintf("%d", p.Gold))
+		case 'e':
+			sb.WriteString(p.promptExits())
+		case 'r':
+			sb.WriteString(p.promptRoomName())
+		case 't':
+			sb.WriteString(p.promptTarget())
+		case '%':
+			sb.WriteByte('%')
+		default:
+			sb.WriteByte('%')
+			sb.WriteByte(format[i])
+		}
+	}
+	return sb.String()
+}
+
+// promptXPToGo returns the experience still needed for the next level (%X token).
+func (p *Player) promptXPToGo() int {
+	if p.Level >= leveling.MaxPlayerLevel {
+		return 0
+	}
+	return leveling.XPForLevel(p.Level+1) - p.Experience
+}
+
+// promptRoomName returns the current room name for the %r token.
+func (p *Player) promptRoomName() string {
+	if p.CurrentRoom == nil {
+		return "Unknown"
+	}
+	return p.CurrentRoom.Name
+}
+
+// promptExits returns the abbreviated exit list for the %e token.
+// Standard directions use their initial; anything else is shown in full.
+func (p *Player) promptExits() string {
+	if p.CurrentRoom == nil {
+		return ""
+	}
+	exits := p.CurrentRoom.GetExits()
+
+	var parts []string
+	for _, dir := range promptExitOrder {
+		if _, ok := exits[dir]; ok {
+			parts = append(parts, strings.ToUpper(dir[:1]))
+			delete(exits, dir)
+		}
+	}
+
+	// Non-standard exits (e.g., "portal") in a stable order
+	var others []string
+	for dir := range exits {
+		others = append(others, dir)
+	}
+	sort.Strings(others)
+	for _, dir := range others {
+		parts = append(parts, " "+dir)
+	}
+
+	return strings.Join(parts, "")
+}
+
+// promptTarget returns the combat target and its health percentage for the %t token.
+func (p *Player) promptTarget() string {
+	if !p.InCombat || p.CombatTarget == "" || p.CurrentRoom == nil {
+		return ""
+	}
+	target := p.CurrentRoom.FindNPC(p.CombatTarget)
+	if target == nil || target.GetMaxHealth() <= 0 {
+		return p.CombatTarget
+	}
+	pct := target.GetHealth() * 100 / target.GetMaxHealth()
+	return fmt.Sprintf("%s %d%%", target.GetName(), pct)
+}
@@ -0,0 +1,90 @@
+package player
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/npc"
+	"github.com/lawnchairsociety/opentowermud/server/internal/world"
+)
+
+// TestRenderPrompt_Tokens tests that each prompt token expands from player state
+func TestRenderPrompt_Tokens(t *testing.T) {
+	p := createTestPlayer()
+	p.Health, p.MaxHealth = 7, 10
+	p.Mana, p.MaxMana = 3, 5
+	p.Experience = 42
+	p.Gold = 15
+
+	room := world.NewRoom("hall", "Dusty Hall", "A hall.", world.RoomTypeRoom)
+	other := world.NewRoom("yard", "Yard", "A yard.", world.RoomTypeRoom)
+	room.AddExit("up", other)
+	room.AddExit("north", other)
+	room.AddExit("portal", other)
+	p.CurrentRoom = room
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"%h/%H %m/%M", "7/10 3/5"},
+		{"xp:%x gold:%g", "xp:42 gold:15"},
+		{"[%e]", "[NU portal]"},
+		{"%r", "Dusty Hall"},
+		{"100%% %q", "100% %q"},
+		{"trailing %", "trailing %"},
+		{"<%t>", "<>"},
+	}
+
+	for _, tt := range tests {
+		if got := p.RenderPrompt(tt.format); got != tt.expected {
+			t.Errorf("RenderPrompt(%q) = %q, expected %q", tt.format, got, tt.expected)
+		}
+	}
+}
+
+// TestRenderPrompt_CombatTarget tests the %t token shows the target's health percentage
+func TestRenderPrompt_CombatTarget(t *testing.T) {
+	p := createTestPlayer()
+	room := world.NewRoom("hall", "Dusty Hall", "A hall.", world.RoomTypeRoom)
+	goblin := npc.NewNPC("goblin", "A goblin.", 1, 20, 2, 0, 5, false, true, "hall", 0, 0)
+	goblin.TakeDamage(5)
+	room.AddNPC(goblin)
+	p.CurrentRoom = room
+	p.InCombat = true
+	p.CombatTarget = "goblin"
+
+	if got := p.RenderPrompt("%t"); got != "goblin 75%" {
+		t.Errorf("Expected 'goblin 75%%', got %q", got)
+	}
+}
+
+// TestSetPrompt_Validation tests prompt length/line break limits and resetting to default
+func TestSetPrompt_Validation(t *testing.T) {
+	p := createTestPlayer()
+
+	if p.GetPrompt() != DefaultPrompt {
+		t.Errorf("Expected default prompt, got %q", p.GetPrompt())
+	}
+
+	if err := p.SetPrompt(strings.Repeat("x", MaxPromptLength+1)); err == nil {
+		t.Error("Expected error for overly long prompt")
+	}
+	if err := p.SetPrompt("hp %h\nmp %m"); err == nil {
+		t.Error("Expected error for prompt with a line break")
+	}
+
+	if err := p.SetPrompt("<%h>"); err != nil {
+		t.Fatalf("SetPrompt failed: %v", err)
+	}
+	if p.GetCustomPrompt() != "<%h>" {
+		t.Errorf("Expected custom prompt '<%%h>', got %q", p.GetCustomPrompt())
+	}
+
+	if err := p.SetPrompt(""); err != nil {
+		t.Fatalf("SetPrompt failed: %v", err)
+	}
+	if p.GetCustomPrompt() != "" || p.GetPrompt() != DefaultPrompt {
+		t.Error("Expected empty prompt to restore the default")
+	}
+}
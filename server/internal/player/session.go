@@ -0,0 +1,124 @@
+package player
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+)
+
+// getClient returns the player's current connection.
+// The client can be replaced when a link-dead player reconnects.
+func (p *Player) getClient() Client {
+	p.clientMu.RLock()
+	defer p.clientMu.RUnlock()
+	return p.client
+}
+
+// GetClient returns the player's current connection.
+func (p *Player) GetClient() Client {
+	return p.getClient()
+}
+
+// IsDisconnected returns true if the player quit or was disconnected by the server
+// (as opposed to losing their connection).
+func (p *Player) IsDisconnected() bool {
+	return p.disconnected
+}
+
+// IsLinkDead returns true if the player's connection dropped and they are
+// waiting in the world for a reconnect.
+func (p *Player) IsLinkDead() bool {
+	p.clientMu.RLock()
+	defer p.clientMu.RUnlock()
+	return p.linkDead
+}
+
+// LinkDeadSince returns when the player's connection dropped.
+// Returns the zero time if the player is not link-dead.
+func (p *Player) LinkDeadSince() time.Time {
+	p.clientMu.RLock()
+	defer p.clientMu.RUnlock()
+	if !p.linkDead {
+		return time.Time{}
+	}
+	return p.linkDeadSince
+}
+
+// SetLinkDead flags the player as link-dead. Output is discarded until they reconnect.
+func (p *Player) SetLinkDead() {
+	p.clientMu.Lock()
+	defer p.clientMu.Unlock()
+	p.linkDead = true
+	p.linkDeadSince = time.Now()
+}
+
+// ClearLinkDead removes the link-dead flag without attaching a new client.
+// Used when the grace period expires so the player can no longer be reattached.
+func (p *Player) ClearLinkDead() {
+	p.clientMu.Lock()
+	defer p.clientMu.Unlock()
+	p.linkDead = false
+}
+
+// BeginTakeover marks the player as being taken over by a new connection, so
+// the command loop on the old one stops running commands and exits. Returns a
+// channel that is closed once it has, or false if another connection is
+// already taking the player over.
+func (p *Player) BeginTakeover() (<-chan struct{}, bool) {
+	p.clientMu.Lock()
+	defer p.clientMu.Unlock()
+	if p.takingOver {
+		return nil, false
+	}
+	p.takingOver = true
+	return p.sessionDone, true
+}
+
+// isTakingOver returns true while a new connection waits to take the player over.
+func (p *Player) isTakingOver() bool {
+	p.clientMu.RLock()
+	defer p.clientMu.RUnlock()
+	return p.takingOver
+}
+
+// SessionReplaced returns true if client is no longer (or soon won't be) the
+// player's connection, because a new connection has taken the player over.
+func (p *Player) SessionReplaced(client Client) bool {
+	p.clientMu.RLock()
+	defer p.clientMu.RUnlock()
+	return p.takingOver || p.client != client
+}
+
+// Reattach connects a new client to a player whose old connection's command
+// loop has exited. Input queued on the old connection is dropped.
+func (p *Player) Reattach(client Client) {
+	p.clientMu.Lock()
+	p.client = client
+	p.linkDead = false
+	p.takingOver = false
+	p.sessionDone = make(chan struct{})
+	p.clientMu.Unlock()
+
+	// Input typed before the connection dropped doesn't carry over to a reconnect
+	p.ClearInputQueue()
+	p.lastActivity = time.Now()
+
+	// The new client has seen nothing yet - resend every GMCP package
+	p.gmcpMu.Lock()
+	p.gmcpSent = nil
+	p.gmcpMu.Unlock()
+}
+
+// ResumeSession runs the command loop for a player who reconnected to a link-dead session.
+func (p *Player) ResumeSession() {
+	p.SendMessage(fmt.Sprintf("\nReconnected. Welcome back, %s!\n", p.Name))
+	p.SendMessage(p.CurrentRoom.GetDescription())
+	if p.InCombat && p.CombatTarget != "" {
+		p.SendMessage(fmt.Sprintf("\nYou are still fighting {npc}%s{/}!\n", p.CombatTarget))
+	}
+	p.SendTyped(command.MessagePrompt, p.GetStatusPrompt())
+	p.UpdateGMCP()
+
+	p.commandLoop()
+}
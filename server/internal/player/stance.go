@@ -0,0 +1,29 @@
+package player
+
+import "time"
+
+// DefendDuration is how long the defend command keeps a player braced, in seconds
+// (two combat rounds).
+const DefendDuration = 6
+
+// Defend braces the player against room-wide attacks for DefendDuration seconds.
+// A defending player doesn't attack.
+func (p *Player) Defend() {
+	p.defendMu.Lock()
+	defer p.defendMu.Unlock()
+	p.defendingUntil = time.Now().Add(DefendDuration * time.Second)
+}
+
+// IsDefending returns true while the player is braced.
+func (p *Player) IsDefending() bool {
+	p.defendMu.Lock()
+	defer p.defendMu.Unlock()
+	return time.Now().Before(p.defendingUntil)
+}
+
+// StopDefending drops the player's guard.
+func (p *Player) StopDefending() {
+	p.defendMu.Lock()
+	defer p.defendMu.Unlock()
+	p.defendingUntil = time.Time{}
+}
@@ -180,6 +180,13 @@ func (s *PlayerStatistics) GetTotalPlayTimeHours() float64 {
 	return float64(s.TotalPlayTimeSeconds) / 3600.0
 }
 
+// GetTotalPlayTimeSeconds returns total play time in seconds.
+func (s *PlayerStatistics) GetTotalPlayTimeSeconds() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.TotalPlayTimeSeconds
+}
+
 // RecordTowerClearWithoutDeath records a deathless tower clear.
 func (s *PlayerStatistics) RecordTowerClearWithoutDeath(towerID string) {
 	s.mu.Lock()
@@ -222,6 +229,26 @@ func (s *PlayerStatistics) GetHighestFloor(towerID string) int {
 	return s.HighestFloor[towerID]
 }
 
+// GetDeepestFloor returns the highest floor reached in any tower.
+func (s *PlayerStatistics) GetDeepestFloor() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	deepest := 0
+	for _, floor := range s.HighestFloor {
+		if floor > deepest {
+			deepest = floor
+		}
+	}
+	return deepest
+}
+
+// GetQuestsCompleted returns the number of quests completed.
+func (s *PlayerStatistics) GetQuestsCompleted() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.QuestsCompleted
+}
+
 // ToJSON serializes statistics to JSON.
 func (s *PlayerStatistics) ToJSON() string {
 	s.mu.RLock()
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/auction"
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+)
+
+// auctionCheckInterval is how often expired auctions are settled.
+const auctionCheckInterval = time.Minute
+
+// startAuctionTicker runs a background ticker that settles expired auctions
+func (s *Server) startAuctionTicker() {
+	if s.db == nil {
+		return
+	}
+
+	ticker := time.NewTicker(auctionCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.shutdown:
+			return
+		case <-ticker.C:
+			s.settleExpiredAuctions()
+		}
+	}
+}
+
+// settleExpiredAuctions closes auctions past their end time and tells any
+// online sellers and winners that the auction house has written to them.
+func (s *Server) settleExpiredAuctions() {
+	closed, err := s.db.ExpireAuctions()
+	if err != nil {
+		logger.Error("Failed to expire auctions", "error", err)
+	}
+
+	for _, l := range closed {
+		if l.HasBid() {
+			logger.Info("Auction sold", "id", l.ID, "item", l.ItemID, "seller", l.SellerName, "buyer", l.BidderName, "price", l.CurrentBid)
+			s.notifyAuction(l.SellerName, fmt.Sprintf("Your auction of {item}%s{/} sold to {player}%s{/} for {gold}%d gold{/}.", l.ItemName, l.BidderName, l.CurrentBid))
+			s.notifyAuction(l.BidderName, fmt.Sprintf("You won the auction for {item}%s{/} with a bid of {gold}%d gold{/}.", l.ItemName, l.CurrentBid))
+		} else {
+			logger.Info("Auction expired", "id", l.ID, "item", l.ItemID, "seller", l.SellerName)
+			s.notifyAuction(l.SellerName, fmt.Sprintf("Your auction of {item}%s{/} ended without a sale.", l.ItemName))
+		}
+	}
+}
+
+// notifyAuction tells a player, if online, that they have mail from the auction house.
+func (s *Server) notifyAuction(name, message string) {
+	if p := s.findOnlinePlayer(name); p != nil {
+		p.SendMessage(fmt.Sprintf("\n%s A letter from the %s awaits you at the mailbox.\n", message, auction.HouseName))
+	}
+}
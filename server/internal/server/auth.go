@@ -143,7 +143,7 @@ func (s *Server) handleLogin(client Client) (*AuthResult, error) {
 
 	// Get password
 	client.WriteLine("Password: ")
-	password, err := client.ReadLine()
+	password, err := readPassword(client)
 	if err != nil {
 		return nil, errors.New("connection closed")
 	}
@@ -253,7 +253,7 @@ func (s *Server) handleRegister(client Client) (*AuthResult, error) {
 	// Get password with validation
 	pwConfig := s.GetServerConfig().Password
 	client.WriteLine(fmt.Sprintf("Choose a password (%s): ", pwConfig.GetRequirementsText()))
-	password, err := client.ReadLine()
+	password, err := readPassword(client)
 	if err != nil {
 		return nil, errors.New("connection closed")
 	}
@@ -264,7 +264,7 @@ func (s *Server) handleRegister(client Client) (*AuthResult, error) {
 
 	// Confirm password
 	client.WriteLine("Confirm password: ")
-	confirmPassword, err := client.ReadLine()
+	confirmPassword, err := readPassword(client)
 	if err != nil {
 		return nil, errors.New("connection closed")
 	}
@@ -330,7 +330,13 @@ func (s *Server) handleCharacterSelection(client Client, account *database.Accou
 				} else {
 					raceDisplay = strings.Title(raceDisplay)
 				}
-				client.WriteLine(fmt.Sprintf("  [%d] %s - Level %d %s %s\n", i+1, c.Name, c.Level, raceDisplay, classDisplay))
+				mode := ""
+				if c.Retired {
+					mode = " [fallen]"
+				} else if c.Hardcore {
+					mode = " [hardcore]"
+				}
+				client.WriteLine(fmt.Sprintf("  [%d] %s - Level %d %s %s%s\n", i+1, c.Name, c.Level, raceDisplay, classDisplay, mode))
 			}
 		}
 
@@ -370,12 +376,18 @@ func (s *Server) handleCharacterSelection(client Client, account *database.Accou
 				if charIndex >= 1 && charIndex <= len(characters) {
 					selected := characters[charIndex-1]
 
-					// Check if character is already online
-					if s.IsCharacterOnline(selected.Name) {
-						client.WriteLine("That character is already logged in.\n")
+					// A fallen hardcore character can only be remembered
+					if selected.Retired {
+						s.showMemorial(client, selected.Name)
 						continue
 					}
 
+					// A character that is already online is taken over by this
+					// connection once the login completes (see reattachSession)
+					if s.IsCharacterOnline(selected.Name) {
+						client.WriteLine("That character is already connected. Taking over the old session.\n")
+					}
+
 					return selected, nil
 				}
 			}
@@ -495,8 +507,14 @@ func (s *Server) handleCharacterCreation(client Client, account *database.Accoun
 	client.WriteLine(fmt.Sprintf("  WIS: %d (%+d)\n", scores.Wisdom, stats.Modifier(scores.Wisdom)))
 	client.WriteLine(fmt.Sprintf("  CHA: %d (%+d)\n", scores.Charisma, stats.Modifier(scores.Charisma)))
 
-	// Create character with assigned ability scores, class, race, and home tower
-	character, err := s.db.CreateCharacterFull(account.ID, name, selectedClass, selectedRace, selectedTower,
+	// Choose hardcore mode, which can't be changed later
+	hardcore, err := s.handleHardcoreSelection(client)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create character with assigned ability scores, class, race, home tower and mode
+	character, err := s.db.CreateCharacterFull(account.ID, name, selectedClass, selectedRace, selectedTower, hardcore,
 		scores.Strength, scores.Dexterity, scores.Constitution,
 		scores.Intelligence, scores.Wisdom, scores.Charisma)
 	if err != nil {
@@ -522,12 +540,48 @@ func (s *Server) handleCharacterCreation(client Client, account *database.Accoun
 		"class", selectedClass,
 		"race", selectedRace,
 		"home_tower", selectedTower,
+		"hardcore", hardcore,
 		"event", "character_create")
 
 	client.WriteLine(fmt.Sprintf("\nCharacter '%s' the %s %s created in %s!\n", character.Name, strings.Title(selectedRace), strings.Title(selectedClass), cityName))
+	if hardcore {
+		client.WriteLine("You have one life. Make it count.\n")
+	}
 	return character, nil
 }
 
+// handleHardcoreSelection asks whether the new character plays in hardcore mode
+func (s *Server) handleHardcoreSelection(client Client) (bool, error) {
+	client.WriteLine("\n--- Hardcore Mode ---\n")
+	client.WriteLine("A hardcore character has one life. When they die, they are retired for good:\n")
+	client.WriteLine("their deeds are kept in a memorial and on the hardcore leaderboard, but they\n")
+	client.WriteLine("can never be played again. Hardcore characters can only trade, give and mail\n")
+	client.WriteLine("with other hardcore characters.\n")
+
+	for {
+		client.WriteLine("\nPlay this character in hardcore mode? (Y/N): ")
+
+		choice, err := client.ReadLine()
+		if err != nil {
+			return false, errors.New("connection closed")
+		}
+		choice = strings.ToLower(strings.TrimSpace(choice))
+		if choice != "y" && choice != "yes" {
+			return false, nil
+		}
+
+		client.WriteLine("This can't be changed later. Are you sure? (Y/N): ")
+		confirm, err := client.ReadLine()
+		if err != nil {
+			return false, errors.New("connection closed")
+		}
+		confirm = strings.ToLower(strings.TrimSpace(confirm))
+		if confirm == "y" || confirm == "yes" {
+			return true, nil
+		}
+	}
+}
+
 // handleClassSelection guides the player through choosing a class
 func (s *Server) handleClassSelection(client Client) (string, error) {
 	client.WriteLine("\n--- Choose Your Class ---\n\n")
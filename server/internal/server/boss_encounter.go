@@ -0,0 +1,228 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+	"github.com/lawnchairsociety/opentowermud/server/internal/npc"
+	"github.com/lawnchairsociety/opentowermud/server/internal/stats"
+	"github.com/lawnchairsociety/opentowermud/server/internal/tower"
+	"github.com/lawnchairsociety/opentowermud/server/internal/world"
+)
+
+// bossEncounter is a scripted boss fight in progress.
+type bossEncounter struct {
+	def       *tower.Encounter
+	boss      *npc.NPC
+	room      *world.Room
+	started   time.Time
+	phase     int              // Index of the current phase, -1 before the first
+	adds      []*npc.NPC       // Mobs called in by phase changes, removed if the boss resets
+	telegraph *tower.Telegraph // Attack being wound up, nil if none
+	windup    int              // Rounds until the telegraphed attack lands
+	cooldown  int              // Rounds until the next telegraphed attack starts
+}
+
+// SetBossEncounters sets the scripted boss fights, by boss mob ID
+func (s *Server) SetBossEncounters(encounters map[string]*tower.Encounter) {
+	s.encounterMu.Lock()
+	defer s.encounterMu.Unlock()
+	s.bossEncounterDefs = encounters
+}
+
+// processBossEncounters runs one combat round of every scripted boss fight:
+// starting new ones, moving through phases, enraging, winding up and landing
+// telegraphed attacks, and resetting fights everyone has left.
+func (s *Server) processBossEncounters() {
+	s.encounterMu.Lock()
+	hasDefs := len(s.bossEncounterDefs) > 0
+	s.encounterMu.Unlock()
+	if !hasDefs {
+		return
+	}
+
+	for _, room := range s.world.GetAllRooms() {
+		for _, boss := range room.GetNPCs() {
+			if !boss.GetIsBoss() || !boss.IsAlive() || !boss.IsInCombat() {
+				continue
+			}
+			if enc := s.bossEncounterFor(boss, room); enc != nil {
+				s.advanceBossEncounter(enc)
+			}
+		}
+	}
+
+	// Fights that are over: the boss died, or everyone left or died
+	var over []*bossEncounter
+	s.encounterMu.Lock()
+	for boss, enc := range s.bossEncounters {
+		if !boss.IsAlive() || !s.encounterHasFighters(enc) {
+			over = append(over, enc)
+			delete(s.bossEncounters, boss)
+		}
+	}
+	s.encounterMu.Unlock()
+
+	for _, enc := range over {
+		if enc.boss.IsAlive() {
+			s.resetBossEncounter(enc)
+		}
+	}
+}
+
+// bossEncounterFor returns the encounter a boss is fighting, starting it if the
+// fight has just begun. Returns nil if the boss has no scripted encounter.
+func (s *Server) bossEncounterFor(boss *npc.NPC, room *world.Room) *bossEncounter {
+	s.encounterMu.Lock()
+	defer s.encounterMu.Unlock()
+	if enc, exists := s.bossEncounters[boss]; exists {
+		return enc
+	}
+	def, exists := s.bossEncounterDefs[boss.GetNPCID()]
+	if !exists {
+		return nil
+	}
+	if s.bossEncounters == nil {
+		s.bossEncounters = make(map[*npc.NPC]*bossEncounter)
+	}
+	enc := &bossEncounter{
+		def:     def,
+		boss:    boss,
+		room:    room,
+		started: time.Now(),
+		phase:   -1,
+	}
+	s.bossEncounters[boss] = enc
+	logger.Info("Boss encounter started", "boss", boss.GetName(), "room", room.GetID(), "fighters", boss.GetTargets())
+	return enc
+}
+
+// encounterHasFighters returns true if anyone the boss is fighting is still
+// alive and in its room
+func (s *Server) encounterHasFighters(enc *bossEncounter) bool {
+	for _, name := range enc.boss.GetTargets() {
+		if p := s.findOnlinePlayer(name); p != nil && p.IsAlive() && p.CurrentRoom == enc.room {
+			return true
+		}
+	}
+	return false
+}
+
+// advanceBossEncounter runs one combat round of a scripted boss fight
+func (s *Server) advanceBossEncounter(enc *bossEncounter) {
+	// Phase changes, possibly several at once after a big hit
+	hpPercent := float64(enc.boss.GetHealth()) * 100 / float64(enc.boss.GetMaxHealth())
+	for next := enc.def.PhaseAt(hpPercent); enc.phase < next; {
+		enc.phase++
+		s.startEncounterPhase(enc)
+	}
+
+	// Enrage timer
+	if enrage := enc.def.Enrage; enrage != nil && !enc.boss.IsEnraged() &&
+		time.Since(enc.started) >= time.Duration(enrage.After)*time.Second {
+		enc.boss.Enrage(enrage.DamagePercent)
+		message := enrage.Message
+		if message == "" {
+			message = fmt.Sprintf("%s flies into a rage!", enc.boss.GetName())
+		}
+		s.BroadcastToRoom(enc.room.GetID(), fmt.Sprintf("\n{warning}%s{/}\n", message), nil)
+		logger.Info("Boss enraged", "boss", enc.boss.GetName(), "room", enc.room.GetID())
+	}
+
+	// Telegraphed attacks: warn, wind up, then land
+	if enc.telegraph != nil {
+		enc.windup--
+		if enc.windup <= 0 {
+			s.landTelegraph(enc)
+			enc.cooldown = enc.telegraph.Interval
+			enc.telegraph = nil
+		}
+		return
+	}
+	if enc.phase < 0 || enc.def.Phases[enc.phase].Telegraph == nil {
+		return
+	}
+	if enc.cooldown > 0 {
+		enc.cooldown--
+		return
+	}
+	enc.telegraph = enc.def.Phases[enc.phase].Telegraph
+	enc.windup = enc.telegraph.Windup
+	warning := enc.telegraph.Warning
+	if warning == "" {
+		warning = fmt.Sprintf("%s begins to gather power for %s!", enc.boss.GetName(), enc.telegraph.Name)
+	}
+	s.BroadcastToRoom(enc.room.GetID(), fmt.Sprintf("\n{warning}%s{/}\n{system}Flee the room or defend yourself!{/}\n", warning), nil)
+}
+
+// startEncounterPhase announces the encounter's current phase and calls in its adds
+func (s *Server) startEncounterPhase(enc *bossEncounter) {
+	phase := enc.def.Phases[enc.phase]
+	if phase.Message != "" {
+		s.BroadcastToRoom(enc.room.GetID(), fmt.Sprintf("\n{warning}%s{/}\n", phase.Message), nil)
+	}
+	for _, wave := range phase.Adds {
+		enc.adds = append(enc.adds, s.callAdds(enc.boss, enc.room, wave.Mob, wave.Count)...)
+	}
+
+	// A new phase starts its telegraphed attack on a fresh timer
+	enc.telegraph = nil
+	if phase.Telegraph != nil {
+		enc.cooldown = phase.Telegraph.Interval
+	}
+	logger.Debug("Boss encounter phase", "boss", enc.boss.GetName(), "phase", enc.phase, "boss_hp", enc.boss.GetHealth())
+}
+
+// landTelegraph hits everyone still in the room with a telegraphed attack.
+// Players who are defending take half damage.
+func (s *Server) landTelegraph(enc *bossEncounter) {
+	message := enc.telegraph.Message
+	if message == "" {
+		message = fmt.Sprintf("%s unleashes %s!", enc.boss.GetName(), enc.telegraph.Name)
+	}
+	s.BroadcastToRoom(enc.room.GetID(), fmt.Sprintf("\n{npc}%s{/}\n", message), nil)
+
+	for _, p := range s.livingPlayersInRoom(enc.room) {
+		damage := stats.ParseDice(enc.telegraph.Damage)
+		if p.IsDefending() {
+			damage /= 2
+			p.SendTyped(command.MessageCombat, "You brace against the blow!\n")
+		}
+		if damage < 1 {
+			damage = 1
+		}
+		damage = p.TakeMagicDamage(damage)
+		p.RecordDamageTaken(damage)
+		p.SendTyped(command.MessageCombat, fmt.Sprintf("You take {damage}%d{/} damage! (%d/%d HP)\n",
+			damage, p.GetHealth(), p.GetMaxHealth()))
+		if !p.IsAlive() {
+			s.handlePlayerDeath(p, enc.boss, enc.room)
+		}
+	}
+}
+
+// resetBossEncounter puts a boss back as it was before the fight once everyone
+// has left or died, and sends its adds away
+func (s *Server) resetBossEncounter(enc *bossEncounter) {
+	for _, add := range enc.adds {
+		if !add.IsAlive() {
+			continue
+		}
+		for _, name := range add.GetTargets() {
+			if p := s.findOnlinePlayer(name); p != nil && p.GetCombatTarget() == add.GetName() {
+				p.EndCombat()
+			}
+		}
+		enc.room.RemoveNPC(add)
+	}
+	enc.boss.Reset()
+
+	message := enc.def.ResetMessage
+	if message == "" {
+		message = fmt.Sprintf("%s's wounds close as it returns to its watch.", enc.boss.GetName())
+	}
+	s.BroadcastToRoom(enc.room.GetID(), fmt.Sprintf("\n{npc}%s{/}\n", message), nil)
+	logger.Info("Boss encounter reset", "boss", enc.boss.GetName(), "room", enc.room.GetID())
+}
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/npc"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+	"github.com/lawnchairsociety/opentowermud/server/internal/tower"
+)
+
+// newEncounterTestServer sets up the players fighting the Goblin King in the
+// hall, with a scripted encounter for him
+func newEncounterTestServer(t *testing.T, enc *tower.Encounter, names ...string) (*Server, []*player.Player, *npc.NPC) {
+	t.Helper()
+	s, players := newPartyTestServer(t, names...)
+	s.SetMobConfig(&npc.NPCsConfig{NPCs: map[string]npc.NPCDefinition{
+		"goblin": {Name: "goblin", Level: 1, Health: 10, Damage: 2, Aggressive: true, Attackable: true, RespawnMedian: 120},
+	}})
+	s.SetBossEncounters(map[string]*tower.Encounter{"goblin_king": enc})
+
+	hall := s.world.GetRoom("hall")
+	king := npc.NewNPC("Goblin King", "The goblin ruler.", 5, 100, 10, 0, 100, true, true, "hall", 0, 0)
+	king.SetNPCID("goblin_king")
+	king.SetBoss(1)
+	hall.AddNPC(king)
+	for _, p := range players {
+		hall.AddPlayer(p.GetName())
+		p.StartCombat("Goblin King")
+		king.StartCombat(p.GetName())
+	}
+	return s, players, king
+}
+
+// TestBossEncounter_PhasesCallAdds tests that phases start as the boss is hurt,
+// and that a phase's add wave joins the fight
+func TestBossEncounter_PhasesCallAdds(t *testing.T) {
+	enc := &tower.Encounter{Phases: []tower.EncounterPhase{
+		{HPBelow: 100, Message: "The Goblin King draws his blade."},
+		{HPBelow: 50, Message: "The Goblin King calls his guard!", Adds: []tower.EncounterAdd{{Mob: "goblin", Count: 2}}},
+	}}
+	s, _, king := newEncounterTestServer(t, enc, "Alice", "Bob")
+	hall := s.world.GetRoom("hall")
+
+	s.processBossEncounters()
+	if state := s.bossEncounters[king]; state == nil || state.phase != 0 {
+		t.Fatalf("Expected the fight to start in the first phase, got %+v", state)
+	}
+	if len(hall.GetNPCs()) != 1 {
+		t.Fatal("Expected no adds before the second phase")
+	}
+
+	king.TakeMagicDamage(60)
+	s.processBossEncounters()
+	if s.bossEncounters[king].phase != 1 {
+		t.Fatalf("Expected the second phase at 40%% health, got %d", s.bossEncounters[king].phase)
+	}
+	npcs := hall.GetNPCs()
+	if len(npcs) != 3 {
+		t.Fatalf("Expected 2 goblins to join, got %d NPCs", len(npcs))
+	}
+	for _, n := range npcs {
+		if n != king && len(n.GetTargets()) != 2 {
+			t.Errorf("Expected each goblin to fight both players, got %v", n.GetTargets())
+		}
+	}
+
+	// Each phase only starts once
+	s.processBossEncounters()
+	if len(hall.GetNPCs()) != 3 {
+		t.Errorf("Expected the add wave not to repeat, got %d NPCs", len(hall.GetNPCs()))
+	}
+}
+
+// TestBossEncounter_Telegraph tests that a telegraphed attack is announced a
+// round ahead, misses players who fled and does half damage to defenders
+func TestBossEncounter_Telegraph(t *testing.T) {
+	enc := &tower.Encounter{Phases: []tower.EncounterPhase{
+		{HPBelow: 100, Telegraph: &tower.Telegraph{Name: "Royal Decree", Damage: "6d1", Windup: 1, Interval: 1}},
+	}}
+	s, players, king := newEncounterTestServer(t, enc, "Alice", "Bob", "Carol")
+	alice, bob, carol := players[0], players[1], players[2]
+	hall := s.world.GetRoom("hall")
+
+	s.processBossEncounters() // Phase starts, telegraph on a one-round timer
+	s.processBossEncounters() // Warning
+	if s.bossEncounters[king].telegraph == nil {
+		t.Fatal("Expected the Goblin King to be winding up")
+	}
+	for _, p := range players {
+		if p.GetHealth() != p.GetMaxHealth() {
+			t.Fatalf("Expected the warning not to hurt %s", p.GetName())
+		}
+	}
+
+	// Alice defends, Bob flees, Carol stands there
+	alice.Defend()
+	s.processPlayerAttack(alice)
+	if king.GetHealth() != king.GetMaxHealth() {
+		t.Error("Expected a defending player not to attack")
+	}
+	hall.RemovePlayer("Bob")
+	bob.CurrentRoom = s.world.GetRoom("corridor")
+
+	s.processBossEncounters() // It lands
+	if got := alice.GetMaxHealth() - alice.GetHealth(); got != 3 {
+		t.Errorf("Expected Alice to take half damage (3), took %d", got)
+	}
+	if bob.GetHealth() != bob.GetMaxHealth() {
+		t.Error("Expected Bob to escape the attack by fleeing")
+	}
+	if got := carol.GetMaxHealth() - carol.GetHealth(); got != 6 {
+		t.Errorf("Expected Carol to take full damage (6), took %d", got)
+	}
+	if s.bossEncounters[king].telegraph != nil {
+		t.Error("Expected the telegraph to be over once it landed")
+	}
+}
+
+// TestBossEncounter_Enrage tests that the boss hits harder once its enrage timer runs out
+func TestBossEncounter_Enrage(t *testing.T) {
+	enc := &tower.Encounter{
+		Phases: []tower.EncounterPhase{{HPBelow: 100}},
+		Enrage: &tower.EncounterEnrage{After: 0, DamagePercent: 100},
+	}
+	s, _, king := newEncounterTestServer(t, enc, "Alice")
+
+	s.processBossEncounters()
+	if !king.IsEnraged() {
+		t.Fatal("Expected the Goblin King to enrage")
+	}
+	if king.GetAttackDamage() != 20 {
+		t.Errorf("Expected double damage (20) while enraged, got %d", king.GetAttackDamage())
+	}
+}
+
+// TestBossEncounter_ResetsWhenAbandoned tests that the boss heals, calms down
+// and sends its adds away once everyone has left
+func TestBossEncounter_ResetsWhenAbandoned(t *testing.T) {
+	enc := &tower.Encounter{
+		Phases: []tower.EncounterPhase{
+			{HPBelow: 100},
+			{HPBelow: 50, Adds: []tower.EncounterAdd{{Mob: "goblin", Count: 2}}},
+		},
+		Enrage: &tower.EncounterEnrage{After: 0, DamagePercent: 50},
+	}
+	s, players, king := newEncounterTestServer(t, enc, "Alice", "Bob")
+	hall := s.world.GetRoom("hall")
+
+	king.TakeMagicDamage(60)
+	s.processBossEncounters()
+	if len(hall.GetNPCs()) != 3 {
+		t.Fatalf("Expected the adds to join, got %d NPCs", len(hall.GetNPCs()))
+	}
+
+	// Alice leaves; Bob is still fighting, so nothing resets
+	hall.RemovePlayer("Alice")
+	players[0].CurrentRoom = s.world.GetRoom("corridor")
+	s.processBossEncounters()
+	if s.bossEncounters[king] == nil {
+		t.Fatal("Expected the fight to go on while Bob is still there")
+	}
+
+	// Bob dies
+	players[1].Health = 0
+	s.processBossEncounters()
+	if s.bossEncounters[king] != nil {
+		t.Fatal("Expected the encounter to end once nobody is left fighting")
+	}
+	if king.GetHealth() != king.GetMaxHealth() || king.IsInCombat() || king.IsEnraged() {
+		t.Errorf("Expected the Goblin King to reset, got %d HP, in combat %v, enraged %v",
+			king.GetHealth(), king.IsInCombat(), king.IsEnraged())
+	}
+	if npcs := hall.GetNPCs(); len(npcs) != 1 || npcs[0] != king {
+		t.Errorf("Expected the adds to be gone, got %d NPCs", len(npcs))
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/channels"
+	"github.com/lawnchairsociety/opentowermud/server/internal/color"
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+)
+
+// SetChannelRegistry sets the chat channel registry and creates a history buffer
+// for each channel. Must be called before the server starts accepting players.
+func (s *Server) SetChannelRegistry(registry *channels.Registry) {
+	s.channelRegistry = registry
+	s.channelHistory = make(map[string]*channels.History)
+	for _, ch := range registry.All() {
+		s.channelHistory[ch.ID] = channels.NewHistory(ch.HistorySize)
+	}
+}
+
+// GetChannelRegistry returns the chat channel registry
+func (s *Server) GetChannelRegistry() *channels.Registry {
+	return s.channelRegistry
+}
+
+// SendChannelMessage records a message in the channel's history and delivers it
+// to every online player listening to the channel except the sender, respecting
+// ignore lists.
+func (s *Server) SendChannelMessage(channelID, senderName, message string) {
+	if s.channelRegistry == nil {
+		return
+	}
+	ch, ok := s.channelRegistry.Get(channelID)
+	if !ok {
+		return
+	}
+
+	if history := s.channelHistory[ch.ID]; history != nil {
+		history.Add(channels.Message{Sender: senderName, Text: message, Time: time.Now()})
+	}
+
+	s.mu.RLock()
+	var listeners []*player.Player
+	for _, p := range s.clients {
+		if p.GetName() != senderName && p.IsListeningToChannel(ch) {
+			listeners = append(listeners, p)
+		}
+	}
+	s.mu.RUnlock()
+
+	line := fmt.Sprintf("{channel}[%s] %s: \"%s\"{/}\n", ch.Name, senderName, color.Escape(message))
+	for _, listener := range listeners {
+		if listener.IsIgnoring(senderName) {
+			continue
+		}
+		listener.SendTyped(command.MessageChat, line)
+		listener.SendChannelGMCP(ch.ID, senderName, message)
+	}
+}
+
+// GetChannelHistory returns the recent messages said on a channel, oldest first.
+func (s *Server) GetChannelHistory(channelID string) []channels.Message {
+	history := s.channelHistory[channelID]
+	if history == nil {
+		return nil
+	}
+	return history.Recent()
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/channels"
+	"github.com/lawnchairsociety/opentowermud/server/internal/race"
+)
+
+// TestSendChannelMessage tests that channel messages reach only players on the
+// channel, skipping the sender, muted listeners, players ignoring the sender and
+// players whose race doesn't allow them on the channel
+func TestSendChannelMessage(t *testing.T) {
+	s, players := newPartyTestServer(t, "Alice", "Bob", "Carol", "Dave", "Erin")
+	alice, bob, carol, dave, erin := players[0], players[1], players[2], players[3], players[4]
+
+	registry := channels.NewRegistry()
+	err := registry.LoadFromConfig(&channels.ChannelsConfig{Channels: map[string]channels.ChannelDefinition{
+		"newbie": {Name: "Newbie", Default: true, History: 2},
+		"elvish": {Name: "Elvish", Default: true, Races: []string{"elf"}},
+	}})
+	if err != nil {
+		t.Fatalf("LoadFromConfig failed: %v", err)
+	}
+	s.SetChannelRegistry(registry)
+
+	newbie, _ := registry.Get("newbie")
+	carol.SetChannelMuted(newbie, true)
+	dave.AddIgnore("Alice")
+	erin.LeaveChannel(newbie)
+
+	s.SendChannelMessage("newbie", "Alice", "where is the shop?")
+
+	received := func(name, text string) bool {
+		c := s.clients[name].GetClient().(*stubClient)
+		for _, line := range c.lines {
+			if strings.Contains(line, text) {
+				return true
+			}
+		}
+		return false
+	}
+
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{"Alice", false}, // sender
+		{"Bob", true},
+		{"Carol", false}, // muted
+		{"Dave", false},  // ignoring the sender
+		{"Erin", false},  // left the channel
+	}
+	for _, tt := range tests {
+		if got := received(tt.name, "where is the shop?"); got != tt.expected {
+			t.Errorf("%s received newbie message = %v, want %v", tt.name, got, tt.expected)
+		}
+	}
+
+	// Race channels only reach that race
+	alice.SetRace(race.Elf)
+	bob.SetRace(race.Dwarf)
+	s.SendChannelMessage("elvish", "Carol", "mae govannen")
+	if received("Bob", "mae govannen") {
+		t.Error("Expected a dwarf not to hear the elvish channel")
+	}
+	if !received("Alice", "mae govannen") {
+		t.Error("Expected an elf to hear the elvish channel")
+	}
+
+	// History keeps the most recent messages per channel
+	s.SendChannelMessage("newbie", "Bob", "second")
+	s.SendChannelMessage("newbie", "Bob", "third")
+	history := s.GetChannelHistory("newbie")
+	if len(history) != 2 || history[0].Text != "second" || history[1].Text != "third" {
+		t.Errorf("Expected newbie history [second third], got %v", history)
+	}
+	if len(s.GetChannelHistory("elvish")) != 1 {
+		t.Error("Expected elvish history to be kept separately")
+	}
+}
@@ -20,3 +20,27 @@ type Client interface {
 	// RemoteAddr returns the client's address for logging.
 	RemoteAddr() string
 }
+
+// EchoController is implemented by clients that can turn off local echo
+// (e.g., telnet via the ECHO option) so passwords aren't shown as typed.
+type EchoController interface {
+	SetEcho(enabled bool) error
+}
+
+// readPassword reads a line with local echo suppressed when the client supports it.
+// Since the client won't echo the user's Enter key either, a newline is written
+// afterwards to keep the following output on its own line.
+func readPassword(client Client) (string, error) {
+	ec, ok := client.(EchoController)
+	if !ok {
+		return client.ReadLine()
+	}
+
+	ec.SetEcho(false)
+	line, err := client.ReadLine()
+	ec.SetEcho(true)
+	if err == nil {
+		client.Write([]byte("\n"))
+	}
+	return line, err
+}
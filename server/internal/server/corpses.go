@@ -0,0 +1,270 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+	"github.com/lawnchairsociety/opentowermud/server/internal/database"
+	"github.com/lawnchairsociety/opentowermud/server/internal/items"
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+)
+
+// corpseCheckInterval is how often corpses past their time decay.
+const corpseCheckInterval = 30 * time.Second
+
+// playerCorpse is a dead player's inventory left where they fell, waiting
+// for them to come back for it.
+type playerCorpse struct {
+	id          int64 // corpses row (or a local ID when there's no database)
+	characterID int64
+	ownerName   string
+	roomID      string
+	items       []*items.Item
+	expiresAt   time.Time
+}
+
+// remaining returns how long the corpse has before it decays
+func (c *playerCorpse) remaining() time.Duration {
+	return time.Until(c.expiresAt)
+}
+
+// leaveCorpse takes everything the player carries and leaves it in a corpse in
+// the room they died in. Returns the corpse, or nil if they carried nothing.
+func (s *Server) leaveCorpse(p *player.Player, roomID string, minutes int) *playerCorpse {
+	if len(p.Inventory) == 0 {
+		return nil
+	}
+	if minutes <= 0 {
+		minutes = 30
+	}
+
+	corpse := &playerCorpse{
+		characterID: p.GetCharacterID(),
+		ownerName:   p.GetName(),
+		roomID:      roomID,
+		items:       p.TakeInventory(),
+		expiresAt:   time.Now().Add(time.Duration(minutes) * time.Minute),
+	}
+
+	// Save the emptied player before the corpse exists, so a crash in between
+	// can lose the items but never leave them both carried and in the corpse.
+	// If the save fails, the player keeps everything and no corpse is left.
+	if s.db != nil && corpse.characterID != 0 {
+		if err := s.savePlayerImpl(p); err != nil {
+			logger.Error("Failed to save player before leaving corpse", "player", corpse.ownerName, "error", err)
+			for _, item := range corpse.items {
+				p.AddItem(item)
+			}
+			return nil
+		}
+	}
+
+	s.corpseMu.Lock()
+	defer s.corpseMu.Unlock()
+
+	if s.db != nil && corpse.characterID != 0 {
+		saved := &database.Corpse{
+			CharacterID: corpse.characterID,
+			OwnerName:   corpse.ownerName,
+			RoomID:      corpse.roomID,
+			ExpiresAt:   corpse.expiresAt,
+		}
+		for _, item := range corpse.items {
+			saved.Items = append(saved.Items, database.CorpseItem{ItemID: item.ID, Wear: item.Wear})
+		}
+		id, err := s.db.CreateCorpse(saved)
+		if err != nil {
+			// The corpse still lies in the world; it just won't survive a restart
+			logger.Error("Failed to save corpse", "player", corpse.ownerName, "error", err)
+		}
+		corpse.id = id
+	}
+	if corpse.id == 0 {
+		s.nextCorpseID--
+		corpse.id = s.nextCorpseID
+	}
+
+	s.corpses = append(s.corpses, corpse)
+	logger.Info("Corpse left", "player", corpse.ownerName, "room", roomID, "items", len(corpse.items))
+	return corpse
+}
+
+// savePlayerNow saves a player right away rather than waiting for the next
+// auto-save. Used when items move into or out of a corpse, so a crash can't
+// leave them in both places or neither.
+func (s *Server) savePlayerNow(p *player.Player) {
+	if s.db == nil || p.GetCharacterID() == 0 {
+		return
+	}
+	if err := s.savePlayerImpl(p); err != nil {
+		logger.Error("Failed to save player", "player", p.GetName(), "error", err)
+	}
+}
+
+// loadCorpses puts back the corpses saved in the database. Called at startup.
+// Corpses that decayed while the server was down go with the next check.
+func (s *Server) loadCorpses() {
+	if s.db == nil {
+		return
+	}
+
+	saved, err := s.db.GetCorpses()
+	if err != nil {
+		logger.Error("Failed to load corpses", "error", err)
+		return
+	}
+
+	s.corpseMu.Lock()
+	defer s.corpseMu.Unlock()
+	for _, c := range saved {
+		corpse := &playerCorpse{
+			id:          c.ID,
+			characterID: c.CharacterID,
+			ownerName:   c.OwnerName,
+			roomID:      c.RoomID,
+			expiresAt:   c.ExpiresAt,
+		}
+		for _, ci := range c.Items {
+			item := s.CreateItem(ci.ItemID)
+			if item == nil {
+				logger.Warning("Unknown item in corpse", "owner", c.OwnerName, "item_id", ci.ItemID)
+				continue
+			}
+			item.Wear = ci.Wear
+			corpse.items = append(corpse.items, item)
+		}
+		s.corpses = append(s.corpses, corpse)
+	}
+	if len(s.corpses) > 0 {
+		logger.Info("Corpses loaded", "count", len(s.corpses))
+	}
+}
+
+// startCorpseTicker runs a background ticker that decays old corpses
+func (s *Server) startCorpseTicker() {
+	s.decayCorpses()
+
+	ticker := time.NewTicker(corpseCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.shutdown:
+			return
+		case <-ticker.C:
+			s.decayCorpses()
+		}
+	}
+}
+
+// decayCorpses removes corpses past their time, along with everything in
+// them, and tells any owners who are online
+func (s *Server) decayCorpses() {
+	s.corpseMu.Lock()
+	var decayed []*playerCorpse
+	kept := s.corpses[:0]
+	for _, corpse := range s.corpses {
+		if corpse.remaining() > 0 {
+			kept = append(kept, corpse)
+		} else {
+			decayed = append(decayed, corpse)
+		}
+	}
+	s.corpses = kept
+	s.corpseMu.Unlock()
+
+	for _, corpse := range decayed {
+		s.removeSavedCorpse(corpse)
+		logger.Info("Corpse decayed", "player", corpse.ownerName, "room", corpse.roomID, "items", len(corpse.items))
+		s.BroadcastToRoom(corpse.roomID, fmt.Sprintf("\nThe corpse of {player}%s{/} crumbles to dust.\n", corpse.ownerName), nil)
+		if p := s.findOnlinePlayer(corpse.ownerName); p != nil {
+			p.SendMessage("\n{warning}Your corpse has decayed, and everything in it is lost.{/}\n")
+		}
+	}
+}
+
+// removeSavedCorpse deletes a recovered or decayed corpse from the database
+func (s *Server) removeSavedCorpse(corpse *playerCorpse) {
+	if s.db == nil || corpse.id <= 0 {
+		return
+	}
+	if err := s.db.DeleteCorpse(corpse.id); err != nil && !errors.Is(err, database.ErrCorpseGone) {
+		logger.Error("Failed to remove corpse", "player", corpse.ownerName, "error", err)
+	}
+}
+
+// GetCorpses returns the player's unrecovered corpses, oldest first
+func (s *Server) GetCorpses(name string) []command.CorpseInfo {
+	s.corpseMu.Lock()
+	defer s.corpseMu.Unlock()
+
+	var result []command.CorpseInfo
+	for _, corpse := range s.corpses {
+		if strings.EqualFold(corpse.ownerName, name) {
+			result = append(result, command.CorpseInfo{
+				RoomID:    corpse.roomID,
+				Items:     len(corpse.items),
+				Remaining: corpse.remaining(),
+			})
+		}
+	}
+	return result
+}
+
+// GetCorpseOwnersInRoom returns whose corpses lie in a room
+func (s *Server) GetCorpseOwnersInRoom(roomID string) []string {
+	s.corpseMu.Lock()
+	defer s.corpseMu.Unlock()
+
+	var owners []string
+	for _, corpse := range s.corpses {
+		if corpse.roomID == roomID {
+			owners = append(owners, corpse.ownerName)
+		}
+	}
+	return owners
+}
+
+// RecoverCorpse gives the player back everything in their corpses in the
+// room they're standing in, and returns the items recovered
+func (s *Server) RecoverCorpse(name string) ([]*items.Item, error) {
+	p := s.findOnlinePlayer(name)
+	if p == nil || p.CurrentRoom == nil {
+		return nil, errors.New("You are not online.")
+	}
+	roomID := p.CurrentRoom.GetID()
+
+	s.corpseMu.Lock()
+	var recovered []*playerCorpse
+	kept := s.corpses[:0]
+	for _, corpse := range s.corpses {
+		if corpse.roomID == roomID && strings.EqualFold(corpse.ownerName, name) {
+			recovered = append(recovered, corpse)
+		} else {
+			kept = append(kept, corpse)
+		}
+	}
+	s.corpses = kept
+	s.corpseMu.Unlock()
+
+	if len(recovered) == 0 {
+		return nil, errors.New("Your corpse isn't here. Type 'corpse' to find it.")
+	}
+
+	var taken []*items.Item
+	for _, corpse := range recovered {
+		s.removeSavedCorpse(corpse)
+		for _, item := range corpse.items {
+			// Carry weight is ignored: it was all on you when you died
+			p.AddItem(item)
+			taken = append(taken, item)
+		}
+		logger.Info("Corpse recovered", "player", corpse.ownerName, "room", roomID, "items", len(corpse.items))
+	}
+	s.savePlayerNow(p)
+	return taken, nil
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+	"github.com/lawnchairsociety/opentowermud/server/internal/world"
+)
+
+// applyDeathPenalties applies the server's death policy to a player who just
+// died in a room, and returns what they lost for the death message
+func (s *Server) applyDeathPenalties(p *player.Player, room *world.Room) []string {
+	policy := s.GetServerConfig().Death
+	var lost []string
+
+	if debt := p.IncurXPDebt(policy.XPDebtPercent); debt > 0 {
+		lost = append(lost, fmt.Sprintf("You owe {warning}%d experience{/}. Half of all experience you earn will go to paying it off.", debt))
+	}
+
+	if policy.GoldLossPercent > 0 {
+		if gold := p.GetGold() * clampPercent(policy.GoldLossPercent) / 100; gold > 0 {
+			p.SetGold(p.GetGold() - gold)
+			lost = append(lost, fmt.Sprintf("You dropped {gold}%d gold{/} as you fell.", gold))
+		}
+	}
+
+	if policy.DurabilityLossPercent > 0 && len(p.Equipment) > 0 {
+		broken := p.WearEquipment(policy.DurabilityLossPercent)
+		msg := "Your equipment is battered by the fall."
+		if len(broken) > 0 {
+			names := make([]string, len(broken))
+			for i, item := range broken {
+				names[i] = "{item}" + item.Name + "{/}"
+			}
+			msg += fmt.Sprintf(" {warning}Broken:{/} %s. Have it repaired at a shop.", strings.Join(names, ", "))
+		}
+		lost = append(lost, msg)
+	}
+
+	if policy.Corpse {
+		if corpse := s.leaveCorpse(p, room.GetID(), policy.CorpseMinutes); corpse != nil {
+			lost = append(lost, fmt.Sprintf("Your belongings lie with your corpse in %s. Recover them within %d minutes or they are lost. Type 'corpse' to find it.",
+				room.Name, int(corpse.remaining().Minutes()+0.5)))
+		}
+	}
+
+	if len(lost) > 0 {
+		logger.Info("Death penalties applied", "player", p.GetName(), "room", room.GetID(), "penalties", len(lost))
+	}
+	return lost
+}
+
+// clampPercent keeps a configured percentage between 0 and 100
+func clampPercent(percent int) int {
+	if percent < 0 {
+		return 0
+	}
+	if percent > 100 {
+		return 100
+	}
+	return percent
+}
@@ -0,0 +1,242 @@
+package server
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+	"github.com/lawnchairsociety/opentowermud/server/internal/config"
+	"github.com/lawnchairsociety/opentowermud/server/internal/items"
+	"github.com/lawnchairsociety/opentowermud/server/internal/leveling"
+	"github.com/lawnchairsociety/opentowermud/server/internal/npc"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+)
+
+// setDeathPolicy gives the server a death policy
+func setDeathPolicy(s *Server, policy config.DeathConfig) {
+	cfg := config.DefaultConfig()
+	cfg.Death = policy
+	s.SetServerConfig(cfg)
+}
+
+// equipTestGear gives a player a sword and a potion to carry and a helmet to wear
+func equipTestGear(p *player.Player) *items.Item {
+	p.AddItem(items.NewWeapon("long sword", "A sword.", 5, 50, 6, false))
+	p.AddItem(items.NewConsumable("healing potion", "A potion.", 1, items.Potion, 10, 20, 0))
+	helmet := items.NewArmor("iron helmet", "A helmet.", 3, 100, 2, items.SlotHead)
+	p.Equipment[items.SlotHead] = helmet
+	return helmet
+}
+
+// TestDeath_NoPenaltiesByDefault tests that with the default policy a death
+// costs nothing but the walk back
+func TestDeath_NoPenaltiesByDefault(t *testing.T) {
+	s, players := newPartyTestServer(t, "Alice")
+	alice := players[0]
+	helmet := equipTestGear(alice)
+	alice.SetGold(100)
+	xp := alice.GetExperience()
+
+	s.killPlayer(alice, "a goblin", s.world.GetRoom("corridor"))
+
+	if alice.GetGold() != 100 || alice.GetXPDebt() != 0 || helmet.Wear != 0 {
+		t.Errorf("Expected no penalties, got gold %d, debt %d, wear %d", alice.GetGold(), alice.GetXPDebt(), helmet.Wear)
+	}
+	if len(alice.Inventory) != 2 || len(s.GetCorpses("Alice")) != 0 {
+		t.Error("Expected Alice to keep her inventory and leave no corpse")
+	}
+	if alice.GetExperience() != xp || alice.GetHealth() != alice.GetMaxHealth() {
+		t.Error("Expected Alice to respawn at full health with her XP")
+	}
+}
+
+// TestDeath_Penalties tests XP debt, gold loss and wear, and that the debt is
+// paid off from later experience
+func TestDeath_Penalties(t *testing.T) {
+	s, players := newPartyTestServer(t, "Alice")
+	alice := players[0]
+	helmet := equipTestGear(alice)
+	helmet.Wear = 90
+	alice.SetGold(100)
+	setDeathPolicy(s, config.DeathConfig{XPDebtPercent: 10, GoldLossPercent: 25, DurabilityLossPercent: 20})
+
+	s.killPlayer(alice, "a goblin", s.world.GetRoom("corridor"))
+
+	wantDebt := (leveling.XPForLevel(2) - leveling.XPForLevel(1)) / 10
+	if alice.GetXPDebt() != wantDebt {
+		t.Errorf("Expected %d XP debt, got %d", wantDebt, alice.GetXPDebt())
+	}
+	if alice.GetGold() != 75 {
+		t.Errorf("Expected Alice to lose a quarter of her gold (75 left), got %d", alice.GetGold())
+	}
+	if !helmet.IsBroken() {
+		t.Errorf("Expected the worn helmet to break, wear is %d", helmet.Wear)
+	}
+	if ac := alice.GetEffectiveArmor(); ac != 0 {
+		t.Errorf("Expected a broken helmet to give no armor, got %d", ac)
+	}
+
+	alice.GainExperience(10)
+	if alice.GetExperience() != 5 || alice.GetXPDebt() != wantDebt-5 {
+		t.Errorf("Expected half of 10 XP to pay the debt, got %d XP and %d debt", alice.GetExperience(), alice.GetXPDebt())
+	}
+	if score := command.ParseCommand("score").Execute(alice, s.world); !strings.Contains(score, "XP Debt") {
+		t.Errorf("Expected score to show the XP debt, got:\n%s", score)
+	}
+}
+
+// TestDeath_CorpseRun tests that the inventory is left in a corpse that only
+// its owner can recover, and only where they fell
+func TestDeath_CorpseRun(t *testing.T) {
+	s, players := newPartyTestServer(t, "Alice", "Bob")
+	alice, bob := players[0], players[1]
+	equipTestGear(alice)
+	setDeathPolicy(s, config.DeathConfig{Corpse: true, CorpseMinutes: 10})
+	corridor := s.world.GetRoom("corridor")
+
+	s.killPlayer(alice, "a goblin", corridor)
+
+	if len(alice.Inventory) != 0 {
+		t.Fatalf("Expected Alice's inventory to be left in her corpse, still carrying %d", len(alice.Inventory))
+	}
+	if alice.Equipment[items.SlotHead] == nil {
+		t.Error("Expected Alice to keep her equipment")
+	}
+
+	listing := command.ParseCommand("corpse").Execute(alice, s.world)
+	if !strings.Contains(listing, "corridor") || !strings.Contains(listing, "2 items") {
+		t.Errorf("Expected the corpse locator to show the corridor, got:\n%s", listing)
+	}
+
+	if _, err := s.RecoverCorpse("Alice"); err == nil {
+		t.Error("Expected recovery from the respawn room to fail")
+	}
+
+	bob.MoveTo(corridor)
+	if look := command.ParseCommand("look").Execute(bob, s.world); !strings.Contains(look, "corpse of {player}Alice") {
+		t.Errorf("Expected the corpse to show in the room, got:\n%s", look)
+	}
+	if _, err := s.RecoverCorpse("Bob"); err == nil {
+		t.Error("Expected Bob not to be able to loot Alice's corpse")
+	}
+
+	alice.MoveTo(corridor)
+	result := command.ParseCommand("corpse recover").Execute(alice, s.world)
+	if !strings.Contains(result, "long sword") || len(alice.Inventory) != 2 {
+		t.Errorf("Expected Alice to recover both items, got %q with %d carried", result, len(alice.Inventory))
+	}
+	if len(s.GetCorpses("Alice")) != 0 {
+		t.Error("Expected the recovered corpse to be gone")
+	}
+}
+
+// TestDeath_CorpseDecays tests that an unrecovered corpse decays with its items
+func TestDeath_CorpseDecays(t *testing.T) {
+	s, players := newPartyTestServer(t, "Alice")
+	alice := players[0]
+	equipTestGear(alice)
+	setDeathPolicy(s, config.DeathConfig{Corpse: true, CorpseMinutes: 10})
+
+	s.killPlayer(alice, "a goblin", s.world.GetRoom("corridor"))
+	s.corpses[0].expiresAt = time.Now().Add(-time.Second)
+	s.decayCorpses()
+
+	if len(s.GetCorpses("Alice")) != 0 || len(s.GetCorpseOwnersInRoom("corridor")) != 0 {
+		t.Error("Expected the corpse to decay")
+	}
+}
+
+// TestDeath_CorpseSurvivesRestart tests that corpses and worn gear are saved
+// and come back when the server starts again
+func TestDeath_CorpseSurvivesRestart(t *testing.T) {
+	s, players := newDBTestServer(t, "Alice")
+	alice := players[0]
+	alice.AddItem(s.CreateItem("long_sword"))
+	alice.Inventory[0].Wear = 30
+	setDeathPolicy(s, config.DeathConfig{Corpse: true, CorpseMinutes: 10})
+
+	s.killPlayer(alice, "a goblin", s.world.GetRoom("corridor"))
+
+	// A fresh server on the same database, as after a restart
+	restarted, _ := newPartyTestServer(t)
+	restarted.SetDatabase(s.db)
+	restarted.SetItemsConfig(s.itemsConfig)
+	restarted.loadCorpses()
+
+	corpses := restarted.GetCorpses("Alice")
+	if len(corpses) != 1 || corpses[0].RoomID != "corridor" || corpses[0].Items != 1 {
+		t.Fatalf("Expected Alice's corpse in the corridor after a restart, got %+v", corpses)
+	}
+	if corpses[0].Remaining <= 9*time.Minute {
+		t.Errorf("Expected the corpse to keep its decay time, %v left", corpses[0].Remaining)
+	}
+	if wear := restarted.corpses[0].items[0].Wear; wear != 30 {
+		t.Errorf("Expected the sword to keep its wear, got %d", wear)
+	}
+}
+
+// TestDeath_CorpseSavedAfterPlayer tests that the player is saved without their
+// belongings before the corpse holding them is, and that no corpse is left if
+// that save fails
+func TestDeath_CorpseSavedAfterPlayer(t *testing.T) {
+	s, players := newDBTestServer(t, "Alice")
+	alice := players[0]
+	alice.AddItem(s.CreateItem("long_sword"))
+
+	if corpse := s.leaveCorpse(alice, "corridor", 10); corpse == nil {
+		t.Fatal("Expected a corpse")
+	}
+	if saved, err := s.db.LoadInventory(alice.GetCharacterID()); err != nil || len(saved) != 0 {
+		t.Errorf("Expected Alice to be saved with nothing carried, got %v (%v)", saved, err)
+	}
+
+	// With the database gone, nothing can be saved: Alice keeps everything
+	alice.AddItem(s.CreateItem("long_sword"))
+	s.db.Close()
+	if corpse := s.leaveCorpse(alice, "corridor", 10); corpse != nil {
+		t.Error("Expected no corpse when the player can't be saved")
+	}
+	if len(alice.Inventory) != 1 || len(s.GetCorpses("Alice")) != 1 {
+		t.Errorf("Expected Alice to keep the sword and only the first corpse to exist, carrying %d", len(alice.Inventory))
+	}
+}
+
+// addTradeHouse turns the town square into a place to bank, auction and use
+// the guild bank: a banker, an auctioneer and a mailbox
+func addTradeHouse(s *Server) {
+	square := s.world.GetRoom("town_square")
+	square.AddFeature("mailbox")
+	banker := npc.NewNPC("banker", "A banker.", 1, 10, 1, 0, 0, false, false, "town_square", 0, 0)
+	banker.SetBanker(true)
+	square.AddNPC(banker)
+	auctioneer := npc.NewNPC("auctioneer", "An auctioneer.", 1, 10, 1, 0, 0, false, false, "town_square", 0, 0)
+	auctioneer.SetAuctioneer(true)
+	square.AddNPC(auctioneer)
+}
+
+// TestDeath_WornGearCantBeStored tests that worn gear can't go anywhere that
+// only remembers the item and not its wear, which would repair it for free
+func TestDeath_WornGearCantBeStored(t *testing.T) {
+	s, players := newDBTestServer(t, "Alice")
+	alice := players[0]
+	addTradeHouse(s)
+	if _, err := s.db.CreateGuild("Iron Vanguard", "IRON", alice.GetCharacterID(), "Alice"); err != nil {
+		t.Fatalf("Failed to create guild: %v", err)
+	}
+	sword := s.CreateItem("long_sword")
+	sword.Wear = 30
+	alice.AddItem(sword)
+
+	for _, input := range []string{"deposit long sword", "guild deposit long sword", "auction sell long sword 100", "stall add long sword 40"} {
+		result := command.ParseCommand(input).Execute(alice, s.world)
+		if !strings.Contains(result, "repaired") || len(alice.Inventory) != 1 {
+			t.Errorf("Expected %q to refuse the worn sword, got %q", input, result)
+		}
+	}
+
+	sword.Wear = 0
+	if result := command.ParseCommand("deposit long sword").Execute(alice, s.world); len(alice.Inventory) != 0 {
+		t.Errorf("Expected the repaired sword to be deposited, got %q", result)
+	}
+}
@@ -0,0 +1,400 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+	"github.com/lawnchairsociety/opentowermud/server/internal/duel"
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+	"github.com/lawnchairsociety/opentowermud/server/internal/world"
+)
+
+// duelChallengeTimeout is how long a duel challenge waits for an answer.
+const duelChallengeTimeout = 60 * time.Second
+
+// duelChallenge is a challenge waiting for the target to accept or decline.
+type duelChallenge struct {
+	from    string
+	expires time.Time
+}
+
+// duelMatch is a duel in progress. Nobody dies in a duel: the first fighter
+// beaten down to 1 HP loses.
+type duelMatch struct {
+	fighters [2]string // Challenger first
+	ids      [2]int64  // Character IDs, for ranked results
+	roomID   string
+	ranked   bool // Fought in an arena, so ratings change
+}
+
+// opponent returns the other fighter's name.
+func (m *duelMatch) opponent(name string) string {
+	if strings.EqualFold(m.fighters[0], name) {
+		return m.fighters[1]
+	}
+	return m.fighters[0]
+}
+
+// duelOf returns the duel a player is fighting, or nil. Caller must hold duelMu.
+func (s *Server) duelOf(name string) *duelMatch {
+	return s.duels[strings.ToLower(name)]
+}
+
+// pendingChallenge returns the open challenge to a player, dropping it if it
+// has expired. Caller must hold duelMu.
+func (s *Server) pendingChallenge(name string) (duelChallenge, bool) {
+	challenge, ok := s.duelChallenges[strings.ToLower(name)]
+	if ok && time.Now().After(challenge.expires) {
+		delete(s.duelChallenges, strings.ToLower(name))
+		return duelChallenge{}, false
+	}
+	return challenge, ok
+}
+
+// checkDuelReady returns an error if a player can't start a duel right now.
+// you is true when the player is the one typing the command.
+func checkDuelReady(p *player.Player, you bool) error {
+	if p.IsInCombat() {
+		if you {
+			return errors.New("You are already fighting!")
+		}
+		return fmt.Errorf("%s is busy fighting.", p.GetName())
+	}
+	if p.GetHealth() <= 1 {
+		if you {
+			return errors.New("You are too badly hurt to duel.")
+		}
+		return fmt.Errorf("%s is too badly hurt to duel.", p.GetName())
+	}
+	return nil
+}
+
+// ChallengeDuel challenges another player in the same room to a duel. If they
+// already challenged the challenger, the duel starts instead.
+// Returns true if the duel started.
+func (s *Server) ChallengeDuel(fromName, toName string) (bool, error) {
+	if s.pilgrimMode {
+		return false, errors.New("This server is in pilgrim mode - exploration only!")
+	}
+	from := s.findOnlinePlayer(fromName)
+	if from == nil {
+		return false, errors.New("You are not online.")
+	}
+	to := s.findOnlinePlayer(toName)
+	if to == nil || !sameRoom(from, to) {
+		return false, fmt.Errorf("%s is not here.", toName)
+	}
+	if to == from {
+		return false, errors.New("You can't duel yourself.")
+	}
+	if err := checkDuelReady(from, true); err != nil {
+		return false, err
+	}
+	if err := checkDuelReady(to, false); err != nil {
+		return false, err
+	}
+	fromName, toName = from.GetName(), to.GetName()
+
+	s.duelMu.Lock()
+	if s.duelOf(fromName) != nil {
+		s.duelMu.Unlock()
+		return false, errors.New("You are already in a duel.")
+	}
+	if s.duelOf(toName) != nil {
+		s.duelMu.Unlock()
+		return false, fmt.Errorf("%s is already in a duel.", toName)
+	}
+
+	// They challenged first: the duel starts
+	if challenge, ok := s.pendingChallenge(fromName); ok && strings.EqualFold(challenge.from, toName) {
+		delete(s.duelChallenges, strings.ToLower(fromName))
+		arena := from.CurrentRoom.Type == world.RoomTypeArena
+		// Beating your own alt would be free rating, so it doesn't count
+		sameAccount := from.GetAccountID() != 0 && from.GetAccountID() == to.GetAccountID()
+		m := &duelMatch{
+			fighters: [2]string{toName, fromName},
+			ids:      [2]int64{to.GetCharacterID(), from.GetCharacterID()},
+			roomID:   from.CurrentRoom.GetID(),
+			ranked:   arena && !sameAccount,
+		}
+		if s.duels == nil {
+			s.duels = make(map[string]*duelMatch)
+		}
+		s.duels[strings.ToLower(fromName)] = m
+		s.duels[strings.ToLower(toName)] = m
+		s.duelMu.Unlock()
+
+		logger.Info("Duel started", "challenger", toName, "opponent", fromName, "room", m.roomID, "ranked", m.ranked)
+		kind := "a duel"
+		if m.ranked {
+			kind = "a ranked duel"
+		}
+		s.BroadcastToRoom(m.roomID, fmt.Sprintf("\n{player}%s{/} and {player}%s{/} square off for %s!\n", toName, fromName, kind), nil)
+		if arena && sameAccount {
+			for _, p := range []*player.Player{from, to} {
+				p.SendMessage("Characters on the same account can't duel for rating, so this duel is unranked.\n")
+			}
+		}
+		return true, nil
+	}
+
+	if s.duelChallenges == nil {
+		s.duelChallenges = make(map[string]duelChallenge)
+	}
+	s.duelChallenges[strings.ToLower(toName)] = duelChallenge{from: fromName, expires: time.Now().Add(duelChallengeTimeout)}
+	s.duelMu.Unlock()
+
+	// Don't reveal an ignore - the challenge just never arrives
+	if !to.IsIgnoring(fromName) {
+		to.SendMessage(fmt.Sprintf("\n{player}%s{/} challenges you to a duel! Type 'duel accept' to fight or 'duel decline' to refuse.\n", fromName))
+	}
+	return false, nil
+}
+
+// AcceptDuel accepts the open challenge to a player and starts the duel.
+// Returns the challenger's name.
+func (s *Server) AcceptDuel(name string) (string, error) {
+	s.duelMu.Lock()
+	challenge, ok := s.pendingChallenge(name)
+	s.duelMu.Unlock()
+	if !ok {
+		return "", errors.New("Nobody has challenged you to a duel.")
+	}
+
+	if _, err := s.ChallengeDuel(name, challenge.from); err != nil {
+		return "", err
+	}
+	return challenge.from, nil
+}
+
+// DeclineDuel refuses the open challenge to a player.
+// Returns the challenger's name.
+func (s *Server) DeclineDuel(name string) (string, error) {
+	s.duelMu.Lock()
+	challenge, ok := s.pendingChallenge(name)
+	delete(s.duelChallenges, strings.ToLower(name))
+	s.duelMu.Unlock()
+	if !ok {
+		return "", errors.New("Nobody has challenged you to a duel.")
+	}
+
+	if challenger := s.findOnlinePlayer(challenge.from); challenger != nil {
+		challenger.SendMessage(fmt.Sprintf("\n{player}%s{/} declines your challenge.\n", name))
+	}
+	return challenge.from, nil
+}
+
+// YieldDuel gives up the player's duel. Their opponent wins.
+func (s *Server) YieldDuel(name string) error {
+	s.duelMu.Lock()
+	m := s.duelOf(name)
+	s.duelMu.Unlock()
+	if m == nil {
+		return errors.New("You aren't in a duel.")
+	}
+
+	p := s.findOnlinePlayer(name)
+	if p != nil {
+		name = p.GetName()
+	}
+	winner := m.opponent(name)
+	s.endDuel(m, winner, name, fmt.Sprintf("\n{player}%s{/} yields. {player}%s{/} wins the duel!\n", name, winner))
+	return nil
+}
+
+// GetDuelOpponent returns who a player is dueling, or false if they aren't.
+func (s *Server) GetDuelOpponent(name string) (string, bool) {
+	s.duelMu.Lock()
+	defer s.duelMu.Unlock()
+	m := s.duelOf(name)
+	if m == nil {
+		return "", false
+	}
+	return m.opponent(name), true
+}
+
+// isDueling returns true if the player is in a duel.
+func (s *Server) isDueling(p *player.Player) bool {
+	_, dueling := s.GetDuelOpponent(p.GetName())
+	return dueling
+}
+
+// processDuels runs one round of every duel in progress. Called from the combat ticker.
+func (s *Server) processDuels() {
+	s.duelMu.Lock()
+	seen := make(map[*duelMatch]bool)
+	var matches []*duelMatch
+	for _, m := range s.duels {
+		if !seen[m] {
+			seen[m] = true
+			matches = append(matches, m)
+		}
+	}
+	s.duelMu.Unlock()
+
+	for _, m := range matches {
+		s.processDuelRound(m)
+	}
+}
+
+// processDuelRound has both fighters attack each other once, in random order.
+// The duel ends when a fighter is beaten down to 1 HP or leaves the room.
+func (s *Server) processDuelRound(m *duelMatch) {
+	fighters := [2]*player.Player{s.findOnlinePlayer(m.fighters[0]), s.findOnlinePlayer(m.fighters[1])}
+	for i, p := range fighters {
+		if p == nil || p.CurrentRoom == nil || p.CurrentRoom.GetID() != m.roomID {
+			loser := m.fighters[i]
+			winner := m.opponent(loser)
+			s.endDuel(m, winner, loser, fmt.Sprintf("\n{player}%s{/} leaves the fight. {player}%s{/} wins the duel!\n", loser, winner))
+			return
+		}
+	}
+	for _, p := range fighters {
+		if p.IsInCombat() {
+			s.cancelDuel(m, "\nThe duel is interrupted by the fighting!\n")
+			return
+		}
+	}
+
+	a, b := fighters[0], fighters[1]
+
+	if rand.Intn(2) == 1 {
+		a, b = b, a
+	}
+	if s.duelAttack(a, b) {
+		s.endDuel(m, a.GetName(), b.GetName(), fmt.Sprintf("\n{player}%s{/} is beaten to their knees. {player}%s{/} wins the duel!\n", b.GetName(), a.GetName()))
+		return
+	}
+	if s.duelAttack(b, a) {
+		s.endDuel(m, b.GetName(), a.GetName(), fmt.Sprintf("\n{player}%s{/} is beaten to their knees. {player}%s{/} wins the duel!\n", a.GetName(), b.GetName()))
+	}
+}
+
+// duelAttack makes one attack in a duel using the same d20 roll against armor
+// class as fighting an NPC. Damage never takes the defender below 1 HP.
+// Returns true if the defender is beaten.
+func (s *Server) duelAttack(attacker, defender *player.Player) bool {
+	attackRoll, attackBreakdown := attacker.RollAttack()
+	defenderAC := defender.GetArmorClass()
+
+	attackVerb := "swing at"
+	attackVerbThirdPerson := "swings at"
+	if attacker.HasRangedWeapon() {
+		attackVerb = "shoot at"
+		attackVerbThirdPerson = "shoots at"
+	}
+
+	if attackRoll < defenderAC {
+		attacker.SendTyped(command.MessageCombat, fmt.Sprintf("\nYou %s {player}%s{/}... (%s vs AC %d) Miss!\n",
+			attackVerb, defender.GetName(), attackBreakdown, defenderAC))
+		defender.SendTyped(command.MessageCombat, fmt.Sprintf("\n{player}%s{/} %s you and misses!\n",
+			attacker.GetName(), attackVerbThirdPerson))
+		return false
+	}
+
+	damage := defender.TakeDamage(attacker.GetAttackDamageAgainst(nil, false))
+	if defender.Health < 1 {
+		damage -= 1 - defender.Health
+		defender.Health = 1
+	}
+
+	logger.Debug("Duel hit",
+		"attacker", attacker.GetName(),
+		"defender", defender.GetName(),
+		"roll", attackRoll,
+		"defender_ac", defenderAC,
+		"damage", damage,
+		"defender_hp", defender.GetHealth())
+
+	attacker.SendTyped(command.MessageCombat, fmt.Sprintf("\nYou %s {player}%s{/}... (%s vs AC %d) Hit!\nYou deal {damage}%d{/} damage! (%d/%d HP)\n",
+		attackVerb, defender.GetName(), attackBreakdown, defenderAC, damage, defender.GetHealth(), defender.GetMaxHealth()))
+	defender.SendTyped(command.MessageCombat, fmt.Sprintf("\n{player}%s{/} hits you for {damage}%d{/} damage!\n",
+		attacker.GetName(), damage))
+	return defender.GetHealth() <= 1
+}
+
+// closeDuel removes a duel from both fighters. Returns false if it had already
+// ended.
+func (s *Server) closeDuel(m *duelMatch) bool {
+	s.duelMu.Lock()
+	defer s.duelMu.Unlock()
+	if s.duelOf(m.fighters[0]) != m {
+		return false
+	}
+	for _, name := range m.fighters {
+		delete(s.duels, strings.ToLower(name))
+	}
+	return true
+}
+
+// announceDuel tells the room, and any fighter who has left it, how a duel ended.
+func (s *Server) announceDuel(m *duelMatch, message string) {
+	s.BroadcastToRoom(m.roomID, message, nil)
+	for _, name := range m.fighters {
+		if p := s.findOnlinePlayer(name); p != nil && (p.CurrentRoom == nil || p.CurrentRoom.GetID() != m.roomID) {
+			p.SendMessage(message)
+		}
+	}
+}
+
+// cancelDuel ends a duel without a winner.
+func (s *Server) cancelDuel(m *duelMatch, message string) {
+	if !s.closeDuel(m) {
+		return
+	}
+	logger.Info("Duel cancelled", "fighter1", m.fighters[0], "fighter2", m.fighters[1])
+	s.announceDuel(m, message)
+}
+
+// endDuel ends a duel with a winner. Ranked duels update both fighters' ratings.
+func (s *Server) endDuel(m *duelMatch, winner, loser, message string) {
+	if !s.closeDuel(m) {
+		return
+	}
+	logger.Info("Duel ended", "winner", winner, "loser", loser, "ranked", m.ranked)
+	s.announceDuel(m, message)
+
+	if !m.ranked || s.db == nil {
+		return
+	}
+	winnerID, loserID := m.ids[0], m.ids[1]
+	if strings.EqualFold(winner, m.fighters[1]) {
+		winnerID, loserID = loserID, winnerID
+	}
+	winnerRating, loserRating, err := s.db.RecordDuelResult(winnerID, loserID)
+	if err != nil {
+		logger.Error("Failed to record duel result", "winner", winner, "loser", loser, "error", err)
+		return
+	}
+	for _, r := range []*duel.Rating{winnerRating, loserRating} {
+		if p := s.findOnlinePlayer(r.Name); p != nil {
+			p.SendMessage(fmt.Sprintf("Your duel rating is now {gold}%d{/} (%d won, %d lost).\n", r.Rating, r.Wins, r.Losses))
+		}
+	}
+}
+
+// removeFromDuel forfeits a player's duel and drops any challenges to or from
+// them. Called when a player leaves the game.
+func (s *Server) removeFromDuel(p *player.Player) {
+	name := p.GetName()
+
+	s.duelMu.Lock()
+	delete(s.duelChallenges, strings.ToLower(name))
+	for target, challenge := range s.duelChallenges {
+		if strings.EqualFold(challenge.from, name) {
+			delete(s.duelChallenges, target)
+		}
+	}
+	m := s.duelOf(name)
+	s.duelMu.Unlock()
+
+	if m != nil {
+		winner := m.opponent(name)
+		s.endDuel(m, winner, name, fmt.Sprintf("\n{player}%s{/} has left the game. {player}%s{/} wins the duel!\n", name, winner))
+	}
+}
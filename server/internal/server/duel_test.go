@@ -0,0 +1,165 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/duel"
+	"github.com/lawnchairsociety/opentowermud/server/internal/world"
+)
+
+// startDuel has Alice challenge Bob and Bob accept.
+func startDuel(t *testing.T, s *Server) {
+	t.Helper()
+	if started, err := s.ChallengeDuel("Alice", "Bob"); err != nil || started {
+		t.Fatalf("ChallengeDuel(Alice, Bob) = %v, %v; want a pending challenge", started, err)
+	}
+	if challenger, err := s.AcceptDuel("Bob"); err != nil || challenger != "Alice" {
+		t.Fatalf("AcceptDuel(Bob) = %q, %v; want Alice's duel to start", challenger, err)
+	}
+}
+
+// TestDuel_FightsToOneHP tests that duel rounds use the normal attack rolls but
+// never take a fighter below 1 HP, and that the beaten fighter loses
+func TestDuel_FightsToOneHP(t *testing.T) {
+	s, players := newDBTestServer(t, "Alice", "Bob")
+	alice, bob := players[0], players[1]
+	alice.Health, bob.Health = 6, 6
+
+	startDuel(t, s)
+	if opponent, ok := s.GetDuelOpponent("Bob"); !ok || opponent != "Alice" {
+		t.Fatalf("GetDuelOpponent(Bob) = %q, %v; want Alice", opponent, ok)
+	}
+
+	for round := 0; round < 500 && s.isDueling(alice); round++ {
+		s.processDuels()
+	}
+	if s.isDueling(alice) || s.isDueling(bob) {
+		t.Fatal("Expected the duel to end")
+	}
+	if alice.GetHealth() < 1 || bob.GetHealth() < 1 {
+		t.Errorf("Expected both fighters to survive, got Alice %d HP and Bob %d HP", alice.GetHealth(), bob.GetHealth())
+	}
+	if alice.GetHealth() != 1 && bob.GetHealth() != 1 {
+		t.Errorf("Expected the loser to be left at 1 HP, got Alice %d HP and Bob %d HP", alice.GetHealth(), bob.GetHealth())
+	}
+	if alice.IsInCombat() || bob.IsInCombat() {
+		t.Error("Expected a duel not to put the fighters into NPC combat")
+	}
+
+	// The town square isn't an arena, so nothing is ranked
+	if board, _ := s.db.GetDuelLeaderboard(10); len(board) != 0 {
+		t.Errorf("Expected an unranked duel to leave the leaderboard empty, got %+v", board)
+	}
+}
+
+// TestDuel_RankedInArena tests that a duel fought in an arena changes both ratings
+func TestDuel_RankedInArena(t *testing.T) {
+	s, players := newDBTestServer(t, "Alice", "Bob")
+	alice, bob := players[0], players[1]
+	s.world.GetRoom("town_square").Type = world.RoomTypeArena
+
+	startDuel(t, s)
+	if err := s.YieldDuel("Bob"); err != nil {
+		t.Fatalf("YieldDuel failed: %v", err)
+	}
+	if err := s.YieldDuel("Bob"); err == nil {
+		t.Error("Expected yielding an ended duel to fail")
+	}
+
+	winner, loser := duel.Adjust(duel.DefaultRating, duel.DefaultRating)
+	aliceRating, _ := s.db.GetDuelRating(alice.GetCharacterID())
+	bobRating, _ := s.db.GetDuelRating(bob.GetCharacterID())
+	if aliceRating.Rating != winner || aliceRating.Wins != 1 {
+		t.Errorf("Expected Alice at %d with 1 win, got %+v", winner, aliceRating)
+	}
+	if bobRating.Rating != loser || bobRating.Losses != 1 {
+		t.Errorf("Expected Bob at %d with 1 loss, got %+v", loser, bobRating)
+	}
+}
+
+// TestDuel_SameAccountUnranked tests that an arena duel between two characters
+// on the same account leaves both ratings alone
+func TestDuel_SameAccountUnranked(t *testing.T) {
+	s, players := newDBTestServer(t, "Alice", "Bob")
+	alice, bob := players[0], players[1]
+	s.world.GetRoom("town_square").Type = world.RoomTypeArena
+	bob.SetAccountID(alice.GetAccountID())
+
+	startDuel(t, s)
+	if err := s.YieldDuel("Bob"); err != nil {
+		t.Fatalf("YieldDuel failed: %v", err)
+	}
+
+	if board, _ := s.db.GetDuelLeaderboard(10); len(board) != 0 {
+		t.Errorf("Expected a duel against an alt to leave the leaderboard empty, got %+v", board)
+	}
+}
+
+// TestDuel_Forfeits tests that leaving the room or the game forfeits a duel
+func TestDuel_Forfeits(t *testing.T) {
+	s, players := newDBTestServer(t, "Alice", "Bob")
+	alice, bob := players[0], players[1]
+	s.world.GetRoom("town_square").Type = world.RoomTypeArena
+
+	startDuel(t, s)
+	bob.CurrentRoom = s.world.GetRoom("hall")
+	s.processDuels()
+	if s.isDueling(alice) {
+		t.Fatal("Expected leaving the room to end the duel")
+	}
+	if r, _ := s.db.GetDuelRating(bob.GetCharacterID()); r.Losses != 1 {
+		t.Errorf("Expected Bob to lose by leaving, got %+v", r)
+	}
+
+	bob.CurrentRoom = s.world.GetRoom("town_square")
+	startDuel(t, s)
+	s.removeFromDuel(alice)
+	if s.isDueling(bob) {
+		t.Fatal("Expected logging out to end the duel")
+	}
+	if r, _ := s.db.GetDuelRating(bob.GetCharacterID()); r.Wins != 1 {
+		t.Errorf("Expected Bob to win when Alice logs out, got %+v", r)
+	}
+}
+
+// TestDuel_Challenges tests the checks made before a duel starts
+func TestDuel_Challenges(t *testing.T) {
+	s, players := newDBTestServer(t, "Alice", "Bob", "Carol")
+	bob, carol := players[1], players[2]
+	carol.CurrentRoom = s.world.GetRoom("hall")
+
+	if _, err := s.ChallengeDuel("Alice", "Alice"); err == nil {
+		t.Error("Expected challenging yourself to fail")
+	}
+	if _, err := s.ChallengeDuel("Alice", "Carol"); err == nil {
+		t.Error("Expected challenging a player in another room to fail")
+	}
+	if _, err := s.AcceptDuel("Bob"); err == nil {
+		t.Error("Expected accepting without a challenge to fail")
+	}
+
+	bob.Health = 1
+	if _, err := s.ChallengeDuel("Alice", "Bob"); err == nil {
+		t.Error("Expected challenging a player at 1 HP to fail")
+	}
+	bob.Health = bob.GetMaxHealth()
+
+	s.ChallengeDuel("Alice", "Bob")
+	if challenger, err := s.DeclineDuel("Bob"); err != nil || challenger != "Alice" {
+		t.Errorf("DeclineDuel(Bob) = %q, %v; want Alice", challenger, err)
+	}
+	if _, err := s.AcceptDuel("Bob"); err == nil {
+		t.Error("Expected a declined challenge to be gone")
+	}
+
+	s.ChallengeDuel("Alice", "Bob")
+	s.duelMu.Lock()
+	challenge := s.duelChallenges["bob"]
+	challenge.expires = time.Now().Add(-time.Second)
+	s.duelChallenges["bob"] = challenge
+	s.duelMu.Unlock()
+	if _, err := s.AcceptDuel("Bob"); err == nil {
+		t.Error("Expected an expired challenge to be gone")
+	}
+}
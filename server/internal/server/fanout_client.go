@@ -0,0 +1,185 @@
+package server
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/color"
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+)
+
+// fanOutClient wraps a player's connection and copies everything the player is sent
+// to any number of observers (admins snooping, players spectating a fight).
+// Optional capabilities (typed output, color, GMCP, echo, traffic stats) are
+// forwarded to the wrapped client, so the player sees no difference.
+type fanOutClient struct {
+	Client
+	mu        sync.RWMutex
+	observers map[string]*observer // keyed by lowercase observer name
+}
+
+// observer is a connection receiving a copy of another player's output.
+type observer struct {
+	client player.Client
+	prefix string // Marks mirrored lines, e.g. "[snoop Bob] "
+}
+
+// newFanOutClient wraps a client so it can be observed.
+func newFanOutClient(client Client) *fanOutClient {
+	return &fanOutClient{
+		Client:    client,
+		observers: make(map[string]*observer),
+	}
+}
+
+// unwrapClient returns the connection underneath a fan-out wrapper. Mirrored output
+// is written to the observer's own connection rather than their wrapper, so watching
+// someone who is watching you can't loop.
+func unwrapClient(client player.Client) player.Client {
+	if fc, ok := client.(*fanOutClient); ok {
+		return fc.Client
+	}
+	return client
+}
+
+// addObserver starts mirroring output to client.
+func (c *fanOutClient) addObserver(name string, client player.Client, prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.observers[strings.ToLower(name)] = &observer{client: unwrapClient(client), prefix: prefix}
+}
+
+// removeObserver stops mirroring output to the named observer.
+func (c *fanOutClient) removeObserver(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.observers, strings.ToLower(name))
+}
+
+// observerCount returns how many observers are attached.
+func (c *fanOutClient) observerCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.observers)
+}
+
+// adoptObservers moves the observers from a previous connection, so a player who
+// reconnects after going link-dead is still being watched.
+func (c *fanOutClient) adoptObservers(old *fanOutClient) {
+	old.mu.Lock()
+	observers := old.observers
+	old.observers = make(map[string]*observer)
+	old.mu.Unlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name, o := range observers {
+		c.observers[name] = o
+	}
+}
+
+// WriteLine sends a message to the player and mirrors it to observers.
+func (c *fanOutClient) WriteLine(message string) error {
+	err := c.Client.WriteLine(message)
+	c.mirror(command.MessageSystem, message)
+	return err
+}
+
+// WriteTyped sends a tagged message to the player and mirrors it to observers.
+func (c *fanOutClient) WriteTyped(kind string, message string) error {
+	var err error
+	if tw, ok := c.Client.(player.TypedWriter); ok {
+		err = tw.WriteTyped(kind, message)
+	} else {
+		err = c.Client.WriteLine(message)
+	}
+	c.mirror(kind, message)
+	return err
+}
+
+// mirror copies a message to every observer. Output has already been rendered for
+// the player's client, so observers using a different color mode get plain text.
+func (c *fanOutClient) mirror(kind, message string) {
+	c.mu.RLock()
+	if len(c.observers) == 0 {
+		c.mu.RUnlock()
+		return
+	}
+	observers := make([]*observer, 0, len(c.observers))
+	for _, o := range c.observers {
+		observers = append(observers, o)
+	}
+	c.mu.RUnlock()
+
+	mode := clientColorMode(c.Client)
+	for _, o := range observers {
+		text := message
+		if observerMode := clientColorMode(o.client); observerMode != mode {
+			text = color.Render(color.Escape(color.StripRendered(text, mode)), observerMode, nil)
+		}
+		text = prefixLines(o.prefix, text)
+
+		if tw, ok := o.client.(player.TypedWriter); ok {
+			tw.WriteTyped(kind, text)
+		} else {
+			o.client.WriteLine(text)
+		}
+	}
+}
+
+// clientColorMode returns the color mode a client renders in.
+func clientColorMode(client player.Client) color.Mode {
+	if cc, ok := client.(player.ColorClient); ok {
+		return cc.ColorMode()
+	}
+	return color.ModeNone
+}
+
+// prefixLines adds prefix to the start of every non-blank line.
+func prefixLines(prefix, text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ColorMode forwards to the wrapped client.
+func (c *fanOutClient) ColorMode() color.Mode {
+	return clientColorMode(c.Client)
+}
+
+// GMCPEnabled forwards to the wrapped client. GMCP isn't mirrored to observers.
+func (c *fanOutClient) GMCPEnabled() bool {
+	if gc, ok := c.Client.(player.GMCPClient); ok {
+		return gc.GMCPEnabled()
+	}
+	return false
+}
+
+// SendGMCP forwards to the wrapped client.
+func (c *fanOutClient) SendGMCP(pkg string, data interface{}) error {
+	if gc, ok := c.Client.(player.GMCPClient); ok {
+		return gc.SendGMCP(pkg, data)
+	}
+	return nil
+}
+
+// SetEcho forwards to the wrapped client.
+func (c *fanOutClient) SetEcho(enabled bool) error {
+	if ec, ok := c.Client.(EchoController); ok {
+		return ec.SetEcho(enabled)
+	}
+	return nil
+}
+
+// TrafficStats forwards to the wrapped client.
+func (c *fanOutClient) TrafficStats() (raw, sent int64, compressed bool) {
+	if tc, ok := c.Client.(player.TrafficCounter); ok {
+		return tc.TrafficStats()
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,32 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+)
+
+// notifyFriends tells every online player with p on their friends list that p
+// has logged in or out.
+func (s *Server) notifyFriends(p *player.Player, online bool) {
+	if s.db == nil {
+		return
+	}
+
+	names, err := s.db.GetFriendedBy(p.GetCharacterID())
+	if err != nil {
+		logger.Warning("Failed to get friended by", "player", p.GetName(), "error", err)
+		return
+	}
+
+	message := fmt.Sprintf("\n{player}%s{/} has logged out.\n", p.GetName())
+	if online {
+		message = fmt.Sprintf("\n{player}%s{/} has logged in.\n", p.GetName())
+	}
+	for _, name := range names {
+		if watcher := s.findOnlinePlayer(name); watcher != nil && !watcher.IsIgnoring(p.GetName()) {
+			watcher.SendMessage(message)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestNotifyFriends tests that logging in and out is announced to the players
+// who have the player on their friends list, and no one else
+func TestNotifyFriends(t *testing.T) {
+	s, players := newDBTestServer(t, "Alice", "Bob", "Carol", "Dave")
+	alice, bob, dave := players[0], players[1], players[3]
+
+	for _, watcher := range []string{"Bob", "Dave"} {
+		if _, err := s.db.AddFriend(s.clients[watcher].GetCharacterID(), alice.GetCharacterID()); err != nil {
+			t.Fatalf("AddFriend failed: %v", err)
+		}
+	}
+	dave.AddIgnore("Alice")
+
+	s.notifyFriends(alice, true)
+	s.notifyFriends(alice, false)
+	s.notifyFriends(bob, true)
+
+	received := func(name, text string) bool {
+		c := s.clients[name].GetClient().(*stubClient)
+		for _, line := range c.lines {
+			if strings.Contains(line, text) {
+				return true
+			}
+		}
+		return false
+	}
+
+	tests := []struct {
+		name     string
+		text     string
+		expected bool
+	}{
+		{"Bob", "Alice has logged in", true},
+		{"Bob", "Alice has logged out", true},
+		{"Carol", "Alice has logged", false},  // not her friend
+		{"Dave", "Alice has logged", false},   // ignoring Alice
+		{"Alice", "Bob has logged in", false}, // friends lists are one-way
+	}
+	for _, tt := range tests {
+		if got := received(tt.name, tt.text); got != tt.expected {
+			t.Errorf("%s received %q = %v, want %v", tt.name, tt.text, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// telnetOptGMCP is the telnet option code for GMCP (Generic MUD Communication Protocol).
+const telnetOptGMCP byte = 201
+
+// GMCPSubprotocol is the WebSocket subprotocol a browser client requests to
+// receive GMCP packages as JSON frames alongside the normal text stream.
+const GMCPSubprotocol = "gmcp"
+
+// gmcpFrame is the JSON envelope used to deliver GMCP packages over WebSocket.
+type gmcpFrame struct {
+	Type    string      `json:"type"`
+	Package string      `json:"package"`
+	Data    interface{} `json:"data"`
+}
+
+// encodeGMCP builds a telnet GMCP payload: "Package.Name <json>".
+func encodeGMCP(pkg string, data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(pkg)
+	if data != nil {
+		payload, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteByte(' ')
+		buf.Write(payload)
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeGMCPFrame builds the WebSocket JSON frame for a GMCP package.
+func encodeGMCPFrame(pkg string, data interface{}) ([]byte, error) {
+	return json.Marshal(gmcpFrame{Type: "gmcp", Package: pkg, Data: data})
+}
+
+// escapeIAC doubles any IAC bytes so data can be embedded in a subnegotiation.
+func escapeIAC(data []byte) []byte {
+	if bytes.IndexByte(data, telnetIAC) == -1 {
+		return data
+	}
+	return bytes.ReplaceAll(data, []byte{telnetIAC}, []byte{telnetIAC, telnetIAC})
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/color"
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+)
+
+// loadGuildMembership looks up the player's guild and caches it on the player,
+// so who, look and guild chat don't need a database round trip.
+func (s *Server) loadGuildMembership(p *player.Player) {
+	if s.db == nil {
+		return
+	}
+
+	g, m, err := s.db.GetGuildMembership(p.GetCharacterID())
+	if err != nil {
+		logger.Warning("Failed to load guild membership", "player", p.GetName(), "error", err)
+		return
+	}
+	if g != nil {
+		p.SetGuild(g.ID, g.Name, g.Tag, m.Rank)
+	}
+}
+
+// SendGuildMessage delivers a guild chat message to every online member of a
+// guild except the sender, respecting ignore lists.
+func (s *Server) SendGuildMessage(guildID int64, senderName, message string) {
+	s.mu.RLock()
+	var members []*player.Player
+	for _, p := range s.clients {
+		if p.GetGuildID() == guildID && p.GetName() != senderName {
+			members = append(members, p)
+		}
+	}
+	s.mu.RUnlock()
+
+	for _, member := range members {
+		if member.IsIgnoring(senderName) {
+			continue
+		}
+		member.SendTyped(command.MessageChat, fmt.Sprintf("{guild}[%s] %s: \"%s\"{/}\n", member.GetGuildTag(), senderName, color.Escape(message)))
+		member.SendChannelGMCP("guild", senderName, message)
+	}
+}
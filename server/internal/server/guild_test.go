@@ -0,0 +1,48 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/guild"
+)
+
+// TestSendGuildMessage tests that guild chat reaches only online members of the
+// sender's guild, skipping the sender and anyone ignoring them
+func TestSendGuildMessage(t *testing.T) {
+	s, players := newPartyTestServer(t, "Alice", "Bob", "Carol", "Dave")
+	alice, bob, carol, dave := players[0], players[1], players[2], players[3]
+
+	alice.SetGuild(1, "Iron Vanguard", "IRON", guild.RankFounder)
+	bob.SetGuild(1, "Iron Vanguard", "IRON", guild.RankMember)
+	carol.SetGuild(1, "Iron Vanguard", "IRON", guild.RankMember)
+	dave.SetGuild(2, "Other Guild", "OTHR", guild.RankFounder)
+	carol.AddIgnore("Alice")
+
+	s.SendGuildMessage(1, "Alice", "rally at the gate")
+
+	received := func(name string) bool {
+		c := s.clients[name].GetClient().(*stubClient)
+		for _, line := range c.lines {
+			if strings.Contains(line, "rally at the gate") {
+				return true
+			}
+		}
+		return false
+	}
+
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{"Alice", false}, // sender
+		{"Bob", true},
+		{"Carol", false}, // ignoring the sender
+		{"Dave", false},  // different guild
+	}
+	for _, tt := range tests {
+		if got := received(tt.name); got != tt.expected {
+			t.Errorf("%s received guild message = %v, want %v", tt.name, got, tt.expected)
+		}
+	}
+}
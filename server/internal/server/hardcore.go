@@ -0,0 +1,82 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/color"
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+	"github.com/lawnchairsociety/opentowermud/server/internal/database"
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+	"github.com/lawnchairsociety/opentowermud/server/internal/world"
+)
+
+// retireHardcore ends a hardcore character's only life. They are saved as they
+// fell, retired to a memorial, and disconnected: the character can never be
+// played again.
+func (s *Server) retireHardcore(p *player.Player, killedBy string, room *world.Room) {
+	stats := p.GetStatistics()
+	floor := stats.GetDeepestFloor()
+	if room.Floor > floor {
+		floor = room.Floor
+	}
+	memorial := &database.Memorial{
+		CharacterID:     p.GetCharacterID(),
+		Name:            p.GetName(),
+		Level:           p.GetLevel(),
+		Experience:      p.GetExperience(),
+		PrimaryClass:    string(p.GetPrimaryClass()),
+		Race:            string(p.GetRace()),
+		KilledBy:        killedBy,
+		DiedIn:          room.Name,
+		FloorReached:    floor,
+		Kills:           stats.GetTotalKills(),
+		QuestsCompleted: stats.GetQuestsCompleted(),
+		PlaySeconds:     p.GetPlayTimeSeconds(),
+		DiedAt:          time.Now(),
+	}
+
+	// Nothing sells from a dead hero's stall
+	p.CloseStall()
+	s.savePlayerNow(p)
+	if s.db != nil && memorial.CharacterID != 0 {
+		if err := s.db.RetireCharacter(memorial); err != nil && !errors.Is(err, database.ErrNotHardcore) {
+			logger.Error("Failed to retire hardcore character", "player", p.GetName(), "error", err)
+		}
+	}
+	p.Retire()
+
+	logger.Info("Hardcore character retired",
+		"player", p.GetName(),
+		"level", memorial.Level,
+		"killed_by", killedBy,
+		"floor_reached", floor)
+
+	p.SendTyped(command.MessageCombat, "\n\n{warning}*** YOU HAVE DIED ***{/}\n")
+	p.SendTyped(command.MessageCombat, "Your hardcore journey ends here. There is no coming back.\n")
+	p.SendMessage(command.FormatMemorial(memorial))
+	p.SendMessage("\nFarewell, hero.\n\n")
+
+	s.BroadcastToRoom(room.GetID(), fmt.Sprintf("%s has been slain by %s!", p.GetName(), killedBy), p)
+	s.BroadcastMessage(fmt.Sprintf("\n{warning}The hardcore hero {player}%s{/} (level %d) has fallen to %s. Type 'memorial %s' to pay your respects.{/}\n",
+		p.GetName(), memorial.Level, killedBy, p.GetName()), p)
+
+	p.Disconnect()
+}
+
+// showMemorial shows a fallen hardcore character's memorial at the character
+// selection screen
+func (s *Server) showMemorial(client Client, name string) {
+	m, err := s.db.GetMemorial(name)
+	if err != nil {
+		if !errors.Is(err, database.ErrMemorialNotFound) {
+			logger.Error("Failed to load memorial", "character", name, "error", err)
+		}
+		client.WriteLine(fmt.Sprintf("\n%s has fallen and can't be played again.\n", name))
+		return
+	}
+	client.WriteLine(color.Strip(command.FormatMemorial(m)))
+	client.WriteLine(fmt.Sprintf("\n%s has fallen and can't be played again.\n", m.Name))
+}
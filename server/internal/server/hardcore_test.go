@@ -0,0 +1,255 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/auction"
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+	"github.com/lawnchairsociety/opentowermud/server/internal/guild"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+)
+
+// makeHardcore turns the players' characters into hardcore characters, as if
+// hardcore mode had been chosen when they were created.
+func makeHardcore(t *testing.T, s *Server, players ...*player.Player) {
+	t.Helper()
+	for _, p := range players {
+		if _, err := s.db.DB().Exec(s.db.Query("UPDATE characters SET hardcore = 1 WHERE id = ?"), p.GetCharacterID()); err != nil {
+			t.Fatalf("Failed to make %s hardcore: %v", p.GetName(), err)
+		}
+		p.SetHardcore(true)
+	}
+}
+
+// TestHardcore_DeathRetiresCharacter tests that a hardcore death leaves a
+// memorial instead of a respawn, and that the character is never saved again
+func TestHardcore_DeathRetiresCharacter(t *testing.T) {
+	s, players := newDBTestServer(t, "Hal", "Norm")
+	hal, norm := players[0], players[1]
+	makeHardcore(t, s, hal)
+	hal.Level = 4
+	hal.RecordKill("goblin")
+	hal.RecordFloorReached("human", 1)
+	hal.SetGold(50)
+	hal.Health = 0
+
+	s.killPlayer(hal, "a cave troll", s.world.GetRoom("stairs"))
+
+	if !hal.IsRetired() || !hal.IsDisconnected() {
+		t.Fatal("Expected Hal to be retired and disconnected")
+	}
+	if hal.GetHealth() != 0 {
+		t.Error("Expected Hal not to respawn")
+	}
+
+	char, err := s.db.GetCharacterByID(hal.GetCharacterID())
+	if err != nil || !char.Retired {
+		t.Fatalf("Expected Hal to be retired in the database, got %+v (%v)", char, err)
+	}
+	m, err := s.db.GetMemorial("Hal")
+	if err != nil {
+		t.Fatalf("Failed to get memorial: %v", err)
+	}
+	// Floor 2 is where Hal died, deeper than the floor 1 on record
+	if m.KilledBy != "a cave troll" || m.DiedIn != "stairs" || m.FloorReached != 2 || m.Level != 4 || m.Kills != 1 {
+		t.Errorf("Unexpected memorial: %+v", m)
+	}
+	if m.BornAt.IsZero() {
+		t.Error("Expected the memorial to record when Hal was created")
+	}
+
+	// Nothing that happens after death reaches the database
+	hal.SetGold(5000)
+	if err := s.SavePlayer(hal); err != nil {
+		t.Fatalf("SavePlayer failed: %v", err)
+	}
+	if char, _ := s.db.GetCharacterByID(hal.GetCharacterID()); char.Gold != 50 {
+		t.Errorf("Expected Hal's gold to stay as it was at death (50), got %d", char.Gold)
+	}
+
+	found := false
+	for _, line := range norm.GetClient().(*stubClient).lines {
+		if strings.Contains(line, "hardcore hero") && strings.Contains(line, "Hal") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected everyone online to hear of Hal's death")
+	}
+
+	// A normal character's death is unchanged
+	s.killPlayer(norm, "a cave troll", s.world.GetRoom("stairs"))
+	if norm.IsRetired() || norm.IsDisconnected() || norm.GetHealth() != norm.GetMaxHealth() {
+		t.Error("Expected Norm to respawn as usual")
+	}
+}
+
+// TestHardcore_MemorialAndLeaderboard tests the memorial and hardcore
+// leaderboard commands
+func TestHardcore_MemorialAndLeaderboard(t *testing.T) {
+	s, players := newDBTestServer(t, "Hal", "Hope", "Norm")
+	hal, hope, norm := players[0], players[1], players[2]
+	makeHardcore(t, s, hal, hope)
+	hal.Level = 4
+
+	s.killPlayer(hal, "a cave troll", s.world.GetRoom("stairs"))
+
+	memorial := command.ParseCommand("memorial Hal").Execute(norm, s.world)
+	if !strings.Contains(memorial, "In Memory of") || !strings.Contains(memorial, "a cave troll") {
+		t.Errorf("Expected Hal's memorial, got:\n%s", memorial)
+	}
+	if list := command.ParseCommand("memorial").Execute(norm, s.world); !strings.Contains(list, "Hal") {
+		t.Errorf("Expected Hal among the fallen, got:\n%s", list)
+	}
+	if result := command.ParseCommand("memorial Hope").Execute(norm, s.world); !strings.Contains(result, "has fallen") {
+		t.Errorf("Expected no memorial for a living hero, got:\n%s", result)
+	}
+
+	board := command.ParseCommand("leaderboard hardcore").Execute(hope, s.world)
+	halAt, hopeAt := strings.Index(board, "Hal"), strings.Index(board, "Hope")
+	if halAt < 0 || hopeAt < 0 || halAt > hopeAt || strings.Contains(board, "Norm") {
+		t.Errorf("Expected fallen Hal above living Hope, and no Norm, got:\n%s", board)
+	}
+	if !strings.Contains(board, "slain by a cave troll") {
+		t.Errorf("Expected the leaderboard to show how Hal fell, got:\n%s", board)
+	}
+}
+
+// TestHardcore_KeptApartFromNormalCharacters tests that hardcore characters
+// can't trade, give or mail with normal characters, but can with each other
+func TestHardcore_KeptApartFromNormalCharacters(t *testing.T) {
+	s, players := newDBTestServer(t, "Hal", "Hope", "Norm")
+	hal, hope, norm := players[0], players[1], players[2]
+	makeHardcore(t, s, hal, hope)
+	s.world.GetRoom("town_square").AddFeature("mailbox")
+	for _, p := range players {
+		p.SetGold(100)
+	}
+
+	if _, err := s.RequestTrade("Hal", "Norm"); err == nil || !strings.Contains(err.Error(), "hardcore") {
+		t.Errorf("Expected Hal not to be able to trade with Norm, got %v", err)
+	}
+	if _, err := s.RequestTrade("Norm", "Hal"); err == nil {
+		t.Error("Expected Norm not to be able to trade with Hal")
+	}
+	if _, err := s.RequestTrade("Hal", "Hope"); err != nil {
+		t.Errorf("Expected hardcore characters to trade with each other, got %v", err)
+	}
+
+	command.ParseCommand("give 10 gold Hal").Execute(norm, s.world)
+	if hal.GetGold() != 100 || norm.GetGold() != 100 {
+		t.Errorf("Expected Norm's gift to Hal to be refused, gold is %d and %d", hal.GetGold(), norm.GetGold())
+	}
+	command.ParseCommand("give 10 gold Hope").Execute(hal, s.world)
+	if hope.GetGold() != 110 {
+		t.Errorf("Expected Hal to give Hope gold, Hope has %d", hope.GetGold())
+	}
+
+	result := command.ParseCommand("mail send Hal Supplies | Take these. gold:50").Execute(norm, s.world)
+	if !strings.Contains(result, "hardcore") || norm.GetGold() != 100 {
+		t.Errorf("Expected Norm's mail to Hal to be refused, got %q", result)
+	}
+	result = command.ParseCommand("mail send Hope Hello | Good luck.").Execute(hal, s.world)
+	if strings.Contains(result, "hardcore") {
+		t.Errorf("Expected Hal to mail Hope, got %q", result)
+	}
+
+	s.killPlayer(hal, "a cave troll", s.world.GetRoom("stairs"))
+	result = command.ParseCommand("mail send Hal Farewell | Rest well.").Execute(hope, s.world)
+	if !strings.Contains(result, "has fallen") {
+		t.Errorf("Expected no mail to reach a fallen hero, got %q", result)
+	}
+}
+
+// TestHardcore_KeptOutOfSharedBanks tests that hardcore characters can't use
+// the account vault or the guild bank, which normal characters share
+func TestHardcore_KeptOutOfSharedBanks(t *testing.T) {
+	s, players := newDBTestServer(t, "Hal", "Norm")
+	hal, norm := players[0], players[1]
+	makeHardcore(t, s, hal)
+	addTradeHouse(s)
+	hal.SetGold(100)
+	norm.SetGold(100)
+
+	guildID, err := s.db.CreateGuild("Iron Vanguard", "IRON", norm.GetCharacterID(), "Norm")
+	if err != nil {
+		t.Fatalf("Failed to create guild: %v", err)
+	}
+	if err := s.db.AddGuildMember(guildID, hal.GetCharacterID(), "Hal", guild.RankMember); err != nil {
+		t.Fatalf("Failed to add guild member: %v", err)
+	}
+
+	for _, input := range []string{"bank", "deposit 10 gold", "withdraw 10 gold", "guild deposit 10 gold", "guild withdraw 10 gold"} {
+		result := command.ParseCommand(input).Execute(hal, s.world)
+		if !strings.Contains(result, "hardcore") || hal.GetGold() != 100 {
+			t.Errorf("Expected %q to be refused to Hal, got %q", input, result)
+		}
+	}
+
+	command.ParseCommand("deposit 10 gold").Execute(norm, s.world)
+	command.ParseCommand("guild deposit 10 gold").Execute(norm, s.world)
+	if norm.GetGold() != 80 {
+		t.Errorf("Expected Norm to use both banks, has %d gold", norm.GetGold())
+	}
+}
+
+// TestHardcore_KeptApartInMarkets tests that auction and stall purchases only
+// happen between two hardcore or two normal characters
+func TestHardcore_KeptApartInMarkets(t *testing.T) {
+	s, players := newDBTestServer(t, "Hal", "Hope", "Norm")
+	hal, hope, norm := players[0], players[1], players[2]
+	makeHardcore(t, s, hal, hope)
+	addTradeHouse(s)
+	for _, p := range players {
+		p.SetGold(200)
+	}
+
+	// listSword has a player put a sword up for auction and returns its number
+	listSword := func(seller string) int64 {
+		t.Helper()
+		p := s.findOnlinePlayer(seller)
+		p.AddItem(s.CreateItem("long_sword"))
+		command.ParseCommand("auction sell long sword 100 50").Execute(p, s.world)
+		listings, err := s.db.SearchAuctions(auction.SearchFilter{SellerID: p.GetCharacterID()}, 10)
+		if err != nil || len(listings) != 1 {
+			t.Fatalf("Expected %s's sword to be listed, got %d listings (%v)", seller, len(listings), err)
+		}
+		return listings[0].ID
+	}
+
+	normSword := listSword("Norm")
+	for _, input := range []string{fmt.Sprintf("auction bid %d 60", normSword), fmt.Sprintf("auction buyout %d", normSword)} {
+		result := command.ParseCommand(input).Execute(hal, s.world)
+		if !strings.Contains(result, "hardcore") || hal.GetGold() != 200 {
+			t.Errorf("Expected %q to be refused to Hal, got %q", input, result)
+		}
+	}
+	hopeSword := listSword("Hope")
+	if result := command.ParseCommand(fmt.Sprintf("auction buyout %d", hopeSword)).Execute(norm, s.world); !strings.Contains(result, "hardcore") {
+		t.Errorf("Expected Norm not to buy Hope's sword, got %q", result)
+	}
+	if command.ParseCommand(fmt.Sprintf("auction buyout %d", hopeSword)).Execute(hal, s.world); hal.GetGold() != 100 {
+		t.Errorf("Expected Hal to buy Hope's sword for 100 gold, has %d gold", hal.GetGold())
+	}
+
+	// A stall with its owner beside it
+	norm.AddToStall(s.CreateItem("long_sword"), 40)
+	norm.OpenStall()
+	if result := command.ParseCommand("purchase sword from Norm").Execute(hope, s.world); !strings.Contains(result, "hardcore") || hope.GetGold() != 200 {
+		t.Errorf("Expected Hope not to buy from Norm's stall, got %q", result)
+	}
+
+	// A stall left open while its owner is offline
+	hope.AddToStall(s.CreateItem("long_sword"), 40)
+	hope.OpenStall()
+	s.handleDisconnect(hope)
+	s.removeClient(hope)
+	if _, err := s.BuyFromUnattendedStall("Norm", "Hope", "sword"); err == nil || !strings.Contains(err.Error(), "hardcore") {
+		t.Errorf("Expected Norm not to buy from Hope's unattended stall, got %v", err)
+	}
+	if _, err := s.BuyFromUnattendedStall("Hal", "Hope", "sword"); err != nil {
+		t.Errorf("Expected Hal to buy from Hope's unattended stall, got %v", err)
+	}
+}
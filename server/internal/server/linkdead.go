@@ -0,0 +1,182 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+)
+
+// linkDeadGrace returns how long a link-dead player stays in the world.
+// Zero means link-dead handling is disabled.
+func (s *Server) linkDeadGrace() time.Duration {
+	if s.serverConfig == nil {
+		return 0
+	}
+	return time.Duration(s.serverConfig.Session.LinkDeadGraceSeconds) * time.Second
+}
+
+// endSession runs when a player's command loop on client exits. Players who quit
+// (or were disconnected by the server) are saved and removed; players whose
+// connection dropped stay in the world as link-dead for the grace period.
+// Nothing happens if a newer connection is taking the player over.
+func (s *Server) endSession(p *player.Player, client player.Client) {
+	if !p.IsDisconnected() && p.SessionReplaced(client) {
+		logger.Debug("Old session ended after takeover", "player", p.GetName(), "remote_addr", client.RemoteAddr())
+		return
+	}
+
+	// A watcher's connection is gone, so their snoop or spectate session ends with it
+	s.StopWatching(p.GetName())
+	// Nobody can trade with a lost connection, so an open trade is cancelled too
+	s.removeFromTrade(p)
+	// A duel can't wait for a lost connection either, so it is forfeited
+	s.removeFromDuel(p)
+
+	if !p.IsDisconnected() && s.linkDeadGrace() > 0 {
+		p.SetLinkDead()
+		logger.Info("Player link-dead", "player", p.GetName(), "grace_seconds", int(s.linkDeadGrace().Seconds()))
+		if room := p.CurrentRoom; room != nil {
+			s.BroadcastToRoom(room.GetID(), fmt.Sprintf("%s has lost their link.", p.GetName()), p)
+		}
+		return
+	}
+
+	// Handle disconnect (save, combat penalty, etc.)
+	s.handleDisconnect(p)
+	s.releaseWatchers(p)
+	s.removeFromParty(p)
+
+	logger.Info("Client disconnected", "player", p.GetName())
+
+	s.removeClient(p)
+	s.notifyFriends(p, false)
+}
+
+// removeClient removes a player from the online list, unless a different
+// player object has since taken the name.
+func (s *Server) removeClient(p *player.Player) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.clients[p.GetName()] == p {
+		delete(s.clients, p.GetName())
+	}
+}
+
+// reattachSession connects a new client to a character that is still in the
+// world. A link-dead character is simply reattached; a character whose old
+// connection is still open (the player reconnected before the server noticed
+// it drop) is taken over: the old connection is closed, and the player is
+// reattached once its command loop has exited.
+// Returns nil if the character isn't online.
+func (s *Server) reattachSession(name string, client Client) (*player.Player, error) {
+	s.mu.Lock()
+	var p *player.Player
+	for playerName, candidate := range s.clients {
+		if strings.EqualFold(playerName, name) && !candidate.IsDisconnected() {
+			p = candidate
+			break
+		}
+	}
+	if p == nil {
+		s.mu.Unlock()
+		return nil, nil
+	}
+	if p.IsLinkDead() {
+		// Reattach while holding the lock so the grace period can't expire underneath us
+		s.attachClient(p, client)
+		s.mu.Unlock()
+		logger.Info("Player reconnected", "player", p.GetName(), "remote_addr", client.RemoteAddr())
+	} else {
+		sessionDone, ok := p.BeginTakeover()
+		old := p.GetClient()
+		s.mu.Unlock()
+		if !ok {
+			return nil, fmt.Errorf("character %s is already being taken over", p.GetName())
+		}
+
+		// Closing the old connection ends its command loop; wait for it so the
+		// two connections never run commands for the player at the same time
+		old.WriteLine("\nThis character has been reconnected from elsewhere.\n")
+		old.Close()
+		<-sessionDone
+
+		s.mu.Lock()
+		if p.IsDisconnected() || s.clients[p.GetName()] != p {
+			// The old connection quit before it closed
+			s.mu.Unlock()
+			return nil, nil
+		}
+		s.attachClient(p, client)
+		s.mu.Unlock()
+		logger.Info("Player session taken over", "player", p.GetName(), "remote_addr", client.RemoteAddr())
+	}
+
+	if room := p.CurrentRoom; room != nil {
+		s.BroadcastToRoom(room.GetID(), fmt.Sprintf("%s has reconnected.", p.GetName()), p)
+	}
+	return p, nil
+}
+
+// attachClient moves a player onto a new client. Callers hold s.mu.
+func (s *Server) attachClient(p *player.Player, client Client) {
+	// Anyone watching the old connection keeps watching the new one
+	if oldFanOut, ok := p.GetClient().(*fanOutClient); ok {
+		if fc, ok := client.(*fanOutClient); ok {
+			fc.adoptObservers(oldFanOut)
+		}
+	}
+	p.Reattach(client)
+}
+
+// removeLinkDeadPlayer saves and removes a player who never reconnected.
+func (s *Server) removeLinkDeadPlayer(p *player.Player) {
+	s.handleDisconnect(p)
+	s.releaseWatchers(p)
+	s.removeFromParty(p)
+	s.removeClient(p)
+	s.notifyFriends(p, false)
+	logger.Info("Link-dead player removed", "player", p.GetName())
+}
+
+// startLinkDeadTicker runs a background ticker that removes link-dead players
+// whose grace period has expired.
+func (s *Server) startLinkDeadTicker() {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.shutdown:
+			return
+		case <-ticker.C:
+			s.checkLinkDeadPlayers()
+		}
+	}
+}
+
+// checkLinkDeadPlayers removes link-dead players whose grace period has expired.
+func (s *Server) checkLinkDeadPlayers() {
+	grace := s.linkDeadGrace()
+
+	s.mu.Lock()
+	var expired []*player.Player
+	for _, p := range s.clients {
+		if !p.IsLinkDead() {
+			continue
+		}
+		if since := p.LinkDeadSince(); grace <= 0 || time.Since(since) >= grace {
+			// Clear the flag while holding the lock so a reconnect can't reattach
+			// to a player that is about to be removed
+			p.ClearLinkDead()
+			expired = append(expired, p)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, p := range expired {
+		s.removeLinkDeadPlayer(p)
+	}
+}
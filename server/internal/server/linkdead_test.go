@@ -0,0 +1,212 @@
+package server
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/config"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+	"github.com/lawnchairsociety/opentowermud/server/internal/world"
+)
+
+// stubClient is a Client that records output and has no input.
+type stubClient struct {
+	lines  []string
+	closed bool
+}
+
+func (c *stubClient) ReadLine() (string, error)      { return "", errors.New("closed") }
+func (c *stubClient) WriteLine(message string) error { c.lines = append(c.lines, message); return nil }
+func (c *stubClient) Write(data []byte) error        { return nil }
+func (c *stubClient) Close() error                   { c.closed = true; return nil }
+func (c *stubClient) RemoteAddr() string             { return "127.0.0.1:4000" }
+
+// lineClient is a Client whose input comes from the test. Like a real
+// connection, ReadLine blocks until a line arrives or the client is closed.
+type lineClient struct {
+	input     chan string
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newLineClient() *lineClient {
+	return &lineClient{input: make(chan string), done: make(chan struct{})}
+}
+
+func (c *lineClient) ReadLine() (string, error) {
+	select {
+	case line := <-c.input:
+		return line, nil
+	case <-c.done:
+		return "", errors.New("closed")
+	}
+}
+func (c *lineClient) WriteLine(message string) error { return nil }
+func (c *lineClient) Write(data []byte) error        { return nil }
+func (c *lineClient) Close() error                   { c.closeOnce.Do(func() { close(c.done) }); return nil }
+func (c *lineClient) RemoteAddr() string             { return "127.0.0.1:4001" }
+
+// runSession runs the player's command loop on its current client the way
+// handleClient does. The returned channel is closed once the session has ended.
+func runSession(s *Server, p *player.Player) <-chan struct{} {
+	client := p.GetClient()
+	ended := make(chan struct{})
+	go func() {
+		defer close(ended)
+		defer s.endSession(p, client)
+		p.ResumeSession()
+	}()
+	return ended
+}
+
+// newLinkDeadTestServer creates a server with a link-dead grace period and one online player.
+func newLinkDeadTestServer(t *testing.T, graceSeconds int) (*Server, *player.Player) {
+	w := world.NewWorld()
+	w.AddRoom(world.NewRoom("town_square", "Town Square", "The square.", world.RoomTypeRoom))
+
+	s := NewServer(":0", w, false)
+	cfg := config.DefaultConfig()
+	cfg.Session.LinkDeadGraceSeconds = graceSeconds
+	s.SetServerConfig(cfg)
+	t.Cleanup(s.Shutdown)
+
+	p := player.NewPlayer("Alice", &stubClient{}, w, s)
+	s.mu.Lock()
+	s.clients["Alice"] = p
+	s.mu.Unlock()
+	return s, p
+}
+
+// TestLinkDead_DroppedConnectionStaysInWorld tests that a dropped connection leaves the player online
+func TestLinkDead_DroppedConnectionStaysInWorld(t *testing.T) {
+	s, p := newLinkDeadTestServer(t, 60)
+
+	s.endSession(p, p.GetClient())
+
+	if !p.IsLinkDead() {
+		t.Fatal("Expected player to be link-dead after connection drop")
+	}
+	if s.FindPlayer("Alice") == nil {
+		t.Fatal("Expected link-dead player to stay online")
+	}
+
+	// Grace period hasn't expired - player should survive the check
+	s.checkLinkDeadPlayers()
+	if s.FindPlayer("Alice") == nil {
+		t.Error("Expected link-dead player to survive before the grace period expires")
+	}
+}
+
+// TestLinkDead_Reattach tests that a reconnect reuses the existing player object
+func TestLinkDead_Reattach(t *testing.T) {
+	s, p := newLinkDeadTestServer(t, 60)
+	s.endSession(p, p.GetClient())
+
+	newClient := &stubClient{}
+	got, err := s.reattachSession("alice", newClient)
+	if err != nil || got != p {
+		t.Fatal("Expected reconnect to reattach the existing player")
+	}
+	if p.IsLinkDead() {
+		t.Error("Expected link-dead flag to clear on reattach")
+	}
+
+	p.SendMessage("hello")
+	if len(newClient.lines) == 0 || newClient.lines[len(newClient.lines)-1] != "hello" {
+		t.Errorf("Expected output on the new client, got %v", newClient.lines)
+	}
+
+}
+
+// TestLinkDead_TakeOverConnectedSession tests that logging in to a character
+// whose old connection is still open moves the player to the new connection
+func TestLinkDead_TakeOverConnectedSession(t *testing.T) {
+	s, p := newLinkDeadTestServer(t, 60)
+	oldClient := newLineClient()
+	p.Reattach(oldClient)
+	oldSession := runSession(s, p)
+
+	newClient := &stubClient{}
+	if got, err := s.reattachSession("Alice", newClient); err != nil || got != p {
+		t.Fatalf("Expected the login to take over the existing player, got %v, %v", got, err)
+	}
+
+	// The old session has ended by the time the takeover returns, and its
+	// ending doesn't touch the player any more
+	select {
+	case <-oldSession:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the old session to end before the takeover returns")
+	}
+	if p.IsLinkDead() || p.GetClient() != newClient || s.FindPlayer("Alice") == nil {
+		t.Error("Expected the player to stay online on the new connection")
+	}
+
+	p.SendMessage("hello")
+	if len(newClient.lines) == 0 || newClient.lines[len(newClient.lines)-1] != "hello" {
+		t.Errorf("Expected output on the new client, got %v", newClient.lines)
+	}
+}
+
+// TestLinkDead_TakeOverDropsQueuedInput tests that commands queued on the old
+// connection neither run nor carry over once it has been taken over
+func TestLinkDead_TakeOverDropsQueuedInput(t *testing.T) {
+	s, p := newLinkDeadTestServer(t, 60)
+	s.serverConfig.Input.QueueDelayMs = 60000
+	oldClient := newLineClient()
+	p = player.NewPlayer("Alice", oldClient, s.world, s)
+	s.mu.Lock()
+	s.clients["Alice"] = p
+	s.mu.Unlock()
+	oldSession := runSession(s, p)
+
+	// The first look runs at once; the other two wait in the queue
+	oldClient.input <- "look;look;look"
+	time.Sleep(50 * time.Millisecond)
+
+	newClient := newLineClient()
+	if got, err := s.reattachSession("Alice", newClient); err != nil || got != p {
+		t.Fatalf("Expected the login to take over the existing player, got %v, %v", got, err)
+	}
+	<-oldSession
+
+	if dropped := p.ClearInputQueue(); dropped != 0 {
+		t.Errorf("Expected the old connection's queue to be dropped, %d commands were left", dropped)
+	}
+	if p.IsLinkDead() || p.GetClient() != newClient || s.FindPlayer("Alice") == nil {
+		t.Error("Expected the player to stay online on the new connection")
+	}
+}
+
+// TestLinkDead_GraceExpires tests that link-dead players are removed once the grace period ends
+func TestLinkDead_GraceExpires(t *testing.T) {
+	s, p := newLinkDeadTestServer(t, 60)
+	s.endSession(p, p.GetClient())
+
+	s.serverConfig.Session.LinkDeadGraceSeconds = 0
+	s.checkLinkDeadPlayers()
+
+	if s.FindPlayer("Alice") != nil {
+		t.Error("Expected link-dead player to be removed after the grace period")
+	}
+	if got, _ := s.reattachSession("Alice", &stubClient{}); got != nil {
+		t.Error("Expected no reattach after removal")
+	}
+}
+
+// TestLinkDead_QuitRemovesImmediately tests that quitting skips the grace period
+func TestLinkDead_QuitRemovesImmediately(t *testing.T) {
+	s, p := newLinkDeadTestServer(t, 60)
+
+	p.Disconnect()
+	s.endSession(p, p.GetClient())
+
+	if p.IsLinkDead() {
+		t.Error("Expected a player who quit not to be link-dead")
+	}
+	if s.FindPlayer("Alice") != nil {
+		t.Error("Expected a player who quit to be removed immediately")
+	}
+}
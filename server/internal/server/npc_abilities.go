@@ -0,0 +1,204 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+	"github.com/lawnchairsociety/opentowermud/server/internal/npc"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+	"github.com/lawnchairsociety/opentowermud/server/internal/spells"
+	"github.com/lawnchairsociety/opentowermud/server/internal/stats"
+	"github.com/lawnchairsociety/opentowermud/server/internal/tower"
+	"github.com/lawnchairsociety/opentowermud/server/internal/world"
+)
+
+// maxSummonRoomNPCs is how many NPCs a room may hold before summon abilities stop calling in adds.
+const maxSummonRoomNPCs = 8
+
+// defaultAbilityPoisonDuration is how long ability poison lasts when the spell doesn't say.
+const defaultAbilityPoisonDuration = 12
+
+// useNPCAbility has an NPC use one of its abilities on its target instead of
+// attacking this round. Returns false if the NPC has no ability ready and should
+// make its normal attack.
+func (s *Server) useNPCAbility(n *npc.NPC, room *world.Room, target *player.Player) bool {
+	if s.spellRegistry == nil || !n.HasAbilities() {
+		return false
+	}
+	ability, ok := n.ChooseAbility()
+	if !ok {
+		return false
+	}
+	spell, exists := s.spellRegistry.GetSpell(ability.SpellID)
+	if !exists {
+		logger.Debug("NPC ability uses an unknown spell", "npc", n.GetName(), "spell", ability.SpellID)
+		return false
+	}
+
+	cooldown := ability.Cooldown
+	if cooldown == 0 {
+		cooldown = spell.Cooldown
+	}
+	if cooldown > 0 {
+		n.StartAbilityCooldown(spell.ID, cooldown)
+	}
+
+	logger.Debug("NPC ability used",
+		"npc", n.GetName(),
+		"spell", spell.ID,
+		"target", target.GetName(),
+		"npc_hp", n.GetHealth())
+
+	if spell.CanTargetRoomEnemies() || spell.IsSelfOnly() {
+		s.BroadcastToRoom(room.GetID(), fmt.Sprintf("\n{npc}%s{/} uses %s!\n", n.GetName(), spell.Name), nil)
+	} else {
+		target.SendTyped(command.MessageCombat, fmt.Sprintf("\n{npc}%s{/} uses %s on you!\n", n.GetName(), spell.Name))
+		s.BroadcastToRoom(room.GetID(), fmt.Sprintf("\n{npc}%s{/} uses %s on {player}%s{/}!\n", n.GetName(), spell.Name, target.GetName()), target)
+	}
+
+	for _, effect := range spell.Effects {
+		if effect.Type == spells.EffectSummon {
+			s.callAdds(n, room, effect.Summon, effect.Amount)
+			continue
+		}
+		switch effect.Target {
+		case spells.TargetSelf:
+			s.applyNPCSelfEffect(n, room, spell, effect)
+		case spells.TargetEnemy:
+			s.applyNPCAbilityEffect(n, room, target, spell, effect)
+		case spells.TargetRoomEnemy:
+			for _, p := range s.livingPlayersInRoom(room) {
+				s.applyNPCAbilityEffect(n, room, p, spell, effect)
+			}
+		}
+	}
+	return true
+}
+
+// applyNPCAbilityEffect applies one hostile ability effect to a player
+func (s *Server) applyNPCAbilityEffect(n *npc.NPC, room *world.Room, p *player.Player, spell *spells.Spell, effect spells.SpellEffect) {
+	if !p.IsAlive() || p.CurrentRoom != room {
+		return
+	}
+
+	switch effect.Type {
+	case spells.EffectDamage:
+		damage := p.TakeMagicDamage(rollEffectAmount(effect))
+		p.RecordDamageTaken(damage)
+		p.SendTyped(command.MessageCombat, fmt.Sprintf("You take {damage}%d{/} damage! (%d/%d HP)\n",
+			damage, p.GetHealth(), p.GetMaxHealth()))
+		if !p.IsAlive() {
+			s.handlePlayerDeath(p, n, room)
+		}
+	case spells.EffectStun, spells.EffectPoison, spells.EffectDebuff, spells.EffectRoot:
+		status, seconds, _ := effect.Status(spell)
+		if effect.Type == spells.EffectPoison && effect.Duration <= 0 {
+			seconds = defaultAbilityPoisonDuration
+		}
+		status.Source = n.GetName()
+		if !p.Effects().Apply(status, seconds) {
+			return
+		}
+		switch effect.Type {
+		case spells.EffectStun:
+			p.SendTyped(command.MessageCombat, fmt.Sprintf("{warning}You are stunned for %d seconds!{/}\n", seconds))
+		case spells.EffectPoison:
+			p.SendTyped(command.MessageCombat, "{warning}You are poisoned!{/}\n")
+		default:
+			p.SendTyped(command.MessageCombat, fmt.Sprintf("{warning}%s: %s for %d seconds!{/}\n", spell.Name, status.Describe(), seconds))
+		}
+	}
+}
+
+// applyNPCSelfEffect applies an ability effect an NPC uses on itself
+func (s *Server) applyNPCSelfEffect(n *npc.NPC, room *world.Room, spell *spells.Spell, effect spells.SpellEffect) {
+	var healed int
+	switch effect.Type {
+	case spells.EffectHeal:
+		healed = n.Heal(rollEffectAmount(effect))
+	case spells.EffectHealPercent:
+		healed = n.Heal(n.GetMaxHealth() * effect.Amount / 100)
+	case spells.EffectBuff:
+		if status, seconds, ok := effect.Status(spell); ok {
+			status.Source = n.GetName()
+			n.Effects().Apply(status, seconds)
+		}
+		return
+	case spells.EffectCleanse:
+		if removed := n.Effects().Cleanse(); len(removed) > 0 {
+			s.BroadcastToRoom(room.GetID(), fmt.Sprintf("{npc}%s{/} shakes off its afflictions!\n", n.GetName()), nil)
+		}
+		return
+	default:
+		return
+	}
+	if healed > 0 {
+		s.BroadcastToRoom(room.GetID(), fmt.Sprintf("{npc}%s{/} recovers {heal}%d{/} health! (%d/%d HP)\n",
+			n.GetName(), healed, n.GetHealth(), n.GetMaxHealth()), nil)
+	}
+}
+
+// callAdds calls mobs into the room to join the summoner's fight, for summon
+// abilities and boss encounter add waves. They are scaled for the floor like any
+// other tower mob, and don't respawn when killed. Returns the mobs called in.
+func (s *Server) callAdds(summoner *npc.NPC, room *world.Room, mobID string, count int) []*npc.NPC {
+	if s.mobConfig == nil {
+		return nil
+	}
+	def, exists := s.mobConfig.NPCs[mobID]
+	if !exists {
+		logger.Warning("NPC summons an unknown mob", "npc", summoner.GetName(), "mob", mobID)
+		return nil
+	}
+	def.ID = mobID
+	def.RespawnMedian = 0
+
+	if count < 1 {
+		count = 1
+	}
+	if present := len(room.GetNPCs()); present+count > maxSummonRoomNPCs {
+		count = maxSummonRoomNPCs - present
+	}
+
+	var adds []*npc.NPC
+	targets := summoner.GetTargets()
+	for i := 0; i < count; i++ {
+		add := tower.CreateScaledMob(&def, room.GetID(), room.GetFloor())
+		for _, name := range targets {
+			add.StartCombat(name)
+		}
+		room.AddNPC(add)
+		adds = append(adds, add)
+		s.BroadcastToRoom(room.GetID(), fmt.Sprintf("{npc}%s{/} answers {npc}%s{/}'s call!\n", add.GetName(), summoner.GetName()), nil)
+	}
+	if len(adds) > 0 {
+		logger.Debug("NPC summoned adds", "npc", summoner.GetName(), "mob", mobID, "count", len(adds), "room", room.GetID())
+	}
+	return adds
+}
+
+// livingPlayersInRoom returns the online players in a room who are still alive.
+// Link-dead players are left out: like aggressive NPCs, room-wide attacks leave
+// them alone.
+func (s *Server) livingPlayersInRoom(room *world.Room) []*player.Player {
+	var players []*player.Player
+	for _, name := range room.GetPlayers() {
+		if p := s.findOnlinePlayer(name); p != nil && p.IsAlive() && !p.IsLinkDead() {
+			players = append(players, p)
+		}
+	}
+	return players
+}
+
+// rollEffectAmount rolls an effect's dice, falling back to its flat amount
+func rollEffectAmount(effect spells.SpellEffect) int {
+	amount := effect.Amount
+	if effect.Dice != "" {
+		amount = stats.ParseDice(effect.Dice)
+	}
+	if amount < 1 {
+		amount = 1
+	}
+	return amount
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/npc"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+	"github.com/lawnchairsociety/opentowermud/server/internal/spells"
+)
+
+const testAbilitySpells = `spells:
+  test_blast:
+    name: "test blast"
+    npc_only: true
+    cooldown: 30
+    effects:
+      - type: "damage"
+        target: "room_enemy"
+        dice: "2d4"
+  test_stun:
+    name: "test stun"
+    npc_only: true
+    effects:
+      - type: "stun"
+        target: "enemy"
+        amount: 10
+  test_venom:
+    name: "test venom"
+    npc_only: true
+    effects:
+      - type: "poison"
+        target: "room_enemy"
+        amount: 4
+        duration: 30
+  test_mend:
+    name: "test mend"
+    npc_only: true
+    cooldown: 60
+    effects:
+      - type: "heal_percent"
+        target: "self"
+        amount: 50
+  test_call:
+    name: "test call"
+    npc_only: true
+    effects:
+      - type: "summon"
+        summon: "goblin"
+        amount: 2
+`
+
+// loadTestSpells loads a spells.yaml document into the server's spell registry
+func loadTestSpells(t *testing.T, s *Server, yaml string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "spells.yaml")
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatalf("Failed to write spells: %v", err)
+	}
+	registry := spells.NewSpellRegistry()
+	if err := registry.LoadFromYAML(path); err != nil {
+		t.Fatalf("Failed to load spells: %v", err)
+	}
+	s.SetSpellRegistry(registry)
+}
+
+// newAbilityTestServer sets up Alice and Bob fighting an ogre in the hall, with
+// the test abilities loaded
+func newAbilityTestServer(t *testing.T) (*Server, []*player.Player, *npc.NPC) {
+	t.Helper()
+	s, players := newPartyTestServer(t, "Alice", "Bob")
+	loadTestSpells(t, s, testAbilitySpells)
+
+	hall := s.world.GetRoom("hall")
+	ogre := npc.NewNPC("ogre", "A big ogre.", 5, 100, 5, 0, 10, true, true, "hall", 0, 0)
+	hall.AddNPC(ogre)
+	for _, p := range players {
+		hall.AddPlayer(p.GetName())
+		p.StartCombat("ogre")
+		ogre.StartCombat(p.GetName())
+	}
+	return s, players, ogre
+}
+
+// TestNPCAbility_RoomDamage tests that a room-wide ability hits every player and
+// then waits out its cooldown
+func TestNPCAbility_RoomDamage(t *testing.T) {
+	s, players, ogre := newAbilityTestServer(t)
+	ogre.SetAbilities([]npc.Ability{{SpellID: "test_blast"}})
+
+	s.processNPCAttacks()
+	for _, p := range players {
+		if p.GetHealth() == p.GetMaxHealth() {
+			t.Errorf("Expected %s to be hit by the blast", p.GetName())
+		}
+	}
+	if _, ready := ogre.ChooseAbility(); ready {
+		t.Error("Expected the blast to be on cooldown after use")
+	}
+}
+
+// TestNPCAbility_RoomDamageSparesLinkDead tests that a room-wide ability
+// doesn't hit a player who has lost their link
+func TestNPCAbility_RoomDamageSparesLinkDead(t *testing.T) {
+	s, players, ogre := newAbilityTestServer(t)
+	alice, bob := players[0], players[1]
+	ogre.SetAbilities([]npc.Ability{{SpellID: "test_blast"}})
+	bob.SetLinkDead()
+
+	s.processNPCAttacks()
+	if alice.GetHealth() == alice.GetMaxHealth() {
+		t.Error("Expected Alice to be hit by the blast")
+	}
+	if bob.GetHealth() != bob.GetMaxHealth() {
+		t.Error("Expected link-dead Bob to be spared the blast")
+	}
+}
+
+// TestNPCAbility_StunAndPoison tests that stunned players lose their attacks and
+// that poison hurts every round and can kill
+func TestNPCAbility_StunAndPoison(t *testing.T) {
+	s, players, ogre := newAbilityTestServer(t)
+	alice, bob := players[0], players[1]
+
+	ogre.SetAbilities([]npc.Ability{{SpellID: "test_stun"}})
+	ogre.AddThreat("Alice", 100)
+	s.processNPCAttacks()
+	if !alice.IsStunned() {
+		t.Fatal("Expected Alice to be stunned")
+	}
+	s.processPlayerAttack(alice)
+	if ogre.GetHealth() != ogre.GetMaxHealth() {
+		t.Error("Expected a stunned player not to attack")
+	}
+
+	ogre.SetAbilities([]npc.Ability{{SpellID: "test_venom"}})
+	s.processNPCAttacks()
+	if !bob.IsPoisoned() {
+		t.Fatal("Expected Bob to be poisoned")
+	}
+	before := bob.GetHealth()
+	s.processPlayerEffects(bob)
+	if bob.GetHealth() != before-4 {
+		t.Errorf("Expected poison to deal 4 damage, got %d", before-bob.GetHealth())
+	}
+
+	bob.Health = 1
+	s.processPlayerEffects(bob)
+	if bob.CurrentRoom.GetID() != "town_square" || bob.GetHealth() != bob.GetMaxHealth() {
+		t.Errorf("Expected Bob to die and respawn, got room %s with %d HP", bob.CurrentRoom.GetID(), bob.GetHealth())
+	}
+	if bob.IsPoisoned() || bob.IsInCombat() {
+		t.Error("Expected death to clear poison and end combat")
+	}
+	for _, target := range ogre.GetTargets() {
+		if target == "Bob" {
+			t.Error("Expected the ogre to stop fighting Bob once he died")
+		}
+	}
+}
+
+// TestNPCAbility_HealBelowThreshold tests that a health-triggered ability waits
+// until the NPC is hurt enough
+func TestNPCAbility_HealBelowThreshold(t *testing.T) {
+	s, _, ogre := newAbilityTestServer(t)
+	ogre.SetAbilities([]npc.Ability{{SpellID: "test_mend", HPBelow: 50}})
+
+	ogre.TakeMagicDamage(40)
+	if _, ready := ogre.ChooseAbility(); ready {
+		t.Fatal("Expected the heal to wait until the ogre is at half health")
+	}
+
+	ogre.TakeMagicDamage(40)
+	s.processNPCAttacks()
+	if ogre.GetHealth() != 70 {
+		t.Errorf("Expected the ogre to heal half its health back to 70, got %d", ogre.GetHealth())
+	}
+}
+
+// TestNPCAbility_SummonsAdds tests that summoned mobs join the fight without respawning
+func TestNPCAbility_SummonsAdds(t *testing.T) {
+	s, _, ogre := newAbilityTestServer(t)
+	s.SetMobConfig(&npc.NPCsConfig{NPCs: map[string]npc.NPCDefinition{
+		"goblin": {Name: "goblin", Level: 1, Health: 10, Damage: 2, Aggressive: true, Attackable: true, RespawnMedian: 120},
+	}})
+	ogre.SetAbilities([]npc.Ability{{SpellID: "test_call"}})
+
+	s.processNPCAttacks()
+	npcs := s.world.GetRoom("hall").GetNPCs()
+	if len(npcs) != 3 {
+		t.Fatalf("Expected the ogre to call in 2 goblins, got %d NPCs", len(npcs))
+	}
+	for _, n := range npcs {
+		if n == ogre {
+			continue
+		}
+		if len(n.GetTargets()) != 2 {
+			t.Errorf("Expected each goblin to join the fight against both players, got %v", n.GetTargets())
+		}
+		if n.GetRespawnMedian() != 0 {
+			t.Error("Expected summoned goblins not to respawn")
+		}
+	}
+
+	// The room fills up
+	for i := 0; i < 10; i++ {
+		s.processNPCAttacks()
+	}
+	if count := len(s.world.GetRoom("hall").GetNPCs()); count > maxSummonRoomNPCs {
+		t.Errorf("Expected summons to stop at %d NPCs, got %d", maxSummonRoomNPCs, count)
+	}
+}
@@ -0,0 +1,443 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/color"
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+	"github.com/lawnchairsociety/opentowermud/server/internal/world"
+)
+
+// maxPartySize is the most players a party can hold.
+const maxPartySize = 6
+
+// party is a group of players who share experience, gold and loot.
+type party struct {
+	leader   string   // Leader's name
+	members  []string // Member names in join order (leader included)
+	lootMode string   // command.LootFree, LootRoundRobin or LootNeedGreed
+	nextLoot int      // Next member in the round-robin rotation
+}
+
+// memberName returns a member's name as stored, or "" if they aren't in the party.
+func (pt *party) memberName(name string) string {
+	for _, m := range pt.members {
+		if strings.EqualFold(m, name) {
+			return m
+		}
+	}
+	return ""
+}
+
+// isLeader returns true if the named player leads the party.
+func (pt *party) isLeader(name string) bool {
+	return strings.EqualFold(pt.leader, name)
+}
+
+// memberList returns a copy of the member names.
+func (pt *party) memberList() []string {
+	return append([]string(nil), pt.members...)
+}
+
+// partyOf returns the party a player belongs to, or nil. Caller must hold partyMu.
+func (s *Server) partyOf(name string) *party {
+	return s.parties[strings.ToLower(name)]
+}
+
+// dropPartyMember removes a member from their party, passing leadership to the
+// longest-standing member if the leader left. A party left with one member is
+// disbanded. Caller must hold partyMu.
+// Returns the members still in the party to notify, and the new leader ("" if unchanged).
+func (s *Server) dropPartyMember(pt *party, name string) (remaining []string, newLeader string, disbanded bool) {
+	for i, m := range pt.members {
+		if strings.EqualFold(m, name) {
+			pt.members = append(pt.members[:i], pt.members[i+1:]...)
+			break
+		}
+	}
+	delete(s.parties, strings.ToLower(name))
+
+	if len(pt.members) <= 1 {
+		remaining = pt.memberList()
+		for _, m := range pt.members {
+			delete(s.parties, strings.ToLower(m))
+		}
+		pt.members = nil
+		return remaining, "", true
+	}
+
+	if pt.isLeader(name) {
+		pt.leader = pt.members[0]
+		newLeader = pt.leader
+	}
+	return pt.memberList(), newLeader, false
+}
+
+// notifyPartyMembers sends a message to each named member who is online, except exclude.
+func (s *Server) notifyPartyMembers(members []string, message string, exclude string) {
+	for _, name := range members {
+		if strings.EqualFold(name, exclude) {
+			continue
+		}
+		if p := s.findOnlinePlayer(name); p != nil {
+			p.SendMessage(message)
+		}
+	}
+}
+
+// announcePartyDeparture tells the remaining members that someone left the party.
+func (s *Server) announcePartyDeparture(remaining []string, newLeader string, disbanded bool, message string) {
+	if disbanded {
+		s.notifyPartyMembers(remaining, message+"\nThe group has been disbanded.\n", "")
+		return
+	}
+	if newLeader != "" {
+		message += fmt.Sprintf("\n{player}%s{/} is now the group leader.", newLeader)
+	}
+	s.notifyPartyMembers(remaining, message+"\n", "")
+}
+
+// InviteToParty invites a player to join the inviter's party. Only the leader can
+// invite; a player who isn't in a party starts one when the invite is accepted.
+func (s *Server) InviteToParty(inviterName, targetName string) error {
+	inviter := s.findOnlinePlayer(inviterName)
+	if inviter == nil {
+		return errors.New("You are not online.")
+	}
+	target := s.findOnlinePlayer(targetName)
+	if target == nil {
+		return fmt.Errorf("Player '%s' is not online.", targetName)
+	}
+	if target == inviter {
+		return errors.New("You can't invite yourself.")
+	}
+
+	s.partyMu.Lock()
+	if pt := s.partyOf(inviter.GetName()); pt != nil {
+		if !pt.isLeader(inviter.GetName()) {
+			s.partyMu.Unlock()
+			return errors.New("Only the group leader can invite players.")
+		}
+		if len(pt.members) >= maxPartySize {
+			s.partyMu.Unlock()
+			return fmt.Errorf("Your group is full (%d members).", maxPartySize)
+		}
+	}
+	if s.partyOf(target.GetName()) != nil {
+		s.partyMu.Unlock()
+		return fmt.Errorf("%s is already in a group.", target.GetName())
+	}
+	if s.partyInvites == nil {
+		s.partyInvites = make(map[string]string)
+	}
+	s.partyInvites[strings.ToLower(target.GetName())] = inviter.GetName()
+	s.partyMu.Unlock()
+
+	// Don't reveal an ignore - the invite just never arrives
+	if !target.IsIgnoring(inviter.GetName()) {
+		target.SendMessage(fmt.Sprintf("\n{player}%s{/} invites you to join their group. Type 'group accept' or 'group decline'.\n", inviter.GetName()))
+	}
+	return nil
+}
+
+// AcceptPartyInvite joins the party of whoever last invited the player.
+func (s *Server) AcceptPartyInvite(name string) error {
+	p := s.findOnlinePlayer(name)
+	if p == nil {
+		return errors.New("You are not online.")
+	}
+	name = p.GetName()
+	key := strings.ToLower(name)
+
+	s.partyMu.Lock()
+	inviterName, ok := s.partyInvites[key]
+	if !ok {
+		s.partyMu.Unlock()
+		return errors.New("You haven't been invited to a group.")
+	}
+	delete(s.partyInvites, key)
+
+	if s.partyOf(name) != nil {
+		s.partyMu.Unlock()
+		return errors.New("You are already in a group.")
+	}
+	if s.findOnlinePlayer(inviterName) == nil {
+		s.partyMu.Unlock()
+		return fmt.Errorf("%s is no longer online.", inviterName)
+	}
+
+	pt := s.partyOf(inviterName)
+	if pt == nil {
+		pt = &party{leader: inviterName, members: []string{inviterName}, lootMode: command.LootFree}
+		if s.parties == nil {
+			s.parties = make(map[string]*party)
+		}
+		s.parties[strings.ToLower(inviterName)] = pt
+	} else if !pt.isLeader(inviterName) {
+		s.partyMu.Unlock()
+		return fmt.Errorf("%s is no longer leading a group.", inviterName)
+	}
+	if len(pt.members) >= maxPartySize {
+		s.partyMu.Unlock()
+		return errors.New("That group is full.")
+	}
+
+	pt.members = append(pt.members, name)
+	s.parties[key] = pt
+	members := pt.memberList()
+	s.partyMu.Unlock()
+
+	logger.Info("Party joined", "player", name, "leader", pt.leader, "size", len(members))
+	s.notifyPartyMembers(members, fmt.Sprintf("\n{player}%s{/} has joined the group.\n", name), name)
+	return nil
+}
+
+// DeclinePartyInvite turns down a pending invite.
+// Returns the name of the player who sent it.
+func (s *Server) DeclinePartyInvite(name string) (string, error) {
+	key := strings.ToLower(name)
+
+	s.partyMu.Lock()
+	inviterName, ok := s.partyInvites[key]
+	delete(s.partyInvites, key)
+	s.partyMu.Unlock()
+
+	if !ok {
+		return "", errors.New("You haven't been invited to a group.")
+	}
+	if inviter := s.findOnlinePlayer(inviterName); inviter != nil {
+		inviter.SendMessage(fmt.Sprintf("\n{player}%s{/} declines your group invitation.\n", name))
+	}
+	return inviterName, nil
+}
+
+// LeaveParty removes a player from their party.
+func (s *Server) LeaveParty(name string) error {
+	s.partyMu.Lock()
+	pt := s.partyOf(name)
+	if pt == nil {
+		s.partyMu.Unlock()
+		return errors.New("You aren't in a group.")
+	}
+	name = pt.memberName(name)
+	remaining, newLeader, disbanded := s.dropPartyMember(pt, name)
+	s.partyMu.Unlock()
+
+	s.announcePartyDeparture(remaining, newLeader, disbanded, fmt.Sprintf("\n{player}%s{/} has left the group.", name))
+	return nil
+}
+
+// KickFromParty removes a member from the leader's party.
+func (s *Server) KickFromParty(leaderName, targetName string) error {
+	s.partyMu.Lock()
+	pt := s.partyOf(leaderName)
+	if pt == nil {
+		s.partyMu.Unlock()
+		return errors.New("You aren't in a group.")
+	}
+	if !pt.isLeader(leaderName) {
+		s.partyMu.Unlock()
+		return errors.New("Only the group leader can remove members.")
+	}
+	targetName = pt.memberName(targetName)
+	if targetName == "" {
+		s.partyMu.Unlock()
+		return errors.New("That player isn't in your group.")
+	}
+	if pt.isLeader(targetName) {
+		s.partyMu.Unlock()
+		return errors.New("You can't remove yourself. Use 'group leave' instead.")
+	}
+	remaining, newLeader, disbanded := s.dropPartyMember(pt, targetName)
+	s.partyMu.Unlock()
+
+	if target := s.findOnlinePlayer(targetName); target != nil {
+		target.SendMessage(fmt.Sprintf("\nYou have been removed from the group by {player}%s{/}.\n", pt.leader))
+	}
+	s.announcePartyDeparture(remaining, newLeader, disbanded, fmt.Sprintf("\n{player}%s{/} has been removed from the group.", targetName))
+	return nil
+}
+
+// SetPartyLeader hands leadership of the party to another member.
+func (s *Server) SetPartyLeader(leaderName, targetName string) error {
+	s.partyMu.Lock()
+	pt := s.partyOf(leaderName)
+	if pt == nil {
+		s.partyMu.Unlock()
+		return errors.New("You aren't in a group.")
+	}
+	if !pt.isLeader(leaderName) {
+		s.partyMu.Unlock()
+		return errors.New("Only the group leader can pass on leadership.")
+	}
+	targetName = pt.memberName(targetName)
+	if targetName == "" {
+		s.partyMu.Unlock()
+		return errors.New("That player isn't in your group.")
+	}
+	pt.leader = targetName
+	members := pt.memberList()
+	s.partyMu.Unlock()
+
+	s.notifyPartyMembers(members, fmt.Sprintf("\n{player}%s{/} is now the group leader.\n", targetName), "")
+	return nil
+}
+
+// DisbandParty breaks up the leader's party.
+func (s *Server) DisbandParty(leaderName string) error {
+	s.partyMu.Lock()
+	pt := s.partyOf(leaderName)
+	if pt == nil {
+		s.partyMu.Unlock()
+		return errors.New("You aren't in a group.")
+	}
+	if !pt.isLeader(leaderName) {
+		s.partyMu.Unlock()
+		return errors.New("Only the group leader can disband the group.")
+	}
+	members := pt.memberList()
+	for _, m := range members {
+		delete(s.parties, strings.ToLower(m))
+	}
+	pt.members = nil
+	s.partyMu.Unlock()
+
+	s.notifyPartyMembers(members, fmt.Sprintf("\n{player}%s{/} has disbanded the group.\n", pt.leader), "")
+	return nil
+}
+
+// SetPartyLootMode changes how the leader's party shares item drops.
+func (s *Server) SetPartyLootMode(leaderName, mode string) error {
+	if !command.IsLootMode(mode) {
+		return fmt.Errorf("Unknown loot mode '%s'. Choose %s, %s or %s.", mode, command.LootFree, command.LootRoundRobin, command.LootNeedGreed)
+	}
+
+	s.partyMu.Lock()
+	pt := s.partyOf(leaderName)
+	if pt == nil {
+		s.partyMu.Unlock()
+		return errors.New("You aren't in a group.")
+	}
+	if !pt.isLeader(leaderName) {
+		s.partyMu.Unlock()
+		return errors.New("Only the group leader can change the loot rules.")
+	}
+	pt.lootMode = mode
+	members := pt.memberList()
+	s.partyMu.Unlock()
+
+	s.notifyPartyMembers(members, fmt.Sprintf("\nGroup loot is now %s.\n", command.LootModeDescription(mode)), leaderName)
+	return nil
+}
+
+// GetPartyInfo returns the party a player belongs to.
+func (s *Server) GetPartyInfo(name string) (command.PartyInfo, bool) {
+	s.partyMu.Lock()
+	defer s.partyMu.Unlock()
+	pt := s.partyOf(name)
+	if pt == nil {
+		return command.PartyInfo{}, false
+	}
+	return command.PartyInfo{Leader: pt.leader, Members: pt.memberList(), LootMode: pt.lootMode}, true
+}
+
+// SendPartyMessage delivers a group chat message to every other member of the
+// sender's party, respecting ignore lists.
+func (s *Server) SendPartyMessage(senderName, message string) error {
+	s.partyMu.Lock()
+	pt := s.partyOf(senderName)
+	var members []string
+	if pt != nil {
+		members = pt.memberList()
+	}
+	s.partyMu.Unlock()
+
+	if pt == nil {
+		return errors.New("You aren't in a group.")
+	}
+
+	for _, name := range members {
+		if strings.EqualFold(name, senderName) {
+			continue
+		}
+		member := s.findOnlinePlayer(name)
+		if member == nil || member.IsIgnoring(senderName) {
+			continue
+		}
+		member.SendTyped(command.MessageChat, fmt.Sprintf("{group}[Group] %s: \"%s\"{/}\n", senderName, color.Escape(message)))
+		member.SendChannelGMCP("group", senderName, message)
+	}
+	return nil
+}
+
+// removeFromParty takes a player out of their party and drops any invites to or
+// from them. Called when a player leaves the game.
+func (s *Server) removeFromParty(p *player.Player) {
+	name := p.GetName()
+
+	s.partyMu.Lock()
+	delete(s.partyInvites, strings.ToLower(name))
+	for invitee, inviter := range s.partyInvites {
+		if strings.EqualFold(inviter, name) {
+			delete(s.partyInvites, invitee)
+		}
+	}
+	pt := s.partyOf(name)
+	var remaining []string
+	var newLeader string
+	var disbanded bool
+	if pt != nil {
+		remaining, newLeader, disbanded = s.dropPartyMember(pt, pt.memberName(name))
+	}
+	s.partyMu.Unlock()
+
+	if pt != nil {
+		s.announcePartyDeparture(remaining, newLeader, disbanded, fmt.Sprintf("\n{player}%s{/} has left the game and the group.", name))
+	}
+}
+
+// sameFloor returns true if two rooms are on the same floor of the same tower.
+func (s *Server) sameFloor(a, b *world.Room) bool {
+	if a == nil || b == nil || a.GetFloor() != b.GetFloor() {
+		return false
+	}
+	_, towerA := s.world.FindRoomWithTowerID(a.GetID())
+	_, towerB := s.world.FindRoomWithTowerID(b.GetID())
+	return towerA == towerB
+}
+
+// rewardRecipients returns who shares the experience and gold for a kill: every
+// attacker, plus their party members on the same floor as the kill.
+// Link-dead party members who weren't fighting don't get a share.
+func (s *Server) rewardRecipients(attackers []string, room *world.Room) []*player.Player {
+	var recipients []*player.Player
+	seen := make(map[*player.Player]bool)
+	add := func(p *player.Player) {
+		if p != nil && !seen[p] {
+			seen[p] = true
+			recipients = append(recipients, p)
+		}
+	}
+
+	for _, name := range attackers {
+		add(s.findOnlinePlayer(name))
+	}
+	for _, name := range attackers {
+		info, ok := s.GetPartyInfo(name)
+		if !ok {
+			continue
+		}
+		for _, memberName := range info.Members {
+			member := s.findOnlinePlayer(memberName)
+			if member == nil || member.IsLinkDead() || !s.sameFloor(member.CurrentRoom, room) {
+				continue
+			}
+			add(member)
+		}
+	}
+	return recipients
+}
@@ -0,0 +1,261 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+	"github.com/lawnchairsociety/opentowermud/server/internal/items"
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+	"github.com/lawnchairsociety/opentowermud/server/internal/world"
+)
+
+// lootRollDuration is how long party members have to roll need or greed on an item.
+const lootRollDuration = 30 * time.Second
+
+// lootRoll is an item a party is rolling need or greed on.
+type lootRoll struct {
+	item     *items.Item
+	room     *world.Room
+	eligible []string          // Members who may roll
+	choices  map[string]string // Lowercase member name -> command.LootNeed, LootGreed or LootPass
+	rolls    map[string]int    // Lowercase member name -> roll (1-100) for need and greed
+	timer    *time.Timer
+	done     bool
+}
+
+// distributeLoot hands out an item dropped by an NPC according to the killers'
+// party loot rules. Players who aren't in a party (and parties on free-for-all)
+// find the item on the floor, as before.
+func (s *Server) distributeLoot(item *items.Item, room *world.Room, attackers []string) {
+	members, mode := s.lootMembers(attackers, room)
+
+	switch mode {
+	case command.LootRoundRobin:
+		s.lootRoundRobin(item, room, members)
+	case command.LootNeedGreed:
+		s.startLootRoll(item, room, members)
+	default:
+		room.AddItem(item)
+	}
+}
+
+// lootMembers returns the party members in the room who can receive loot and the
+// party's loot mode. The party is the first attacker's that belongs to one.
+func (s *Server) lootMembers(attackers []string, room *world.Room) ([]*player.Player, string) {
+	for _, name := range attackers {
+		info, ok := s.GetPartyInfo(name)
+		if !ok {
+			continue
+		}
+		var members []*player.Player
+		for _, memberName := range info.Members {
+			member := s.findOnlinePlayer(memberName)
+			if member != nil && !member.IsLinkDead() && member.CurrentRoom == room {
+				members = append(members, member)
+			}
+		}
+		return members, info.LootMode
+	}
+	return nil, command.LootFree
+}
+
+// lootRoundRobin gives the item to the next member in the party's rotation.
+// If they can't carry it, it drops to the floor.
+func (s *Server) lootRoundRobin(item *items.Item, room *world.Room, members []*player.Player) {
+	if len(members) == 0 {
+		room.AddItem(item)
+		return
+	}
+
+	// Advance the party's rotation, skipping members who aren't here
+	s.partyMu.Lock()
+	var winner *player.Player
+	if pt := s.partyOf(members[0].GetName()); pt != nil && len(pt.members) > 0 {
+		for i := 0; i < len(pt.members) && winner == nil; i++ {
+			candidate := pt.members[(pt.nextLoot+i)%len(pt.members)]
+			for _, m := range members {
+				if strings.EqualFold(m.GetName(), candidate) {
+					winner = m
+					pt.nextLoot = (pt.nextLoot + i + 1) % len(pt.members)
+					break
+				}
+			}
+		}
+	}
+	s.partyMu.Unlock()
+	if winner == nil {
+		winner = members[0]
+	}
+
+	s.awardLoot(winner, item, room, members, "round robin")
+}
+
+// awardLoot puts an item in the winner's inventory (or on the floor if they
+// can't carry it) and tells the members who got it.
+func (s *Server) awardLoot(winner *player.Player, item *items.Item, room *world.Room, members []*player.Player, reason string) {
+	if !winner.CanCarry(item) {
+		room.AddItem(item)
+		for _, m := range members {
+			m.SendTyped(command.MessageCombat, fmt.Sprintf("{player}%s{/} wins {item}%s{/} (%s) but can't carry it - it falls to the floor.\n", winner.GetName(), item.Name, reason))
+		}
+		return
+	}
+
+	winner.AddItem(item)
+	logger.Debug("Party loot awarded", "player", winner.GetName(), "item", item.ID, "reason", reason)
+	for _, m := range members {
+		if m == winner {
+			m.SendTyped(command.MessageCombat, fmt.Sprintf("You receive {item}%s{/} (%s).\n", item.Name, reason))
+		} else {
+			m.SendTyped(command.MessageCombat, fmt.Sprintf("{player}%s{/} receives {item}%s{/} (%s).\n", winner.GetName(), item.Name, reason))
+		}
+	}
+}
+
+// startLootRoll opens a need/greed roll on an item for the members in the room.
+func (s *Server) startLootRoll(item *items.Item, room *world.Room, members []*player.Player) {
+	if len(members) == 0 {
+		room.AddItem(item)
+		return
+	}
+
+	roll := &lootRoll{
+		item:    item,
+		room:    room,
+		choices: make(map[string]string),
+		rolls:   make(map[string]int),
+	}
+	for _, m := range members {
+		roll.eligible = append(roll.eligible, m.GetName())
+	}
+
+	s.partyMu.Lock()
+	s.lootRolls = append(s.lootRolls, roll)
+	roll.timer = time.AfterFunc(lootRollDuration, func() { s.finishLootRoll(roll) })
+	s.partyMu.Unlock()
+
+	for _, m := range members {
+		m.SendTyped(command.MessageCombat, fmt.Sprintf("Rolling for {item}%s{/}: type 'need', 'greed' or 'pass' (%d seconds).\n", item.Name, int(lootRollDuration.Seconds())))
+	}
+}
+
+// RollOnLoot records a player's need, greed or pass on every open loot roll they
+// haven't answered yet. Returns a summary for the player.
+func (s *Server) RollOnLoot(name, choice string) (string, error) {
+	s.partyMu.Lock()
+	var answered []*lootRoll
+	var results []string
+	for _, roll := range s.lootRolls {
+		key := strings.ToLower(name)
+		if roll.done || !containsFold(roll.eligible, name) {
+			continue
+		}
+		if _, already := roll.choices[key]; already {
+			continue
+		}
+		roll.choices[key] = choice
+		if choice == command.LootPass {
+			results = append(results, fmt.Sprintf("You pass on {item}%s{/}.", roll.item.Name))
+		} else {
+			roll.rolls[key] = rand.Intn(100) + 1
+			results = append(results, fmt.Sprintf("You roll %s on {item}%s{/}: %d", choice, roll.item.Name, roll.rolls[key]))
+		}
+		answered = append(answered, roll)
+	}
+	s.partyMu.Unlock()
+
+	if len(answered) == 0 {
+		return "", errors.New("There is nothing to roll on.")
+	}
+
+	// Anyone else at the roll sees the result, and the roll ends once everyone has answered
+	for _, roll := range answered {
+		for _, memberName := range roll.eligible {
+			if strings.EqualFold(memberName, name) {
+				continue
+			}
+			if m := s.findOnlinePlayer(memberName); m != nil {
+				if choice == command.LootPass {
+					m.SendMessage(fmt.Sprintf("{player}%s{/} passes on {item}%s{/}.\n", name, roll.item.Name))
+				} else {
+					m.SendMessage(fmt.Sprintf("{player}%s{/} rolls %s on {item}%s{/}.\n", name, choice, roll.item.Name))
+				}
+			}
+		}
+
+		s.partyMu.Lock()
+		complete := len(roll.choices) >= len(roll.eligible)
+		s.partyMu.Unlock()
+		if complete {
+			s.finishLootRoll(roll)
+		}
+	}
+
+	return strings.Join(results, "\n"), nil
+}
+
+// finishLootRoll awards an item to the highest need roll, or the highest greed
+// roll if nobody needed it. If everyone passed, the item drops to the floor.
+func (s *Server) finishLootRoll(roll *lootRoll) {
+	s.partyMu.Lock()
+	if roll.done {
+		s.partyMu.Unlock()
+		return
+	}
+	roll.done = true
+	roll.timer.Stop()
+	for i, r := range s.lootRolls {
+		if r == roll {
+			s.lootRolls = append(s.lootRolls[:i], s.lootRolls[i+1:]...)
+			break
+		}
+	}
+
+	winnerName, reason := "", ""
+	for _, want := range []string{command.LootNeed, command.LootGreed} {
+		best := 0
+		for _, name := range roll.eligible {
+			key := strings.ToLower(name)
+			if roll.choices[key] == want && roll.rolls[key] > best {
+				winnerName, best = name, roll.rolls[key]
+				reason = fmt.Sprintf("%s roll of %d", want, best)
+			}
+		}
+		if winnerName != "" {
+			break
+		}
+	}
+	s.partyMu.Unlock()
+
+	var members []*player.Player
+	for _, name := range roll.eligible {
+		if m := s.findOnlinePlayer(name); m != nil {
+			members = append(members, m)
+		}
+	}
+
+	winner := s.findOnlinePlayer(winnerName)
+	if winner == nil {
+		roll.room.AddItem(roll.item)
+		for _, m := range members {
+			m.SendTyped(command.MessageCombat, fmt.Sprintf("Nobody claimed {item}%s{/} - it lies on the floor.\n", roll.item.Name))
+		}
+		return
+	}
+	s.awardLoot(winner, roll.item, roll.room, members, reason)
+}
+
+// containsFold returns true if names contains name (case-insensitive).
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
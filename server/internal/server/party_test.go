@@ -0,0 +1,265 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+	"github.com/lawnchairsociety/opentowermud/server/internal/items"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+	"github.com/lawnchairsociety/opentowermud/server/internal/world"
+)
+
+// newPartyTestServer creates a server with two rooms on floor 1, one on floor 2,
+// and an online player for each name, all standing in the first room.
+func newPartyTestServer(t *testing.T, names ...string) (*Server, []*player.Player) {
+	w := world.NewWorld()
+	w.AddRoom(world.NewRoom("town_square", "Town Square", "The square.", world.RoomTypeRoom))
+	for _, id := range []string{"hall", "corridor", "stairs"} {
+		room := world.NewRoom(id, id, "A room.", world.RoomTypeRoom)
+		room.Floor = 1
+		w.AddRoom(room)
+	}
+	w.GetRoom("stairs").Floor = 2
+
+	s := NewServer(":0", w, false)
+	t.Cleanup(s.Shutdown)
+
+	var players []*player.Player
+	for _, name := range names {
+		p := player.NewPlayer(name, &stubClient{}, w, s)
+		p.CurrentRoom = w.GetRoom("hall")
+		s.mu.Lock()
+		s.clients[name] = p
+		s.mu.Unlock()
+		players = append(players, p)
+	}
+	return s, players
+}
+
+// formParty has the first player invite the others, who all accept.
+func formParty(t *testing.T, s *Server, players []*player.Player) {
+	t.Helper()
+	for _, p := range players[1:] {
+		if err := s.InviteToParty(players[0].GetName(), p.GetName()); err != nil {
+			t.Fatalf("InviteToParty(%s) failed: %v", p.GetName(), err)
+		}
+		if err := s.AcceptPartyInvite(p.GetName()); err != nil {
+			t.Fatalf("AcceptPartyInvite(%s) failed: %v", p.GetName(), err)
+		}
+	}
+}
+
+// TestParty_InviteAndAccept tests that accepting an invite forms a party led by the inviter
+func TestParty_InviteAndAccept(t *testing.T) {
+	s, players := newPartyTestServer(t, "Alice", "Bob")
+
+	if err := s.AcceptPartyInvite("Bob"); err == nil {
+		t.Error("Expected accept without an invite to fail")
+	}
+
+	formParty(t, s, players)
+
+	info, ok := s.GetPartyInfo("bob")
+	if !ok {
+		t.Fatal("Expected Bob to be in a party")
+	}
+	if info.Leader != "Alice" {
+		t.Errorf("Expected Alice to lead, got %s", info.Leader)
+	}
+	if len(info.Members) != 2 || info.Members[0] != "Alice" || info.Members[1] != "Bob" {
+		t.Errorf("Expected members [Alice Bob], got %v", info.Members)
+	}
+	if info.LootMode != command.LootFree {
+		t.Errorf("Expected default loot mode %s, got %s", command.LootFree, info.LootMode)
+	}
+
+	if err := s.InviteToParty("Alice", "Bob"); err == nil {
+		t.Error("Expected inviting a player already in a group to fail")
+	}
+}
+
+// TestParty_LeaderOnlyActions tests that only the leader can invite, kick or change loot rules
+func TestParty_LeaderOnlyActions(t *testing.T) {
+	s, players := newPartyTestServer(t, "Alice", "Bob", "Carol", "Dave")
+	formParty(t, s, players[:3])
+
+	tests := []struct {
+		name string
+		fn   func() error
+	}{
+		{"invite", func() error { return s.InviteToParty("Bob", "Dave") }},
+		{"kick", func() error { return s.KickFromParty("Bob", "Carol") }},
+		{"leader", func() error { return s.SetPartyLeader("Bob", "Bob") }},
+		{"disband", func() error { return s.DisbandParty("Bob") }},
+		{"loot", func() error { return s.SetPartyLootMode("Bob", command.LootRoundRobin) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fn(); err == nil {
+				t.Errorf("Expected %s by a non-leader to fail", tt.name)
+			}
+		})
+	}
+
+	if err := s.KickFromParty("Alice", "Carol"); err != nil {
+		t.Fatalf("Expected leader to kick Carol, got %v", err)
+	}
+	if _, ok := s.GetPartyInfo("Carol"); ok {
+		t.Error("Expected Carol to be out of the party after the kick")
+	}
+}
+
+// TestParty_LeaderLeavesHandsOff tests that leadership passes on when the leader leaves
+func TestParty_LeaderLeavesHandsOff(t *testing.T) {
+	s, players := newPartyTestServer(t, "Alice", "Bob", "Carol")
+	formParty(t, s, players)
+
+	if err := s.LeaveParty("Alice"); err != nil {
+		t.Fatalf("LeaveParty failed: %v", err)
+	}
+
+	info, ok := s.GetPartyInfo("Carol")
+	if !ok {
+		t.Fatal("Expected the party to survive with two members")
+	}
+	if info.Leader != "Bob" {
+		t.Errorf("Expected Bob to take over as leader, got %s", info.Leader)
+	}
+
+	// One member left - the party disbands
+	if err := s.LeaveParty("Carol"); err != nil {
+		t.Fatalf("LeaveParty failed: %v", err)
+	}
+	if _, ok := s.GetPartyInfo("Bob"); ok {
+		t.Error("Expected a one-member party to be disbanded")
+	}
+}
+
+// TestParty_RemovedOnLogout tests that leaving the game takes a player out of their party
+func TestParty_RemovedOnLogout(t *testing.T) {
+	s, players := newPartyTestServer(t, "Alice", "Bob", "Carol")
+	formParty(t, s, players[:2])
+	if err := s.InviteToParty("Alice", "Carol"); err != nil {
+		t.Fatalf("InviteToParty failed: %v", err)
+	}
+
+	s.removeFromParty(players[0])
+
+	if _, ok := s.GetPartyInfo("Bob"); ok {
+		t.Error("Expected the party to disband when the leader logs out")
+	}
+	if err := s.AcceptPartyInvite("Carol"); err == nil {
+		t.Error("Expected invites from a logged-out player to be dropped")
+	}
+}
+
+// TestParty_RewardRecipients tests that kill rewards reach party members on the same floor only
+func TestParty_RewardRecipients(t *testing.T) {
+	s, players := newPartyTestServer(t, "Alice", "Bob", "Carol", "Dave")
+	formParty(t, s, players[:3])
+	players[1].CurrentRoom = s.world.GetRoom("corridor") // Same floor, different room
+	players[2].CurrentRoom = s.world.GetRoom("stairs")   // Different floor
+
+	recipients := s.rewardRecipients([]string{"Alice", "Dave"}, s.world.GetRoom("hall"))
+
+	got := make(map[string]bool)
+	for _, p := range recipients {
+		got[p.GetName()] = true
+	}
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Alice", true}, // Attacker
+		{"Dave", true},  // Attacker outside the party
+		{"Bob", true},   // Party member on the same floor
+		{"Carol", false},
+	}
+	for _, tt := range tests {
+		if got[tt.name] != tt.want {
+			t.Errorf("%s in recipients = %v, want %v", tt.name, got[tt.name], tt.want)
+		}
+	}
+	if len(recipients) != 3 {
+		t.Errorf("Expected 3 recipients without duplicates, got %d", len(recipients))
+	}
+}
+
+// TestParty_RoundRobinLoot tests that round-robin loot rotates through members in the room
+func TestParty_RoundRobinLoot(t *testing.T) {
+	s, players := newPartyTestServer(t, "Alice", "Bob", "Carol")
+	formParty(t, s, players)
+	if err := s.SetPartyLootMode("Alice", command.LootRoundRobin); err != nil {
+		t.Fatalf("SetPartyLootMode failed: %v", err)
+	}
+	players[2].CurrentRoom = s.world.GetRoom("corridor") // Carol isn't at the kill
+
+	hall := s.world.GetRoom("hall")
+	want := []string{"Alice", "Bob", "Alice"}
+	for i, name := range want {
+		item := items.NewItem("gem", "A gem.", 0.1, items.Misc, 10)
+		s.distributeLoot(item, hall, []string{"Bob"})
+
+		var winner string
+		for _, p := range players {
+			for _, it := range p.GetInventory() {
+				if it == item {
+					winner = p.GetName()
+				}
+			}
+		}
+		if winner != name {
+			t.Errorf("Drop %d went to %q, want %q", i+1, winner, name)
+		}
+	}
+}
+
+// TestParty_NeedBeatsGreed tests that a need roll wins over a greed roll
+func TestParty_NeedBeatsGreed(t *testing.T) {
+	s, players := newPartyTestServer(t, "Alice", "Bob")
+	formParty(t, s, players)
+	if err := s.SetPartyLootMode("Alice", command.LootNeedGreed); err != nil {
+		t.Fatalf("SetPartyLootMode failed: %v", err)
+	}
+
+	item := items.NewItem("sword", "A sword.", 1, items.Weapon, 50)
+	s.distributeLoot(item, s.world.GetRoom("hall"), []string{"Alice"})
+
+	if _, err := s.RollOnLoot("Alice", command.LootGreed); err != nil {
+		t.Fatalf("RollOnLoot failed: %v", err)
+	}
+	if _, err := s.RollOnLoot("Alice", command.LootNeed); err == nil {
+		t.Error("Expected a second roll on the same item to fail")
+	}
+	if _, err := s.RollOnLoot("Bob", command.LootNeed); err != nil {
+		t.Fatalf("RollOnLoot failed: %v", err)
+	}
+
+	if !players[1].HasItem("sword") {
+		t.Error("Expected Bob's need roll to win the sword")
+	}
+	if players[0].HasItem("sword") {
+		t.Error("Expected Alice's greed roll to lose")
+	}
+}
+
+// TestParty_EveryonePasses tests that an item nobody wants drops to the floor
+func TestParty_EveryonePasses(t *testing.T) {
+	s, players := newPartyTestServer(t, "Alice", "Bob")
+	formParty(t, s, players)
+	if err := s.SetPartyLootMode("Alice", command.LootNeedGreed); err != nil {
+		t.Fatalf("SetPartyLootMode failed: %v", err)
+	}
+
+	hall := s.world.GetRoom("hall")
+	s.distributeLoot(items.NewItem("rag", "A rag.", 0.1, items.Misc, 1), hall, []string{"Alice"})
+	for _, name := range []string{"Alice", "Bob"} {
+		if _, err := s.RollOnLoot(name, command.LootPass); err != nil {
+			t.Fatalf("RollOnLoot failed: %v", err)
+		}
+	}
+
+	if _, found := hall.FindItem("rag"); !found {
+		t.Error("Expected the passed item to land on the floor")
+	}
+}
@@ -174,6 +174,13 @@ func (s *Server) loadPlayer(client Client, auth *AuthResult) (*player.Player, er
 	// Load key ring
 	p.SetKeyRingFromString(char.KeyRing)
 
+	// Load death penalties: XP still owed, and wear on the items just loaded
+	p.SetXPDebt(char.XPDebt)
+	p.SetItemWearFromJSON(char.ItemWear)
+
+	// Load hardcore mode
+	p.SetHardcore(char.Hardcore)
+
 	// Load discovered portals
 	if char.DiscoveredPortals != "" {
 		portalStrs := strings.Split(char.DiscoveredPortals, ",")
@@ -262,6 +269,41 @@ func (s *Server) loadPlayer(client Client, auth *AuthResult) (*player.Player, er
 		p.SetStatisticsFromJSON(char.Statistics)
 	}
 
+	// Load custom prompt (ignore invalid data and fall back to the default)
+	if char.Prompt != "" {
+		_ = p.SetPrompt(char.Prompt)
+	}
+
+	// Load color preferences
+	if char.ColorPrefs != "" {
+		if err := p.SetColorPreferencesFromJSON(char.ColorPrefs); err != nil {
+			logger.Warning("Invalid color preferences", "character", char.Name, "error", err)
+		}
+	}
+
+	// Load chat channel preferences
+	if char.ChannelPrefs != "" {
+		if err := p.SetChannelPreferencesFromJSON(char.ChannelPrefs); err != nil {
+			logger.Warning("Invalid channel preferences", "character", char.Name, "error", err)
+		}
+	}
+
+	// Load friends list privacy
+	p.SetLocationHidden(char.HideLocation)
+
+	// Load command aliases
+	if char.Aliases != "" {
+		if err := p.SetAliasesFromJSON(char.Aliases); err != nil {
+			logger.Warning("Invalid aliases", "character", char.Name, "error", err)
+		}
+	}
+
+	// Load guild membership
+	s.loadGuildMembership(p)
+
+	// Take back the stall, which may have been selling while they were away
+	s.reclaimStall(p)
+
 	logger.Info("Player loaded",
 		"player", char.Name,
 		"player_level", char.Level,
@@ -293,6 +335,12 @@ func (s *Server) savePlayerImpl(p *player.Player) error {
 		return fmt.Errorf("player has no character ID")
 	}
 
+	// A fallen hardcore character was saved as they died; their memorial is the last word
+	if p.IsRetired() {
+		logger.Debug("Skipping save of retired character", "player", p.GetName())
+		return nil
+	}
+
 	// Build character data
 	char := &database.Character{
 		ID:                charID,
@@ -332,6 +380,13 @@ func (s *Server) savePlayerImpl(p *player.Player) error {
 		VisitedLabyrinthGates: p.GetVisitedLabyrinthGatesString(),
 		TalkedToLoreNPCs:      p.GetTalkedToLoreNPCsString(),
 		Statistics:            p.GetStatisticsJSON(),
+		Prompt:                p.GetCustomPrompt(),
+		ColorPrefs:            p.GetColorPreferencesJSON(),
+		ChannelPrefs:          p.GetChannelPreferencesJSON(),
+		HideLocation:          p.IsLocationHidden(),
+		Aliases:               p.GetAliasesJSON(),
+		XPDebt:                p.GetXPDebt(),
+		ItemWear:              p.GetItemWearJSON(),
 	}
 
 	// Get inventory and equipment IDs
@@ -361,6 +416,17 @@ func (s *Server) savePlayerImpl(p *player.Player) error {
 		return fmt.Errorf("failed to save character: %w", saveErr)
 	}
 
+	// An unattended stall is settled in the database as it sells, so it's left alone
+	if !s.hasUnattendedStall(p.GetName()) {
+		stallItems := make([]database.StallItem, 0, len(p.GetStallInventory()))
+		for _, si := range p.GetStallInventory() {
+			stallItems = append(stallItems, database.StallItem{ItemID: si.Item.ID, Price: si.Price})
+		}
+		if err := s.db.SaveStall(charID, p.GetName(), p.GetRoomID(), p.IsStallOpen(), stallItems); err != nil {
+			return fmt.Errorf("failed to save stall: %w", err)
+		}
+	}
+
 	logger.Debug("Player saved",
 		"player", p.GetName(),
 		"room", char.RoomID,
@@ -493,6 +559,9 @@ func (s *Server) handleDisconnect(p *player.Player) {
 	} else {
 		logger.Info("Auto-saved player on disconnect",
 			"player", p.GetName())
+
+		// An open stall keeps selling while the player is away
+		s.leaveStallUnattended(p)
 	}
 
 	// Remove from current room
@@ -11,6 +11,7 @@ import (
 
 	"github.com/gorilla/websocket"
 	"github.com/lawnchairsociety/opentowermud/server/internal/antispam"
+	"github.com/lawnchairsociety/opentowermud/server/internal/channels"
 	"github.com/lawnchairsociety/opentowermud/server/internal/chatfilter"
 	"github.com/lawnchairsociety/opentowermud/server/internal/command"
 	"github.com/lawnchairsociety/opentowermud/server/internal/config"
@@ -48,12 +49,37 @@ type Server struct {
 	db                  *database.Database
 	itemsConfig         *items.ItemsConfig
 	spellRegistry       *spells.SpellRegistry
+	mobConfig           *npc.NPCsConfig // Mob definitions, for adds summoned by NPC abilities and boss encounters
 	recipeRegistry      *crafting.RecipeRegistry
 	questRegistry       *quest.QuestRegistry
+	channelRegistry     *channels.Registry
+	channelHistory      map[string]*channels.History // Recent messages by channel ID
 	serverConfig        *config.ServerConfig
 	connLimiter         *ConnLimiter
 	loginRateLimiter    *LoginRateLimiter
 	bossTracker         *tower.BossTracker
+	tlsListener         net.Listener
+	certReloader        *certReloader
+	watching            map[string]*watchSession // Snoop/spectate sessions by lowercase watcher name
+	watchMu             sync.Mutex
+	parties             map[string]*party // Parties by lowercase member name
+	partyInvites        map[string]string // Pending invites: lowercase invitee -> inviter's name
+	lootRolls           []*lootRoll       // Open need/greed rolls
+	partyMu             sync.Mutex
+	trades              map[string]*tradeSession // Open trades by lowercase participant name
+	tradeRequests       map[string]string        // Pending requests: lowercase target -> requester's name
+	tradeMu             sync.Mutex
+	duels               map[string]*duelMatch    // Duels in progress by lowercase fighter name
+	duelChallenges      map[string]duelChallenge // Open challenges by lowercase target name
+	duelMu              sync.Mutex
+	unattendedStalls    map[string]*unattendedStall // Open stalls of offline players by lowercase owner name
+	stallMu             sync.Mutex
+	bossEncounterDefs   map[string]*tower.Encounter // Scripted boss fights by boss mob ID
+	bossEncounters      map[*npc.NPC]*bossEncounter // Scripted boss fights in progress
+	encounterMu         sync.Mutex
+	corpses             []*playerCorpse // Unrecovered player corpses, oldest first
+	nextCorpseID        int64           // Corpse IDs when there's no database to hand them out
+	corpseMu            sync.Mutex
 }
 
 func NewServer(address string, world *world.World, pilgrimMode bool) *Server {
@@ -89,6 +115,11 @@ func (s *Server) GetSpellRegistry() *spells.SpellRegistry {
 	return s.spellRegistry
 }
 
+// SetMobConfig sets the mob definitions that NPC abilities summon adds from
+func (s *Server) SetMobConfig(config *npc.NPCsConfig) {
+	s.mobConfig = config
+}
+
 // SetRecipeRegistry sets the recipe registry
 func (s *Server) SetRecipeRegistry(registry *crafting.RecipeRegistry) {
 	s.recipeRegistry = registry
@@ -224,10 +255,21 @@ func (s *Server) Start() error {
 
 	// Start the idle timeout checker
 	go s.startIdleTimeoutTicker()
+	go s.startLinkDeadTicker()
 
 	// Start the auto-save ticker
 	go s.startAutoSaveTicker()
 
+	// Start the auction expiry ticker
+	go s.startAuctionTicker()
+
+	// Reopen the stalls players left selling while offline
+	s.loadUnattendedStalls()
+
+	// Put back the corpses players hadn't recovered, and decay them on time
+	s.loadCorpses()
+	go s.startCorpseTicker()
+
 	for {
 		select {
 		case <-s.shutdown:
@@ -272,6 +314,10 @@ func (s *Server) handleConnection(conn net.Conn) {
 	}()
 
 	client := NewTelnetClient(conn)
+	if err := client.Negotiate(); err != nil {
+		logger.Debug("Telnet negotiation failed", "remote_addr", remoteAddr, "error", err)
+		return
+	}
 	s.handleClient(client)
 }
 
@@ -286,33 +332,39 @@ func (s *Server) handleClient(client Client) {
 		return
 	}
 
+	// Wrap the connection so admins can snoop and players can spectate
+	client = newFanOutClient(client)
+
+	// Reattach to the character if it is still in the world, either after a
+	// dropped connection or from an old connection that hasn't dropped yet
+	p, err := s.reattachSession(authResult.Character.Name, client)
+	if err != nil {
+		logger.Info("Reconnect refused", "character", authResult.Character.Name, "error", err)
+		client.WriteLine("That character is already being reconnected elsewhere. Please try again.\n")
+		return
+	}
+	if p != nil {
+		defer s.endSession(p, client)
+		p.ResumeSession()
+		return
+	}
+
 	// Check if this is a new player (never played before)
 	isNewPlayer := authResult.Character.LastPlayed == nil
 
 	// Load character data and create player
-	p, err := s.loadPlayer(client, authResult)
+	p, err = s.loadPlayer(client, authResult)
 	if err != nil {
 		logger.Error("Failed to load player", "character", authResult.Character.Name, "error", err)
 		client.WriteLine("Failed to load character. Please try again.\n")
 		return
 	}
 
-	name := p.GetName()
-
 	s.mu.Lock()
-	s.clients[name] = p
+	s.clients[p.GetName()] = p
 	s.mu.Unlock()
 
-	defer func() {
-		// Handle disconnect (save, combat penalty, etc.)
-		s.handleDisconnect(p)
-
-		logger.Info("Client disconnected", "player", name)
-
-		s.mu.Lock()
-		delete(s.clients, name)
-		s.mu.Unlock()
-	}()
+	defer s.endSession(p, client)
 
 	// Send special welcome message and starting equipment for new players
 	if isNewPlayer {
@@ -320,8 +372,10 @@ func (s *Server) handleClient(client Client) {
 		s.sendNewPlayerWelcome(p)
 	}
 
-	// Check for unread mail
+	// Check for unread mail and report stall sales made while offline
 	s.notifyUnreadMail(p)
+	s.reportStallSales(p)
+	s.notifyFriends(p, true)
 
 	// Handle player session
 	p.HandleSession()
@@ -353,6 +407,7 @@ func (s *Server) handleWebSocketUpgrade(w http.ResponseWriter, r *http.Request)
 	upgrader := websocket.Upgrader{
 		ReadBufferSize:  1024,
 		WriteBufferSize: 1024,
+		Subprotocols:    []string{JSONSubprotocol, GMCPSubprotocol},
 		CheckOrigin: func(r *http.Request) bool {
 			origin := r.Header.Get("Origin")
 			cfg := s.GetServerConfig()
@@ -425,6 +480,11 @@ func (s *Server) Shutdown() {
 		if s.listener != nil {
 			s.listener.Close()
 		}
+		s.mu.RLock()
+		if s.tlsListener != nil {
+			s.tlsListener.Close()
+		}
+		s.mu.RUnlock()
 
 		// Stop the respawn manager
 		s.respawnManager.Stop()
@@ -628,6 +688,11 @@ func (s *Server) GetAntispamConfig() *antispam.Config {
 	return &cfg
 }
 
+// GetInputConfig returns the command stacking and speedwalk settings
+func (s *Server) GetInputConfig() *config.InputConfig {
+	return &s.GetServerConfig().Input
+}
+
 // GetChatFilter returns the chat filter
 func (s *Server) GetChatFilter() *chatfilter.ChatFilter {
 	return s.chatFilter
@@ -656,6 +721,11 @@ func (s *Server) BroadcastToRoom(roomID string, message string, exclude interfac
 // BroadcastToRoomFromPlayer sends a message to all players in a specific room,
 // respecting ignore lists if senderName is provided
 func (s *Server) BroadcastToRoomFromPlayer(roomID string, message string, exclude interface{}, senderName string) {
+	kind := command.MessageSystem
+	if senderName != "" {
+		kind = command.MessageChat
+	}
+
 	// Get the room to access its player list
 	room := s.world.GetRoom(roomID)
 	if room == nil {
@@ -697,7 +767,7 @@ func (s *Server) BroadcastToRoomFromPlayer(roomID string, message string, exclud
 			continue
 		}
 
-		client.SendMessage(message)
+		client.SendTyped(kind, message)
 	}
 }
 
@@ -709,6 +779,11 @@ func (s *Server) BroadcastToFloor(floor int, message string, exclude interface{}
 // BroadcastToFloorFromPlayer sends a message to all players on a specific tower floor,
 // respecting ignore lists if senderName is provided
 func (s *Server) BroadcastToFloorFromPlayer(floor int, message string, exclude interface{}, senderName string) {
+	kind := command.MessageSystem
+	if senderName != "" {
+		kind = command.MessageChat
+	}
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -745,11 +820,49 @@ func (s *Server) BroadcastToFloorFromPlayer(floor int, message string, exclude i
 		}
 
 		if currentRoom.GetFloor() == floor {
-			client.SendMessage(message)
+			client.SendTyped(kind, message)
 		}
 	}
 }
 
+// SendChannelToRoom sends a Comm.Channel.Text GMCP package to everyone in a room,
+// respecting ignore lists. The plain-text message is delivered separately.
+func (s *Server) SendChannelToRoom(roomID string, channel string, talker string, text string) {
+	room := s.world.GetRoom(roomID)
+	if room == nil {
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, playerName := range room.GetPlayers() {
+		client, exists := s.clients[playerName]
+		if !exists || client.IsIgnoring(talker) {
+			continue
+		}
+		client.SendChannelGMCP(channel, talker, text)
+	}
+}
+
+// SendChannelToFloor sends a Comm.Channel.Text GMCP package to everyone on a floor,
+// respecting ignore lists. The plain-text message is delivered separately.
+func (s *Server) SendChannelToFloor(floor int, channel string, talker string, text string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, client := range s.clients {
+		if client.IsIgnoring(talker) {
+			continue
+		}
+		room, ok := client.GetCurrentRoom().(*world.Room)
+		if !ok || room == nil || room.GetFloor() != floor {
+			continue
+		}
+		client.SendChannelGMCP(channel, talker, text)
+	}
+}
+
 // startIdleTimeoutTicker runs a background ticker that disconnects idle players
 func (s *Server) startIdleTimeoutTicker() {
 	ticker := time.NewTicker(1 * time.Minute) // Check every minute
@@ -847,6 +960,10 @@ func (s *Server) checkIdlePlayers() {
 		if p.IsStallOpen() && len(p.GetStallInventory()) > 0 {
 			continue
 		}
+		// Link-dead players are handled by the link-dead grace period instead
+		if p.IsLinkDead() {
+			continue
+		}
 		if p.IsIdle(timeout) {
 			idlePlayers = append(idlePlayers, p)
 		}
@@ -878,6 +995,7 @@ func (s *Server) startRegenerationTicker() {
 			s.mu.RLock()
 			for _, client := range s.clients {
 				client.Regenerate()
+				client.UpdateGMCP()
 			}
 			s.mu.RUnlock()
 		}
@@ -903,18 +1021,50 @@ func (s *Server) startCombatTicker() {
 			}
 			s.mu.RUnlock()
 
+			// Remember who was fighting so they get a prompt even if the fight ended this round
+			wasInCombat := make(map[*player.Player]bool)
+			for _, p := range players {
+				if p.IsInCombat() || s.isDueling(p) {
+					wasInCombat[p] = true
+				}
+			}
+
 			// Process all player attacks first
 			for _, p := range players {
 				s.processPlayerAttack(p)
 			}
 
+			// Scripted boss fights change phase, enrage and land telegraphed attacks
+			s.processBossEncounters()
+
 			// Process all NPC attacks (one attack per NPC)
 			s.processNPCAttacks()
 
+			// Duels fight their own round between the two players
+			s.processDuels()
+
+			// Status effects tick: poison hurts and regeneration heals
+			for _, p := range players {
+				s.processPlayerEffects(p)
+			}
+			s.processNPCEffects()
+
 			// Check for aggressive NPCs attacking players
 			for _, p := range players {
 				s.checkAggressiveNPCs(p)
 			}
+
+			// Show the prompt to everyone involved in combat this round,
+			// and push vitals/status changes to GMCP clients
+			for _, p := range players {
+				if wasInCombat[p] || p.IsInCombat() || s.isDueling(p) {
+					p.SendTyped(command.MessagePrompt, p.GetStatusPrompt())
+				}
+				p.UpdateGMCP()
+			}
+
+			// Spectators stop watching once the fight they came for is over
+			s.checkSpectators()
 		}
 	}
 }
@@ -926,6 +1076,18 @@ func (s *Server) processPlayerAttack(p *player.Player) {
 		return
 	}
 
+	// A stunned player loses their attack
+	if p.IsStunned() {
+		p.SendTyped(command.MessageCombat, "\nYou are stunned and can't attack!\n")
+		return
+	}
+
+	// A defending player holds their guard instead of attacking
+	if p.IsDefending() {
+		p.SendTyped(command.MessageCombat, "\nYou hold your guard.\n")
+		return
+	}
+
 	// Get the room
 	roomIface := p.GetCurrentRoom()
 	if roomIface == nil {
@@ -944,7 +1106,7 @@ func (s *Server) processPlayerAttack(p *player.Player) {
 			"player", p.GetName(),
 			"target", p.GetCombatTarget())
 		p.EndCombat()
-		p.SendMessage("\nYour opponent has vanished!\n")
+		p.SendTyped(command.MessageCombat, "\nYour opponent has vanished!\n")
 		return
 	}
 
@@ -970,7 +1132,7 @@ func (s *Server) processPlayerAttack(p *player.Player) {
 
 	if attackRoll < npcAC {
 		// Miss!
-		p.SendMessage(fmt.Sprintf("\nYou %s %s... (%s vs AC %d) Miss!\n",
+		p.SendTyped(command.MessageCombat, fmt.Sprintf("\nYou %s {npc}%s{/}... (%s vs AC %d) Miss!\n",
 			attackVerb, npc.GetName(), attackBreakdown, npcAC))
 
 		// Notify other fighters
@@ -979,7 +1141,7 @@ func (s *Server) processPlayerAttack(p *player.Player) {
 			if targetName != p.GetName() {
 				if targetPlayerInterface := s.FindPlayer(targetName); targetPlayerInterface != nil {
 					if targetPlayer, ok := targetPlayerInterface.(*player.Player); ok {
-						targetPlayer.SendMessage(fmt.Sprintf("\n%s %s %s and misses!\n",
+						targetPlayer.SendTyped(command.MessageCombat, fmt.Sprintf("\n{player}%s{/} %s {npc}%s{/} and misses!\n",
 							p.GetName(), attackVerbThirdPerson, npc.GetName()))
 					}
 				}
@@ -1011,7 +1173,7 @@ func (s *Server) processPlayerAttack(p *player.Player) {
 		"target_max_hp", npc.GetMaxHealth())
 
 	// Send message to attacker with dice details
-	p.SendMessage(fmt.Sprintf("\nYou %s %s... (%s vs AC %d) Hit!\nYou deal %d damage! (%d/%d HP)\n",
+	p.SendTyped(command.MessageCombat, fmt.Sprintf("\nYou %s {npc}%s{/}... (%s vs AC %d) Hit!\nYou deal {damage}%d{/} damage! (%d/%d HP)\n",
 		attackVerb, npc.GetName(), attackBreakdown, npcAC, npcDamageTaken, npc.GetHealth(), npc.GetMaxHealth()))
 
 	// Send message to all other players fighting this NPC
@@ -1020,7 +1182,7 @@ func (s *Server) processPlayerAttack(p *player.Player) {
 		if targetName != p.GetName() {
 			if targetPlayerInterface := s.FindPlayer(targetName); targetPlayerInterface != nil {
 				if targetPlayer, ok := targetPlayerInterface.(*player.Player); ok {
-					targetPlayer.SendMessage(fmt.Sprintf("\n%s hits %s for %d damage! (%d/%d HP)\n",
+					targetPlayer.SendTyped(command.MessageCombat, fmt.Sprintf("\n{player}%s{/} hits {npc}%s{/} for {damage}%d{/} damage! (%d/%d HP)\n",
 						p.GetName(), npc.GetName(), npcDamageTaken, npc.GetHealth(), npc.GetMaxHealth()))
 				}
 			}
@@ -1104,9 +1266,14 @@ func (s *Server) processNPCAttacks() {
 				continue
 			}
 
-			// NPC attacks the target - roll d20 + level vs player AC
+			// NPCs with abilities may use one instead of attacking
+			if s.useNPCAbility(npc, room, targetPlayer) {
+				continue
+			}
+
+			// NPC attacks the target - roll d20 + level (+ hit buffs and debuffs) vs player AC
 			playerAC := targetPlayer.GetArmorClass()
-			npcAttackRoll := stats.D20() + npc.GetLevel()
+			npcAttackRoll := stats.D20() + npc.GetLevel() + npc.GetHitBonus()
 
 			if npcAttackRoll < playerAC {
 				// Miss!
@@ -1122,10 +1289,10 @@ func (s *Server) processNPCAttacks() {
 					if fighterInterface := s.FindPlayer(fighterName); fighterInterface != nil {
 						if fighter, ok := fighterInterface.(*player.Player); ok {
 							if fighterName == targetName {
-								fighter.SendMessage(fmt.Sprintf("%s attacks you... (%d vs AC %d) Miss!\n",
+								fighter.SendTyped(command.MessageCombat, fmt.Sprintf("{npc}%s{/} attacks you... (%d vs AC %d) Miss!\n",
 									npc.GetName(), npcAttackRoll, playerAC))
 							} else {
-								fighter.SendMessage(fmt.Sprintf("%s attacks %s and misses!\n",
+								fighter.SendTyped(command.MessageCombat, fmt.Sprintf("{npc}%s{/} attacks {player}%s{/} and misses!\n",
 									npc.GetName(), targetName))
 							}
 						}
@@ -1157,11 +1324,11 @@ func (s *Server) processNPCAttacks() {
 					if fighter, ok := fighterInterface.(*player.Player); ok {
 						if fighterName == targetName {
 							// Message for the target
-							fighter.SendMessage(fmt.Sprintf("%s attacks you... (%d vs AC %d) Hit! %d damage! (%d/%d HP)\n",
+							fighter.SendTyped(command.MessageCombat, fmt.Sprintf("{npc}%s{/} attacks you... (%d vs AC %d) Hit! {damage}%d{/} damage! (%d/%d HP)\n",
 								npc.GetName(), npcAttackRoll, playerAC, playerDamageTaken, targetPlayer.GetHealth(), targetPlayer.GetMaxHealth()))
 						} else {
 							// Message for other fighters
-							fighter.SendMessage(fmt.Sprintf("%s hits %s for %d damage!\n",
+							fighter.SendTyped(command.MessageCombat, fmt.Sprintf("{npc}%s{/} hits {player}%s{/} for {damage}%d{/} damage!\n",
 								npc.GetName(), targetName, playerDamageTaken))
 						}
 					}
@@ -1191,46 +1358,49 @@ func (s *Server) handleNPCDeath(npc *npc.NPC, room *world.Room) {
 		"attackers", strings.Join(attackers, ", "),
 		"attacker_count", len(attackers),
 		"xp_awarded", totalXP)
+	// Experience and gold are shared with party members on the same floor
+	recipients := s.rewardRecipients(attackers, room)
 	xpPerPlayer := totalXP
-	if len(attackers) > 1 {
-		xpPerPlayer = totalXP / len(attackers)
+	if len(recipients) > 1 {
+		xpPerPlayer = totalXP / len(recipients)
 	}
 
 	// Build attacker names list for broadcast
 	attackerNames := make([]string, 0, len(attackers))
 
-	// Award XP and send messages to all attackers
-	for _, attackerName := range attackers {
-		if attackerInterface := s.FindPlayer(attackerName); attackerInterface != nil {
-			attacker, ok := attackerInterface.(*player.Player)
-			if !ok {
-				continue
-			}
+	// Award XP and send messages to everyone sharing the kill
+	for _, attacker := range recipients {
+		attackerName := attacker.GetName()
+		fought := containsFold(attackers, attackerName)
 
-			// End combat
+		// End combat (party members who weren't fighting may be in a fight of their own)
+		if fought {
 			attacker.EndCombat()
+		}
 
-			// Award experience and check for level-ups
-			levelUps := attacker.GainExperience(xpPerPlayer)
+		// Award experience and check for level-ups
+		levelUps := attacker.GainExperience(xpPerPlayer)
 
-			// Send victory messages
-			if len(attackers) == 1 {
-				attacker.SendMessage(fmt.Sprintf("\nYou have slain %s!\n", npc.GetName()))
-				attacker.SendMessage(fmt.Sprintf("You gain %d experience points.\n", xpPerPlayer))
-			} else {
-				attacker.SendMessage(fmt.Sprintf("\nYour group has slain %s!\n", npc.GetName()))
-				attacker.SendMessage(fmt.Sprintf("You gain %d experience points (split %d ways).\n", xpPerPlayer, len(attackers)))
-			}
+		// Send victory messages
+		if len(recipients) == 1 {
+			attacker.SendTyped(command.MessageCombat, fmt.Sprintf("\nYou have slain {npc}%s{/}!\n", npc.GetName()))
+			attacker.SendTyped(command.MessageCombat, fmt.Sprintf("You gain %d experience points.\n", xpPerPlayer))
+		} else {
+			attacker.SendTyped(command.MessageCombat, fmt.Sprintf("\nYour group has slain {npc}%s{/}!\n", npc.GetName()))
+			attacker.SendTyped(command.MessageCombat, fmt.Sprintf("You gain %d experience points (split %d ways).\n", xpPerPlayer, len(recipients)))
+		}
 
-			// Send level-up notifications
-			for _, lu := range levelUps {
-				attacker.SendMessage(fmt.Sprintf("\n*** LEVEL UP! ***\n"))
-				attacker.SendMessage(fmt.Sprintf("You are now level %d!\n", lu.NewLevel))
-				attacker.SendMessage(fmt.Sprintf("Max Health increased by %d (now %d)\n", lu.HPGain, attacker.GetMaxHealth()))
-				attacker.SendMessage(fmt.Sprintf("Max Mana increased by %d (now %d)\n", lu.ManaGain, attacker.GetMaxMana()))
-				attacker.SendMessage("You feel completely refreshed!\n")
-			}
+		// Send level-up notifications
+		for _, lu := range levelUps {
+			attacker.SendTyped(command.MessageCombat, fmt.Sprintf("\n{system}*** LEVEL UP! ***{/}\n"))
+			attacker.SendTyped(command.MessageCombat, fmt.Sprintf("You are now level %d!\n", lu.NewLevel))
+			attacker.SendTyped(command.MessageCombat, fmt.Sprintf("Max Health increased by %d (now %d)\n", lu.HPGain, attacker.GetMaxHealth()))
+			attacker.SendTyped(command.MessageCombat, fmt.Sprintf("Max Mana increased by %d (now %d)\n", lu.ManaGain, attacker.GetMaxMana()))
+			attacker.SendTyped(command.MessageCombat, "You feel completely refreshed!\n")
+		}
 
+		// Kill credit (statistics, quests, boss titles) goes to those who fought
+		if fought {
 			attackerNames = append(attackerNames, attackerName)
 
 			// Record kill in player statistics
@@ -1255,7 +1425,7 @@ func (s *Server) handleNPCDeath(npc *npc.NPC, room *world.Room) {
 										if targetName == "" {
 											targetName = obj.Target
 										}
-										attacker.SendMessage(fmt.Sprintf("Quest progress: %s - %d/%d\n", targetName, current, obj.Required))
+										attacker.SendTyped(command.MessageCombat, fmt.Sprintf("Quest progress: %s - %d/%d\n", targetName, current, obj.Required))
 									}
 								}
 							}
@@ -1266,34 +1436,28 @@ func (s *Server) handleNPCDeath(npc *npc.NPC, room *world.Room) {
 		}
 	}
 
-	// Roll for gold drop and award to attackers
+	// Roll for gold drop and split it among everyone sharing the kill
 	goldDrop := npc.RollGold()
-	if goldDrop > 0 {
-		goldPerPlayer := goldDrop
-		if len(attackers) > 1 {
-			goldPerPlayer = goldDrop / len(attackers)
-		}
-		for _, attackerName := range attackers {
-			if attackerInterface := s.FindPlayer(attackerName); attackerInterface != nil {
-				if attacker, ok := attackerInterface.(*player.Player); ok {
-					attacker.AddGold(goldPerPlayer)
-					if len(attackers) == 1 {
-						attacker.SendMessage(fmt.Sprintf("You loot %d gold.\n", goldPerPlayer))
-					} else {
-						attacker.SendMessage(fmt.Sprintf("You loot %d gold (split %d ways).\n", goldPerPlayer, len(attackers)))
-					}
-				}
+	if goldDrop > 0 && len(recipients) > 0 {
+		goldPerPlayer := goldDrop / len(recipients)
+		for _, attacker := range recipients {
+			attacker.AddGold(goldPerPlayer)
+			if len(recipients) == 1 {
+				attacker.SendTyped(command.MessageCombat, fmt.Sprintf("You loot {gold}%d{/} gold.\n", goldPerPlayer))
+			} else {
+				attacker.SendTyped(command.MessageCombat, fmt.Sprintf("You loot {gold}%d{/} gold (split %d ways).\n", goldPerPlayer, len(recipients)))
 			}
 		}
 	}
 
-	// Roll for loot drops and add items to the room
+	// Roll for loot drops and hand them out by the party's loot rules
 	droppedLoot := npc.RollLoot()
 	if len(droppedLoot) > 0 && s.itemsConfig != nil {
+		var droppedItems []*items.Item
 		var droppedItemNames []string
 		for _, itemID := range droppedLoot {
 			if item, exists := s.itemsConfig.GetItemByID(itemID); exists {
-				room.AddItem(item)
+				droppedItems = append(droppedItems, item)
 				droppedItemNames = append(droppedItemNames, item.Name)
 			} else {
 				logger.Warning("Unknown item in loot drop", "item_id", itemID, "npc", npc.GetName())
@@ -1305,11 +1469,14 @@ func (s *Server) handleNPCDeath(npc *npc.NPC, room *world.Room) {
 			for _, attackerName := range attackers {
 				if attackerInterface := s.FindPlayer(attackerName); attackerInterface != nil {
 					if attacker, ok := attackerInterface.(*player.Player); ok {
-						attacker.SendMessage(lootMsg)
+						attacker.SendTyped(command.MessageCombat, lootMsg)
 					}
 				}
 			}
 		}
+		for _, item := range droppedItems {
+			s.distributeLoot(item, room, attackers)
+		}
 	}
 
 	// If this was a boss, drop the boss key
@@ -1323,7 +1490,7 @@ func (s *Server) handleNPCDeath(npc *npc.NPC, room *world.Room) {
 		for _, attackerName := range attackers {
 			if attackerInterface := s.FindPlayer(attackerName); attackerInterface != nil {
 				if attacker, ok := attackerInterface.(*player.Player); ok {
-					attacker.SendMessage(fmt.Sprintf("\n*** %s dropped a %s! ***\n", npc.GetName(), bossKey.Name))
+					attacker.SendTyped(command.MessageCombat, fmt.Sprintf("\n*** %s dropped a %s! ***\n", npc.GetName(), bossKey.Name))
 				}
 			}
 		}
@@ -1575,20 +1742,42 @@ func (s *Server) respawnNPC(npc *npc.NPC) {
 	s.BroadcastToRoom(originalRoomID, fmt.Sprintf("%s appears in the area.", npc.GetName()), nil)
 }
 
-// handlePlayerDeath handles what happens when a player dies
+// handlePlayerDeath handles what happens when a player is killed by an NPC
 func (s *Server) handlePlayerDeath(p *player.Player, npc *npc.NPC, room *world.Room) {
+	npc.EndCombat(p.GetName())
+	s.killPlayer(p, npc.GetName(), room)
+}
+
+// killPlayer handles what happens when a player dies, whatever killed them
+func (s *Server) killPlayer(p *player.Player, killedBy string, room *world.Room) {
 	// Log player death
 	logger.Info("Player died",
 		"player", p.GetName(),
-		"killed_by", npc.GetName(),
+		"killed_by", killedBy,
 		"room", room.GetID())
 
 	// Record death in player statistics
 	p.RecordDeath()
+	p.RecordDeathLocation(room.GetID())
 
-	// End combat for player and remove from NPC's target list
+	// End combat for player and remove from the NPC's target list
+	if p.IsInCombat() {
+		if opponent := room.FindNPC(p.GetCombatTarget()); opponent != nil {
+			opponent.EndCombat(p.GetName())
+		}
+	}
 	p.EndCombat()
-	npc.EndCombat(p.GetName())
+	p.ClearAfflictions()
+	p.StopDefending()
+
+	// A hardcore character has no respawn: they are retired to a memorial
+	if p.IsHardcore() {
+		s.retireHardcore(p, killedBy, room)
+		return
+	}
+
+	// Apply the server's death policy (XP debt, gold loss, wear, corpse)
+	penalties := s.applyDeathPenalties(p, room)
 
 	// Respawn at the spawn room of the tower the player died in
 	_, towerID := s.world.FindRoomWithTowerID(room.GetID())
@@ -1599,12 +1788,15 @@ func (s *Server) handlePlayerDeath(p *player.Player, npc *npc.NPC, room *world.R
 	respawnRoom := s.world.GetStartingRoomForTower(towerID)
 
 	// Send death message
-	// Note: No gold/XP penalty - respawning at town is the only penalty
-	p.SendMessage("\n\n*** YOU HAVE DIED ***\n")
-	p.SendMessage(fmt.Sprintf("You will respawn at %s.\n\n", respawnRoom.Name))
+	// Note: Unless server.yaml sets a death policy, respawning at town is the only penalty
+	p.SendTyped(command.MessageCombat, "\n\n{warning}*** YOU HAVE DIED ***{/}\n")
+	for _, penalty := range penalties {
+		p.SendTyped(command.MessageCombat, penalty+"\n")
+	}
+	p.SendTyped(command.MessageCombat, fmt.Sprintf("You will respawn at %s.\n\n", respawnRoom.Name))
 
 	// Broadcast to room
-	s.BroadcastToRoom(room.GetID(), fmt.Sprintf("%s has been slain by %s!", p.GetName(), npc.GetName()), p)
+	s.BroadcastToRoom(room.GetID(), fmt.Sprintf("%s has been slain by %s!", p.GetName(), killedBy), p)
 
 	// Respawn player
 	p.Health = p.GetMaxHealth()
@@ -1612,8 +1804,11 @@ func (s *Server) handlePlayerDeath(p *player.Player, npc *npc.NPC, room *world.R
 
 	// Move to respawn room
 	p.MoveTo(respawnRoom)
+	if len(penalties) > 0 {
+		s.savePlayerNow(p)
+	}
 
-	p.SendMessage(respawnRoom.GetDescriptionForPlayer(p.GetName()) + "\n")
+	p.SendTyped(command.MessageRoom, respawnRoom.GetDescriptionForPlayer(p.GetName()) + "\n")
 }
 
 // handleNPCFlee handles an NPC fleeing from combat
@@ -1652,7 +1847,7 @@ func (s *Server) handleNPCFlee(n *npc.NPC, room *world.Room) {
 	for _, targetName := range targets {
 		if targetPlayerInterface := s.FindPlayer(targetName); targetPlayerInterface != nil {
 			if targetPlayer, ok := targetPlayerInterface.(*player.Player); ok {
-				targetPlayer.SendMessage(fleeMessage)
+				targetPlayer.SendTyped(command.MessageCombat, fleeMessage)
 				targetPlayer.EndCombat()
 			}
 		}
@@ -1694,6 +1889,11 @@ func (s *Server) checkAggressiveNPCs(p *player.Player) {
 		return
 	}
 
+	// Skip link-dead players - they can't respond, so don't start new fights with them
+	if p.IsLinkDead() {
+		return
+	}
+
 	// Get the room
 	roomIface := p.GetCurrentRoom()
 	if roomIface == nil {
@@ -1727,7 +1927,7 @@ func (s *Server) checkAggressiveNPCs(p *player.Player) {
 		n.StartCombat(p.GetName())
 
 		// Send messages
-		p.SendMessage(fmt.Sprintf("\n%s attacks you!\n", n.GetName()))
+		p.SendTyped(command.MessageCombat, fmt.Sprintf("\n{npc}%s{/} attacks you!\n", n.GetName()))
 		s.BroadcastToRoom(room.GetID(), fmt.Sprintf("%s attacks %s!", n.GetName(), p.GetName()), p)
 
 		// Only allow one NPC to attack per tick
@@ -1780,12 +1980,16 @@ func (s *Server) GetOnlinePlayersDetailed() []command.PlayerInfo {
 
 	players := make([]command.PlayerInfo, 0, len(s.clients))
 	for _, p := range s.clients {
+		raw, sent, compressed := p.GetTrafficStats()
 		players = append(players, command.PlayerInfo{
-			Name:    p.GetName(),
-			Level:   p.GetLevel(),
-			RoomID:  p.GetRoomID(),
-			IP:      p.GetRemoteAddr(),
-			IsAdmin: p.IsAdmin(),
+			Name:       p.GetName(),
+			Level:      p.GetLevel(),
+			RoomID:     p.GetRoomID(),
+			IP:         p.GetRemoteAddr(),
+			IsAdmin:    p.IsAdmin(),
+			BytesRaw:   raw,
+			BytesSent:  sent,
+			Compressed: compressed,
 		})
 	}
 	return players
@@ -1820,6 +2024,12 @@ func (s *Server) KickPlayer(playerName string, reason string) bool {
 	// Disconnect the player
 	targetPlayer.Disconnect()
 
+	// A link-dead player has no session to clean up after them
+	if targetPlayer.IsLinkDead() {
+		targetPlayer.ClearLinkDead()
+		go s.removeLinkDeadPlayer(targetPlayer)
+	}
+
 	return true
 }
 
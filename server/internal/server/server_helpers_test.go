@@ -0,0 +1,42 @@
+package server
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/database"
+	"github.com/lawnchairsociety/opentowermud/server/internal/items"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+)
+
+// newDBTestServer creates a party test server backed by a database, with each
+// player given an account and a character and standing in the town square.
+func newDBTestServer(t *testing.T, names ...string) (*Server, []*player.Player) {
+	t.Helper()
+	s, players := newPartyTestServer(t, names...)
+
+	db, err := database.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	s.SetDatabase(db)
+	s.SetItemsConfig(&items.ItemsConfig{Items: map[string]items.ItemDefinition{
+		"long_sword": {Name: "long sword", Description: "A sword.", Weight: 1, Type: "weapon", Value: 50},
+	}})
+
+	for _, p := range players {
+		account, err := db.CreateAccount(p.GetName()+"_acct", "password123")
+		if err != nil {
+			t.Fatalf("Failed to create account: %v", err)
+		}
+		char, err := db.CreateCharacterWithClassAndRace(account.ID, p.GetName(), "warrior", "human", 15, 14, 13, 12, 10, 8)
+		if err != nil {
+			t.Fatalf("Failed to create character: %v", err)
+		}
+		p.SetAccountID(account.ID)
+		p.SetCharacterID(char.ID)
+		p.CurrentRoom = s.world.GetRoom("town_square")
+	}
+	return s, players
+}
@@ -0,0 +1,279 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+	"github.com/lawnchairsociety/opentowermud/server/internal/database"
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+)
+
+// unattendedStall is an open stall whose owner is offline. It keeps selling
+// from the room it was left in; the proceeds are mailed to the owner.
+type unattendedStall struct {
+	ownerName string
+	roomID    string
+	hardcore  bool // Only hardcore characters can buy from a hardcore owner
+	items     []unattendedItem
+}
+
+// unattendedItem is an item for sale in an unattended stall.
+type unattendedItem struct {
+	id        int64 // stall_items row, so the sale can be settled in the database
+	stallItem *command.StallItem
+}
+
+// newUnattendedStall builds an unattended stall from a saved one, skipping
+// items that no longer exist.
+func (s *Server) newUnattendedStall(saved *database.Stall) *unattendedStall {
+	stall := &unattendedStall{
+		ownerName: saved.CharacterName,
+		roomID:    saved.RoomID,
+	}
+	hardcore, _, err := s.db.GetCharacterMode(saved.CharacterID)
+	if err != nil {
+		logger.Warning("Failed to look up stall owner mode", "owner", stall.ownerName, "error", err)
+	}
+	stall.hardcore = hardcore
+	for _, si := range saved.Items {
+		item := s.CreateItem(si.ItemID)
+		if item == nil {
+			logger.Warning("Unknown item in stall", "owner", stall.ownerName, "item_id", si.ItemID)
+			continue
+		}
+		stall.items = append(stall.items, unattendedItem{
+			id:        si.ID,
+			stallItem: &command.StallItem{Item: item, Price: si.Price},
+		})
+	}
+	return stall
+}
+
+// loadUnattendedStalls sets up the open stalls saved in the database. Called at
+// startup, when every stall owner is offline.
+func (s *Server) loadUnattendedStalls() {
+	if s.db == nil {
+		return
+	}
+
+	saved, err := s.db.GetOpenStalls()
+	if err != nil {
+		logger.Error("Failed to load player stalls", "error", err)
+		return
+	}
+
+	s.stallMu.Lock()
+	defer s.stallMu.Unlock()
+	if s.unattendedStalls == nil {
+		s.unattendedStalls = make(map[string]*unattendedStall)
+	}
+	for _, st := range saved {
+		if stall := s.newUnattendedStall(st); len(stall.items) > 0 {
+			s.unattendedStalls[strings.ToLower(stall.ownerName)] = stall
+		}
+	}
+	if len(s.unattendedStalls) > 0 {
+		logger.Info("Player stalls loaded", "count", len(s.unattendedStalls))
+	}
+}
+
+// hasUnattendedStall returns true if the named player's stall is selling without them.
+func (s *Server) hasUnattendedStall(name string) bool {
+	s.stallMu.Lock()
+	defer s.stallMu.Unlock()
+	_, ok := s.unattendedStalls[strings.ToLower(name)]
+	return ok
+}
+
+// leaveStallUnattended keeps a departing player's open stall selling in their
+// room. The stall must already have been saved.
+func (s *Server) leaveStallUnattended(p *player.Player) {
+	if s.db == nil || !p.IsStallOpen() || len(p.GetStallInventory()) == 0 {
+		return
+	}
+
+	saved, err := s.db.LoadStall(p.GetCharacterID())
+	if err != nil || saved == nil || !saved.Open {
+		logger.Warning("Failed to leave stall unattended", "player", p.GetName(), "error", err)
+		return
+	}
+	stall := s.newUnattendedStall(saved)
+
+	s.stallMu.Lock()
+	if s.unattendedStalls == nil {
+		s.unattendedStalls = make(map[string]*unattendedStall)
+	}
+	s.unattendedStalls[strings.ToLower(p.GetName())] = stall
+	s.stallMu.Unlock()
+
+	// The items now belong to the unattended stall
+	p.ClearStall()
+
+	logger.Info("Stall left unattended", "player", p.GetName(), "room", stall.roomID, "items", len(stall.items))
+	s.BroadcastToRoom(stall.roomID, fmt.Sprintf("%s leaves their stall open, unattended.", p.GetName()), p)
+}
+
+// reclaimStall gives a logging-in player back their saved stall. An open stall
+// stays open if they are in the room it was left in.
+func (s *Server) reclaimStall(p *player.Player) {
+	if s.db == nil {
+		return
+	}
+
+	// Stop selling before loading, so the saved stall reflects every sale
+	s.stallMu.Lock()
+	delete(s.unattendedStalls, strings.ToLower(p.GetName()))
+	s.stallMu.Unlock()
+
+	saved, err := s.db.LoadStall(p.GetCharacterID())
+	if err != nil {
+		logger.Warning("Failed to load stall", "player", p.GetName(), "error", err)
+		return
+	}
+	if saved == nil {
+		return
+	}
+
+	for _, item := range s.newUnattendedStall(saved).items {
+		p.AddToStall(item.stallItem.Item, item.stallItem.Price)
+	}
+	if saved.Open && p.CurrentRoom != nil && p.CurrentRoom.GetID() == saved.RoomID {
+		p.OpenStall()
+	}
+}
+
+// reportStallSales tells a player what their stall sold while they were away.
+func (s *Server) reportStallSales(p *player.Player) {
+	if s.db == nil {
+		return
+	}
+
+	sales, err := s.db.TakeStallSales(p.GetCharacterID())
+	if err != nil {
+		logger.Warning("Failed to get stall sales", "player", p.GetName(), "error", err)
+		return
+	}
+	if len(sales) == 0 {
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n=== Stall Sales While You Were Away ===\n")
+	total := 0
+	for _, sale := range sales {
+		sb.WriteString(fmt.Sprintf("  %-25s %5d gold  to {player}%s{/}\n", sale.ItemName, sale.Price, sale.BuyerName))
+		total += sale.Price
+	}
+	sb.WriteString(fmt.Sprintf("Total: {gold}%d gold{/}. The proceeds are waiting at the mailbox.\n", total))
+	p.SendMessage(sb.String())
+}
+
+// GetUnattendedStall returns the owner's name and the items for sale in an
+// offline player's stall in the room, or false if there isn't one.
+func (s *Server) GetUnattendedStall(roomID, ownerName string) (string, []*command.StallItem, bool) {
+	s.stallMu.Lock()
+	defer s.stallMu.Unlock()
+
+	stall, ok := s.unattendedStalls[strings.ToLower(ownerName)]
+	if !ok || stall.roomID != roomID {
+		return "", nil, false
+	}
+	stallItems := make([]*command.StallItem, len(stall.items))
+	for i, item := range stall.items {
+		stallItems[i] = item.stallItem
+	}
+	return stall.ownerName, stallItems, true
+}
+
+// GetUnattendedStalls returns the item count of each offline player's stall in
+// the room, by owner name.
+func (s *Server) GetUnattendedStalls(roomID string) map[string]int {
+	s.stallMu.Lock()
+	defer s.stallMu.Unlock()
+
+	counts := make(map[string]int)
+	for _, stall := range s.unattendedStalls {
+		if stall.roomID == roomID {
+			counts[stall.ownerName] = len(stall.items)
+		}
+	}
+	return counts
+}
+
+// BuyFromUnattendedStall buys an item from an offline player's stall in the
+// buyer's room. The buyer pays now and the price is mailed to the owner.
+func (s *Server) BuyFromUnattendedStall(buyerName, ownerName, itemName string) (*command.StallItem, error) {
+	buyer := s.findOnlinePlayer(buyerName)
+	if buyer == nil || buyer.CurrentRoom == nil {
+		return nil, errors.New("You are not online.")
+	}
+	if s.db == nil {
+		return nil, errors.New("Stalls aren't available right now.")
+	}
+
+	s.stallMu.Lock()
+	defer s.stallMu.Unlock()
+
+	stall, ok := s.unattendedStalls[strings.ToLower(ownerName)]
+	if !ok || stall.roomID != buyer.CurrentRoom.GetID() {
+		return nil, fmt.Errorf("Player '%s' is not online.", ownerName)
+	}
+	if buyer.IsHardcore() != stall.hardcore {
+		return nil, errors.New("Hardcore characters can only trade with other hardcore characters.")
+	}
+
+	index := -1
+	partial := strings.ToLower(itemName)
+	for i, item := range stall.items {
+		if strings.Contains(strings.ToLower(item.stallItem.Item.Name), partial) {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return nil, fmt.Errorf("%s doesn't have '%s' in their stall.", stall.ownerName, itemName)
+	}
+	item := stall.items[index]
+	stallItem := item.stallItem
+
+	if buyer.GetGold() < stallItem.Price {
+		return nil, fmt.Errorf("You don't have enough gold. The %s costs %d gold, but you only have %d.",
+			stallItem.Item.Name, stallItem.Price, buyer.GetGold())
+	}
+	if !buyer.CanCarry(stallItem.Item) {
+		return nil, errors.New("You can't carry any more weight.")
+	}
+
+	buyer.SpendGold(stallItem.Price)
+	if err := s.db.BuyFromStall(item.id, buyer.GetName(), stallItem.Item.Name); err != nil {
+		buyer.AddGold(stallItem.Price)
+		if errors.Is(err, database.ErrStallItemGone) {
+			s.removeUnattendedItem(stall, index)
+			return nil, fmt.Errorf("The %s has already been sold.", stallItem.Item.Name)
+		}
+		logger.Error("Failed to buy from stall", "buyer", buyer.GetName(), "owner", stall.ownerName, "error", err)
+		return nil, errors.New("Something went wrong with the purchase.")
+	}
+	s.removeUnattendedItem(stall, index)
+	buyer.AddItem(stallItem.Item)
+
+	logger.Info("Unattended stall purchase",
+		"buyer", buyer.GetName(),
+		"seller", stall.ownerName,
+		"item", stallItem.Item.ID,
+		"price", stallItem.Price)
+
+	return stallItem, nil
+}
+
+// removeUnattendedItem takes an item off an unattended stall, removing the
+// stall once it is empty. Caller must hold stallMu.
+func (s *Server) removeUnattendedItem(stall *unattendedStall, index int) {
+	stall.items = append(stall.items[:index], stall.items[index+1:]...)
+	if len(stall.items) == 0 {
+		delete(s.unattendedStalls, strings.ToLower(stall.ownerName))
+	}
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+)
+
+// TestStall_SellsWhileOwnerOffline tests that an open stall keeps selling after
+// its owner logs out, and that they get it back with a sales report
+func TestStall_SellsWhileOwnerOffline(t *testing.T) {
+	s, players := newDBTestServer(t, "Alice", "Bob")
+	alice, bob := players[0], players[1]
+	alice.AddToStall(s.CreateItem("long_sword"), 40)
+	alice.OpenStall()
+	bob.SetGold(100)
+
+	s.handleDisconnect(alice)
+	s.removeClient(alice)
+
+	if counts := s.GetUnattendedStalls("town_square"); counts["Alice"] != 1 {
+		t.Fatalf("Expected Alice's stall to stay open with 1 item, got %v", counts)
+	}
+	if _, stallItems, ok := s.GetUnattendedStall("hall", "Alice"); ok || stallItems != nil {
+		t.Error("Expected the stall to only be found in the room it was left in")
+	}
+
+	if _, err := s.BuyFromUnattendedStall("Bob", "Alice", "axe"); err == nil {
+		t.Error("Expected buying an item the stall doesn't have to fail")
+	}
+	bought, err := s.BuyFromUnattendedStall("Bob", "Alice", "sword")
+	if err != nil {
+		t.Fatalf("BuyFromUnattendedStall failed: %v", err)
+	}
+	if bought.Price != 40 || !bob.HasItem("long sword") || bob.GetGold() != 60 {
+		t.Errorf("Expected Bob to pay 40 gold for the sword, has %d gold", bob.GetGold())
+	}
+	if counts := s.GetUnattendedStalls("town_square"); len(counts) != 0 {
+		t.Errorf("Expected the empty stall to be taken down, got %v", counts)
+	}
+
+	// Alice logs back in
+	client := &stubClient{}
+	returning := player.NewPlayer("Alice", client, s.world, s)
+	returning.SetCharacterID(alice.GetCharacterID())
+	returning.CurrentRoom = s.world.GetRoom("town_square")
+	s.reclaimStall(returning)
+	s.reportStallSales(returning)
+
+	if !returning.IsStallOpen() || len(returning.GetStallInventory()) != 0 {
+		t.Error("Expected Alice to get back her open, now empty stall")
+	}
+	report := strings.Join(client.lines, "")
+	if !strings.Contains(report, "long sword") || !strings.Contains(report, "Bob") {
+		t.Errorf("Expected a sales report naming the sword and Bob, got %q", report)
+	}
+
+	count, err := s.db.GetUnreadMailCount(alice.GetCharacterID())
+	if err != nil || count != 1 {
+		t.Errorf("Expected Alice to have 1 letter with the proceeds, got %d (%v)", count, err)
+	}
+}
+
+// TestStall_KeptBetweenSessions tests that a closed stall's items survive logging out
+func TestStall_KeptBetweenSessions(t *testing.T) {
+	s, players := newDBTestServer(t, "Alice")
+	alice := players[0]
+	alice.AddToStall(s.CreateItem("long_sword"), 25)
+
+	s.handleDisconnect(alice)
+	s.removeClient(alice)
+
+	if counts := s.GetUnattendedStalls("town_square"); len(counts) != 0 {
+		t.Errorf("Expected a closed stall not to sell while Alice is away, got %v", counts)
+	}
+
+	returning := player.NewPlayer("Alice", &stubClient{}, s.world, s)
+	returning.SetCharacterID(alice.GetCharacterID())
+	returning.CurrentRoom = s.world.GetRoom("town_square")
+	s.reclaimStall(returning)
+
+	stallItems := returning.GetStallInventory()
+	if returning.IsStallOpen() || len(stallItems) != 1 || stallItems[0].Price != 25 {
+		t.Errorf("Expected Alice's closed stall to hold the sword at 25 gold, got %d items", len(stallItems))
+	}
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+	"github.com/lawnchairsociety/opentowermud/server/internal/effects"
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+	"github.com/lawnchairsociety/opentowermud/server/internal/npc"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+	"github.com/lawnchairsociety/opentowermud/server/internal/stats"
+	"github.com/lawnchairsociety/opentowermud/server/internal/world"
+)
+
+// processPlayerEffects runs one combat round of a player's status effects:
+// poison hurts and regeneration heals
+func (s *Server) processPlayerEffects(p *player.Player) {
+	died := false
+	p.Effects().Tick(func(e effects.Effect) {
+		if died {
+			return
+		}
+		switch {
+		case e.Kind == effects.KindPoison:
+			damage := p.TakeMagicDamage(rollStatusAmount(e))
+			p.RecordDamageTaken(damage)
+			p.SendTyped(command.MessageCombat, fmt.Sprintf("\nPoison burns through your veins for {damage}%d{/} damage! (%d/%d HP)\n",
+				damage, p.GetHealth(), p.GetMaxHealth()))
+			if !p.IsAlive() && p.CurrentRoom != nil {
+				died = true
+				s.killPlayer(p, e.Source+"'s poison", p.CurrentRoom)
+			}
+		case e.Stat == effects.StatRegen:
+			if healed := p.Heal(e.Modifier()); healed > 0 {
+				p.SendTyped(command.MessageCombat, fmt.Sprintf("\n%s restores {heal}%d{/} health. (%d/%d HP)\n",
+					e.Name, healed, p.GetHealth(), p.GetMaxHealth()))
+			}
+		}
+	})
+}
+
+// processNPCEffects runs one combat round of every NPC's status effects.
+// An NPC poisoned to death is credited to the players it was fighting.
+func (s *Server) processNPCEffects() {
+	for _, room := range s.world.GetAllRooms() {
+		for _, n := range room.GetNPCs() {
+			if n.IsAlive() {
+				s.tickNPCEffects(n, room)
+			}
+		}
+	}
+}
+
+// tickNPCEffects runs one combat round of an NPC's status effects
+func (s *Server) tickNPCEffects(n *npc.NPC, room *world.Room) {
+	n.Effects().Tick(func(e effects.Effect) {
+		if !n.IsAlive() {
+			return
+		}
+		switch {
+		case e.Kind == effects.KindPoison:
+			damage := n.TakeMagicDamage(rollStatusAmount(e))
+			s.BroadcastToRoom(room.GetID(), fmt.Sprintf("\n{npc}%s{/} suffers {damage}%d{/} damage from %s! (%d/%d HP)\n",
+				n.GetName(), damage, e.Name, n.GetHealth(), n.GetMaxHealth()), nil)
+			if !n.IsAlive() {
+				logger.Debug("NPC killed by poison", "npc", n.GetName(), "source", e.Source, "room", room.GetID())
+				s.handleNPCDeath(n, room)
+			}
+		case e.Stat == effects.StatRegen:
+			n.Heal(e.Modifier())
+		}
+	})
+}
+
+// rollStatusAmount rolls a ticking effect's dice, falling back to its flat amount
+func rollStatusAmount(e effects.Effect) int {
+	amount := e.Amount
+	if e.Dice != "" {
+		amount = stats.ParseDice(e.Dice)
+	}
+	if amount < 1 {
+		amount = 1
+	}
+	return amount
+}
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+	"github.com/lawnchairsociety/opentowermud/server/internal/effects"
+	"github.com/lawnchairsociety/opentowermud/server/internal/npc"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+)
+
+const testStatusSpells = `spells:
+  test_mark:
+    name: "test mark"
+    effects:
+      - type: "debuff"
+        target: "enemy"
+        buff_type: "taken"
+        amount: 50
+        duration: 30
+  test_spikes:
+    name: "test spikes"
+    effects:
+      - type: "poison"
+        target: "room_enemy"
+        amount: 3
+        duration: 30
+  test_ward:
+    name: "test ward"
+    effects:
+      - type: "buff"
+        target: "self"
+        buff_type: "ac"
+        amount: 2
+        duration: 60
+  test_renew:
+    name: "test renew"
+    effects:
+      - type: "buff"
+        target: "self"
+        buff_type: "regen"
+        amount: 5
+        duration: 30
+  test_purify:
+    name: "test purify"
+    effects:
+      - type: "cleanse"
+        target: "self"
+`
+
+// newStatusTestServer sets up Alice alone in the hall with an ogre, with the
+// test spells loaded and plenty of mana to cast them
+func newStatusTestServer(t *testing.T) (*Server, *player.Player, *npc.NPC) {
+	t.Helper()
+	s, players := newPartyTestServer(t, "Alice")
+	loadTestSpells(t, s, testStatusSpells)
+
+	alice := players[0]
+	alice.Mana, alice.MaxMana = 100, 100
+	hall := s.world.GetRoom("hall")
+	hall.AddPlayer("Alice")
+	ogre := npc.NewNPC("ogre", "A big ogre.", 5, 100, 5, 0, 10, true, true, "hall", 0, 0)
+	hall.AddNPC(ogre)
+	return s, alice, ogre
+}
+
+// cast has a player cast a spell through the command parser
+func cast(s *Server, p *player.Player, args string) string {
+	return command.ParseCommand("cast "+args).Execute(p, s.world)
+}
+
+// TestStatusEffects_DebuffAndPoisonOnNPCs tests that player debuffs change the
+// damage an NPC takes, and that poison hurts it every round until it dies
+func TestStatusEffects_DebuffAndPoisonOnNPCs(t *testing.T) {
+	s, alice, ogre := newStatusTestServer(t)
+
+	cast(s, alice, "test_mark ogre")
+	if got := ogre.TakeMagicDamage(10); got != 15 {
+		t.Errorf("Expected a marked ogre to take 50%% more damage (15), took %d", got)
+	}
+	if !alice.IsInCombat() || !ogre.IsInCombat() {
+		t.Error("Expected a debuff to start the fight")
+	}
+
+	cast(s, alice, "test_spikes")
+	if !ogre.Effects().Has(effects.KindPoison) {
+		t.Fatal("Expected the ogre to be poisoned")
+	}
+	before := ogre.GetHealth()
+	s.processNPCEffects()
+	if got := before - ogre.GetHealth(); got != 4 {
+		t.Errorf("Expected poison to deal 3 damage (4 while marked), got %d", got)
+	}
+
+	ogre.Health = 1
+	xp := alice.GetExperience()
+	s.processNPCEffects()
+	if ogre.IsAlive() {
+		t.Fatal("Expected the poison to kill the ogre")
+	}
+	if alice.GetExperience() <= xp || alice.IsInCombat() {
+		t.Error("Expected Alice to be credited with the poison kill")
+	}
+	if len(s.world.GetRoom("hall").GetNPCs()) != 0 {
+		t.Error("Expected the dead ogre to leave the room")
+	}
+}
+
+// TestStatusEffects_MobTypeImmunity tests that undead shrug off poison
+func TestStatusEffects_MobTypeImmunity(t *testing.T) {
+	s, alice, ogre := newStatusTestServer(t)
+	ogre.SetMobType(npc.MobTypeUndead)
+
+	result := cast(s, alice, "test_spikes")
+	if ogre.Effects().Has(effects.KindPoison) {
+		t.Error("Expected an undead ogre not to be poisoned")
+	}
+	if !strings.Contains(result, "immune") {
+		t.Errorf("Expected the caster to be told the ogre is immune, got %q", result)
+	}
+
+	// Other effects still work
+	ogre.Stun(5)
+	if !ogre.IsStunned() {
+		t.Error("Expected poison immunity not to stop a stun")
+	}
+}
+
+// TestStatusEffects_BuffsRegenAndCleanse tests player buffs, regeneration each
+// round, the affects listing, and that cleansing only removes harmful effects
+func TestStatusEffects_BuffsRegenAndCleanse(t *testing.T) {
+	s, alice, _ := newStatusTestServer(t)
+
+	ac := alice.GetArmorClass()
+	cast(s, alice, "test_ward")
+	if got := alice.GetArmorClass(); got != ac+2 {
+		t.Errorf("Expected the ward to add 2 AC (%d), got %d", ac+2, got)
+	}
+
+	cast(s, alice, "test_renew")
+	alice.Health = alice.GetMaxHealth() - 20
+	s.processPlayerEffects(alice)
+	if got := alice.GetMaxHealth() - alice.GetHealth(); got != 15 {
+		t.Errorf("Expected regeneration to heal 5, still missing %d", got)
+	}
+
+	alice.Poison("spider", 2, 30)
+	alice.Stun(10)
+	listing := command.ParseCommand("affects").Execute(alice, s.world)
+	for _, want := range []string{"test ward", "+2 AC", "Poisoned", "Stunned"} {
+		if !strings.Contains(listing, want) {
+			t.Errorf("Expected affects to show %q, got:\n%s", want, listing)
+		}
+	}
+
+	// Stunned players can't cast, so let the stun lapse
+	alice.Effects().Remove(effects.KindStun)
+	cast(s, alice, "test_purify")
+	if alice.IsPoisoned() {
+		t.Error("Expected the cleanse to remove the poison")
+	}
+	if alice.GetArmorClass() != ac+2 {
+		t.Error("Expected the cleanse to leave buffs alone")
+	}
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"io"
+)
+
+// Telnet command bytes (RFC 854).
+const (
+	telnetSE   byte = 240 // End of subnegotiation
+	telnetNOP  byte = 241 // No operation
+	telnetGA   byte = 249 // Go ahead
+	telnetSB   byte = 250 // Begin subnegotiation
+	telnetWILL byte = 251
+	telnetWONT byte = 252
+	telnetDO   byte = 253
+	telnetDONT byte = 254
+	telnetIAC  byte = 255 // Interpret as command
+)
+
+// Telnet option codes supported by the server.
+const (
+	telnetOptEcho  byte = 1  // RFC 857
+	telnetOptSGA   byte = 3  // Suppress go-ahead, RFC 858
+	telnetOptTTYPE byte = 24 // Terminal type, RFC 1091 (with MTTS extension)
+	telnetOptNAWS  byte = 31 // Negotiate about window size, RFC 1073
+	telnetOptMCCP2 byte = 86 // Mud Client Compression Protocol v2
+)
+
+// Terminal type subnegotiation commands (RFC 1091).
+const (
+	ttypeIS   byte = 0
+	ttypeSEND byte = 1
+)
+
+// MTTS capability flags reported via the "MTTS <n>" terminal type.
+// See https://tintin.mudhalla.net/protocols/mtts/
+const (
+	MTTSAnsi            = 1
+	MTTSVT100           = 2
+	MTTSUTF8            = 4
+	MTTS256Colors       = 8
+	MTTSMouseTracking   = 16
+	MTTSOSCColorPalette = 32
+	MTTSScreenReader    = 64
+	MTTSProxy           = 128
+	MTTSTrueColor       = 256
+	MTTSMNES            = 512
+	MTTSMSLP            = 1024
+	MTTSSSL             = 2048
+)
+
+// maxSubnegotiationSize caps the buffered payload of a single SB ... SE sequence
+// so a misbehaving client can't grow the buffer without bound.
+const maxSubnegotiationSize = 8192
+
+// telnetParseState tracks where the parser is within the telnet byte stream.
+type telnetParseState int
+
+const (
+	telnetStateData telnetParseState = iota
+	telnetStateIAC
+	telnetStateOption // Saw IAC WILL/WONT/DO/DONT, waiting for option byte
+	telnetStateSB     // Saw IAC SB, waiting for option byte
+	telnetStateSBData // Inside subnegotiation payload
+	telnetStateSBIAC  // Saw IAC inside subnegotiation payload
+)
+
+// telnetHandler receives the negotiation events parsed out of the byte stream.
+type telnetHandler interface {
+	handleTelnetCommand(cmd, opt byte)
+	handleTelnetSubnegotiation(opt byte, data []byte)
+}
+
+// telnetReader strips telnet command sequences out of a raw connection stream,
+// forwarding negotiation to a telnetHandler and passing plain data through.
+type telnetReader struct {
+	r       io.Reader
+	handler telnetHandler
+	state   telnetParseState
+	command byte   // Pending WILL/WONT/DO/DONT
+	sbOpt   byte   // Option being subnegotiated
+	sbBuf   []byte // Subnegotiation payload
+	raw     []byte
+}
+
+// newTelnetReader creates a telnetReader wrapping r.
+func newTelnetReader(r io.Reader, handler telnetHandler) *telnetReader {
+	return &telnetReader{
+		r:       r,
+		handler: handler,
+		raw:     make([]byte, 4096),
+	}
+}
+
+// Read fills p with data bytes from the underlying reader, consuming any telnet
+// commands along the way. It only returns 0 bytes on error.
+func (t *telnetReader) Read(p []byte) (int, error) {
+	for {
+		// Filtered output is never larger than the raw input, so reading at most
+		// len(p) raw bytes guarantees the data fits.
+		size := len(p)
+		if size > len(t.raw) {
+			size = len(t.raw)
+		}
+		n, err := t.r.Read(t.raw[:size])
+		out := t.filter(t.raw[:n], p)
+		if out > 0 || err != nil {
+			return out, err
+		}
+	}
+}
+
+// filter runs the state machine over in, writing data bytes to out.
+// Returns the number of data bytes written.
+func (t *telnetReader) filter(in, out []byte) int {
+	n := 0
+	for _, b := range in {
+		switch t.state {
+		case telnetStateData:
+			switch b {
+			case telnetIAC:
+				t.state = telnetStateIAC
+			case 0:
+				// Telnet sends CR as CR NUL; drop the NUL
+			default:
+				out[n] = b
+				n++
+			}
+
+		case telnetStateIAC:
+			switch b {
+			case telnetIAC:
+				// Escaped 255 data byte
+				out[n] = b
+				n++
+				t.state = telnetStateData
+			case telnetWILL, telnetWONT, telnetDO, telnetDONT:
+				t.command = b
+				t.state = telnetStateOption
+			case telnetSB:
+				t.state = telnetStateSB
+			default:
+				// NOP, GA, AYT, etc. carry no data we need
+				t.state = telnetStateData
+			}
+
+		case telnetStateOption:
+			t.handler.handleTelnetCommand(t.command, b)
+			t.state = telnetStateData
+
+		case telnetStateSB:
+			t.sbOpt = b
+			t.sbBuf = t.sbBuf[:0]
+			t.state = telnetStateSBData
+
+		case telnetStateSBData:
+			if b == telnetIAC {
+				t.state = telnetStateSBIAC
+			} else if len(t.sbBuf) < maxSubnegotiationSize {
+				t.sbBuf = append(t.sbBuf, b)
+			}
+
+		case telnetStateSBIAC:
+			switch b {
+			case telnetSE:
+				t.handler.handleTelnetSubnegotiation(t.sbOpt, t.sbBuf)
+				t.state = telnetStateData
+			case telnetIAC:
+				if len(t.sbBuf) < maxSubnegotiationSize {
+					t.sbBuf = append(t.sbBuf, b)
+				}
+				t.state = telnetStateSBData
+			default:
+				// Malformed sequence; abandon the subnegotiation
+				t.state = telnetStateData
+			}
+		}
+	}
+	return n
+}
@@ -2,23 +2,90 @@ package server
 
 import (
 	"bufio"
+	"compress/zlib"
+	"io"
 	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/color"
 )
 
+// maxTTYPERequests is how many times the server cycles the terminal type
+// request. MTTS clients report client name, terminal type, then "MTTS <flags>".
+const maxTTYPERequests = 3
+
 // TelnetClient wraps a raw TCP connection for telnet-style communication.
+// Telnet option negotiation (IAC sequences) is handled transparently:
+// ReadLine only ever returns plain text.
 type TelnetClient struct {
 	conn    net.Conn
 	scanner *bufio.Scanner
 	writer  *bufio.Writer
+	writeMu sync.Mutex // Protects writer (negotiation replies are written from the read path)
+
+	// Output compression (MCCP2) and traffic accounting
+	wire      *countingWriter // Counts bytes actually sent on the socket
+	zw        *zlib.Writer    // Non-nil while MCCP2 compression is active
+	bytesRaw  atomic.Int64    // Bytes written before compression
+	bytesSent atomic.Int64    // Bytes written to the socket
+
+	// Negotiation state
+	mu            sync.RWMutex
+	localEnabled  [256]bool // Options the server has agreed to perform (WILL)
+	localPending  [256]bool // Options the server has offered and awaits a reply for
+	remoteEnabled [256]bool // Options the client has agreed to perform
+	remotePending [256]bool // Options the server has requested and awaits a reply for
+
+	// Values learned from negotiation
+	width        int
+	height       int
+	clientName   string
+	terminalType string
+	mttsFlags    int
+	ttypeCount   int
+	lastTTYPE    string
 }
 
 // NewTelnetClient creates a new TelnetClient from a TCP connection.
 func NewTelnetClient(conn net.Conn) *TelnetClient {
-	return &TelnetClient{
-		conn:    conn,
-		scanner: bufio.NewScanner(conn),
-		writer:  bufio.NewWriter(conn),
-	}
+	c := &TelnetClient{conn: conn}
+	c.wire = &countingWriter{w: conn, count: &c.bytesSent}
+	c.writer = bufio.NewWriter(c.wire)
+	c.scanner = bufio.NewScanner(newTelnetReader(conn, c))
+	return c
+}
+
+// countingWriter counts the bytes passing through to the underlying writer.
+type countingWriter struct {
+	w     io.Writer
+	count *atomic.Int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.count.Add(int64(n))
+	return n, err
+}
+
+// Negotiate sends the server's initial option requests to the client.
+// Clients that don't speak telnet simply ignore these.
+func (c *TelnetClient) Negotiate() error {
+	c.mu.Lock()
+	c.remotePending[telnetOptNAWS] = true
+	c.remotePending[telnetOptTTYPE] = true
+	c.localPending[telnetOptGMCP] = true
+	c.localPending[telnetOptMCCP2] = true
+	c.mu.Unlock()
+
+	return c.writeRaw(
+		[]byte{telnetIAC, telnetDO, telnetOptNAWS},
+		[]byte{telnetIAC, telnetDO, telnetOptTTYPE},
+		[]byte{telnetIAC, telnetWILL, telnetOptGMCP},
+		[]byte{telnetIAC, telnetWILL, telnetOptMCCP2},
+	)
 }
 
 // ReadLine reads a line from the connection (blocking).
@@ -35,23 +102,44 @@ func (c *TelnetClient) ReadLine() (string, error) {
 }
 
 // WriteLine writes a message followed by a newline to the client.
+// Any 0xFF bytes are doubled so the client doesn't read them as IAC.
 func (c *TelnetClient) WriteLine(message string) error {
-	if _, err := c.writer.WriteString(message); err != nil {
+	if strings.IndexByte(message, telnetIAC) != -1 {
+		message = string(escapeIAC([]byte(message)))
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	n, err := c.writer.WriteString(message)
+	c.bytesRaw.Add(int64(n))
+	if err != nil {
 		return err
 	}
-	return c.writer.Flush()
+	return c.flushLocked()
 }
 
-// Write writes raw bytes to the client.
+// Write writes data bytes to the client, doubling any 0xFF bytes like WriteLine.
+// Telnet commands go through writeRaw instead.
 func (c *TelnetClient) Write(data []byte) error {
-	if _, err := c.writer.Write(data); err != nil {
+	data = escapeIAC(data)
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	n, err := c.writer.Write(data)
+	c.bytesRaw.Add(int64(n))
+	if err != nil {
 		return err
 	}
-	return c.writer.Flush()
+	return c.flushLocked()
 }
 
-// Close closes the underlying connection.
+// Close closes the underlying connection, ending the compressed stream first if active.
 func (c *TelnetClient) Close() error {
+	c.writeMu.Lock()
+	if c.zw != nil {
+		c.writer.Flush()
+		c.zw.Close()
+		c.zw = nil
+	}
+	c.writeMu.Unlock()
 	return c.conn.Close()
 }
 
@@ -65,3 +153,305 @@ func (c *TelnetClient) RemoteAddr() string {
 func (c *TelnetClient) GetConn() net.Conn {
 	return c.conn
 }
+
+// SetEcho turns the client's local echo on or off.
+// Disabling echo is done by the server claiming WILL ECHO, which tells the
+// client the server is responsible for echoing (and then it doesn't).
+func (c *TelnetClient) SetEcho(enabled bool) error {
+	c.mu.Lock()
+	serverEchoes := c.localEnabled[telnetOptEcho] || c.localPending[telnetOptEcho]
+	if enabled == !serverEchoes {
+		c.mu.Unlock()
+		return nil
+	}
+	var cmd byte
+	if enabled {
+		c.localEnabled[telnetOptEcho] = false
+		c.localPending[telnetOptEcho] = false
+		cmd = telnetWONT
+	} else {
+		c.localPending[telnetOptEcho] = true
+		cmd = telnetWILL
+	}
+	c.mu.Unlock()
+
+	return c.writeRaw([]byte{telnetIAC, cmd, telnetOptEcho})
+}
+
+// WindowSize returns the terminal size reported via NAWS.
+// Returns 0, 0 if the client hasn't reported a size.
+func (c *TelnetClient) WindowSize() (width, height int) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.width, c.height
+}
+
+// TerminalType returns the terminal type reported via TTYPE (e.g., "XTERM-256COLOR").
+func (c *TelnetClient) TerminalType() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.terminalType
+}
+
+// ClientName returns the client name reported as the first TTYPE response (e.g., "MUDLET").
+func (c *TelnetClient) ClientName() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.clientName
+}
+
+// MTTSFlags returns the MTTS capability bitmask, or 0 if not reported.
+func (c *TelnetClient) MTTSFlags() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.mttsFlags
+}
+
+// ColorMode returns how color markup should be rendered for this client.
+// Clients that report 256-color support (via MTTS or their terminal type) get
+// xterm-256 colors; everything else gets basic ANSI, which every MUD client handles.
+func (c *TelnetClient) ColorMode() color.Mode {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.mttsFlags&MTTS256Colors != 0 || strings.Contains(strings.ToUpper(c.terminalType), "256COLOR") {
+		return color.Mode256
+	}
+	return color.ModeANSI
+}
+
+// TrafficStats returns the number of bytes written by the server before
+// compression, the number actually sent on the socket, and whether MCCP2
+// compression is currently active.
+func (c *TelnetClient) TrafficStats() (raw, sent int64, compressed bool) {
+	c.writeMu.Lock()
+	compressed = c.zw != nil
+	c.writeMu.Unlock()
+	return c.bytesRaw.Load(), c.bytesSent.Load(), compressed
+}
+
+// startCompression begins MCCP2 compression. Per the spec, the subnegotiation
+// announcing compression is the last uncompressed data the client receives.
+func (c *TelnetClient) startCompression() error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.zw != nil {
+		return nil
+	}
+	n, err := c.writer.Write([]byte{telnetIAC, telnetSB, telnetOptMCCP2, telnetIAC, telnetSE})
+	c.bytesRaw.Add(int64(n))
+	if err != nil {
+		return err
+	}
+	if err := c.writer.Flush(); err != nil {
+		return err
+	}
+	c.zw = zlib.NewWriter(c.wire)
+	c.writer.Reset(c.zw)
+	return nil
+}
+
+// stopCompression ends the zlib stream and returns to uncompressed output.
+func (c *TelnetClient) stopCompression() error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.zw == nil {
+		return nil
+	}
+	c.writer.Flush()
+	err := c.zw.Close()
+	c.zw = nil
+	c.writer.Reset(c.wire)
+	return err
+}
+
+// flushLocked pushes buffered output (and any pending compressed data) to the socket.
+// The caller must hold writeMu.
+func (c *TelnetClient) flushLocked() error {
+	if err := c.writer.Flush(); err != nil {
+		return err
+	}
+	if c.zw != nil {
+		return c.zw.Flush()
+	}
+	return nil
+}
+
+// GMCPEnabled returns true once the client has agreed to receive GMCP.
+func (c *TelnetClient) GMCPEnabled() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.localEnabled[telnetOptGMCP]
+}
+
+// SendGMCP sends a GMCP package as a telnet subnegotiation.
+// Does nothing if the client hasn't enabled GMCP.
+func (c *TelnetClient) SendGMCP(pkg string, data interface{}) error {
+	if !c.GMCPEnabled() {
+		return nil
+	}
+	payload, err := encodeGMCP(pkg, data)
+	if err != nil {
+		return err
+	}
+	return c.writeRaw(
+		[]byte{telnetIAC, telnetSB, telnetOptGMCP},
+		escapeIAC(payload),
+		[]byte{telnetIAC, telnetSE},
+	)
+}
+
+// supportsLocal returns true if the server is willing to perform the option
+// when the client asks for it unprompted.
+func supportsLocal(opt byte) bool {
+	return opt == telnetOptSGA
+}
+
+// supportsRemote returns true if the server wants the client to perform the option.
+func supportsRemote(opt byte) bool {
+	return opt == telnetOptNAWS || opt == telnetOptTTYPE
+}
+
+// handleTelnetCommand implements telnetHandler. Follows the RFC 1143 rules
+// closely enough to never reply to an acknowledgement (which would loop).
+func (c *TelnetClient) handleTelnetCommand(cmd, opt byte) {
+	var reply []byte
+	var enabledRemote, enabledLocal, disabledLocal bool
+
+	c.mu.Lock()
+	switch cmd {
+	case telnetWILL:
+		switch {
+		case c.remotePending[opt]:
+			c.remotePending[opt] = false
+			c.remoteEnabled[opt] = true
+			enabledRemote = true
+		case c.remoteEnabled[opt]:
+			// Already enabled, nothing to do
+		case supportsRemote(opt):
+			c.remoteEnabled[opt] = true
+			enabledRemote = true
+			reply = []byte{telnetIAC, telnetDO, opt}
+		default:
+			reply = []byte{telnetIAC, telnetDONT, opt}
+		}
+
+	case telnetWONT:
+		if c.remoteEnabled[opt] {
+			reply = []byte{telnetIAC, telnetDONT, opt}
+		}
+		c.remoteEnabled[opt] = false
+		c.remotePending[opt] = false
+
+	case telnetDO:
+		switch {
+		case c.localPending[opt]:
+			c.localPending[opt] = false
+			c.localEnabled[opt] = true
+			enabledLocal = true
+		case c.localEnabled[opt]:
+			// Already enabled, nothing to do
+		case supportsLocal(opt):
+			c.localEnabled[opt] = true
+			reply = []byte{telnetIAC, telnetWILL, opt}
+		default:
+			reply = []byte{telnetIAC, telnetWONT, opt}
+		}
+
+	case telnetDONT:
+		if c.localEnabled[opt] {
+			reply = []byte{telnetIAC, telnetWONT, opt}
+			disabledLocal = true
+		}
+		c.localEnabled[opt] = false
+		c.localPending[opt] = false
+	}
+	c.mu.Unlock()
+
+	// Compression must end before replying so the WONT arrives uncompressed
+	if disabledLocal && opt == telnetOptMCCP2 {
+		c.stopCompression()
+	}
+	if reply != nil {
+		c.writeRaw(reply)
+	}
+	if enabledRemote && opt == telnetOptTTYPE {
+		c.requestTerminalType()
+	}
+	if enabledLocal && opt == telnetOptMCCP2 {
+		c.startCompression()
+	}
+}
+
+// handleTelnetSubnegotiation implements telnetHandler.
+func (c *TelnetClient) handleTelnetSubnegotiation(opt byte, data []byte) {
+	switch opt {
+	case telnetOptNAWS:
+		if len(data) < 4 {
+			return
+		}
+		c.mu.Lock()
+		c.width = int(data[0])<<8 | int(data[1])
+		c.height = int(data[2])<<8 | int(data[3])
+		c.mu.Unlock()
+
+	case telnetOptTTYPE:
+		if len(data) < 1 || data[0] != ttypeIS {
+			return
+		}
+		if c.recordTerminalType(string(data[1:])) {
+			c.requestTerminalType()
+		}
+	}
+}
+
+// recordTerminalType stores one TTYPE response and returns true if the server
+// should ask again to walk the MTTS cycle.
+func (c *TelnetClient) recordTerminalType(value string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// A repeated value means the client has no more types to offer
+	if value == c.lastTTYPE {
+		return false
+	}
+	c.lastTTYPE = value
+
+	switch {
+	case strings.HasPrefix(strings.ToUpper(value), "MTTS "):
+		if flags, err := strconv.Atoi(strings.TrimSpace(value[5:])); err == nil {
+			c.mttsFlags = flags
+		}
+		return false
+	case c.ttypeCount == 1:
+		// First response is the client name; also use it as the terminal type
+		// in case the client doesn't cycle.
+		c.clientName = value
+		c.terminalType = value
+	default:
+		c.terminalType = value
+	}
+
+	return c.ttypeCount < maxTTYPERequests
+}
+
+// requestTerminalType asks the client for its (next) terminal type.
+func (c *TelnetClient) requestTerminalType() {
+	c.mu.Lock()
+	c.ttypeCount++
+	c.mu.Unlock()
+	c.writeRaw([]byte{telnetIAC, telnetSB, telnetOptTTYPE, ttypeSEND, telnetIAC, telnetSE})
+}
+
+// writeRaw writes one or more telnet command sequences in a single flush.
+func (c *TelnetClient) writeRaw(seqs ...[]byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	for _, seq := range seqs {
+		n, err := c.writer.Write(seq)
+		c.bytesRaw.Add(int64(n))
+		if err != nil {
+			return err
+		}
+	}
+	return c.flushLocked()
+}
@@ -0,0 +1,305 @@
+package server
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingHandler captures telnet events for inspection.
+type recordingHandler struct {
+	commands [][2]byte
+	subnegs  map[byte][]byte
+}
+
+func (h *recordingHandler) handleTelnetCommand(cmd, opt byte) {
+	h.commands = append(h.commands, [2]byte{cmd, opt})
+}
+
+func (h *recordingHandler) handleTelnetSubnegotiation(opt byte, data []byte) {
+	if h.subnegs == nil {
+		h.subnegs = make(map[byte][]byte)
+	}
+	h.subnegs[opt] = append([]byte(nil), data...)
+}
+
+// TestTelnetReader_StripsCommands tests that IAC sequences are removed from the data stream
+func TestTelnetReader_StripsCommands(t *testing.T) {
+	input := []byte{'l', 'o'}
+	input = append(input, telnetIAC, telnetWILL, telnetOptNAWS)
+	input = append(input, 'o')
+	input = append(input, telnetIAC, telnetSB, telnetOptNAWS, 0, 80, 0, 24, telnetIAC, telnetSE)
+	input = append(input, 'k', '\r', 0, '\n')
+
+	h := &recordingHandler{}
+	data, err := io.ReadAll(newTelnetReader(bytes.NewReader(input), h))
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+
+	if string(data) != "look\r\n" {
+		t.Errorf("Expected %q, got %q", "look\r\n", string(data))
+	}
+	if len(h.commands) != 1 || h.commands[0] != [2]byte{telnetWILL, telnetOptNAWS} {
+		t.Errorf("Expected one WILL NAWS command, got %v", h.commands)
+	}
+	if !bytes.Equal(h.subnegs[telnetOptNAWS], []byte{0, 80, 0, 24}) {
+		t.Errorf("Expected NAWS payload [0 80 0 24], got %v", h.subnegs[telnetOptNAWS])
+	}
+}
+
+// TestTelnetReader_EscapedIAC tests that IAC IAC is passed through as a single 255 byte,
+// both in data and inside subnegotiation
+func TestTelnetReader_EscapedIAC(t *testing.T) {
+	input := []byte{'a', telnetIAC, telnetIAC, 'b'}
+	input = append(input, telnetIAC, telnetSB, telnetOptNAWS, 0, telnetIAC, telnetIAC, 0, 24, telnetIAC, telnetSE)
+
+	h := &recordingHandler{}
+	data, _ := io.ReadAll(newTelnetReader(bytes.NewReader(input), h))
+
+	if !bytes.Equal(data, []byte{'a', 255, 'b'}) {
+		t.Errorf("Expected escaped IAC in data, got %v", data)
+	}
+	if !bytes.Equal(h.subnegs[telnetOptNAWS], []byte{0, 255, 0, 24}) {
+		t.Errorf("Expected escaped IAC in subnegotiation, got %v", h.subnegs[telnetOptNAWS])
+	}
+}
+
+// pipeTelnetClient creates a TelnetClient on one end of a pipe and collects
+// everything the server writes from the other end.
+func pipeTelnetClient(t *testing.T) (*TelnetClient, net.Conn, func() []byte) {
+	serverConn, peer := net.Pipe()
+	client := NewTelnetClient(serverConn)
+
+	var mu sync.Mutex
+	var received []byte
+	go func() {
+		buf := make([]byte, 256)
+		for {
+			n, err := peer.Read(buf)
+			mu.Lock()
+			received = append(received, buf[:n]...)
+			mu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	t.Cleanup(func() {
+		serverConn.Close()
+		peer.Close()
+	})
+
+	output := func() []byte {
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]byte(nil), received...)
+	}
+	return client, peer, output
+}
+
+// TestTelnetClient_NAWSAndMTTS tests window size and the MTTS terminal type cycle
+func TestTelnetClient_NAWSAndMTTS(t *testing.T) {
+	client, peer, output := pipeTelnetClient(t)
+
+	if err := client.Negotiate(); err != nil {
+		t.Fatalf("Negotiate failed: %v", err)
+	}
+	if !bytes.Contains(output(), []byte{telnetIAC, telnetDO, telnetOptTTYPE}) {
+		t.Fatal("Expected server to send DO TTYPE")
+	}
+
+	go func() {
+		peer.Write([]byte{telnetIAC, telnetWILL, telnetOptNAWS})
+		peer.Write([]byte{telnetIAC, telnetSB, telnetOptNAWS, 0, 120, 0, 40, telnetIAC, telnetSE})
+		peer.Write([]byte{telnetIAC, telnetWILL, telnetOptTTYPE})
+		for _, v := range []string{"MUDLET", "XTERM-256COLOR", "MTTS 137"} {
+			msg := []byte{telnetIAC, telnetSB, telnetOptTTYPE, ttypeIS}
+			msg = append(msg, v...)
+			msg = append(msg, telnetIAC, telnetSE)
+			peer.Write(msg)
+		}
+		peer.Write([]byte("look\r\n"))
+	}()
+
+	line, err := client.ReadLine()
+	if err != nil {
+		t.Fatalf("ReadLine failed: %v", err)
+	}
+	if line != "look" {
+		t.Errorf("Expected 'look', got %q", line)
+	}
+
+	if w, h := client.WindowSize(); w != 120 || h != 40 {
+		t.Errorf("Expected window 120x40, got %dx%d", w, h)
+	}
+	if client.ClientName() != "MUDLET" {
+		t.Errorf("Expected client name MUDLET, got %q", client.ClientName())
+	}
+	if client.TerminalType() != "XTERM-256COLOR" {
+		t.Errorf("Expected terminal type XTERM-256COLOR, got %q", client.TerminalType())
+	}
+	if client.MTTSFlags() != 137 {
+		t.Errorf("Expected MTTS flags 137, got %d", client.MTTSFlags())
+	}
+	if client.MTTSFlags()&MTTS256Colors == 0 {
+		t.Error("Expected 256 color support flag")
+	}
+
+	sendReq := []byte{telnetIAC, telnetSB, telnetOptTTYPE, ttypeSEND, telnetIAC, telnetSE}
+	if n := bytes.Count(output(), sendReq); n != maxTTYPERequests {
+		t.Errorf("Expected %d TTYPE SEND requests, got %d", maxTTYPERequests, n)
+	}
+}
+
+// TestTelnetClient_RefusesUnknownOptions tests that unsupported options are declined
+func TestTelnetClient_RefusesUnknownOptions(t *testing.T) {
+	client, peer, output := pipeTelnetClient(t)
+
+	go func() {
+		peer.Write([]byte{telnetIAC, telnetWILL, 99})
+		peer.Write([]byte{telnetIAC, telnetDO, 98})
+		peer.Write([]byte("hi\n"))
+	}()
+
+	if _, err := client.ReadLine(); err != nil {
+		t.Fatalf("ReadLine failed: %v", err)
+	}
+
+	out := output()
+	if !bytes.Contains(out, []byte{telnetIAC, telnetDONT, 99}) {
+		t.Error("Expected DONT for unsupported client option")
+	}
+	if !bytes.Contains(out, []byte{telnetIAC, telnetWONT, 98}) {
+		t.Error("Expected WONT for unsupported server option")
+	}
+}
+
+// TestTelnetClient_SetEcho tests echo suppression for password prompts
+func TestTelnetClient_SetEcho(t *testing.T) {
+	client, _, output := pipeTelnetClient(t)
+
+	client.SetEcho(false)
+	client.SetEcho(false) // Should not resend
+	client.SetEcho(true)
+
+	out := output()
+	if n := bytes.Count(out, []byte{telnetIAC, telnetWILL, telnetOptEcho}); n != 1 {
+		t.Errorf("Expected exactly one WILL ECHO, got %d", n)
+	}
+	if !bytes.Contains(out, []byte{telnetIAC, telnetWONT, telnetOptEcho}) {
+		t.Error("Expected WONT ECHO when echo is re-enabled")
+	}
+}
+
+// TestTelnetClient_EscapesIACInOutput tests that 0xFF in game text is doubled
+// so clients don't mistake it for a telnet command
+func TestTelnetClient_EscapesIACInOutput(t *testing.T) {
+	client, _, output := pipeTelnetClient(t)
+
+	client.WriteLine("say \xffhi")
+	client.Write([]byte{'a', telnetIAC, 'b'})
+
+	expected := []byte{'s', 'a', 'y', ' ', telnetIAC, telnetIAC, 'h', 'i', 'a', telnetIAC, telnetIAC, 'b'}
+	if out := output(); !bytes.Equal(out, expected) {
+		t.Errorf("Expected %v, got %v", expected, out)
+	}
+}
+
+// TestTelnetClient_GMCP tests that GMCP is only sent after the client agrees to it
+func TestTelnetClient_GMCP(t *testing.T) {
+	client, peer, output := pipeTelnetClient(t)
+
+	client.Negotiate()
+	client.SendGMCP("Char.Vitals", map[string]int{"hp": 10})
+	if bytes.Contains(output(), []byte{telnetIAC, telnetSB, telnetOptGMCP}) {
+		t.Fatal("Expected no GMCP before the client sends DO GMCP")
+	}
+
+	go func() {
+		peer.Write([]byte{telnetIAC, telnetDO, telnetOptGMCP})
+		peer.Write([]byte("hi\n"))
+	}()
+	if _, err := client.ReadLine(); err != nil {
+		t.Fatalf("ReadLine failed: %v", err)
+	}
+	if !client.GMCPEnabled() {
+		t.Fatal("Expected GMCP to be enabled after DO GMCP")
+	}
+
+	client.SendGMCP("Char.Vitals", map[string]int{"hp": 10})
+	expected := []byte{telnetIAC, telnetSB, telnetOptGMCP}
+	expected = append(expected, `Char.Vitals {"hp":10}`...)
+	expected = append(expected, telnetIAC, telnetSE)
+	if !bytes.Contains(output(), expected) {
+		t.Errorf("Expected GMCP subnegotiation %q in output", expected)
+	}
+}
+
+// TestTelnetClient_MCCP2 tests that output is zlib-compressed after the client agrees to MCCP2
+func TestTelnetClient_MCCP2(t *testing.T) {
+	client, peer, output := pipeTelnetClient(t)
+
+	go func() {
+		peer.Write([]byte{telnetIAC, telnetDO, telnetOptMCCP2})
+		peer.Write([]byte("hi\n"))
+	}()
+	// MCCP2 wasn't offered, so an unsolicited DO must be refused
+	if _, err := client.ReadLine(); err != nil {
+		t.Fatalf("ReadLine failed: %v", err)
+	}
+	if !bytes.Contains(output(), []byte{telnetIAC, telnetWONT, telnetOptMCCP2}) {
+		t.Fatal("Expected WONT MCCP2 for an unsolicited DO")
+	}
+
+	client.Negotiate()
+	go func() {
+		peer.Write([]byte{telnetIAC, telnetDO, telnetOptMCCP2})
+		peer.Write([]byte("hi\n"))
+	}()
+	if _, err := client.ReadLine(); err != nil {
+		t.Fatalf("ReadLine failed: %v", err)
+	}
+
+	// Everything so far, including the start sequence, went out uncompressed
+	if raw, sent, _ := client.TrafficStats(); raw != sent {
+		t.Errorf("Expected raw and sent bytes to match before compressed output, got raw=%d sent=%d", raw, sent)
+	}
+
+	message := strings.Repeat("A goblin swings at you and misses. ", 20)
+	client.WriteLine(message)
+
+	out := output()
+	start := []byte{telnetIAC, telnetSB, telnetOptMCCP2, telnetIAC, telnetSE}
+	idx := bytes.Index(out, start)
+	if idx == -1 {
+		t.Fatal("Expected MCCP2 start sequence")
+	}
+
+	zr, err := zlib.NewReader(bytes.NewReader(out[idx+len(start):]))
+	if err != nil {
+		t.Fatalf("Failed to open zlib stream: %v", err)
+	}
+	buf := make([]byte, len(message))
+	if _, err := io.ReadFull(zr, buf); err != nil {
+		t.Fatalf("Failed to decompress: %v", err)
+	}
+	if string(buf) != message {
+		t.Errorf("Decompressed output mismatch: %q", string(buf))
+	}
+
+	raw, sent, compressed := client.TrafficStats()
+	if !compressed {
+		t.Error("Expected compression to be active")
+	}
+	if sent >= raw {
+		t.Errorf("Expected fewer bytes sent than written, got raw=%d sent=%d", raw, sent)
+	}
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+)
+
+// tlsHandshakeTimeout bounds how long a client has to complete the TLS handshake.
+const tlsHandshakeTimeout = 10 * time.Second
+
+// certReloader serves a TLS certificate that can be reloaded from disk while
+// the listener is running.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+}
+
+// newCertReloader loads the certificate and key, failing if they can't be read.
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// reload re-reads the certificate and key. On failure the previous certificate
+// stays in use.
+func (r *certReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+	return nil
+}
+
+// getCertificate is used as tls.Config.GetCertificate so every handshake
+// picks up the most recently loaded certificate.
+func (r *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// StartTLS starts a telnet listener that requires TLS on the given address.
+// Connections are handled exactly like plain telnet once the handshake completes.
+func (s *Server) StartTLS(address, certFile, keyFile string) error {
+	reloader, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		return err
+	}
+
+	listener, err := tls.Listen("tcp", address, &tls.Config{
+		GetCertificate: reloader.getCertificate,
+		MinVersion:     tls.VersionTLS12,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start TLS server: %w", err)
+	}
+
+	s.mu.Lock()
+	s.tlsListener = listener
+	s.certReloader = reloader
+	s.mu.Unlock()
+
+	logger.Info("TLS telnet server listening", "address", address)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			select {
+			case <-s.shutdown:
+				return nil
+			default:
+				logger.Error("Error accepting TLS connection", "error", err)
+				continue
+			}
+		}
+
+		go s.handleTLSConnection(conn)
+	}
+}
+
+// handleTLSConnection completes the TLS handshake before handing the connection
+// to the telnet handler, so a client that never finishes it can't hold a slot open.
+func (s *Server) handleTLSConnection(conn net.Conn) {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		tlsConn.SetDeadline(time.Now().Add(tlsHandshakeTimeout))
+		if err := tlsConn.Handshake(); err != nil {
+			logger.Debug("TLS handshake failed", "remote_addr", conn.RemoteAddr().String(), "error", err)
+			conn.Close()
+			return
+		}
+		tlsConn.SetDeadline(time.Time{})
+	}
+	s.handleConnection(conn)
+}
+
+// ReloadTLSCertificate re-reads the TLS certificate and key from disk.
+// New connections use the new certificate; existing connections are unaffected.
+// Does nothing if the TLS listener isn't running.
+func (s *Server) ReloadTLSCertificate() error {
+	s.mu.RLock()
+	reloader := s.certReloader
+	s.mu.RUnlock()
+
+	if reloader == nil {
+		return nil
+	}
+	if err := reloader.reload(); err != nil {
+		return err
+	}
+	logger.Info("TLS certificate reloaded", "cert_file", reloader.certFile)
+	return nil
+}
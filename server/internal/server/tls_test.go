@@ -0,0 +1,96 @@
+package server
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/world"
+)
+
+// writeTestCert writes a self-signed certificate and key for commonName.
+func writeTestCert(t *testing.T, certFile, keyFile, commonName string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(certFile, certPEM, 0600); err != nil {
+		t.Fatalf("Failed to write certificate: %v", err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+}
+
+// TestCertReloader_Reload tests that a reload swaps in the new certificate and
+// that a failed reload keeps the old one
+func TestCertReloader_Reload(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	if _, err := newCertReloader(certFile, keyFile); err == nil {
+		t.Fatal("Expected an error for missing certificate files")
+	}
+
+	writeTestCert(t, certFile, keyFile, "first")
+	r, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("newCertReloader failed: %v", err)
+	}
+	first, _ := r.getCertificate(nil)
+
+	writeTestCert(t, certFile, keyFile, "second")
+	if err := r.reload(); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	second, _ := r.getCertificate(nil)
+	if bytes.Equal(first.Certificate[0], second.Certificate[0]) {
+		t.Fatal("Expected a new certificate after reload")
+	}
+
+	os.WriteFile(certFile, []byte("not a certificate"), 0600)
+	if err := r.reload(); err == nil {
+		t.Fatal("Expected an error reloading an invalid certificate")
+	}
+	current, _ := r.getCertificate(nil)
+	if !bytes.Equal(current.Certificate[0], second.Certificate[0]) {
+		t.Error("Expected the previous certificate to stay in use after a failed reload")
+	}
+}
+
+// TestReloadTLSCertificate_NotRunning tests that reloading without a TLS listener is a no-op
+func TestReloadTLSCertificate_NotRunning(t *testing.T) {
+	s := NewServer(":0", world.NewWorld(), false)
+	defer s.Shutdown()
+
+	if err := s.ReloadTLSCertificate(); err != nil {
+		t.Errorf("Expected no error without a TLS listener, got %v", err)
+	}
+}
@@ -0,0 +1,448 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+	"github.com/lawnchairsociety/opentowermud/server/internal/database"
+	"github.com/lawnchairsociety/opentowermud/server/internal/items"
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+)
+
+// tradeOffer is what one side of a trade puts up.
+type tradeOffer struct {
+	name     string
+	items    []*items.Item // The exact inventory items offered
+	gold     int
+	accepted bool
+}
+
+// itemNames returns the names of the offered items.
+func (o *tradeOffer) itemNames() []string {
+	names := make([]string, len(o.items))
+	for i, item := range o.items {
+		names[i] = item.Name
+	}
+	return names
+}
+
+// itemIDs returns the IDs of the offered items, for the audit log.
+func (o *tradeOffer) itemIDs() []string {
+	ids := make([]string, len(o.items))
+	for i, item := range o.items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+// isOffered returns true if this exact item is already in the offer.
+func (o *tradeOffer) isOffered(item *items.Item) bool {
+	for _, offered := range o.items {
+		if offered == item {
+			return true
+		}
+	}
+	return false
+}
+
+// tradeSession is an open trade window between two players. Nothing changes
+// hands until both sides accept the current offers.
+type tradeSession struct {
+	sides [2]*tradeOffer // Requester first
+}
+
+// offers returns the named player's side of the trade and their partner's.
+func (t *tradeSession) offers(name string) (mine, theirs *tradeOffer) {
+	if strings.EqualFold(t.sides[0].name, name) {
+		return t.sides[0], t.sides[1]
+	}
+	return t.sides[1], t.sides[0]
+}
+
+// resetAccepts withdraws both acceptances after an offer changes.
+func (t *tradeSession) resetAccepts() {
+	t.sides[0].accepted = false
+	t.sides[1].accepted = false
+}
+
+// tradeOf returns the trade a player is in, or nil. Caller must hold tradeMu.
+func (s *Server) tradeOf(name string) *tradeSession {
+	return s.trades[strings.ToLower(name)]
+}
+
+// closeTrade removes a trade from both participants. Caller must hold tradeMu.
+func (s *Server) closeTrade(t *tradeSession) {
+	for _, side := range t.sides {
+		delete(s.trades, strings.ToLower(side.name))
+	}
+}
+
+// notifyTradePartner tells the other side of a trade that something changed.
+func (s *Server) notifyTradePartner(partnerName, message string) {
+	if partner := s.findOnlinePlayer(partnerName); partner != nil {
+		partner.SendMessage(message)
+	}
+}
+
+// sameRoom returns true if both players are standing in the same room.
+func sameRoom(a, b *player.Player) bool {
+	return a.CurrentRoom != nil && b.CurrentRoom != nil && a.CurrentRoom.GetID() == b.CurrentRoom.GetID()
+}
+
+// RequestTrade asks another player in the same room to trade. If they already
+// asked to trade with the requester, the trade window opens instead.
+// Returns true if the window opened.
+func (s *Server) RequestTrade(fromName, toName string) (bool, error) {
+	from := s.findOnlinePlayer(fromName)
+	if from == nil {
+		return false, errors.New("You are not online.")
+	}
+	to := s.findOnlinePlayer(toName)
+	if to == nil || !sameRoom(from, to) {
+		return false, fmt.Errorf("%s is not here.", toName)
+	}
+	if to == from {
+		return false, errors.New("You can't trade with yourself.")
+	}
+	if from.IsHardcore() != to.IsHardcore() {
+		return false, errors.New("Hardcore characters can only trade with other hardcore characters.")
+	}
+	fromName, toName = from.GetName(), to.GetName()
+
+	s.tradeMu.Lock()
+	if s.tradeOf(fromName) != nil {
+		s.tradeMu.Unlock()
+		return false, errors.New("You are already trading. Type 'trade cancel' to stop.")
+	}
+	if s.tradeOf(toName) != nil {
+		s.tradeMu.Unlock()
+		return false, fmt.Errorf("%s is already trading with someone.", toName)
+	}
+
+	// They asked first: open the window
+	if requester, ok := s.tradeRequests[strings.ToLower(fromName)]; ok && strings.EqualFold(requester, toName) {
+		delete(s.tradeRequests, strings.ToLower(fromName))
+		t := &tradeSession{sides: [2]*tradeOffer{{name: toName}, {name: fromName}}}
+		if s.trades == nil {
+			s.trades = make(map[string]*tradeSession)
+		}
+		s.trades[strings.ToLower(fromName)] = t
+		s.trades[strings.ToLower(toName)] = t
+		s.tradeMu.Unlock()
+
+		logger.Debug("Trade opened", "player1", toName, "player2", fromName)
+		to.SendMessage(fmt.Sprintf("\n{player}%s{/} agrees to trade. Type 'trade' to see the trade window.\n", fromName))
+		return true, nil
+	}
+
+	if s.tradeRequests == nil {
+		s.tradeRequests = make(map[string]string)
+	}
+	s.tradeRequests[strings.ToLower(toName)] = fromName
+	s.tradeMu.Unlock()
+
+	// Don't reveal an ignore - the request just never arrives
+	if !to.IsIgnoring(fromName) {
+		to.SendMessage(fmt.Sprintf("\n{player}%s{/} wants to trade with you. Type 'trade %s' to open a trade window.\n", fromName, fromName))
+	}
+	return false, nil
+}
+
+// GetTradeWindow returns both sides of a player's open trade.
+func (s *Server) GetTradeWindow(name string) (command.TradeWindow, bool) {
+	s.tradeMu.Lock()
+	defer s.tradeMu.Unlock()
+
+	t := s.tradeOf(name)
+	if t == nil {
+		return command.TradeWindow{}, false
+	}
+	mine, theirs := t.offers(name)
+	return command.TradeWindow{
+		Partner: theirs.name,
+		Mine:    command.TradeOffer{Items: mine.itemNames(), Gold: mine.gold, Accepted: mine.accepted},
+		Theirs:  command.TradeOffer{Items: theirs.itemNames(), Gold: theirs.gold, Accepted: theirs.accepted},
+	}, true
+}
+
+// OfferTradeItem adds an item from the player's inventory to their offer.
+// Returns the name of the item offered.
+func (s *Server) OfferTradeItem(name, itemName string) (string, error) {
+	p := s.findOnlinePlayer(name)
+	if p == nil {
+		return "", errors.New("You are not online.")
+	}
+
+	s.tradeMu.Lock()
+	t := s.tradeOf(name)
+	if t == nil {
+		s.tradeMu.Unlock()
+		return "", errors.New("You aren't trading with anyone.")
+	}
+	mine, theirs := t.offers(name)
+
+	// Only items that aren't already on the table can be offered
+	var available []*items.Item
+	for _, item := range p.Inventory {
+		if !mine.isOffered(item) {
+			available = append(available, item)
+		}
+	}
+	item, found := items.FindItem(available, itemName)
+	if !found {
+		s.tradeMu.Unlock()
+		return "", fmt.Errorf("You don't have '%s' to offer.", itemName)
+	}
+	if item.Unique {
+		s.tradeMu.Unlock()
+		return "", fmt.Errorf("%s is bound to you and can't be traded.", item.Name)
+	}
+
+	mine.items = append(mine.items, item)
+	t.resetAccepts()
+	partner := theirs.name
+	s.tradeMu.Unlock()
+
+	s.notifyTradePartner(partner, fmt.Sprintf("\n{player}%s{/} offers {item}%s{/}.\n", p.GetName(), item.Name))
+	return item.Name, nil
+}
+
+// WithdrawTradeItem takes an item back out of the player's offer.
+// Returns the name of the item withdrawn.
+func (s *Server) WithdrawTradeItem(name, itemName string) (string, error) {
+	s.tradeMu.Lock()
+	t := s.tradeOf(name)
+	if t == nil {
+		s.tradeMu.Unlock()
+		return "", errors.New("You aren't trading with anyone.")
+	}
+	mine, theirs := t.offers(name)
+
+	item, found := items.FindItem(mine.items, itemName)
+	if !found {
+		s.tradeMu.Unlock()
+		return "", fmt.Errorf("You haven't offered '%s'.", itemName)
+	}
+	for i, offered := range mine.items {
+		if offered == item {
+			mine.items = append(mine.items[:i], mine.items[i+1:]...)
+			break
+		}
+	}
+	t.resetAccepts()
+	partner := theirs.name
+	s.tradeMu.Unlock()
+
+	s.notifyTradePartner(partner, fmt.Sprintf("\n{player}%s{/} takes back {item}%s{/}.\n", mine.name, item.Name))
+	return item.Name, nil
+}
+
+// OfferTradeGold sets how much gold the player puts up. Zero withdraws the gold.
+func (s *Server) OfferTradeGold(name string, amount int) error {
+	p := s.findOnlinePlayer(name)
+	if p == nil {
+		return errors.New("You are not online.")
+	}
+	if amount < 0 {
+		return errors.New("You can't offer a negative amount of gold.")
+	}
+	if amount > p.GetGold() {
+		return fmt.Errorf("You only have %d gold.", p.GetGold())
+	}
+
+	s.tradeMu.Lock()
+	t := s.tradeOf(name)
+	if t == nil {
+		s.tradeMu.Unlock()
+		return errors.New("You aren't trading with anyone.")
+	}
+	mine, theirs := t.offers(name)
+	if mine.gold == amount {
+		s.tradeMu.Unlock()
+		return nil
+	}
+	mine.gold = amount
+	t.resetAccepts()
+	partner := theirs.name
+	s.tradeMu.Unlock()
+
+	if amount == 0 {
+		s.notifyTradePartner(partner, fmt.Sprintf("\n{player}%s{/} takes back their gold.\n", p.GetName()))
+	} else {
+		s.notifyTradePartner(partner, fmt.Sprintf("\n{player}%s{/} offers {gold}%d{/} gold.\n", p.GetName(), amount))
+	}
+	return nil
+}
+
+// CancelTrade closes the player's trade window without exchanging anything.
+func (s *Server) CancelTrade(name string) error {
+	s.tradeMu.Lock()
+	t := s.tradeOf(name)
+	if t == nil {
+		s.tradeMu.Unlock()
+		return errors.New("You aren't trading with anyone.")
+	}
+	mine, theirs := t.offers(name)
+	s.closeTrade(t)
+	s.tradeMu.Unlock()
+
+	s.notifyTradePartner(theirs.name, fmt.Sprintf("\n{player}%s{/} cancels the trade.\n", mine.name))
+	return nil
+}
+
+// AcceptTrade accepts the current offers. Once both sides have accepted, the
+// trade is checked again and the items and gold change hands in one step.
+// Returns true if the trade completed. If the final check fails, both
+// acceptances are withdrawn and the window stays open.
+func (s *Server) AcceptTrade(name string) (bool, error) {
+	s.tradeMu.Lock()
+	t := s.tradeOf(name)
+	if t == nil {
+		s.tradeMu.Unlock()
+		return false, errors.New("You aren't trading with anyone.")
+	}
+	mine, theirs := t.offers(name)
+	mine.accepted = true
+	if !theirs.accepted {
+		s.tradeMu.Unlock()
+		s.notifyTradePartner(theirs.name, fmt.Sprintf("\n{player}%s{/} accepts the trade. Type 'trade accept' to complete it.\n", mine.name))
+		return false, nil
+	}
+
+	a := s.findOnlinePlayer(t.sides[0].name)
+	b := s.findOnlinePlayer(t.sides[1].name)
+	if a == nil || b == nil {
+		s.closeTrade(t)
+		s.tradeMu.Unlock()
+		return false, errors.New("Your trading partner is no longer here. The trade is cancelled.")
+	}
+	if err := s.checkTrade(t, a, b); err != nil {
+		t.resetAccepts()
+		s.tradeMu.Unlock()
+		s.notifyTradePartner(theirs.name, fmt.Sprintf("\nThe trade could not be completed: %s\n", err.Error()))
+		return false, err
+	}
+
+	s.exchangeTrade(t.sides[0], a, b)
+	s.exchangeTrade(t.sides[1], b, a)
+	s.closeTrade(t)
+	s.tradeMu.Unlock()
+
+	record := &database.TradeRecord{
+		Player1:      t.sides[0].name,
+		Player1Items: t.sides[0].itemIDs(),
+		Player1Gold:  t.sides[0].gold,
+		Player2:      t.sides[1].name,
+		Player2Items: t.sides[1].itemIDs(),
+		Player2Gold:  t.sides[1].gold,
+		RoomID:       a.GetRoomID(),
+	}
+	logger.Info("Trade completed",
+		"player1", record.Player1,
+		"player1_items", strings.Join(record.Player1Items, ","),
+		"player1_gold", record.Player1Gold,
+		"player2", record.Player2,
+		"player2_items", strings.Join(record.Player2Items, ","),
+		"player2_gold", record.Player2Gold)
+
+	if s.db != nil {
+		if err := s.db.LogTrade(record); err != nil {
+			logger.Error("Failed to record trade", "error", err)
+		}
+		for _, p := range []*player.Player{a, b} {
+			if err := s.savePlayerImpl(p); err != nil {
+				logger.Error("Failed to save player after trade", "player", p.GetName(), "error", err)
+			}
+		}
+	}
+
+	s.notifyTradePartner(theirs.name, fmt.Sprintf("\n{player}%s{/} accepts. The trade is complete.\n", mine.name))
+	return true, nil
+}
+
+// checkTrade verifies that both sides can still deliver their offers and that
+// each player can carry what they receive. Caller must hold tradeMu.
+func (s *Server) checkTrade(t *tradeSession, a, b *player.Player) error {
+	if !sameRoom(a, b) {
+		return errors.New("You must be in the same room to trade.")
+	}
+
+	players := [2]*player.Player{a, b}
+	for i, side := range t.sides {
+		p := players[i]
+		for _, item := range side.items {
+			if !holdsItem(p, item) {
+				return fmt.Errorf("%s no longer has %s.", p.GetName(), item.Name)
+			}
+		}
+		if p.GetGold() < side.gold {
+			return fmt.Errorf("%s no longer has %d gold.", p.GetName(), side.gold)
+		}
+	}
+
+	for i, p := range players {
+		outgoing := items.GetTotalWeight(t.sides[i].items)
+		incoming := items.GetTotalWeight(t.sides[1-i].items)
+		if p.GetCurrentWeight()-outgoing+incoming > p.MaxCarryWeight {
+			return fmt.Errorf("%s can't carry that much.", p.GetName())
+		}
+	}
+	return nil
+}
+
+// holdsItem returns true if this exact item is in the player's inventory.
+func holdsItem(p *player.Player, item *items.Item) bool {
+	for _, held := range p.Inventory {
+		if held == item {
+			return true
+		}
+	}
+	return false
+}
+
+// exchangeTrade moves one side's offer from giver to receiver. Caller must hold
+// tradeMu and have checked the trade.
+func (s *Server) exchangeTrade(side *tradeOffer, giver, receiver *player.Player) {
+	for _, item := range side.items {
+		for i, held := range giver.Inventory {
+			if held == item {
+				giver.Inventory = append(giver.Inventory[:i], giver.Inventory[i+1:]...)
+				break
+			}
+		}
+		receiver.AddItem(item)
+	}
+	if side.gold > 0 {
+		giver.SpendGold(side.gold)
+		receiver.AddGold(side.gold)
+	}
+}
+
+// removeFromTrade cancels a player's open trade and drops any trade requests to
+// or from them. Called when a player leaves the game.
+func (s *Server) removeFromTrade(p *player.Player) {
+	name := p.GetName()
+
+	s.tradeMu.Lock()
+	delete(s.tradeRequests, strings.ToLower(name))
+	for target, requester := range s.tradeRequests {
+		if strings.EqualFold(requester, name) {
+			delete(s.tradeRequests, target)
+		}
+	}
+	t := s.tradeOf(name)
+	var partner string
+	if t != nil {
+		_, theirs := t.offers(name)
+		partner = theirs.name
+		s.closeTrade(t)
+	}
+	s.tradeMu.Unlock()
+
+	if t != nil {
+		s.notifyTradePartner(partner, fmt.Sprintf("\n{player}%s{/} has left the game. The trade is cancelled.\n", name))
+	}
+}
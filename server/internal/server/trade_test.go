@@ -0,0 +1,168 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/items"
+)
+
+// openTrade has Alice ask Bob to trade and Bob agree.
+func openTrade(t *testing.T, s *Server) {
+	t.Helper()
+	if opened, err := s.RequestTrade("Alice", "Bob"); err != nil || opened {
+		t.Fatalf("RequestTrade(Alice, Bob) = %v, %v; want a pending request", opened, err)
+	}
+	if opened, err := s.RequestTrade("Bob", "Alice"); err != nil || !opened {
+		t.Fatalf("RequestTrade(Bob, Alice) = %v, %v; want the window to open", opened, err)
+	}
+}
+
+// TestTrade_Swap tests that items and gold change hands once both sides accept
+func TestTrade_Swap(t *testing.T) {
+	s, players := newPartyTestServer(t, "Alice", "Bob")
+	alice, bob := players[0], players[1]
+	sword := items.NewItem("sword", "A sword.", 1, items.Weapon, 50)
+	alice.AddItem(sword)
+	alice.SetGold(0)
+	bob.SetGold(100)
+
+	if _, err := s.OfferTradeItem("Alice", "sword"); err == nil {
+		t.Error("Expected offering without an open trade to fail")
+	}
+	openTrade(t, s)
+
+	if _, err := s.OfferTradeItem("Alice", "sword"); err != nil {
+		t.Fatalf("OfferTradeItem failed: %v", err)
+	}
+	if _, err := s.OfferTradeItem("Alice", "sword"); err == nil {
+		t.Error("Expected offering the same sword twice to fail")
+	}
+	if err := s.OfferTradeGold("Bob", 200); err == nil {
+		t.Error("Expected offering more gold than Bob has to fail")
+	}
+	if err := s.OfferTradeGold("Bob", 60); err != nil {
+		t.Fatalf("OfferTradeGold failed: %v", err)
+	}
+
+	window, ok := s.GetTradeWindow("Bob")
+	if !ok || window.Partner != "Alice" || len(window.Theirs.Items) != 1 || window.Mine.Gold != 60 {
+		t.Fatalf("Unexpected trade window: %+v", window)
+	}
+
+	if completed, err := s.AcceptTrade("Alice"); err != nil || completed {
+		t.Fatalf("AcceptTrade(Alice) = %v, %v; want to wait for Bob", completed, err)
+	}
+	if completed, err := s.AcceptTrade("Bob"); err != nil || !completed {
+		t.Fatalf("AcceptTrade(Bob) = %v, %v; want the trade to complete", completed, err)
+	}
+
+	if alice.HasItem("sword") || !bob.HasItem("sword") {
+		t.Error("Expected the sword to move from Alice to Bob")
+	}
+	if alice.GetGold() != 60 || bob.GetGold() != 40 {
+		t.Errorf("Expected Alice 60 gold and Bob 40, got %d and %d", alice.GetGold(), bob.GetGold())
+	}
+	if _, ok := s.GetTradeWindow("Alice"); ok {
+		t.Error("Expected the trade window to close")
+	}
+}
+
+// TestTrade_ChangeResetsAccept tests that changing an offer withdraws both acceptances
+func TestTrade_ChangeResetsAccept(t *testing.T) {
+	s, players := newPartyTestServer(t, "Alice", "Bob")
+	alice, bob := players[0], players[1]
+	alice.AddItem(items.NewItem("sword", "A sword.", 1, items.Weapon, 50))
+	alice.AddItem(items.NewItem("shield", "A shield.", 1, items.Armor, 50))
+	bob.SetGold(100)
+	openTrade(t, s)
+
+	s.OfferTradeItem("Alice", "sword")
+	s.OfferTradeGold("Bob", 50)
+	s.AcceptTrade("Bob")
+
+	// Alice swaps the sword for the shield after Bob accepted
+	s.WithdrawTradeItem("Alice", "sword")
+	s.OfferTradeItem("Alice", "shield")
+
+	if window, _ := s.GetTradeWindow("Bob"); window.Mine.Accepted {
+		t.Error("Expected Bob's acceptance to be withdrawn")
+	}
+	if completed, _ := s.AcceptTrade("Alice"); completed {
+		t.Error("Expected the trade to wait for Bob to accept again")
+	}
+	if !alice.HasItem("shield") || bob.GetGold() != 100 {
+		t.Error("Expected nothing to change hands yet")
+	}
+}
+
+// TestTrade_FinalChecks tests that a trade which can no longer go through
+// changes nothing and stays open
+func TestTrade_FinalChecks(t *testing.T) {
+	s, players := newPartyTestServer(t, "Alice", "Bob")
+	alice, bob := players[0], players[1]
+	anvil := items.NewItem("anvil", "A heavy anvil.", 50, items.Misc, 10)
+	alice.AddItem(anvil)
+	bob.MaxCarryWeight = 10
+	openTrade(t, s)
+
+	s.OfferTradeItem("Alice", "anvil")
+	s.AcceptTrade("Alice")
+	if completed, err := s.AcceptTrade("Bob"); err == nil || completed {
+		t.Fatal("Expected the trade to fail because Bob can't carry the anvil")
+	}
+	if !alice.HasItem("anvil") || bob.HasItem("anvil") {
+		t.Error("Expected the anvil to stay with Alice")
+	}
+	if window, ok := s.GetTradeWindow("Alice"); !ok || window.Mine.Accepted {
+		t.Error("Expected the trade to stay open with acceptances withdrawn")
+	}
+
+	// Alice drops the anvil while the offer stands
+	bob.MaxCarryWeight = 100
+	alice.RemoveItem("anvil")
+	s.AcceptTrade("Alice")
+	if completed, err := s.AcceptTrade("Bob"); err == nil || completed {
+		t.Fatal("Expected the trade to fail because Alice no longer has the anvil")
+	}
+	if bob.HasItem("anvil") {
+		t.Error("Expected Bob not to receive an item Alice no longer has")
+	}
+}
+
+// TestTrade_UniqueItemsRefused tests that unique items can't be offered
+func TestTrade_UniqueItemsRefused(t *testing.T) {
+	s, players := newPartyTestServer(t, "Alice", "Bob")
+	trophy := items.NewItem("trophy", "A boss trophy.", 1, items.Misc, 0)
+	trophy.Unique = true
+	players[0].AddItem(trophy)
+	openTrade(t, s)
+
+	if _, err := s.OfferTradeItem("Alice", "trophy"); err == nil {
+		t.Error("Expected offering a unique item to fail")
+	}
+}
+
+// TestTrade_RequiresSameRoom tests that players can only trade with someone beside them
+func TestTrade_RequiresSameRoom(t *testing.T) {
+	s, players := newPartyTestServer(t, "Alice", "Bob")
+	players[1].CurrentRoom = s.world.GetRoom("corridor")
+
+	if _, err := s.RequestTrade("Alice", "Bob"); err == nil {
+		t.Error("Expected a trade request across rooms to fail")
+	}
+}
+
+// TestTrade_CancelledOnLogout tests that leaving the game cancels an open trade
+func TestTrade_CancelledOnLogout(t *testing.T) {
+	s, players := newPartyTestServer(t, "Alice", "Bob", "Carol")
+	openTrade(t, s)
+	if _, err := s.RequestTrade("Carol", "Alice"); err == nil {
+		t.Error("Expected a request to a player who is already trading to fail")
+	}
+
+	s.removeFromTrade(players[0])
+
+	if _, ok := s.GetTradeWindow("Bob"); ok {
+		t.Error("Expected Bob's trade to be cancelled when Alice logs out")
+	}
+}
@@ -0,0 +1,203 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/logger"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+	"github.com/lawnchairsociety/opentowermud/server/internal/world"
+)
+
+// watchSession records who a player is watching. Snooping admins can still play;
+// spectators are read-only until they stop watching.
+type watchSession struct {
+	target   *player.Player
+	spectate bool
+}
+
+// findOnlinePlayer returns the online player with the given name (case-insensitive).
+func (s *Server) findOnlinePlayer(name string) *player.Player {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for playerName, p := range s.clients {
+		if strings.EqualFold(playerName, name) {
+			return p
+		}
+	}
+	return nil
+}
+
+// StartSnoop mirrors everything sent to a player to an admin's connection.
+// Whether the target is told depends on the admin.snoop_notify setting.
+func (s *Server) StartSnoop(watcherName, targetName string) error {
+	watcher, target, err := s.startWatch(watcherName, targetName, false)
+	if err != nil {
+		return err
+	}
+
+	cfg := s.GetServerConfig()
+	if cfg.Admin.NotifySnoopTarget(target.IsAdmin()) {
+		target.SendMessage(fmt.Sprintf("\n*** %s is now watching your session. ***\n", watcher.GetName()))
+	}
+	return nil
+}
+
+// StartSpectate mirrors a player's fight to a read-only spectator. Players can only
+// spectate boss fights; admins can spectate any fight.
+func (s *Server) StartSpectate(watcherName, targetName string) error {
+	watcher, target, err := s.startWatch(watcherName, targetName, true)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Spectate started", "spectator", watcher.GetName(), "target", target.GetName())
+	target.SendMessage(fmt.Sprintf("\n{player}%s{/} is now spectating your fight.\n", watcher.GetName()))
+	return nil
+}
+
+// startWatch attaches watcher as an observer of target's connection.
+func (s *Server) startWatch(watcherName, targetName string, spectate bool) (*player.Player, *player.Player, error) {
+	watcher := s.findOnlinePlayer(watcherName)
+	if watcher == nil {
+		return nil, nil, errors.New("You are not online.")
+	}
+	target := s.findOnlinePlayer(targetName)
+	if target == nil {
+		return nil, nil, fmt.Errorf("Player '%s' is not online.", targetName)
+	}
+	if target == watcher {
+		return nil, nil, errors.New("You can't watch yourself.")
+	}
+	if target.IsLinkDead() {
+		return nil, nil, fmt.Errorf("%s is link-dead.", target.GetName())
+	}
+	if spectate {
+		if watcher.IsInCombat() {
+			return nil, nil, errors.New("You can't spectate while you're fighting!")
+		}
+		if !target.IsInCombat() {
+			return nil, nil, fmt.Errorf("%s isn't in a fight.", target.GetName())
+		}
+		if !watcher.IsAdmin() && !s.isInBossFight(target) {
+			return nil, nil, fmt.Errorf("%s isn't fighting a boss.", target.GetName())
+		}
+	}
+	fc, ok := target.GetClient().(*fanOutClient)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s's connection can't be watched.", target.GetName())
+	}
+
+	// Watching someone new ends the previous session
+	s.StopWatching(watcher.GetName())
+
+	prefix := fmt.Sprintf("[snoop %s] ", target.GetName())
+	if spectate {
+		prefix = fmt.Sprintf("[%s] ", target.GetName())
+	}
+	fc.addObserver(watcher.GetName(), watcher.GetClient(), prefix)
+
+	s.watchMu.Lock()
+	if s.watching == nil {
+		s.watching = make(map[string]*watchSession)
+	}
+	s.watching[strings.ToLower(watcher.GetName())] = &watchSession{target: target, spectate: spectate}
+	s.watchMu.Unlock()
+
+	return watcher, target, nil
+}
+
+// StopWatching ends a player's snoop or spectate session.
+// Returns the name of the player they were watching, or "" if they weren't watching anyone.
+func (s *Server) StopWatching(watcherName string) string {
+	key := strings.ToLower(watcherName)
+
+	s.watchMu.Lock()
+	session, ok := s.watching[key]
+	delete(s.watching, key)
+	s.watchMu.Unlock()
+
+	if !ok {
+		return ""
+	}
+
+	if fc, ok := session.target.GetClient().(*fanOutClient); ok {
+		fc.removeObserver(watcherName)
+	}
+
+	target := session.target
+	if session.spectate {
+		target.SendMessage(fmt.Sprintf("\n{player}%s{/} stopped spectating your fight.\n", watcherName))
+	} else if s.GetServerConfig().Admin.NotifySnoopTarget(target.IsAdmin()) {
+		target.SendMessage(fmt.Sprintf("\n*** %s is no longer watching your session. ***\n", watcherName))
+	}
+	return target.GetName()
+}
+
+// GetWatchTarget returns who a player is watching and whether they are a read-only
+// spectator. Returns "" if they aren't watching anyone.
+func (s *Server) GetWatchTarget(watcherName string) (targetName string, spectating bool) {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+	session, ok := s.watching[strings.ToLower(watcherName)]
+	if !ok {
+		return "", false
+	}
+	return session.target.GetName(), session.spectate
+}
+
+// releaseWatchers ends every session watching p, and any session p had open.
+// Called when a player leaves the game.
+func (s *Server) releaseWatchers(p *player.Player) {
+	s.StopWatching(p.GetName())
+
+	s.watchMu.Lock()
+	var watchers []string
+	for name, session := range s.watching {
+		if session.target == p {
+			watchers = append(watchers, name)
+			delete(s.watching, name)
+		}
+	}
+	s.watchMu.Unlock()
+
+	for _, name := range watchers {
+		if watcher := s.findOnlinePlayer(name); watcher != nil {
+			watcher.SendMessage(fmt.Sprintf("\n%s has left the game. You stop watching.\n", p.GetName()))
+		}
+	}
+}
+
+// checkSpectators ends spectate sessions whose fight is over.
+// Called from the combat ticker.
+func (s *Server) checkSpectators() {
+	s.watchMu.Lock()
+	var finished []string
+	for name, session := range s.watching {
+		if session.spectate && !session.target.IsInCombat() {
+			finished = append(finished, name)
+		}
+	}
+	s.watchMu.Unlock()
+
+	for _, name := range finished {
+		targetName := s.StopWatching(name)
+		if watcher := s.findOnlinePlayer(name); watcher != nil && targetName != "" {
+			watcher.SendMessage(fmt.Sprintf("\n%s's fight is over. You stop spectating.\n", targetName))
+		}
+	}
+}
+
+// isInBossFight returns true if the player is fighting a boss.
+func (s *Server) isInBossFight(p *player.Player) bool {
+	if !p.IsInCombat() {
+		return false
+	}
+	room, ok := p.GetCurrentRoom().(*world.Room)
+	if !ok || room == nil {
+		return false
+	}
+	npc := room.FindNPC(p.GetCombatTarget())
+	return npc != nil && npc.GetIsBoss()
+}
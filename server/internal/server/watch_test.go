@@ -0,0 +1,157 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/color"
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
+	"github.com/lawnchairsociety/opentowermud/server/internal/config"
+	"github.com/lawnchairsociety/opentowermud/server/internal/player"
+)
+
+// ansiStubClient is a stubClient that renders ANSI color.
+type ansiStubClient struct {
+	stubClient
+}
+
+func (c *ansiStubClient) ColorMode() color.Mode { return color.ModeANSI }
+
+// addWatchTestPlayer adds an online player whose connection can be observed.
+func addWatchTestPlayer(s *Server, name string) (*player.Player, *stubClient) {
+	raw := &stubClient{}
+	p := player.NewPlayer(name, newFanOutClient(raw), s.world, s)
+	s.mu.Lock()
+	s.clients[name] = p
+	s.mu.Unlock()
+	return p, raw
+}
+
+// joined returns everything a stub client received as one string.
+func joined(c *stubClient) string {
+	return strings.Join(c.lines, "")
+}
+
+// TestFanOutClient_MirrorsToObservers tests that output reaches the player and every observer
+func TestFanOutClient_MirrorsToObservers(t *testing.T) {
+	raw := &stubClient{}
+	fc := newFanOutClient(raw)
+	observer := &stubClient{}
+	fc.addObserver("Admin", observer, "[snoop Bob] ")
+
+	fc.WriteTyped(command.MessageCombat, "\nYou hit the goblin.\n")
+
+	if joined(raw) != "\nYou hit the goblin.\n" {
+		t.Errorf("Expected player to receive output unchanged, got %q", joined(raw))
+	}
+	if joined(observer) != "\n[snoop Bob] You hit the goblin.\n" {
+		t.Errorf("Expected prefixed copy for observer, got %q", joined(observer))
+	}
+
+	fc.removeObserver("admin")
+	fc.WriteLine("more")
+	if len(observer.lines) != 1 {
+		t.Errorf("Expected no output after the observer was removed, got %v", observer.lines)
+	}
+}
+
+// TestFanOutClient_ConvertsColorMode tests that observers with a different color mode get plain text
+func TestFanOutClient_ConvertsColorMode(t *testing.T) {
+	fc := newFanOutClient(&ansiStubClient{})
+	observer := &stubClient{}
+	fc.addObserver("Admin", observer, "")
+
+	if fc.ColorMode() != color.ModeANSI {
+		t.Fatal("Expected the wrapper to report the wrapped client's color mode")
+	}
+
+	fc.WriteLine(color.Render("{npc}goblin{/} {{ruins}", color.ModeANSI, nil))
+	if joined(observer) != "goblin {ruins}" {
+		t.Errorf("Expected plain text for the observer, got %q", joined(observer))
+	}
+}
+
+// TestSnoop_MirrorsAndNotifies tests snooping a player with the notify policy
+func TestSnoop_MirrorsAndNotifies(t *testing.T) {
+	s, _ := newLinkDeadTestServer(t, 60)
+	_, adminRaw := addWatchTestPlayer(s, "Admin")
+	bob, bobRaw := addWatchTestPlayer(s, "Bob")
+
+	if err := s.StartSnoop("Admin", "bob"); err != nil {
+		t.Fatalf("StartSnoop failed: %v", err)
+	}
+	if !strings.Contains(joined(bobRaw), "Admin is now watching") {
+		t.Error("Expected target to be told about the snoop by default")
+	}
+	if target, spectating := s.GetWatchTarget("admin"); target != "Bob" || spectating {
+		t.Errorf("Expected a snoop of Bob, got %q spectating=%v", target, spectating)
+	}
+
+	bob.SendMessage("You feel hungry.")
+	if !strings.Contains(joined(adminRaw), "[snoop Bob] You feel hungry.") {
+		t.Errorf("Expected snooped output, got %q", joined(adminRaw))
+	}
+
+	if s.StopWatching("Admin") != "Bob" {
+		t.Error("Expected StopWatching to return the target")
+	}
+	adminRaw.lines = nil
+	bob.SendMessage("Still hungry.")
+	if len(adminRaw.lines) != 0 {
+		t.Errorf("Expected no output after snooping stopped, got %v", adminRaw.lines)
+	}
+}
+
+// TestSnoop_SilentPolicy tests that the target isn't told when the policy is "never"
+func TestSnoop_SilentPolicy(t *testing.T) {
+	s, _ := newLinkDeadTestServer(t, 60)
+	s.serverConfig.Admin.SnoopNotify = config.SnoopNotifyNever
+	addWatchTestPlayer(s, "Admin")
+	_, bobRaw := addWatchTestPlayer(s, "Bob")
+
+	if err := s.StartSnoop("Admin", "Bob"); err != nil {
+		t.Fatalf("StartSnoop failed: %v", err)
+	}
+	s.StopWatching("Admin")
+	if len(bobRaw.lines) != 0 {
+		t.Errorf("Expected no notification, got %v", bobRaw.lines)
+	}
+}
+
+// TestSpectate_RequiresBossFight tests that players can only spectate fights against bosses
+func TestSpectate_RequiresBossFight(t *testing.T) {
+	s, _ := newLinkDeadTestServer(t, 60)
+	addWatchTestPlayer(s, "Viewer")
+	bob, _ := addWatchTestPlayer(s, "Bob")
+
+	if err := s.StartSpectate("Viewer", "Bob"); err == nil {
+		t.Fatal("Expected an error spectating a player who isn't fighting")
+	}
+
+	bob.StartCombat("rat")
+	if err := s.StartSpectate("Viewer", "Bob"); err == nil {
+		t.Fatal("Expected an error spectating a fight that isn't against a boss")
+	}
+	if target, _ := s.GetWatchTarget("Viewer"); target != "" {
+		t.Errorf("Expected no watch session, got %q", target)
+	}
+}
+
+// TestReleaseWatchers tests that watchers are released when the target leaves
+func TestReleaseWatchers(t *testing.T) {
+	s, _ := newLinkDeadTestServer(t, 60)
+	_, adminRaw := addWatchTestPlayer(s, "Admin")
+	bob, _ := addWatchTestPlayer(s, "Bob")
+
+	if err := s.StartSnoop("Admin", "Bob"); err != nil {
+		t.Fatalf("StartSnoop failed: %v", err)
+	}
+	s.releaseWatchers(bob)
+
+	if target, _ := s.GetWatchTarget("Admin"); target != "" {
+		t.Errorf("Expected snoop to end when the target leaves, got %q", target)
+	}
+	if !strings.Contains(joined(adminRaw), "Bob has left the game") {
+		t.Errorf("Expected the admin to be told, got %q", joined(adminRaw))
+	}
+}
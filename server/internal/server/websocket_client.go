@@ -1,10 +1,15 @@
 package server
 
 import (
+	"encoding/json"
+	"html"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/gorilla/websocket"
+	"github.com/lawnchairsociety/opentowermud/server/internal/color"
+	"github.com/lawnchairsociety/opentowermud/server/internal/command"
 )
 
 // MaxWebSocketMessageSize is the maximum size of a WebSocket message in bytes.
@@ -12,11 +17,37 @@ import (
 // 4KB is generous for MUD commands which are typically short text strings.
 const MaxWebSocketMessageSize = 4096
 
+// JSONSubprotocol is the WebSocket subprotocol a browser client requests to use
+// structured JSON mode. Clients can also opt in by sending {"type":"hello","mode":"json"}
+// as their first message.
+const JSONSubprotocol = "json"
+
+// jsonEnvelope is a typed message sent to clients in JSON mode.
+// Text is HTML: color markup arrives as <span class="c-category"> (see ColorMode).
+type jsonEnvelope struct {
+	Type string      `json:"type"`
+	Text string      `json:"text,omitempty"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+// jsonInbound is a message received from a client in JSON mode.
+type jsonInbound struct {
+	Type string `json:"type"` // "hello" or "command"
+	Mode string `json:"mode"` // For hello: "json" to enable JSON mode
+	Text string `json:"text"` // For command: the command line to execute
+}
+
 // WebSocketClient wraps a WebSocket connection for browser-based communication.
 type WebSocketClient struct {
 	conn    *websocket.Conn
 	readBuf []string   // Buffer for lines when a message contains multiple lines
 	mu      sync.Mutex // Protects readBuf
+	writeMu sync.Mutex // Serializes writes (gorilla allows one concurrent writer)
+	gmcp    bool       // Client negotiated the GMCP subprotocol
+	bytes   atomic.Int64
+
+	jsonMode  atomic.Bool // Structured JSON mode (opt-in)
+	readCount int         // Messages read so far (only touched by the reading goroutine)
 }
 
 // NewWebSocketClient creates a new WebSocketClient from a WebSocket connection.
@@ -24,10 +55,13 @@ func NewWebSocketClient(conn *websocket.Conn) *WebSocketClient {
 	// Set read limit to prevent memory exhaustion from oversized messages
 	conn.SetReadLimit(MaxWebSocketMessageSize)
 
-	return &WebSocketClient{
+	c := &WebSocketClient{
 		conn:    conn,
 		readBuf: make([]string, 0),
+		gmcp:    conn.Subprotocol() == GMCPSubprotocol,
 	}
+	c.jsonMode.Store(conn.Subprotocol() == JSONSubprotocol)
+	return c
 }
 
 // ReadLine reads a line from the WebSocket connection (blocking).
@@ -51,8 +85,13 @@ func (c *WebSocketClient) ReadLine() (string, error) {
 			return "", err
 		}
 
+		c.readCount++
+
 		// Convert to string and split by newlines (in case client sends multiple lines)
 		text := string(message)
+		if cmd, handled := c.handleJSONMessage(message); handled {
+			text = cmd
+		}
 		lines := strings.Split(text, "\n")
 
 		// Filter out empty lines and trim whitespace
@@ -80,17 +119,117 @@ func (c *WebSocketClient) ReadLine() (string, error) {
 	}
 }
 
+// handleJSONMessage interprets a JSON message from the client.
+// Returns the command text to execute and true if the message was JSON the
+// client is allowed to send; otherwise the message is treated as plain text.
+func (c *WebSocketClient) handleJSONMessage(message []byte) (string, bool) {
+	trimmed := strings.TrimSpace(string(message))
+	if !strings.HasPrefix(trimmed, "{") {
+		return "", false
+	}
+
+	var in jsonInbound
+	if err := json.Unmarshal([]byte(trimmed), &in); err != nil {
+		return "", false
+	}
+
+	switch in.Type {
+	case "hello":
+		// Opt-in is only honored as the first message of the session
+		if c.readCount == 1 && in.Mode == "json" {
+			c.jsonMode.Store(true)
+			return "", true
+		}
+	case "command":
+		if c.jsonMode.Load() {
+			return in.Text, true
+		}
+	}
+	return "", false
+}
+
+// IsJSONMode returns true if the client opted into structured JSON mode.
+func (c *WebSocketClient) IsJSONMode() bool {
+	return c.jsonMode.Load()
+}
+
+// ColorMode returns how color markup should be rendered for this client.
+// JSON mode clients get HTML spans with CSS classes (c-room, c-npc, ...);
+// plain-text clients get no color.
+func (c *WebSocketClient) ColorMode() color.Mode {
+	if c.jsonMode.Load() {
+		return color.ModeHTML
+	}
+	return color.ModeNone
+}
+
 // WriteLine writes a message to the WebSocket client.
 // Unlike telnet, we don't need to add newlines - the message is self-contained.
+// In JSON mode the message is HTML-escaped and sent as a "system" envelope.
+// Game output from players goes through WriteTyped, already rendered as HTML.
 func (c *WebSocketClient) WriteLine(message string) error {
-	return c.conn.WriteMessage(websocket.TextMessage, []byte(message))
+	if c.jsonMode.Load() {
+		message = html.EscapeString(message)
+	}
+	return c.WriteTyped(command.MessageSystem, message)
+}
+
+// WriteTyped writes a message tagged with its kind (room, combat, chat, ...).
+// In plain-text mode the kind is ignored.
+func (c *WebSocketClient) WriteTyped(kind string, message string) error {
+	if !c.jsonMode.Load() {
+		return c.Write([]byte(message))
+	}
+	return c.writeJSON(jsonEnvelope{Type: kind, Text: message})
+}
+
+// writeJSON marshals v and sends it as a single text message.
+func (c *WebSocketClient) writeJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.Write(data)
 }
 
 // Write writes raw bytes to the WebSocket client as a text message.
 func (c *WebSocketClient) Write(data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.bytes.Add(int64(len(data)))
 	return c.conn.WriteMessage(websocket.TextMessage, data)
 }
 
+// TrafficStats returns bytes written to the client. WebSocket output isn't
+// compressed by the server, so raw and sent are the same.
+func (c *WebSocketClient) TrafficStats() (raw, sent int64, compressed bool) {
+	n := c.bytes.Load()
+	return n, n, false
+}
+
+// GMCPEnabled returns true if the client negotiated the GMCP subprotocol
+// or is in JSON mode (which carries GMCP data as envelopes).
+func (c *WebSocketClient) GMCPEnabled() bool {
+	return c.gmcp || c.jsonMode.Load()
+}
+
+// SendGMCP sends a GMCP package as a typed JSON frame.
+// In JSON mode, Char.Vitals is sent as a "vitals" envelope.
+// Does nothing if GMCP isn't enabled.
+func (c *WebSocketClient) SendGMCP(pkg string, data interface{}) error {
+	if !c.GMCPEnabled() {
+		return nil
+	}
+	if pkg == "Char.Vitals" && c.jsonMode.Load() {
+		return c.writeJSON(jsonEnvelope{Type: command.MessageVitals, Data: data})
+	}
+	frame, err := encodeGMCPFrame(pkg, data)
+	if err != nil {
+		return err
+	}
+	return c.Write(frame)
+}
+
 // Close closes the WebSocket connection.
 func (c *WebSocketClient) Close() error {
 	return c.conn.Close()
@@ -183,3 +183,101 @@ func TestWebSocketClient_RemoteAddr(t *testing.T) {
 		t.Error("RemoteAddr should not be empty")
 	}
 }
+
+// TestWebSocketClient_JSONMode tests the hello opt-in, JSON commands, and typed envelopes
+func TestWebSocketClient_JSONMode(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	received := make(chan string, 2)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Fatalf("Failed to upgrade: %v", err)
+		}
+		defer conn.Close()
+
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"hello","mode":"json"}`))
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"command","text":"look"}`))
+
+		for i := 0; i < 2; i++ {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			received <- string(msg)
+		}
+	}))
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	client := NewWebSocketClient(conn)
+	if client.IsJSONMode() {
+		t.Fatal("JSON mode should be off until the client opts in")
+	}
+
+	line, err := client.ReadLine()
+	if err != nil {
+		t.Fatalf("ReadLine failed: %v", err)
+	}
+	if line != "look" {
+		t.Errorf("Expected 'look' from JSON command, got '%s'", line)
+	}
+	if !client.IsJSONMode() {
+		t.Fatal("Expected JSON mode after hello")
+	}
+
+	client.WriteTyped("room", "Dusty Hall")
+	client.WriteLine("Welcome!")
+
+	expected := []string{
+		`{"type":"room","text":"Dusty Hall"}`,
+		`{"type":"system","text":"Welcome!"}`,
+	}
+	for _, want := range expected {
+		select {
+		case msg := <-received:
+			if msg != want {
+				t.Errorf("Expected %s, got %s", want, msg)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Timeout waiting for message")
+		}
+	}
+}
+
+// TestWebSocketClient_JSONIgnoredInTextMode tests that JSON commands are plain text unless opted in
+func TestWebSocketClient_JSONIgnoredInTextMode(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Fatalf("Failed to upgrade: %v", err)
+		}
+		defer conn.Close()
+
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"command","text":"look"}`))
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	client := NewWebSocketClient(conn)
+
+	line, _ := client.ReadLine()
+	if line != `{"type":"command","text":"look"}` {
+		t.Errorf("Expected raw text in plain mode, got '%s'", line)
+	}
+}
@@ -15,6 +15,7 @@ type SpellEffectDefinition struct {
 	Dice     string `yaml:"dice,omitempty"`      // Dice notation e.g. "1d6", "2d4+2"
 	Duration int    `yaml:"duration,omitempty"`  // Duration in seconds for timed effects
 	BuffType string `yaml:"buff_type,omitempty"` // Type of buff/debuff (ac, hit, damage, taken)
+	Summon   string `yaml:"summon,omitempty"`    // Mob ID called in by summon effects
 }
 
 // SpellDefinition represents a spell definition from the YAML file.
@@ -26,6 +27,7 @@ type SpellDefinition struct {
 	Level          int                     `yaml:"level"`
 	Effects        []SpellEffectDefinition `yaml:"effects"`
 	AllowedClasses []string                `yaml:"allowed_classes,omitempty"` // Classes that can learn this spell
+	NPCOnly        bool                    `yaml:"npc_only,omitempty"`        // Only used by NPCs as a combat ability
 }
 
 // SpellsConfig represents the structure of the spells.yaml file.
@@ -94,6 +96,8 @@ func StringToEffectType(s string) EffectType {
 		return EffectCleanse
 	case "multi_attack":
 		return EffectMultiAttack
+	case "summon":
+		return EffectSummon
 	default:
 		return EffectHeal
 	}
@@ -150,6 +154,7 @@ func CreateSpellFromDefinition(id string, def SpellDefinition) *Spell {
 			Dice:     e.Dice,
 			Duration: e.Duration,
 			BuffType: StringToBuffType(e.BuffType),
+			Summon:   e.Summon,
 		}
 	}
 
@@ -162,6 +167,7 @@ func CreateSpellFromDefinition(id string, def SpellDefinition) *Spell {
 		Level:          def.Level,
 		Effects:        effects,
 		AllowedClasses: def.AllowedClasses,
+		NPCOnly:        def.NPCOnly,
 	}
 }
 
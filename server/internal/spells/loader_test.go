@@ -14,6 +14,7 @@ func TestStringToEffectType(t *testing.T) {
 		{"heal", EffectHeal},
 		{"damage", EffectDamage},
 		{"heal_percent", EffectHealPercent},
+		{"summon", EffectSummon},
 		{"unknown", EffectHeal}, // Default case
 	}
 
@@ -151,6 +152,7 @@ func TestGetSpellsForClass(t *testing.T) {
 	registry.spells["cleric_spell"] = &Spell{ID: "cleric_spell", Level: 1, AllowedClasses: []string{"cleric"}}
 	registry.spells["universal"] = &Spell{ID: "universal", Level: 1, AllowedClasses: []string{}} // Empty = all classes
 	registry.spells["high_level_mage"] = &Spell{ID: "high_level_mage", Level: 10, AllowedClasses: []string{"mage"}}
+	registry.spells["boss_ability"] = &Spell{ID: "boss_ability", Level: 1, NPCOnly: true} // Never available to players
 
 	// Test mage at level 5 - should get mage_spell and universal
 	mageSpells := registry.GetSpellsForClass("mage", 5)
@@ -19,6 +19,7 @@ const (
 	EffectResurrect   EffectType = "resurrect"    // Revive dead player
 	EffectCleanse     EffectType = "cleanse"      // Remove debuffs
 	EffectMultiAttack EffectType = "multi_attack" // Attack multiple times
+	EffectSummon      EffectType = "summon"       // Call in mobs to join the fight (NPC only)
 )
 
 // TargetType represents what a spell can target.
@@ -53,6 +54,7 @@ type SpellEffect struct {
 	Dice     string   // Dice notation for effect (e.g., "1d6", "2d4+2") - used with ability modifier
 	Duration int      // Duration in seconds for timed effects (buffs, debuffs, poison, root)
 	BuffType BuffType // Type of buff/debuff (ac, hit, damage, taken)
+	Summon   string   // Mob ID to call in for summon effects (Amount = how many)
 }
 
 // Spell represents a castable spell with its properties.
@@ -65,11 +67,16 @@ type Spell struct {
 	Effects        []SpellEffect
 	Level          int      // Minimum class level to learn
 	AllowedClasses []string // Classes that can learn this spell (empty = all classes)
+	NPCOnly        bool     // Only NPCs use this spell (as a combat ability); players can't learn it
 }
 
 // IsAllowedForClass returns true if the specified class can learn this spell.
 // If AllowedClasses is empty, the spell is available to all classes.
+// NPC-only spells are never available to players.
 func (s *Spell) IsAllowedForClass(className string) bool {
+	if s.NPCOnly {
+		return false
+	}
 	if len(s.AllowedClasses) == 0 {
 		return true // No restrictions, available to all
 	}
@@ -197,3 +204,23 @@ func (s *Spell) HasRootEffect() bool {
 	}
 	return false
 }
+
+// CanTargetRoomAllies returns true if the spell affects the caster's party members in the room.
+func (s *Spell) CanTargetRoomAllies() bool {
+	for _, effect := range s.Effects {
+		if effect.Target == TargetRoomAlly {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTargetDeadAlly returns true if the spell targets a fallen ally (resurrection).
+func (s *Spell) CanTargetDeadAlly() bool {
+	for _, effect := range s.Effects {
+		if effect.Target == TargetDeadAlly {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package spells
+
+import "github.com/lawnchairsociety/opentowermud/server/internal/effects"
+
+// Default durations for timed effects when the spell doesn't give one.
+const (
+	DefaultEffectDuration  = 30 // Seconds a buff, debuff, poison or root lasts
+	DefaultCoatingDuration = 60 // Seconds a weapon coating lasts before it dries up
+)
+
+// Status returns the status effect a spell effect puts on its target and how
+// many seconds it lasts. ok is false for effects that aren't status effects
+// (damage, heals, cleanses and so on).
+//
+// A poison cast on yourself coats your weapon instead: its dice are added to
+// your next "amount" hits.
+func (e SpellEffect) Status(spell *Spell) (status effects.Effect, seconds int, ok bool) {
+	status = effects.Effect{ID: spell.ID, Name: spell.Name, Stacks: 1}
+	seconds = e.Duration
+	if seconds <= 0 {
+		seconds = DefaultEffectDuration
+	}
+
+	switch e.Type {
+	case EffectBuff, EffectDebuff:
+		status.Kind = effects.KindBuff
+		if e.Type == EffectDebuff {
+			status.Kind = effects.KindDebuff
+		}
+		status.Stat = effects.Stat(e.BuffType)
+		status.Amount = e.Amount
+	case EffectPoison:
+		if e.Target == TargetSelf {
+			status.Kind = effects.KindBuff
+			status.Stat = effects.StatCoating
+			status.Dice = e.Dice
+			status.Charges = e.Amount
+			if e.Duration <= 0 {
+				seconds = DefaultCoatingDuration
+			}
+			break
+		}
+		status.Kind = effects.KindPoison
+		status.Amount = e.Amount
+		status.Dice = e.Dice
+	case EffectStun:
+		// Stuns have always given their length as the amount
+		status.Kind = effects.KindStun
+		if e.Amount > 0 {
+			seconds = e.Amount
+		}
+	case EffectRoot:
+		status.Kind = effects.KindRoot
+	default:
+		return effects.Effect{}, 0, false
+	}
+	return status, seconds, true
+}
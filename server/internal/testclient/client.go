@@ -186,6 +186,13 @@ func newTestClientInternal(name string, address string) (*TestClient, error) {
 		time.Sleep(150 * time.Millisecond)
 	}
 
+	// Decline hardcore mode (Y/N prompt)
+	if err := client.SendCommand("N"); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to decline hardcore mode: %w", err)
+	}
+	time.Sleep(150 * time.Millisecond)
+
 	// Verify we're in the game (should see room description)
 	if !client.WaitForMessage("Town Square", 3*time.Second) {
 		// Check if we got an error message
@@ -50,6 +50,9 @@ func CreateCityFloor(config *CityConfig) (*Floor, error) {
 	// First pass: create all rooms
 	for roomID, def := range config.Rooms {
 		roomType := world.RoomTypeCity // Default to city type
+		if def.Type == world.RoomTypeArena.String() {
+			roomType = world.RoomTypeArena
+		}
 
 		room := world.NewRoom(roomID, def.Name, def.Description, roomType)
 		room.Floor = 0
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/world"
 )
 
 func TestLoadCityFromYAML(t *testing.T) {
@@ -23,9 +25,9 @@ func TestLoadCityFromYAML(t *testing.T) {
 		t.Fatal("Config is nil")
 	}
 
-	// Should have 21 rooms (10 original + 6 castle rooms + 1 artisan's market + 2 military district + 2 crafting shops)
-	if len(config.Rooms) != 21 {
-		t.Errorf("Expected 21 rooms, got %d", len(config.Rooms))
+	// Should have 22 rooms (10 original + 6 castle rooms + 1 artisan's market + 2 military district + 2 crafting shops + 1 arena)
+	if len(config.Rooms) != 22 {
+		t.Errorf("Expected 22 rooms, got %d", len(config.Rooms))
 	}
 
 	// Check for required rooms (all prefixed with human_)
@@ -51,6 +53,7 @@ func TestLoadCityFromYAML(t *testing.T) {
 		"human_military_district_east",
 		"human_alchemist_shop",
 		"human_mage_tower",
+		"human_arena",
 	}
 
 	for _, roomID := range requiredRooms {
@@ -82,9 +85,9 @@ func TestCreateCityFloor(t *testing.T) {
 		t.Errorf("Floor number = %d, want 0", floor.Number)
 	}
 
-	// Should have 21 rooms (10 original + 6 castle rooms + 1 artisan's market + 2 military district + 2 crafting shops)
-	if floor.RoomCount() != 21 {
-		t.Errorf("Room count = %d, want 21", floor.RoomCount())
+	// Should have 22 rooms (10 original + 6 castle rooms + 1 artisan's market + 2 military district + 2 crafting shops + 1 arena)
+	if floor.RoomCount() != 22 {
+		t.Errorf("Room count = %d, want 22", floor.RoomCount())
 	}
 
 	// Should be marked as city
@@ -153,6 +156,14 @@ func TestCityFloorFeatures(t *testing.T) {
 	if !entrance.HasFeature("stairs_up") {
 		t.Error("Tower entrance should have stairs_up feature")
 	}
+
+	// Arena keeps its room type; other city rooms default to city
+	if arena := floor.GetRoom("human_arena"); arena.Type != world.RoomTypeArena {
+		t.Errorf("Arena type = %v, want arena", arena.Type)
+	}
+	if temple.Type != world.RoomTypeCity {
+		t.Errorf("Temple type = %v, want city", temple.Type)
+	}
 }
 
 func TestCityFloorPortalAndStairs(t *testing.T) {
@@ -0,0 +1,129 @@
+package tower
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EncountersFile is the file in each tower's floor directory that scripts its boss fights.
+const EncountersFile = "encounters.yaml"
+
+// EncountersYAML represents a tower's encounters.yaml file
+type EncountersYAML struct {
+	Encounters map[string]*Encounter `yaml:"encounters"` // Keyed by boss mob ID
+}
+
+// Encounter scripts a boss fight: phases the boss moves through as it is hurt,
+// an optional enrage timer, and what happens when the fight is abandoned.
+type Encounter struct {
+	Phases       []EncounterPhase `yaml:"phases"`        // Ordered from highest hp_below to lowest
+	Enrage       *EncounterEnrage `yaml:"enrage"`        // Optional enrage timer
+	ResetMessage string           `yaml:"reset_message"` // Announced when everyone leaves or dies and the boss resets
+}
+
+// EncounterPhase is one stage of a boss fight.
+type EncounterPhase struct {
+	HPBelow   float64        `yaml:"hp_below"`  // Phase starts at or below this percentage of max health (100 = from the start)
+	Message   string         `yaml:"message"`   // Announced to the room when the phase starts
+	Adds      []EncounterAdd `yaml:"adds"`      // Mobs called into the fight when the phase starts
+	Telegraph *Telegraph     `yaml:"telegraph"` // Room-wide attack the boss winds up during this phase
+}
+
+// EncounterAdd is a wave of mobs that joins the fight.
+type EncounterAdd struct {
+	Mob   string `yaml:"mob"`   // Mob ID (from mobs.yaml)
+	Count int    `yaml:"count"` // How many (default 1)
+}
+
+// Telegraph is a room-wide attack announced ahead of time. Players can flee
+// the room before it lands, or defend to halve the damage.
+type Telegraph struct {
+	Name     string `yaml:"name"`     // "Meteor Storm"
+	Warning  string `yaml:"warning"`  // Announced when the boss starts winding up
+	Message  string `yaml:"message"`  // Announced when the attack lands
+	Windup   int    `yaml:"windup"`   // Combat rounds between the warning and the hit (default 1)
+	Interval int    `yaml:"interval"` // Combat rounds between one hit and the next warning (default 3)
+	Damage   string `yaml:"damage"`   // Damage dice, e.g. "6d6"
+}
+
+// EncounterEnrage makes the boss hit harder if the fight runs too long.
+type EncounterEnrage struct {
+	After         int    `yaml:"after"`          // Seconds into the fight
+	DamagePercent int    `yaml:"damage_percent"` // Extra melee damage once enraged (default 50)
+	Message       string `yaml:"message"`        // Announced when the boss enrages
+}
+
+// LoadEncounters loads a tower's boss encounters from a YAML file. A missing
+// file just means the tower has no scripted encounters.
+func LoadEncounters(path string) (map[string]*Encounter, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encounters file: %w", err)
+	}
+
+	var file EncountersYAML
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse encounters YAML: %w", err)
+	}
+
+	for bossID, enc := range file.Encounters {
+		if enc == nil || len(enc.Phases) == 0 {
+			return nil, fmt.Errorf("encounter %s has no phases", bossID)
+		}
+		enc.normalize()
+		for _, phase := range enc.Phases {
+			if phase.Telegraph != nil && phase.Telegraph.Damage == "" {
+				return nil, fmt.Errorf("encounter %s: telegraph %q has no damage", bossID, phase.Telegraph.Name)
+			}
+			for _, add := range phase.Adds {
+				if add.Mob == "" {
+					return nil, fmt.Errorf("encounter %s: add wave has no mob", bossID)
+				}
+			}
+		}
+	}
+	return file.Encounters, nil
+}
+
+// normalize sorts the phases and fills in defaults
+func (e *Encounter) normalize() {
+	sort.SliceStable(e.Phases, func(i, j int) bool {
+		return e.Phases[i].HPBelow > e.Phases[j].HPBelow
+	})
+	for i := range e.Phases {
+		for j := range e.Phases[i].Adds {
+			if e.Phases[i].Adds[j].Count < 1 {
+				e.Phases[i].Adds[j].Count = 1
+			}
+		}
+		if t := e.Phases[i].Telegraph; t != nil {
+			if t.Windup < 1 {
+				t.Windup = 1
+			}
+			if t.Interval < 1 {
+				t.Interval = 3
+			}
+		}
+	}
+	if e.Enrage != nil && e.Enrage.DamagePercent < 1 {
+		e.Enrage.DamagePercent = 50
+	}
+}
+
+// PhaseAt returns the index of the latest phase a boss at the given health
+// percentage has reached, or -1 if it hasn't reached the first one.
+func (e *Encounter) PhaseAt(hpPercent float64) int {
+	phase := -1
+	for i, p := range e.Phases {
+		if hpPercent <= p.HPBelow {
+			phase = i
+		}
+	}
+	return phase
+}
@@ -0,0 +1,145 @@
+package tower
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lawnchairsociety/opentowermud/server/internal/npc"
+)
+
+func TestLoadEncounters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), EncountersFile)
+	yamlContent := `
+encounters:
+  goblin_king:
+    phases:
+      - hp_below: 30
+        message: "The Goblin King calls his guard!"
+        adds:
+          - mob: goblin
+      - hp_below: 100
+        message: "The Goblin King draws his blade."
+        telegraph:
+          name: "Royal Decree"
+          damage: "2d6"
+    enrage:
+      after: 120
+`
+	if err := os.WriteFile(path, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	encounters, err := LoadEncounters(path)
+	if err != nil {
+		t.Fatalf("LoadEncounters failed: %v", err)
+	}
+	enc := encounters["goblin_king"]
+	if enc == nil {
+		t.Fatal("Expected an encounter for goblin_king")
+	}
+
+	// Phases are sorted from the start of the fight to the end
+	if len(enc.Phases) != 2 || enc.Phases[0].HPBelow != 100 || enc.Phases[1].HPBelow != 30 {
+		t.Fatalf("Expected phases at 100%% then 30%%, got %+v", enc.Phases)
+	}
+
+	// Defaults are filled in
+	telegraph := enc.Phases[0].Telegraph
+	if telegraph.Windup != 1 || telegraph.Interval != 3 {
+		t.Errorf("Expected telegraph windup 1 and interval 3, got %d and %d", telegraph.Windup, telegraph.Interval)
+	}
+	if enc.Phases[1].Adds[0].Count != 1 {
+		t.Errorf("Expected an add wave to default to 1 mob, got %d", enc.Phases[1].Adds[0].Count)
+	}
+	if enc.Enrage.DamagePercent != 50 {
+		t.Errorf("Expected enrage to default to 50%% extra damage, got %d", enc.Enrage.DamagePercent)
+	}
+}
+
+func TestLoadEncounters_Missing(t *testing.T) {
+	encounters, err := LoadEncounters(filepath.Join(t.TempDir(), EncountersFile))
+	if err != nil || len(encounters) != 0 {
+		t.Errorf("Expected a missing file to mean no encounters, got %v, %v", encounters, err)
+	}
+}
+
+func TestLoadEncounters_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"no phases", "encounters:\n  goblin_king:\n    reset_message: \"Hm.\"\n"},
+		{"telegraph without damage", "encounters:\n  goblin_king:\n    phases:\n      - hp_below: 100\n        telegraph:\n          name: \"Slam\"\n"},
+		{"add without mob", "encounters:\n  goblin_king:\n    phases:\n      - hp_below: 50\n        adds:\n          - count: 2\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), EncountersFile)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write test file: %v", err)
+			}
+			if _, err := LoadEncounters(path); err == nil {
+				t.Error("Expected LoadEncounters to fail")
+			}
+		})
+	}
+}
+
+func TestEncounterPhaseAt(t *testing.T) {
+	enc := &Encounter{Phases: []EncounterPhase{{HPBelow: 100}, {HPBelow: 60}, {HPBelow: 25}}}
+	tests := []struct {
+		hpPercent float64
+		want      int
+	}{
+		{100, 0},
+		{61, 0},
+		{60, 1},
+		{26, 1},
+		{25, 2},
+		{1, 2},
+	}
+	for _, tt := range tests {
+		if got := enc.PhaseAt(tt.hpPercent); got != tt.want {
+			t.Errorf("PhaseAt(%v) = %d, want %d", tt.hpPercent, got, tt.want)
+		}
+	}
+
+	late := &Encounter{Phases: []EncounterPhase{{HPBelow: 50}}}
+	if got := late.PhaseAt(80); got != -1 {
+		t.Errorf("PhaseAt(80) = %d, want -1 before the first phase", got)
+	}
+}
+
+// TestTowerEncounterFiles checks that every tower's encounters load and only
+// script bosses and adds that exist
+func TestTowerEncounterFiles(t *testing.T) {
+	dataDir := findDataDir()
+	if dataDir == "" {
+		t.Skip("Could not find data directory")
+	}
+	mobs, err := npc.LoadNPCsFromYAML(filepath.Join(dataDir, "mobs", "mobs.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to load mobs: %v", err)
+	}
+
+	for _, theme := range GetAllThemes() {
+		encounters, err := LoadEncounters(filepath.Join(dataDir, "towers", string(theme.ID), EncountersFile))
+		if err != nil {
+			t.Errorf("%s: %v", theme.ID, err)
+			continue
+		}
+		for bossID, enc := range encounters {
+			if def, exists := mobs.NPCs[bossID]; !exists || !def.Boss {
+				t.Errorf("%s: encounter %s doesn't script a boss", theme.ID, bossID)
+			}
+			for _, phase := range enc.Phases {
+				for _, wave := range phase.Adds {
+					if _, exists := mobs.NPCs[wave.Mob]; !exists {
+						t.Errorf("%s: encounter %s calls in unknown mob %s", theme.ID, bossID, wave.Mob)
+					}
+				}
+			}
+		}
+	}
+}
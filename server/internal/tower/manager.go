@@ -22,6 +22,7 @@ type TowerManager struct {
 	worldDir   string
 	mobConfig  *npc.NPCsConfig
 	itemConfig *items.ItemsConfig
+	encounters map[string]*Encounter // Scripted boss fights by boss mob ID, from every initialized tower
 	mu         sync.RWMutex
 }
 
@@ -102,6 +103,18 @@ func (m *TowerManager) InitializeTower(id TowerID, seed int64) error {
 		}
 	}
 
+	// Load scripted boss encounters, if the tower has any
+	encounters, err := LoadEncounters(filepath.Join(m.dataDir, "towers", string(id), EncountersFile))
+	if err != nil {
+		return fmt.Errorf("failed to load encounters for tower %s: %w", id, err)
+	}
+	for bossID, enc := range encounters {
+		if m.encounters == nil {
+			m.encounters = make(map[string]*Encounter)
+		}
+		m.encounters[bossID] = enc
+	}
+
 	m.towers[id] = t
 	return nil
 }
@@ -277,6 +290,17 @@ func (m *TowerManager) GetMobSpawner(id TowerID) *MobSpawner {
 	return t.GetMobSpawner()
 }
 
+// GetEncounters returns the scripted boss encounters of all initialized towers, by boss mob ID.
+func (m *TowerManager) GetEncounters() map[string]*Encounter {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make(map[string]*Encounter, len(m.encounters))
+	for bossID, enc := range m.encounters {
+		result[bossID] = enc
+	}
+	return result
+}
+
 // ==================== Labyrinth Management ====================
 
 // InitializeLabyrinth loads and initializes the labyrinth, spawning mobs if configured.
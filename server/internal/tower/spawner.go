@@ -279,6 +279,12 @@ func (s *MobSpawner) spawnMobsInRoom(room *world.Room, floorNum, tier int, rng *
 
 // createScaledMob creates an NPC from a definition with floor-scaled stats
 func (s *MobSpawner) createScaledMob(def *npc.NPCDefinition, roomID string, floorNum int) *npc.NPC {
+	return CreateScaledMob(def, roomID, floorNum)
+}
+
+// CreateScaledMob creates an NPC from a definition with stats scaled for the given floor.
+// Also used for mobs summoned into a fight by a boss ability.
+func CreateScaledMob(def *npc.NPCDefinition, roomID string, floorNum int) *npc.NPC {
 	// Apply floor scaling to stats
 	scaledHP := ScaleHP(def.Health, floorNum)
 	scaledDamage := ScaleDamage(def.Damage, floorNum)
@@ -301,6 +307,14 @@ func (s *MobSpawner) createScaledMob(def *npc.NPCDefinition, roomID string, floo
 		def.RespawnMedian,
 		def.RespawnVariation,
 	)
+	// Remember the definition it came from (scripted boss encounters are keyed by it)
+	if def.ID != "" {
+		mob.SetNPCID(def.ID)
+	}
+	// Creature type for class bonuses and status effect immunities
+	if def.MobType != "" {
+		mob.SetMobType(npc.StringToMobType(def.MobType))
+	}
 
 	// Copy loot table for percentage-based drops
 	if len(def.LootTable) > 0 {
@@ -314,6 +328,11 @@ func (s *MobSpawner) createScaledMob(def *npc.NPCDefinition, roomID string, floo
 		mob.SetLootTable(lootTable)
 	}
 
+	// Copy combat abilities (boss mechanics)
+	if len(def.Abilities) > 0 {
+		mob.SetAbilities(npc.CreateAbilitiesFromDefinition(*def))
+	}
+
 	return mob
 }
 
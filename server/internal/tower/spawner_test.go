@@ -272,6 +272,11 @@ func TestSpawnBoss_MarkedAsBoss(t *testing.T) {
 	if boss.GetFloor() != 10 {
 		t.Errorf("Boss floor = %d, want 10", boss.GetFloor())
 	}
+
+	// Verify it remembers its definition, which boss encounters are keyed by
+	if boss.GetNPCID() != "goblin_king" {
+		t.Errorf("Boss NPCID = %q, want goblin_king", boss.GetNPCID())
+	}
 }
 
 // TestSpawnRegularMob_NotMarkedAsBoss tests that regular mobs are not marked as bosses
@@ -193,13 +193,13 @@ func (r *Room) GetDescriptionForPlayerWithCustomDesc(playerName string, baseDesc
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	desc := fmt.Sprintf("\n=== %s ===\n%s\n", r.Name, baseDesc)
+	desc := fmt.Sprintf("\n=== {room}%s{/} ===\n%s\n", r.Name, baseDesc)
 
 	// Show NPCs in the room
 	if len(r.NPCs) > 0 {
 		npcNames := make([]string, len(r.NPCs))
 		for i, n := range r.NPCs {
-			npcNames[i] = fmt.Sprintf("%s (Level %d)", n.GetName(), n.GetLevel())
+			npcNames[i] = fmt.Sprintf("{npc}%s{/} (Level %d)", n.GetName(), n.GetLevel())
 		}
 		desc += "\nNPCs here: " + strings.Join(npcNames, ", ") + "\n"
 	}
@@ -208,7 +208,7 @@ func (r *Room) GetDescriptionForPlayerWithCustomDesc(playerName string, baseDesc
 	otherPlayers := make([]string, 0)
 	for _, name := range r.Players {
 		if name != playerName {
-			otherPlayers = append(otherPlayers, name)
+			otherPlayers = append(otherPlayers, "{player}"+name+"{/}")
 		}
 	}
 	if len(otherPlayers) > 0 {
@@ -218,7 +218,7 @@ func (r *Room) GetDescriptionForPlayerWithCustomDesc(playerName string, baseDesc
 	if len(r.Items) > 0 {
 		itemNames := make([]string, len(r.Items))
 		for i, item := range r.Items {
-			itemNames[i] = item.Name
+			itemNames[i] = "{item}" + item.Name + "{/}"
 		}
 		desc += "\nYou can see: " + strings.Join(itemNames, ", ") + "\n"
 	}
@@ -247,7 +247,7 @@ func (r *Room) GetDescriptionForPlayerWithCustomDesc(playerName string, baseDesc
 		exits = append(exits, "down")
 	}
 	if len(exits) > 0 {
-		desc += "\nExits: " + strings.Join(exits, ", ") + "\n"
+		desc += "\nExits: {exits}" + strings.Join(exits, ", ") + "{/}\n"
 	}
 
 	// Show room features that players can interact with
@@ -307,13 +307,13 @@ func (r *Room) GetDescriptionForPlayerFilteredWithCustomDesc(playerName string,
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	desc := fmt.Sprintf("\n=== %s ===\n%s\n", r.Name, baseDesc)
+	desc := fmt.Sprintf("\n=== {room}%s{/} ===\n%s\n", r.Name, baseDesc)
 
 	// Show NPCs in the room
 	if len(r.NPCs) > 0 {
 		npcNames := make([]string, len(r.NPCs))
 		for i, n := range r.NPCs {
-			npcNames[i] = fmt.Sprintf("%s (Level %d)", n.GetName(), n.GetLevel())
+			npcNames[i] = fmt.Sprintf("{npc}%s{/} (Level %d)", n.GetName(), n.GetLevel())
 		}
 		desc += "\nNPCs here: " + strings.Join(npcNames, ", ") + "\n"
 	}
@@ -322,7 +322,7 @@ func (r *Room) GetDescriptionForPlayerFilteredWithCustomDesc(playerName string,
 	otherPlayers := make([]string, 0)
 	for _, name := range r.Players {
 		if name != playerName {
-			otherPlayers = append(otherPlayers, name)
+			otherPlayers = append(otherPlayers, "{player}"+name+"{/}")
 		}
 	}
 	if len(otherPlayers) > 0 {
@@ -341,7 +341,7 @@ func (r *Room) GetDescriptionForPlayerFilteredWithCustomDesc(playerName string,
 		var visibleItems []string
 		for _, item := range r.Items {
 			if !excludeMap[item.ID] {
-				visibleItems = append(visibleItems, item.Name)
+				visibleItems = append(visibleItems, "{item}"+item.Name+"{/}")
 			}
 		}
 
@@ -373,7 +373,7 @@ func (r *Room) GetDescriptionForPlayerFilteredWithCustomDesc(playerName string,
 		exits = append(exits, "down")
 	}
 	if len(exits) > 0 {
-		desc += "\nExits: " + strings.Join(exits, ", ") + "\n"
+		desc += "\nExits: {exits}" + strings.Join(exits, ", ") + "{/}\n"
 	}
 
 	// Show room features that players can interact with
@@ -473,6 +473,20 @@ func (r *Room) GetExits() map[string]string {
 	return exits
 }
 
+// GetExitIDs returns a map of direction -> destination room ID for all exits
+func (r *Room) GetExitIDs() map[string]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	exits := make(map[string]string)
+	for direction, room := range r.Exits {
+		if room != nil {
+			exits[direction] = room.ID
+		}
+	}
+	return exits
+}
+
 // HasFeature checks if the room has a specific feature
 func (r *Room) HasFeature(feature string) bool {
 	r.mu.RLock()
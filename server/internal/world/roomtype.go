@@ -12,6 +12,7 @@ const (
 	RoomTypeBoss                          // Boss rooms (every 10 floors)
 	RoomTypeLabyrinth                     // Labyrinth passages
 	RoomTypeLabyrinthGate                 // City gate rooms in the labyrinth
+	RoomTypeArena                         // City arenas where duels are ranked
 )
 
 // String returns the string representation of a RoomType
@@ -33,6 +34,8 @@ func (t RoomType) String() string {
 		return "labyrinth"
 	case RoomTypeLabyrinthGate:
 		return "labyrinth_gate"
+	case RoomTypeArena:
+		return "arena"
 	default:
 		return "unknown"
 	}
@@ -57,6 +60,8 @@ func ParseRoomType(s string) (RoomType, bool) {
 		return RoomTypeLabyrinth, true
 	case "labyrinth_gate":
 		return RoomTypeLabyrinthGate, true
+	case "arena":
+		return RoomTypeArena, true
 	default:
 		return RoomTypeCity, false
 	}
@@ -13,6 +13,7 @@ func TestRoomTypeString(t *testing.T) {
 		{RoomTypeStairs, "stairs"},
 		{RoomTypeTreasure, "treasure"},
 		{RoomTypeBoss, "boss"},
+		{RoomTypeArena, "arena"},
 	}
 
 	for _, tt := range tests {
@@ -34,6 +35,7 @@ func TestParseRoomType(t *testing.T) {
 		{"stairs", RoomTypeStairs, true},
 		{"treasure", RoomTypeTreasure, true},
 		{"boss", RoomTypeBoss, true},
+		{"arena", RoomTypeArena, true},
 		{"invalid", RoomTypeCity, false},
 	}
 